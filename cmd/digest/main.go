@@ -0,0 +1,111 @@
+// Команда digest печатает недельный дайджест по PR команды (или всех команд) в Markdown или JSON.
+//
+//	go run ./cmd/digest -team Backend -to 2025-11-24 -format markdown
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/statsStorage"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/db"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"go.uber.org/zap"
+)
+
+const digestTimeout = time.Minute
+
+func main() {
+	teamName := flag.String("team", "", "team name; all teams if empty")
+	toRaw := flag.String("to", "", "end of the period (YYYY-MM-DD, exclusive); now if empty")
+	days := flag.Int("days", int(domain.DefaultDigestPeriod/(24*time.Hour)), "length of the period in days")
+	format := flag.String("format", "markdown", "output format: markdown or json")
+	flag.Parse()
+
+	// Логи в stderr, чтобы не смешивались с дайджестом в stdout
+	config := zap.NewProductionConfig()
+	config.OutputPaths = []string{"stderr"}
+	baseLogger, err := config.Build()
+	if err != nil {
+		log.Fatal("error building logger: ", err)
+	}
+	logger.Logger = baseLogger.Sugar()
+
+	if err = godotenv.Load(); err != nil {
+		logger.Logger.Warn("no .env file, using process environment")
+	}
+
+	if *format != "markdown" && *format != "json" {
+		logger.Logger.Fatalf("invalid format %q: expected markdown or json", *format)
+	}
+	if *days <= 0 {
+		logger.Logger.Fatal("days must be positive")
+	}
+
+	to := time.Now()
+	if *toRaw != "" {
+		parsed, err := time.Parse(time.DateOnly, *toRaw)
+		if err != nil {
+			logger.Logger.Fatalf("invalid to %q: expected YYYY-MM-DD", *toRaw)
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -*days)
+
+	ctx, cancel := context.WithTimeout(context.Background(), digestTimeout)
+	defer cancel()
+
+	conn, err := db.Connect(ctx)
+	if err != nil {
+		logger.Logger.Fatal("error connecting to database, exiting...")
+	}
+	defer conn.Close()
+
+	statsRepo := statsStorage.NewStatsStorage(conn)
+
+	teamNames := []string{*teamName}
+	if *teamName == "" {
+		roster, err := teamStorage.NewTeamStorage(conn).GetRoster(ctx)
+		if err != nil {
+			logger.Logger.Fatal("error getting teams: ", err)
+		}
+		teamNames = make([]string, 0, len(roster))
+		for _, team := range roster {
+			teamNames = append(teamNames, team.TeamName)
+		}
+	}
+
+	digests := make([]*domain.TeamDigest, 0, len(teamNames))
+	for _, name := range teamNames {
+		digest, err := statsRepo.GetTeamDigest(ctx, name, from, to)
+		if err != nil {
+			logger.Logger.Fatalf("error building digest for team %s: %v", name, err)
+		}
+		digests = append(digests, digest)
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(digests); err != nil {
+			logger.Logger.Fatal("error writing digest: ", err)
+		}
+		return
+	}
+
+	rendered := make([]string, 0, len(digests))
+	for _, digest := range digests {
+		rendered = append(rendered, utils.RenderTeamDigestMarkdown(digest))
+	}
+	fmt.Print(strings.Join(rendered, "\n---\n\n"))
+}
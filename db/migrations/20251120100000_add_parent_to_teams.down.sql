@@ -0,0 +1,2 @@
+drop index if exists idx_teams_parent;
+alter table teams drop column if exists parent_id;
\ No newline at end of file
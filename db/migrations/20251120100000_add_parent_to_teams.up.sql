@@ -0,0 +1,4 @@
+alter table teams add column if not exists parent_id uuid references teams(id) on delete set null;
+
+-- индекс для обхода дерева вниз (поиск дочерних команд)
+create index idx_teams_parent on teams(parent_id);
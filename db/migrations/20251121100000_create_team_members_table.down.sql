@@ -0,0 +1 @@
+drop table if exists team_members;
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- членство пользователей в командах (многие ко многим);
+-- users.team_id остаётся основной (primary) командой пользователя и может быть пустым
+create table if not exists team_members (
+    team_id uuid not null references teams(id) on delete cascade,
+    user_id varchar(255) not null references users(id) on delete cascade,
+    created_at timestamp default now(),
+    primary key (team_id, user_id)
+);
+
+-- с team_id справится праймари кей, для поиска команд пользователя нужен отдельный индекс
+create index idx_team_members_user on team_members(user_id);
+
+-- переносим текущее членство из users.team_id
+insert into team_members (team_id, user_id)
+select team_id, id from users where team_id is not null
+on conflict do nothing;
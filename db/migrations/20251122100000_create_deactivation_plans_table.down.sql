@@ -0,0 +1 @@
+drop table if exists deactivation_plans;
\ No newline at end of file
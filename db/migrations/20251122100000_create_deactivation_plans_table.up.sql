@@ -0,0 +1,12 @@
+-- сохранённые превью деактивации: план можно применить по id ровно в том виде, в котором его показали
+create table if not exists deactivation_plans (
+    id uuid primary key default gen_random_uuid(),
+    team_name varchar(100) not null,
+    user_ids text[] not null,
+    reassignments jsonb not null,
+    -- ревьюверы затронутых PR на момент превью, по ним при применении проверяется, что план не устарел
+    reviewers_snapshot jsonb not null,
+    created_at timestamp not null default now(),
+    expires_at timestamp not null,
+    applied_at timestamp
+);
@@ -0,0 +1 @@
+alter table pr_reviewers drop column if exists assignment_meta;
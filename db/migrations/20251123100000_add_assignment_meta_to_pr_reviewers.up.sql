@@ -0,0 +1,2 @@
+-- объяснение назначения: стратегия, число кандидатов, исключённые с причиной, seed случайного выбора
+alter table pr_reviewers add column if not exists assignment_meta jsonb;
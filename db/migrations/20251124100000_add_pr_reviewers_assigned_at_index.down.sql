@@ -0,0 +1 @@
+drop index if exists idx_pr_reviewers_assigned_at;
@@ -0,0 +1,2 @@
+-- статистика ревью фильтрует назначения по времени
+create index if not exists idx_pr_reviewers_assigned_at on pr_reviewers(assigned_at);
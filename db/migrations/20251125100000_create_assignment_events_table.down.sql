@@ -0,0 +1 @@
+drop table if exists assignment_events;
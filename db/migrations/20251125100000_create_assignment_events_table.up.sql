@@ -0,0 +1,14 @@
+-- история замен ревьюверов и неудачных попыток замены (NO_CANDIDATE);
+-- строки только добавляются, поэтому счётчики по ней переживают рестарт сервиса
+create table if not exists assignment_events (
+    id bigserial primary key,
+    pull_request_id varchar(255) not null references pull_requests(id) on delete cascade,
+    event_type varchar(32) not null,
+    strategy varchar(32),
+    old_reviewer_id varchar(255),
+    new_reviewer_id varchar(255),
+    created_at timestamp not null default now()
+);
+
+create index idx_assignment_events_pr on assignment_events(pull_request_id);
+create index idx_assignment_events_created_at on assignment_events(created_at);
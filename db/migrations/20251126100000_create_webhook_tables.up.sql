@@ -0,0 +1,20 @@
+-- соответствие логинов в VCS (GitHub, GitLab) пользователям сервиса
+create table if not exists vcs_user_mappings (
+    provider varchar(32) not null,
+    login varchar(255) not null,
+    user_id varchar(255) not null references users(id) on delete cascade,
+    created_at timestamp not null default now(),
+    primary key (provider, login)
+);
+
+-- обработанные доставки вебхуков: повторная доставка с тем же id не обрабатывается второй раз
+create table if not exists webhook_deliveries (
+    provider varchar(32) not null,
+    delivery_id varchar(255) not null,
+    event varchar(64) not null,
+    action varchar(64) not null,
+    result varchar(32) not null,
+    pull_request_id varchar(255),
+    created_at timestamp not null default now(),
+    primary key (provider, delivery_id)
+);
@@ -0,0 +1,11 @@
+-- Значение из enum удалить нельзя, поэтому тип пересоздаётся; закрытые PR снова считаются открытыми
+update pull_requests set status = 'OPEN' where status = 'CLOSED';
+
+alter type pr_status rename to pr_status_old;
+create type pr_status as enum ('OPEN', 'MERGED');
+
+alter table pull_requests alter column status drop default;
+alter table pull_requests alter column status type pr_status using status::text::pr_status;
+alter table pull_requests alter column status set default 'OPEN';
+
+drop type pr_status_old;
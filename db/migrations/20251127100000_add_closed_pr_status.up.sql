@@ -0,0 +1 @@
+alter type pr_status add value if not exists 'CLOSED';
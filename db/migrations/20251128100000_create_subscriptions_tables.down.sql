@@ -0,0 +1,2 @@
+drop table if exists subscription_deliveries;
+drop table if exists subscriptions;
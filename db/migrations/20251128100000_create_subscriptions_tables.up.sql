@@ -0,0 +1,29 @@
+-- подписки внешних систем на доменные события
+create table if not exists subscriptions (
+    id uuid primary key,
+    url text not null,
+    secret text not null,
+    event_types text[] not null,
+    is_active boolean not null default true,
+    created_at timestamp not null default now()
+);
+
+-- очередь и журнал исходящих доставок: строка на пару (подписка, событие)
+create table if not exists subscription_deliveries (
+    id bigserial primary key,
+    subscription_id uuid not null references subscriptions(id) on delete cascade,
+    event_id varchar(64) not null,
+    event_type varchar(64) not null,
+    payload jsonb not null,
+    status varchar(16) not null default 'PENDING',
+    attempts int not null default 0,
+    next_attempt_at timestamp,
+    last_status_code int,
+    last_error text,
+    created_at timestamp not null default now(),
+    delivered_at timestamp,
+    unique (subscription_id, event_id)
+);
+
+create index idx_subscription_deliveries_due on subscription_deliveries(next_attempt_at) where status = 'PENDING';
+create index idx_subscription_deliveries_log on subscription_deliveries(subscription_id, created_at desc);
@@ -0,0 +1,15 @@
+-- outbox доменных событий: пишется в той же транзакции, что и изменение, публикуется ретранслятором
+create table if not exists outbox_events (
+    id bigserial primary key,
+    event_id varchar(64) not null unique,
+    event_type varchar(64) not null,
+    occurred_at timestamp not null,
+    data jsonb not null,
+    attempts int not null default 0,
+    last_error text,
+    available_at timestamp not null default now(),
+    created_at timestamp not null default now(),
+    published_at timestamp
+);
+
+create index idx_outbox_events_pending on outbox_events(available_at, id) where published_at is null;
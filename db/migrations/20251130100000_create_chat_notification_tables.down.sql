@@ -0,0 +1,3 @@
+drop table if exists chat_notifications;
+drop table if exists user_chat_mentions;
+drop table if exists team_chat_channels;
@@ -0,0 +1,31 @@
+-- incoming-webhook чата (Slack, Mattermost) для уведомлений ревьюверов команды
+create table if not exists team_chat_channels (
+    team_id uuid primary key references teams(id) on delete cascade,
+    url text not null,
+    created_at timestamp not null default now()
+);
+
+-- как упоминать пользователя в чате: <@U024BE7LH> в Slack, @login в Mattermost
+create table if not exists user_chat_mentions (
+    user_id varchar(255) primary key references users(id) on delete cascade,
+    mention varchar(255) not null
+);
+
+-- очередь уведомлений: одно сообщение на (событие, получатель, вид уведомления)
+create table if not exists chat_notifications (
+    id bigserial primary key,
+    event_id varchar(64) not null,
+    kind varchar(32) not null,
+    user_id varchar(255) not null,
+    url text not null,
+    text text not null,
+    status varchar(16) not null default 'PENDING',
+    attempts int not null default 0,
+    next_attempt_at timestamp,
+    last_error text,
+    created_at timestamp not null default now(),
+    sent_at timestamp,
+    unique (event_id, user_id, kind)
+);
+
+create index idx_chat_notifications_due on chat_notifications(next_attempt_at) where status = 'PENDING';
@@ -0,0 +1,2 @@
+drop table if exists email_notifications;
+drop table if exists user_notification_preferences;
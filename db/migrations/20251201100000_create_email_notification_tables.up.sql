@@ -0,0 +1,35 @@
+-- настройки уведомлений пользователя; без записи действуют настройки по умолчанию (только чат, сразу)
+create table if not exists user_notification_preferences (
+    user_id varchar(255) primary key references users(id) on delete cascade,
+    email varchar(255),
+    channels varchar(16)[] not null default '{CHAT}',
+    delivery varchar(16) not null default 'IMMEDIATE',
+    digest_hour smallint not null default 9,
+    quiet_hours_start smallint,
+    quiet_hours_end smallint,
+    timezone varchar(64) not null default 'UTC',
+    updated_at timestamp not null default now()
+);
+
+-- очередь писем: одно письмо на (событие, получатель, вид уведомления); письма дайджеста
+-- отправляются пачкой в одном сообщении
+create table if not exists email_notifications (
+    id bigserial primary key,
+    event_id varchar(64) not null,
+    kind varchar(32) not null,
+    user_id varchar(255) not null,
+    email varchar(255) not null,
+    subject text not null,
+    text_body text not null,
+    html_body text not null,
+    digest boolean not null default false,
+    status varchar(16) not null default 'PENDING',
+    attempts int not null default 0,
+    next_attempt_at timestamp,
+    last_error text,
+    created_at timestamp not null default now(),
+    sent_at timestamp,
+    unique (event_id, user_id, kind)
+);
+
+create index idx_email_notifications_due on email_notifications(next_attempt_at) where status = 'PENDING';
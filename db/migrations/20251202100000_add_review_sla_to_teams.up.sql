@@ -0,0 +1,2 @@
+-- сколько часов PR команды может ждать ревью; null - SLA не задан
+alter table teams add column if not exists review_sla_hours int;
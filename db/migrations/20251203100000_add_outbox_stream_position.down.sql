@@ -0,0 +1,2 @@
+alter table outbox_events drop column if exists stream_position;
+drop sequence if exists outbox_stream_position_seq;
@@ -0,0 +1,16 @@
+-- позиция события в потоке очереди ревью: выдаётся при отметке о публикации под advisory-блокировкой,
+-- поэтому, в отличие от id, позиции коммитятся строго по возрастанию
+create sequence if not exists outbox_stream_position_seq;
+alter table outbox_events add column if not exists stream_position bigint unique;
+
+-- уже опубликованные события получают позиции в порядке записи
+update outbox_events o
+set stream_position = p.position
+from (
+    select id, row_number() over (order by id) as position
+    from outbox_events
+    where published_at is not null
+) p
+where o.id = p.id;
+
+select setval('outbox_stream_position_seq', coalesce(max(stream_position), 0) + 1, false) from outbox_events;
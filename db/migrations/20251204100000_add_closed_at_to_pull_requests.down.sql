@@ -0,0 +1 @@
+alter table pull_requests drop column if exists closed_at;
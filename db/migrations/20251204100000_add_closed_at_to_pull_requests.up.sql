@@ -0,0 +1,2 @@
+-- когда PR закрыт без мерджа; null - не закрыт. У PR, закрытых до появления колонки, время неизвестно
+alter table pull_requests add column if not exists closed_at timestamp;
@@ -0,0 +1,2 @@
+drop index if exists idx_outbox_events_published_at;
+alter table outbox_events alter column stream_position drop not null;
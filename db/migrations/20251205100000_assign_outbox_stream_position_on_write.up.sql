@@ -0,0 +1,18 @@
+-- позиция в потоке очереди ревью теперь выдаётся при записи события, а не при публикации:
+-- ещё не опубликованные события получают позиции в порядке записи
+update outbox_events o
+set stream_position = p.position
+from (
+    select id,
+        (select coalesce(max(stream_position), 0) from outbox_events) + row_number() over (order by id) as position
+    from outbox_events
+    where stream_position is null
+) p
+where o.id = p.id;
+
+select setval('outbox_stream_position_seq', coalesce(max(stream_position), 0) + 1, false) from outbox_events;
+
+alter table outbox_events alter column stream_position set not null;
+
+-- очистка удаляет опубликованные события старше срока хранения
+create index if not exists idx_outbox_events_published_at on outbox_events(published_at) where published_at is not null;
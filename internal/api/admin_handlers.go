@@ -0,0 +1,25 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/middleware"
+	"github.com/nedokyrill/avito-pr-api/internal/services"
+)
+
+type AdminHandler struct {
+	adminService services.AdminService
+}
+
+func NewAdminHandler(adminService services.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+func (h *AdminHandler) InitAdminHandlers(router *gin.RouterGroup) {
+	adminGroup := router.Group("/admin")
+	{
+		adminGroup.POST("/import", middleware.AuthMiddleware(), h.adminService.ImportRoster)
+		adminGroup.GET("/export", middleware.AuthMiddleware(), h.adminService.ExportRoster)
+	}
+}
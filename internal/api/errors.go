@@ -0,0 +1,86 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// statusCodes - коды ошибок API и соответствующие им HTTP-статусы; остальные коды - 400
+var statusCodes = map[domain.ErrorResponseErrorCode]int{
+	domain.NotFound:           http.StatusNotFound,
+	domain.PrExists:           http.StatusConflict,
+	domain.PrMerged:           http.StatusConflict,
+	domain.PrClosed:           http.StatusConflict,
+	domain.NotAssigned:        http.StatusConflict,
+	domain.NoCandidate:        http.StatusConflict,
+	domain.PlanAlreadyApplied: http.StatusConflict,
+	domain.PlanStale:          http.StatusConflict,
+}
+
+// deactivationStatusCodes - статусы деактивации участников, отличные от statusCodes: NO_CANDIDATE
+// здесь значит, что запрос оставил бы PR без ревьюверов, - это ошибка запроса (400)
+var deactivationStatusCodes = map[domain.ErrorResponseErrorCode]int{
+	domain.NoCandidate: http.StatusBadRequest,
+}
+
+// respondError отвечает на ошибку сервисного метода: *domain.Error - с его кодом и статусом
+// из statusCodes, прочие ошибки логируются и отдаются как 500 с internalMsg
+func respondError(c *gin.Context, err error, logMsg, internalMsg string) {
+	respondErrorWithStatuses(c, err, nil, logMsg, internalMsg)
+}
+
+// respondErrorWithStatuses - respondError, в котором статусы из overrides важнее statusCodes
+func respondErrorWithStatuses(
+	c *gin.Context,
+	err error,
+	overrides map[domain.ErrorResponseErrorCode]int,
+	logMsg, internalMsg string,
+) {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		status, ok := overrides[domainErr.Code]
+		if !ok {
+			status, ok = statusCodes[domainErr.Code]
+		}
+		if !ok {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, domainErr.Response())
+		return
+	}
+
+	logger.Logger.Error(logMsg, err)
+	c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+		domain.InternalError,
+		internalMsg,
+	))
+}
+
+// bindJSON разбирает тело запроса в req; при ошибке отвечает 400 и возвращает false
+func bindJSON(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid request body",
+		))
+		return false
+	}
+	return true
+}
+
+// requireQuery возвращает обязательный query-параметр; если его нет, отвечает 400 и возвращает false
+func requireQuery(c *gin.Context, name string) (string, bool) {
+	value := c.Query(name)
+	if value == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			name+" query parameter is required",
+		))
+		return "", false
+	}
+	return value, true
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	logger.Logger = zap.NewNop().Sugar()
+}
+
+// newTestContext - gin-контекст с запросом method path и JSON-телом body
+func newTestContext(method, path, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, path, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c, w
+}
+
+// requireErrorResponse проверяет статус ответа и код ошибки в теле
+func requireErrorResponse(t *testing.T, w *httptest.ResponseRecorder, status int, code domain.ErrorResponseErrorCode) {
+	t.Helper()
+	require.Equal(t, status, w.Code)
+	var response domain.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, code, response.Error.Code)
+}
+
+func TestRespondError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   domain.ErrorResponseErrorCode
+	}{
+		{"not found", domain.NewError(domain.NotFound, "team not found"), http.StatusNotFound, domain.NotFound},
+		{"invalid request", domain.NewError(domain.InvalidRequest, "bad"), http.StatusBadRequest, domain.InvalidRequest},
+		{"team exists", domain.NewError(domain.TeamExists, "exists"), http.StatusBadRequest, domain.TeamExists},
+		{"PR exists", domain.NewError(domain.PrExists, "exists"), http.StatusConflict, domain.PrExists},
+		{"PR merged", domain.NewError(domain.PrMerged, "merged"), http.StatusConflict, domain.PrMerged},
+		{"no candidate", domain.NewError(domain.NoCandidate, "none"), http.StatusConflict, domain.NoCandidate},
+		{"stale plan", domain.NewError(domain.PlanStale, "stale"), http.StatusConflict, domain.PlanStale},
+		{"wrapped domain error", fmt.Errorf("wrap: %w", domain.NewError(domain.NotAssigned, "no")), http.StatusConflict, domain.NotAssigned},
+		{"storage error", errors.New("connection refused"), http.StatusInternalServerError, domain.InternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newTestContext(http.MethodGet, "/", "")
+
+			respondError(c, tt.err, "error merging PR: ", domain.ErrMergePRMsg)
+
+			requireErrorResponse(t, w, tt.status, tt.code)
+		})
+	}
+
+	t.Run("storage error is hidden", func(t *testing.T) {
+		c, w := newTestContext(http.MethodGet, "/", "")
+
+		respondError(c, errors.New("connection refused"), "error merging PR: ", domain.ErrMergePRMsg)
+
+		assert.Contains(t, w.Body.String(), domain.ErrMergePRMsg)
+		assert.NotContains(t, w.Body.String(), "connection refused")
+	})
+}
+
+func TestBindJSON(t *testing.T) {
+	t.Run("invalid body", func(t *testing.T) {
+		c, w := newTestContext(http.MethodPost, "/", "invalid json")
+
+		var req domain.TeamMembershipRequest
+		assert.False(t, bindJSON(c, &req))
+		requireErrorResponse(t, w, http.StatusBadRequest, domain.InvalidRequest)
+	})
+
+	t.Run("missing required field", func(t *testing.T) {
+		c, w := newTestContext(http.MethodPost, "/", `{"team_name": "Backend"}`)
+
+		var req domain.TeamMembershipRequest
+		assert.False(t, bindJSON(c, &req))
+		requireErrorResponse(t, w, http.StatusBadRequest, domain.InvalidRequest)
+	})
+
+	t.Run("valid body", func(t *testing.T) {
+		c, _ := newTestContext(http.MethodPost, "/", `{"team_name": "Backend", "user_id": "u1"}`)
+
+		var req domain.TeamMembershipRequest
+		require.True(t, bindJSON(c, &req))
+		assert.Equal(t, domain.TeamMembershipRequest{TeamName: "Backend", UserID: "u1"}, req)
+	})
+}
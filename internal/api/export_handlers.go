@@ -0,0 +1,25 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/middleware"
+	"github.com/nedokyrill/avito-pr-api/internal/services"
+)
+
+type ExportHandler struct {
+	exportService services.ExportService
+}
+
+func NewExportHandler(exportService services.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+func (h *ExportHandler) InitExportHandlers(router *gin.RouterGroup) {
+	exportGroup := router.Group("/export")
+	{
+		exportGroup.GET("/pullRequests", middleware.AuthMiddleware(), h.exportService.ExportPullRequests)
+		exportGroup.GET("/reviews", middleware.AuthMiddleware(), h.exportService.ExportReviews)
+	}
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/middleware"
+	"github.com/nedokyrill/avito-pr-api/internal/services"
+)
+
+type NotificationHandler struct {
+	notificationService services.NotificationService
+}
+
+func NewNotificationHandler(notificationService services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+func (h *NotificationHandler) InitNotificationHandlers(router *gin.RouterGroup) {
+	notificationGroup := router.Group("/notifications")
+	{
+		notificationGroup.POST("/teamChannels", middleware.AuthMiddleware(), h.notificationService.SetTeamChannel)
+		notificationGroup.GET("/teamChannels", middleware.AuthMiddleware(), h.notificationService.ListTeamChannels)
+		notificationGroup.POST("/teamChannels/delete", middleware.AuthMiddleware(), h.notificationService.DeleteTeamChannel)
+		notificationGroup.POST("/userMentions", middleware.AuthMiddleware(), h.notificationService.SetUserMention)
+		notificationGroup.GET("/userMentions", middleware.AuthMiddleware(), h.notificationService.ListUserMentions)
+		notificationGroup.GET("/preferences", middleware.AuthMiddleware(), h.notificationService.GetPreferences)
+		notificationGroup.POST("/preferences", middleware.AuthMiddleware(), h.notificationService.SetPreferences)
+	}
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
 	"github.com/nedokyrill/avito-pr-api/internal/middleware"
 	"github.com/nedokyrill/avito-pr-api/internal/services"
 )
@@ -19,8 +22,69 @@ func NewPullRequestHandler(prService services.PullRequestService) *PullRequestHa
 func (h *PullRequestHandler) InitPullRequestHandlers(router *gin.RouterGroup) {
 	prGroup := router.Group("/pullRequest")
 	{
-		prGroup.POST("/create", middleware.AuthMiddleware(), h.prService.CreatePullRequest)
-		prGroup.POST("/merge", middleware.AuthMiddleware(), h.prService.MergePullRequest)
-		prGroup.POST("/reassign", middleware.AuthMiddleware(), h.prService.ReassignReviewer)
+		prGroup.POST("/create", middleware.AuthMiddleware(), h.CreatePullRequest)
+		prGroup.POST("/merge", middleware.AuthMiddleware(), h.MergePullRequest)
+		prGroup.POST("/reassign", middleware.AuthMiddleware(), h.ReassignReviewer)
+		prGroup.POST("/previewAssignment", middleware.AuthMiddleware(), h.PreviewAssignment)
+	}
+}
+
+func (h *PullRequestHandler) CreatePullRequest(c *gin.Context) {
+	var req domain.CreatePullRequestRequest
+	if !bindJSON(c, &req) {
+		return
 	}
+
+	response, err := h.prService.CreatePullRequest(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "error creating PR: ", domain.ErrCreatePRMsg)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *PullRequestHandler) MergePullRequest(c *gin.Context) {
+	var req domain.MergePullRequestRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	response, err := h.prService.MergePullRequest(c.Request.Context(), req.PullRequestID)
+	if err != nil {
+		respondError(c, err, "error merging PR: ", domain.ErrMergePRMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *PullRequestHandler) ReassignReviewer(c *gin.Context) {
+	var req domain.ReassignReviewerRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	response, err := h.prService.ReassignReviewer(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "error reassigning reviewer: ", domain.ErrReassignReviewerMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *PullRequestHandler) PreviewAssignment(c *gin.Context) {
+	var req domain.PreviewAssignmentRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	preview, err := h.prService.PreviewAssignment(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "error previewing assignment: ", domain.ErrPreviewAssignMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
 }
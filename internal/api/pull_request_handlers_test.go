@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePullRequestService - PullRequestService для тестов обработчиков; невызываемые методы не реализованы
+type fakePullRequestService struct {
+	services.PullRequestService
+	createPullRequest func(ctx context.Context, req domain.CreatePullRequestRequest) (*domain.PullRequestResponse, error)
+	mergePullRequest  func(ctx context.Context, prID string) (*domain.PullRequestResponse, error)
+	reassignReviewer  func(ctx context.Context, req domain.ReassignReviewerRequest) (*domain.ReassignReviewerResponse, error)
+}
+
+func (f *fakePullRequestService) CreatePullRequest(
+	ctx context.Context,
+	req domain.CreatePullRequestRequest,
+) (*domain.PullRequestResponse, error) {
+	return f.createPullRequest(ctx, req)
+}
+
+func (f *fakePullRequestService) MergePullRequest(ctx context.Context, prID string) (*domain.PullRequestResponse, error) {
+	return f.mergePullRequest(ctx, prID)
+}
+
+func (f *fakePullRequestService) ReassignReviewer(
+	ctx context.Context,
+	req domain.ReassignReviewerRequest,
+) (*domain.ReassignReviewerResponse, error) {
+	return f.reassignReviewer(ctx, req)
+}
+
+func TestPullRequestHandler_CreatePullRequest(t *testing.T) {
+	t.Run("created PR", func(t *testing.T) {
+		handler := NewPullRequestHandler(&fakePullRequestService{
+			createPullRequest: func(_ context.Context, req domain.CreatePullRequestRequest) (*domain.PullRequestResponse, error) {
+				return &domain.PullRequestResponse{PR: &domain.PullRequest{
+					PullRequestId:   req.PullRequestID,
+					PullRequestName: req.PullRequestName,
+					AuthorId:        req.AuthorID,
+					Status:          domain.PullRequestStatusOPEN,
+				}}, nil
+			},
+		})
+		c, w := newTestContext(http.MethodPost, "/pullRequest/create",
+			`{"pull_request_id": "pr-1", "pull_request_name": "Add feature", "author_id": "u1"}`)
+
+		handler.CreatePullRequest(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"pull_request_id":"pr-1"`)
+	})
+
+	t.Run("missing author_id", func(t *testing.T) {
+		handler := NewPullRequestHandler(&fakePullRequestService{})
+		c, w := newTestContext(http.MethodPost, "/pullRequest/create", `{"pull_request_id": "pr-1", "pull_request_name": "Add feature"}`)
+
+		handler.CreatePullRequest(c)
+
+		requireErrorResponse(t, w, http.StatusBadRequest, domain.InvalidRequest)
+	})
+
+	t.Run("PR exists", func(t *testing.T) {
+		handler := NewPullRequestHandler(&fakePullRequestService{
+			createPullRequest: func(context.Context, domain.CreatePullRequestRequest) (*domain.PullRequestResponse, error) {
+				return nil, domain.NewError(domain.PrExists, "PR id already exists")
+			},
+		})
+		c, w := newTestContext(http.MethodPost, "/pullRequest/create",
+			`{"pull_request_id": "pr-1", "pull_request_name": "Add feature", "author_id": "u1"}`)
+
+		handler.CreatePullRequest(c)
+
+		requireErrorResponse(t, w, http.StatusConflict, domain.PrExists)
+	})
+}
+
+func TestPullRequestHandler_MergePullRequest(t *testing.T) {
+	t.Run("closed PR", func(t *testing.T) {
+		handler := NewPullRequestHandler(&fakePullRequestService{
+			mergePullRequest: func(_ context.Context, prID string) (*domain.PullRequestResponse, error) {
+				assert.Equal(t, "pr-1", prID)
+				return nil, domain.NewError(domain.PrClosed, "cannot merge closed PR")
+			},
+		})
+		c, w := newTestContext(http.MethodPost, "/pullRequest/merge", `{"pull_request_id": "pr-1"}`)
+
+		handler.MergePullRequest(c)
+
+		requireErrorResponse(t, w, http.StatusConflict, domain.PrClosed)
+	})
+}
+
+func TestPullRequestHandler_ReassignReviewer(t *testing.T) {
+	t.Run("no candidate", func(t *testing.T) {
+		handler := NewPullRequestHandler(&fakePullRequestService{
+			reassignReviewer: func(context.Context, domain.ReassignReviewerRequest) (*domain.ReassignReviewerResponse, error) {
+				return nil, domain.NewError(domain.NoCandidate, "no active replacement candidate in team")
+			},
+		})
+		c, w := newTestContext(http.MethodPost, "/pullRequest/reassign", `{"pull_request_id": "pr-1", "old_user_id": "u2"}`)
+
+		handler.ReassignReviewer(c)
+
+		requireErrorResponse(t, w, http.StatusConflict, domain.NoCandidate)
+	})
+
+	t.Run("invalid request body", func(t *testing.T) {
+		handler := NewPullRequestHandler(&fakePullRequestService{})
+		c, w := newTestContext(http.MethodPost, "/pullRequest/reassign", "invalid")
+
+		handler.ReassignReviewer(c)
+
+		requireErrorResponse(t, w, http.StatusBadRequest, domain.InvalidRequest)
+	})
+}
@@ -10,14 +10,35 @@ func InitRoutes(
 	teamService services.TeamService,
 	userService services.UserService,
 	prService services.PullRequestService,
+	adminService services.AdminService,
+	scimService services.ScimService,
+	statsService services.StatsService,
+	exportService services.ExportService,
+	webhookService services.WebhookService,
+	subscriptionService services.SubscriptionService,
+	notificationService services.NotificationService,
 ) {
 	teamHandler := NewTeamHandler(teamService)
 	userHandler := NewUserHandler(userService)
 	prHandler := NewPullRequestHandler(prService)
+	adminHandler := NewAdminHandler(adminService)
+	scimHandler := NewScimHandler(scimService)
+	statsHandler := NewStatsHandler(statsService)
+	exportHandler := NewExportHandler(exportService)
+	webhookHandler := NewWebhookHandler(webhookService)
+	subscriptionHandler := NewSubscriptionHandler(subscriptionService)
+	notificationHandler := NewNotificationHandler(notificationService)
 
 	api := router.Group("/")
 
 	teamHandler.InitTeamHandlers(api)
 	userHandler.InitUserHandlers(api)
 	prHandler.InitPullRequestHandlers(api)
+	adminHandler.InitAdminHandlers(api)
+	scimHandler.InitScimHandlers(api)
+	statsHandler.InitStatsHandlers(api)
+	exportHandler.InitExportHandlers(api)
+	webhookHandler.InitWebhookHandlers(api)
+	subscriptionHandler.InitSubscriptionHandlers(api)
+	notificationHandler.InitNotificationHandlers(api)
 }
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/middleware"
+	"github.com/nedokyrill/avito-pr-api/internal/services"
+)
+
+type ScimHandler struct {
+	scimService services.ScimService
+}
+
+func NewScimHandler(scimService services.ScimService) *ScimHandler {
+	return &ScimHandler{
+		scimService: scimService,
+	}
+}
+
+func (h *ScimHandler) InitScimHandlers(router *gin.RouterGroup) {
+	scimGroup := router.Group("/scim/v2", middleware.AuthMiddleware())
+	{
+		scimGroup.GET("/ServiceProviderConfig", h.scimService.GetServiceProviderConfig)
+
+		scimGroup.GET("/Users", h.scimService.ListUsers)
+		scimGroup.POST("/Users", h.scimService.CreateUser)
+		scimGroup.GET("/Users/:id", h.scimService.GetUser)
+		scimGroup.PUT("/Users/:id", h.scimService.ReplaceUser)
+		scimGroup.PATCH("/Users/:id", h.scimService.PatchUser)
+		scimGroup.DELETE("/Users/:id", h.scimService.DeleteUser)
+
+		scimGroup.GET("/Groups", h.scimService.ListGroups)
+		scimGroup.POST("/Groups", h.scimService.CreateGroup)
+		scimGroup.GET("/Groups/:id", h.scimService.GetGroup)
+		scimGroup.PUT("/Groups/:id", h.scimService.ReplaceGroup)
+		scimGroup.PATCH("/Groups/:id", h.scimService.PatchGroup)
+		scimGroup.DELETE("/Groups/:id", h.scimService.DeleteGroup)
+	}
+}
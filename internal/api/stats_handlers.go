@@ -0,0 +1,26 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/middleware"
+	"github.com/nedokyrill/avito-pr-api/internal/services"
+)
+
+type StatsHandler struct {
+	statsService services.StatsService
+}
+
+func NewStatsHandler(statsService services.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+func (h *StatsHandler) InitStatsHandlers(router *gin.RouterGroup) {
+	statsGroup := router.Group("/stats")
+	{
+		statsGroup.GET("/users", middleware.AuthMiddleware(), h.statsService.GetUserStats)
+		statsGroup.GET("/teams", middleware.AuthMiddleware(), h.statsService.GetTeamStats)
+		statsGroup.GET("/digest", middleware.AuthMiddleware(), h.statsService.GetTeamDigest)
+	}
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/middleware"
+	"github.com/nedokyrill/avito-pr-api/internal/services"
+)
+
+type SubscriptionHandler struct {
+	subscriptionService services.SubscriptionService
+}
+
+func NewSubscriptionHandler(subscriptionService services.SubscriptionService) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		subscriptionService: subscriptionService,
+	}
+}
+
+func (h *SubscriptionHandler) InitSubscriptionHandlers(router *gin.RouterGroup) {
+	subscriptionGroup := router.Group("/subscriptions")
+	{
+		subscriptionGroup.POST("/create", middleware.AuthMiddleware(), h.subscriptionService.CreateSubscription)
+		subscriptionGroup.GET("/list", middleware.AuthMiddleware(), h.subscriptionService.ListSubscriptions)
+		subscriptionGroup.POST("/delete", middleware.AuthMiddleware(), h.subscriptionService.DeleteSubscription)
+		subscriptionGroup.GET("/deliveries", middleware.AuthMiddleware(), h.subscriptionService.ListDeliveries)
+		subscriptionGroup.POST("/deliveries/retry", middleware.AuthMiddleware(), h.subscriptionService.RetryDelivery)
+	}
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
 	"github.com/nedokyrill/avito-pr-api/internal/middleware"
 	"github.com/nedokyrill/avito-pr-api/internal/services"
 )
@@ -19,7 +22,121 @@ func NewTeamHandler(teamService services.TeamService) *TeamHandler {
 func (h *TeamHandler) InitTeamHandlers(router *gin.RouterGroup) {
 	teamGroup := router.Group("/team")
 	{
-		teamGroup.POST("/add", h.teamService.CreateTeam)
-		teamGroup.GET("/get", middleware.AuthMiddleware(), h.teamService.GetTeam)
+		teamGroup.POST("/add", h.CreateTeam)
+		teamGroup.GET("/get", middleware.AuthMiddleware(), h.GetTeam)
+		teamGroup.GET("/tree", middleware.AuthMiddleware(), h.GetTeamTree)
+		teamGroup.POST("/setParent", middleware.AuthMiddleware(), h.SetTeamParent)
+		teamGroup.POST("/setReviewSla", middleware.AuthMiddleware(), h.SetReviewSLA)
+		teamGroup.POST("/addMember", middleware.AuthMiddleware(), h.AddTeamMember)
+		teamGroup.POST("/removeMember", middleware.AuthMiddleware(), h.RemoveTeamMember)
+	}
+}
+
+func (h *TeamHandler) CreateTeam(c *gin.Context) {
+	var team domain.Team
+	if !bindJSON(c, &team) {
+		return
+	}
+
+	created, err := h.teamService.CreateTeam(c.Request.Context(), team)
+	if err != nil {
+		respondError(c, err, "error creating team with members: ", domain.ErrCreateTeamMsg)
+		return
 	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"team": created,
+	})
+}
+
+func (h *TeamHandler) GetTeam(c *gin.Context) {
+	teamName, ok := requireQuery(c, "team_name")
+	if !ok {
+		return
+	}
+
+	team, err := h.teamService.GetTeam(c.Request.Context(), teamName)
+	if err != nil {
+		respondError(c, err, "error getting team: ", domain.ErrGetTeamMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+func (h *TeamHandler) GetTeamTree(c *gin.Context) {
+	roots, err := h.teamService.GetTeamTree(c.Request.Context(), c.Query("team_name"))
+	if err != nil {
+		respondError(c, err, "error getting teams hierarchy: ", domain.ErrGetTeamTreeMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"teams": roots,
+	})
+}
+
+func (h *TeamHandler) SetTeamParent(c *gin.Context) {
+	var req domain.SetTeamParentRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	team, err := h.teamService.SetTeamParent(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "error setting team parent: ", domain.ErrSetTeamParentMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team": team,
+	})
+}
+
+func (h *TeamHandler) SetReviewSLA(c *gin.Context) {
+	var req domain.SetReviewSLARequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.teamService.SetReviewSLA(c.Request.Context(), req); err != nil {
+		respondError(c, err, "error setting team review SLA: ", domain.ErrSetReviewSLAMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, req)
+}
+
+func (h *TeamHandler) AddTeamMember(c *gin.Context) {
+	var req domain.TeamMembershipRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	team, err := h.teamService.AddTeamMember(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "error adding team member: ", domain.ErrTeamMembershipMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team": team,
+	})
+}
+
+func (h *TeamHandler) RemoveTeamMember(c *gin.Context) {
+	var req domain.TeamMembershipRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	team, err := h.teamService.RemoveTeamMember(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "error removing team member: ", domain.ErrTeamMembershipMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team": team,
+	})
 }
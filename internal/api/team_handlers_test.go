@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTeamService - TeamService для тестов обработчиков; невызываемые методы не реализованы
+type fakeTeamService struct {
+	services.TeamService
+	createTeam   func(ctx context.Context, team domain.Team) (*domain.Team, error)
+	getTeam      func(ctx context.Context, teamName string) (*domain.Team, error)
+	setReviewSLA func(ctx context.Context, req domain.SetReviewSLARequest) error
+}
+
+func (f *fakeTeamService) CreateTeam(ctx context.Context, team domain.Team) (*domain.Team, error) {
+	return f.createTeam(ctx, team)
+}
+
+func (f *fakeTeamService) GetTeam(ctx context.Context, teamName string) (*domain.Team, error) {
+	return f.getTeam(ctx, teamName)
+}
+
+func (f *fakeTeamService) SetReviewSLA(ctx context.Context, req domain.SetReviewSLARequest) error {
+	return f.setReviewSLA(ctx, req)
+}
+
+func TestTeamHandler_CreateTeam(t *testing.T) {
+	t.Run("created team is wrapped", func(t *testing.T) {
+		handler := NewTeamHandler(&fakeTeamService{
+			createTeam: func(_ context.Context, team domain.Team) (*domain.Team, error) {
+				return &team, nil
+			},
+		})
+		c, w := newTestContext(http.MethodPost, "/team/add", `{"team_name": "Backend", "members": []}`)
+
+		handler.CreateTeam(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"team":{"team_name":"Backend","members":[]}}`, w.Body.String())
+	})
+
+	t.Run("invalid request body", func(t *testing.T) {
+		handler := NewTeamHandler(&fakeTeamService{})
+		c, w := newTestContext(http.MethodPost, "/team/add", "invalid json")
+
+		handler.CreateTeam(c)
+
+		requireErrorResponse(t, w, http.StatusBadRequest, domain.InvalidRequest)
+	})
+
+	t.Run("team exists", func(t *testing.T) {
+		handler := NewTeamHandler(&fakeTeamService{
+			createTeam: func(context.Context, domain.Team) (*domain.Team, error) {
+				return nil, domain.NewError(domain.TeamExists, "team_name already exists")
+			},
+		})
+		c, w := newTestContext(http.MethodPost, "/team/add", `{"team_name": "Backend", "members": []}`)
+
+		handler.CreateTeam(c)
+
+		requireErrorResponse(t, w, http.StatusBadRequest, domain.TeamExists)
+	})
+}
+
+func TestTeamHandler_GetTeam(t *testing.T) {
+	t.Run("missing team_name", func(t *testing.T) {
+		handler := NewTeamHandler(&fakeTeamService{})
+		c, w := newTestContext(http.MethodGet, "/team/get", "")
+
+		handler.GetTeam(c)
+
+		requireErrorResponse(t, w, http.StatusBadRequest, domain.InvalidRequest)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		handler := NewTeamHandler(&fakeTeamService{
+			getTeam: func(_ context.Context, teamName string) (*domain.Team, error) {
+				assert.Equal(t, "Backend", teamName)
+				return nil, domain.NewError(domain.NotFound, "team not found")
+			},
+		})
+		c, w := newTestContext(http.MethodGet, "/team/get?team_name=Backend", "")
+
+		handler.GetTeam(c)
+
+		requireErrorResponse(t, w, http.StatusNotFound, domain.NotFound)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		handler := NewTeamHandler(&fakeTeamService{
+			getTeam: func(context.Context, string) (*domain.Team, error) {
+				return nil, errors.New("db error")
+			},
+		})
+		c, w := newTestContext(http.MethodGet, "/team/get?team_name=Backend", "")
+
+		handler.GetTeam(c)
+
+		requireErrorResponse(t, w, http.StatusInternalServerError, domain.InternalError)
+		assert.Contains(t, w.Body.String(), domain.ErrGetTeamMsg)
+	})
+}
+
+func TestTeamHandler_SetReviewSLA(t *testing.T) {
+	t.Run("echoes request", func(t *testing.T) {
+		handler := NewTeamHandler(&fakeTeamService{
+			setReviewSLA: func(_ context.Context, req domain.SetReviewSLARequest) error {
+				assert.Equal(t, "Backend", req.TeamName)
+				return nil
+			},
+		})
+		c, w := newTestContext(http.MethodPost, "/team/setReviewSla", `{"team_name": "Backend", "review_sla_hours": 24}`)
+
+		handler.SetReviewSLA(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"team_name":"Backend","review_sla_hours":24}`, w.Body.String())
+	})
+}
@@ -1,7 +1,13 @@
 package api
 
 import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
 	"github.com/nedokyrill/avito-pr-api/internal/middleware"
 	"github.com/nedokyrill/avito-pr-api/internal/services"
 )
@@ -19,8 +25,192 @@ func NewUserHandler(userService services.UserService) *UserHandler {
 func (h *UserHandler) InitUserHandlers(router *gin.RouterGroup) {
 	usersGroup := router.Group("/users")
 	{
-		usersGroup.POST("/setIsActive", middleware.AuthMiddleware(), h.userService.SetIsActive)
-		usersGroup.GET("/getReview", middleware.AuthMiddleware(), h.userService.GetUserReviews)
-		usersGroup.POST("/deactivateTeamMembers", middleware.AuthMiddleware(), h.userService.DeactivateTeamMembers)
+		usersGroup.POST("/setIsActive", middleware.AuthMiddleware(), h.SetIsActive)
+		usersGroup.POST("/setPrimaryTeam", middleware.AuthMiddleware(), h.SetPrimaryTeam)
+		usersGroup.GET("/getReview", middleware.AuthMiddleware(), h.GetUserReviews)
+		usersGroup.GET("/reviewStream", middleware.AuthMiddleware(), h.GetReviewStream)
+		usersGroup.POST("/deactivateTeamMembers", middleware.AuthMiddleware(), h.DeactivateTeamMembers)
+		usersGroup.POST("/deactivateTeamMembers/preview", middleware.AuthMiddleware(), h.PreviewDeactivateTeamMembers)
+		usersGroup.POST("/deactivateTeamMembers/apply", middleware.AuthMiddleware(), h.ApplyDeactivationPlan)
+		usersGroup.POST("/activateTeamMembers", middleware.AuthMiddleware(), h.ActivateTeamMembers)
+	}
+}
+
+func (h *UserHandler) SetIsActive(c *gin.Context) {
+	var req domain.SetIsActiveRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, err := h.userService.SetIsActive(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "error setting user active status: ", domain.ErrSetActiveMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": user,
+	})
+}
+
+func (h *UserHandler) SetPrimaryTeam(c *gin.Context) {
+	var req domain.SetPrimaryTeamRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, err := h.userService.SetPrimaryTeam(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "error setting primary team: ", domain.ErrSetPrimaryTeamMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": user,
+	})
+}
+
+func (h *UserHandler) GetUserReviews(c *gin.Context) {
+	userID, ok := requireQuery(c, "user_id")
+	if !ok {
+		return
+	}
+
+	prs, err := h.userService.GetUserReviews(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "error getting user reviews: ", domain.ErrGetUserReviewsMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":       userID,
+		"pull_requests": prs,
+	})
+}
+
+// GetReviewStream отдаёт изменения очереди ревью пользователя как Server-Sent Events.
+// Last-Event-ID продолжает поток после указанного события
+func (h *UserHandler) GetReviewStream(c *gin.Context) {
+	userID, ok := requireQuery(c, "user_id")
+	if !ok {
+		return
+	}
+
+	var lastEventID *int64
+	if header := c.GetHeader("Last-Event-ID"); header != "" {
+		cursor, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || cursor < 0 {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+				domain.InvalidRequest,
+				"invalid Last-Event-ID",
+			))
+			return
+		}
+		lastEventID = &cursor
+	}
+
+	err := h.userService.StreamReviews(c.Request.Context(), userID, lastEventID, &sseSink{c: c})
+	if err != nil {
+		respondError(c, err, "error opening review stream: ", domain.ErrReviewStreamMsg)
+	}
+}
+
+func (h *UserHandler) DeactivateTeamMembers(c *gin.Context) {
+	var req domain.DeactivateTeamMembersRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	response, err := h.userService.DeactivateTeamMembers(c.Request.Context(), req)
+	if err != nil {
+		respondErrorWithStatuses(c, err, deactivationStatusCodes,
+			"error deactivating team members: ", domain.ErrDeactivatingUsersMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) PreviewDeactivateTeamMembers(c *gin.Context) {
+	var req domain.DeactivateTeamMembersRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	plan, err := h.userService.PreviewDeactivateTeamMembers(c.Request.Context(), req)
+	if err != nil {
+		respondErrorWithStatuses(c, err, deactivationStatusCodes,
+			"error building deactivation plan: ", domain.ErrDeactivationPlanMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+func (h *UserHandler) ApplyDeactivationPlan(c *gin.Context) {
+	var req domain.ApplyDeactivationPlanRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	response, err := h.userService.ApplyDeactivationPlan(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "error applying deactivation plan: ", domain.ErrDeactivationPlanMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) ActivateTeamMembers(c *gin.Context) {
+	var req domain.ActivateTeamMembersRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	response, err := h.userService.ActivateTeamMembers(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "error activating team members: ", domain.ErrActivatingUsersMsg)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// sseSink пишет поток очереди ревью в ответ как Server-Sent Events
+type sseSink struct {
+	c *gin.Context
+}
+
+func (s *sseSink) Open() {
+	// Поток живёт дольше WriteTimeout сервера; у тестового ResponseRecorder дедлайна нет
+	_ = http.NewResponseController(s.c.Writer).SetWriteDeadline(time.Time{})
+
+	s.c.Header("Content-Type", "text/event-stream")
+	s.c.Header("Cache-Control", "no-cache")
+	s.c.Header("Connection", "keep-alive")
+	s.c.Header("X-Accel-Buffering", "no")
+	s.c.Status(http.StatusOK)
+	s.c.Writer.Flush()
+}
+
+func (s *sseSink) Send(event domain.ReviewStreamEvent) error {
+	err := sse.Encode(s.c.Writer, sse.Event{
+		Id:    strconv.FormatInt(event.ID, 10),
+		Event: string(event.Type),
+		Data:  event,
+	})
+	if err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+func (s *sseSink) Ping() error {
+	if _, err := s.c.Writer.WriteString(": ping\n\n"); err != nil {
+		return err
 	}
+	s.c.Writer.Flush()
+	return nil
 }
@@ -0,0 +1,255 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUserService - UserService для тестов обработчиков; невызываемые методы не реализованы
+type fakeUserService struct {
+	services.UserService
+	getUserReviews        func(ctx context.Context, userID string) ([]domain.PullRequestShort, error)
+	streamReviews         func(ctx context.Context, userID string, lastEventID *int64, sink domain.ReviewStreamSink) error
+	applyDeactivationPlan func(ctx context.Context, req domain.ApplyDeactivationPlanRequest) (*domain.DeactivateTeamMembersResponse, error)
+	deactivateTeamMembers func(ctx context.Context, req domain.DeactivateTeamMembersRequest) (*domain.DeactivateTeamMembersResponse, error)
+	previewDeactivation   func(ctx context.Context, req domain.DeactivateTeamMembersRequest) (*domain.DeactivationPlan, error)
+}
+
+func (f *fakeUserService) DeactivateTeamMembers(
+	ctx context.Context,
+	req domain.DeactivateTeamMembersRequest,
+) (*domain.DeactivateTeamMembersResponse, error) {
+	return f.deactivateTeamMembers(ctx, req)
+}
+
+func (f *fakeUserService) PreviewDeactivateTeamMembers(
+	ctx context.Context,
+	req domain.DeactivateTeamMembersRequest,
+) (*domain.DeactivationPlan, error) {
+	return f.previewDeactivation(ctx, req)
+}
+
+func (f *fakeUserService) GetUserReviews(ctx context.Context, userID string) ([]domain.PullRequestShort, error) {
+	return f.getUserReviews(ctx, userID)
+}
+
+func (f *fakeUserService) StreamReviews(
+	ctx context.Context,
+	userID string,
+	lastEventID *int64,
+	sink domain.ReviewStreamSink,
+) error {
+	return f.streamReviews(ctx, userID, lastEventID, sink)
+}
+
+func (f *fakeUserService) ApplyDeactivationPlan(
+	ctx context.Context,
+	req domain.ApplyDeactivationPlanRequest,
+) (*domain.DeactivateTeamMembersResponse, error) {
+	return f.applyDeactivationPlan(ctx, req)
+}
+
+func TestUserHandler_GetUserReviews(t *testing.T) {
+	t.Run("returns reviews of the user", func(t *testing.T) {
+		handler := NewUserHandler(&fakeUserService{
+			getUserReviews: func(_ context.Context, userID string) ([]domain.PullRequestShort, error) {
+				return []domain.PullRequestShort{{PullRequestId: "pr-1", AuthorId: "u1"}}, nil
+			},
+		})
+		c, w := newTestContext(http.MethodGet, "/users/getReview?user_id=u2", "")
+
+		handler.GetUserReviews(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t,
+			`{"user_id":"u2","pull_requests":[{"pull_request_id":"pr-1","pull_request_name":"","author_id":"u1","status":""}]}`,
+			w.Body.String(),
+		)
+	})
+
+	t.Run("missing user_id", func(t *testing.T) {
+		handler := NewUserHandler(&fakeUserService{})
+		c, w := newTestContext(http.MethodGet, "/users/getReview", "")
+
+		handler.GetUserReviews(c)
+
+		requireErrorResponse(t, w, http.StatusBadRequest, domain.InvalidRequest)
+	})
+}
+
+func TestUserHandler_GetReviewStream(t *testing.T) {
+	occurredAt := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("writes events as server-sent events", func(t *testing.T) {
+		handler := NewUserHandler(&fakeUserService{
+			streamReviews: func(_ context.Context, userID string, lastEventID *int64, sink domain.ReviewStreamSink) error {
+				assert.Equal(t, "u2", userID)
+				require.NotNil(t, lastEventID)
+				assert.Equal(t, int64(10), *lastEventID)
+
+				sink.Open()
+				require.NoError(t, sink.Send(domain.ReviewStreamEvent{
+					ID:            11,
+					Type:          domain.ReviewStreamAssigned,
+					EventID:       "event-1",
+					PullRequestID: "pr-1",
+					OccurredAt:    occurredAt,
+				}))
+				require.NoError(t, sink.Ping())
+				return nil
+			},
+		})
+		c, w := newTestContext(http.MethodGet, "/users/reviewStream?user_id=u2", "")
+		c.Request.Header.Set("Last-Event-ID", "10")
+
+		handler.GetReviewStream(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		assert.Equal(t,
+			"id:11\nevent:assigned\ndata:{\"type\":\"assigned\",\"event_id\":\"event-1\",\"pull_request_id\":\"pr-1\","+
+				"\"occurred_at\":\"2025-12-01T10:00:00Z\"}\n\n: ping\n\n",
+			w.Body.String(),
+		)
+	})
+
+	t.Run("without Last-Event-ID", func(t *testing.T) {
+		handler := NewUserHandler(&fakeUserService{
+			streamReviews: func(_ context.Context, _ string, lastEventID *int64, sink domain.ReviewStreamSink) error {
+				assert.Nil(t, lastEventID)
+				sink.Open()
+				return nil
+			},
+		})
+		c, w := newTestContext(http.MethodGet, "/users/reviewStream?user_id=u2", "")
+
+		handler.GetReviewStream(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("missing user_id", func(t *testing.T) {
+		handler := NewUserHandler(&fakeUserService{})
+		c, w := newTestContext(http.MethodGet, "/users/reviewStream", "")
+
+		handler.GetReviewStream(c)
+
+		requireErrorResponse(t, w, http.StatusBadRequest, domain.InvalidRequest)
+	})
+
+	t.Run("invalid Last-Event-ID", func(t *testing.T) {
+		handler := NewUserHandler(&fakeUserService{})
+		c, w := newTestContext(http.MethodGet, "/users/reviewStream?user_id=u2", "")
+		c.Request.Header.Set("Last-Event-ID", "abc")
+
+		handler.GetReviewStream(c)
+
+		requireErrorResponse(t, w, http.StatusBadRequest, domain.InvalidRequest)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		handler := NewUserHandler(&fakeUserService{
+			streamReviews: func(context.Context, string, *int64, domain.ReviewStreamSink) error {
+				return domain.NewError(domain.NotFound, "user not found")
+			},
+		})
+		c, w := newTestContext(http.MethodGet, "/users/reviewStream?user_id=u2", "")
+
+		handler.GetReviewStream(c)
+
+		requireErrorResponse(t, w, http.StatusNotFound, domain.NotFound)
+	})
+
+	t.Run("storage error before streaming", func(t *testing.T) {
+		handler := NewUserHandler(&fakeUserService{
+			streamReviews: func(context.Context, string, *int64, domain.ReviewStreamSink) error {
+				return errors.New("db error")
+			},
+		})
+		c, w := newTestContext(http.MethodGet, "/users/reviewStream?user_id=u2", "")
+
+		handler.GetReviewStream(c)
+
+		requireErrorResponse(t, w, http.StatusInternalServerError, domain.InternalError)
+	})
+}
+
+func TestUserHandler_ActivateTeamMembers(t *testing.T) {
+	t.Run("empty user_ids", func(t *testing.T) {
+		handler := NewUserHandler(&fakeUserService{})
+		c, w := newTestContext(http.MethodPost, "/users/activateTeamMembers", `{"team_name": "Backend", "user_ids": []}`)
+
+		handler.ActivateTeamMembers(c)
+
+		requireErrorResponse(t, w, http.StatusBadRequest, domain.InvalidRequest)
+	})
+}
+
+func TestUserHandler_DeactivateTeamMembers(t *testing.T) {
+	body := `{"team_name": "Backend", "user_ids": ["u2"]}`
+	noCandidateErr := domain.NewError(domain.NoCandidate, "PR pr-1 would be left without reviewers")
+
+	t.Run("no candidate is a bad request", func(t *testing.T) {
+		handler := NewUserHandler(&fakeUserService{
+			deactivateTeamMembers: func(context.Context, domain.DeactivateTeamMembersRequest) (*domain.DeactivateTeamMembersResponse, error) {
+				return nil, noCandidateErr
+			},
+		})
+		c, w := newTestContext(http.MethodPost, "/users/deactivateTeamMembers", body)
+
+		handler.DeactivateTeamMembers(c)
+
+		requireErrorResponse(t, w, http.StatusBadRequest, domain.NoCandidate)
+	})
+
+	t.Run("no candidate in preview is a bad request", func(t *testing.T) {
+		handler := NewUserHandler(&fakeUserService{
+			previewDeactivation: func(context.Context, domain.DeactivateTeamMembersRequest) (*domain.DeactivationPlan, error) {
+				return nil, noCandidateErr
+			},
+		})
+		c, w := newTestContext(http.MethodPost, "/users/deactivateTeamMembers/preview", body)
+
+		handler.PreviewDeactivateTeamMembers(c)
+
+		requireErrorResponse(t, w, http.StatusBadRequest, domain.NoCandidate)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		handler := NewUserHandler(&fakeUserService{
+			deactivateTeamMembers: func(context.Context, domain.DeactivateTeamMembersRequest) (*domain.DeactivateTeamMembersResponse, error) {
+				return nil, domain.NewError(domain.NotFound, "team not found")
+			},
+		})
+		c, w := newTestContext(http.MethodPost, "/users/deactivateTeamMembers", body)
+
+		handler.DeactivateTeamMembers(c)
+
+		requireErrorResponse(t, w, http.StatusNotFound, domain.NotFound)
+	})
+}
+
+func TestUserHandler_ApplyDeactivationPlan(t *testing.T) {
+	t.Run("stale plan is a conflict", func(t *testing.T) {
+		handler := NewUserHandler(&fakeUserService{
+			applyDeactivationPlan: func(_ context.Context, req domain.ApplyDeactivationPlanRequest) (*domain.DeactivateTeamMembersResponse, error) {
+				assert.Equal(t, "plan-1", req.PlanID)
+				return nil, domain.NewError(domain.PlanStale, "deactivation plan has expired, preview it again")
+			},
+		})
+		c, w := newTestContext(http.MethodPost, "/users/deactivateTeamMembers/apply", `{"plan_id": "plan-1"}`)
+
+		handler.ApplyDeactivationPlan(c)
+
+		requireErrorResponse(t, w, http.StatusConflict, domain.PlanStale)
+	})
+}
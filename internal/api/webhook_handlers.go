@@ -0,0 +1,29 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/middleware"
+	"github.com/nedokyrill/avito-pr-api/internal/services"
+)
+
+type WebhookHandler struct {
+	webhookService services.WebhookService
+}
+
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+func (h *WebhookHandler) InitWebhookHandlers(router *gin.RouterGroup) {
+	webhookGroup := router.Group("/webhooks")
+	{
+		// Приёмники вебхуков аутентифицируются подписью провайдера, а не заголовком Authorization
+		webhookGroup.POST("/github", h.webhookService.HandleGitHubWebhook)
+		webhookGroup.POST("/gitlab", h.webhookService.HandleGitLabWebhook)
+
+		webhookGroup.POST("/userMappings", middleware.AuthMiddleware(), h.webhookService.SetUserMapping)
+		webhookGroup.GET("/userMappings", middleware.AuthMiddleware(), h.webhookService.ListUserMappings)
+	}
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -10,14 +11,30 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/nedokyrill/avito-pr-api/internal/api"
+	"github.com/nedokyrill/avito-pr-api/internal/grpcapi"
 	"github.com/nedokyrill/avito-pr-api/internal/server"
+	"github.com/nedokyrill/avito-pr-api/internal/services/adminService"
+	"github.com/nedokyrill/avito-pr-api/internal/services/exportService"
+	"github.com/nedokyrill/avito-pr-api/internal/services/notificationService"
+	"github.com/nedokyrill/avito-pr-api/internal/services/outboxService"
 	"github.com/nedokyrill/avito-pr-api/internal/services/pullRequestService"
+	"github.com/nedokyrill/avito-pr-api/internal/services/scimService"
+	"github.com/nedokyrill/avito-pr-api/internal/services/statsService"
+	"github.com/nedokyrill/avito-pr-api/internal/services/subscriptionService"
 	"github.com/nedokyrill/avito-pr-api/internal/services/teamService"
 	"github.com/nedokyrill/avito-pr-api/internal/services/userService"
+	"github.com/nedokyrill/avito-pr-api/internal/services/webhookService"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/exportStorage"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/metricsStorage"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/notificationStorage"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/outboxStorage"
 	"github.com/nedokyrill/avito-pr-api/internal/storage/prReviewersStorage"
 	"github.com/nedokyrill/avito-pr-api/internal/storage/pullRequestStorage"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/statsStorage"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/subscriptionStorage"
 	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
 	"github.com/nedokyrill/avito-pr-api/internal/storage/userStorage"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/webhookStorage"
 	"github.com/nedokyrill/avito-pr-api/pkg/consts"
 	"github.com/nedokyrill/avito-pr-api/pkg/metrics"
 	"github.com/nedokyrill/avito-pr-api/pkg/utils/db"
@@ -52,17 +69,52 @@ func Run() {
 	userRepo := userStorage.NewUserStorage(conn)
 	prRepo := pullRequestStorage.NewPullRequestStorage(conn)
 	prReviewersRepo := prReviewersStorage.NewPrReviewersStorage(conn)
+	statsRepo := statsStorage.NewStatsStorage(conn)
+	metricsRepo := metricsStorage.NewMetricsStorage(conn)
+	exportRepo := exportStorage.NewExportStorage(conn)
+	webhookRepo := webhookStorage.NewWebhookStorage(conn)
+	subscriptionRepo := subscriptionStorage.NewSubscriptionStorage(conn)
+	outboxRepo := outboxStorage.NewOutboxStorage(conn)
+	notificationRepo := notificationStorage.NewNotificationStorage(conn)
 
 	// Init SERVICE layer
+	subscriptionSvc := subscriptionService.NewSubscriptionService(subscriptionRepo)
 	teamSvc := teamService.NewTeamService(teamRepo, userRepo)
-	userSvc := userService.NewUserService(userRepo, prReviewersRepo, teamRepo)
+	userSvc := userService.NewUserService(userRepo, prReviewersRepo, teamRepo, outboxRepo)
 	prSvc := pullRequestService.NewPullRequestService(prRepo, prReviewersRepo, userRepo, teamRepo)
+	adminSvc := adminService.NewAdminService(teamRepo, prReviewersRepo)
+	scimSvc := scimService.NewScimService(userRepo, teamRepo, userSvc)
+	statsSvc := statsService.NewStatsService(statsRepo)
+	exportSvc := exportService.NewExportService(exportRepo)
+	webhookSvc := webhookService.NewWebhookService(webhookRepo, prSvc, webhookService.Secrets{
+		GitHub: os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		GitLab: os.Getenv("GITLAB_WEBHOOK_SECRET"),
+	})
+	chatTemplates, err := notificationService.ParseTemplates(notificationService.TemplatesFromEnv(os.Getenv))
+	if err != nil {
+		logger.Logger.Fatalw("error parsing chat notification templates, exiting...",
+			"error", err)
+	}
+	// Письма отправляются, только если задан SMTP_ADDR
+	var emailTemplates *notificationService.EmailTemplates
+	if os.Getenv("SMTP_ADDR") != "" {
+		emailTemplates, err = notificationService.LoadEmailTemplates(os.Getenv("EMAIL_TEMPLATE_DIR"))
+		if err != nil {
+			logger.Logger.Fatalw("error loading email templates, exiting...",
+				"error", err)
+		}
+	}
+	notificationSvc := notificationService.NewNotificationService(notificationRepo, prRepo, prReviewersRepo, chatTemplates, emailTemplates)
 
 	// Init ROUTER
 	router := ginRouter.InitRouter()
 
 	// Register METRICS
 	prometheus.MustRegister(metrics.PRLifecycleDurationHours)
+	prometheus.MustRegister(metrics.HTTPRequestsTotal, metrics.HTTPRequestDurationSeconds)
+	prometheus.MustRegister(metrics.NewPRLifecycleCollector(metricsRepo, metrics.DefaultMaxTeamLabels))
+	prometheus.MustRegister(metrics.NewReviewLoadCollector(metricsRepo, metrics.DefaultMaxTeamLabels, metrics.DefaultMaxUserLabels))
+	prometheus.MustRegister(metrics.NewDBPoolCollector(metrics.PgxPoolStats(conn)), metrics.DBQueryDurationSeconds)
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	// Init API routes
@@ -71,6 +123,13 @@ func Run() {
 		teamSvc,
 		userSvc,
 		prSvc,
+		adminSvc,
+		scimSvc,
+		statsSvc,
+		exportSvc,
+		webhookSvc,
+		subscriptionSvc,
+		notificationSvc,
 	)
 
 	// Init SERVER
@@ -79,16 +138,61 @@ func Run() {
 	// Start SERVER
 	go srv.Start()
 
+	// Init and start gRPC SERVER, если задан GRPC_PORT
+	var grpcSrv *server.GRPCServer
+	if os.Getenv("GRPC_PORT") != "" {
+		grpcSrv = server.NewGRPCServer(grpcapi.NewServer(teamSvc, userSvc, prSvc))
+		go grpcSrv.Start()
+	}
+
+	// Start outbox RELAY and CLEANER, outgoing webhooks DISPATCHER, chat and email NOTIFIERS, review REMINDER
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	publisher := outboxService.NewMultiPublisher(subscriptionSvc, notificationSvc)
+	relay := outboxService.NewRelay(outboxRepo, publisher, outboxService.DefaultRelayConfig())
+	go relay.Run(workersCtx)
+	outboxCleaner := outboxService.NewCleaner(outboxRepo, outboxService.DefaultCleanerConfig())
+	go outboxCleaner.Run(workersCtx)
+	dispatcher := subscriptionService.NewDispatcher(subscriptionRepo, &http.Client{}, subscriptionService.DefaultDispatcherConfig())
+	go dispatcher.Run(workersCtx)
+	notifier := notificationService.NewNotifier(notificationRepo, &http.Client{}, notificationService.DefaultNotifierConfig())
+	go notifier.Run(workersCtx)
+	reminder := notificationService.NewReminder(notificationSvc, notificationService.DefaultReminderConfig())
+	go reminder.Run(workersCtx)
+	if emailTemplates != nil {
+		mailer := notificationService.NewSMTPMailer(notificationService.SMTPConfig{
+			Addr:     os.Getenv("SMTP_ADDR"),
+			From:     os.Getenv("SMTP_FROM"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		})
+		emailNotifier := notificationService.NewEmailNotifier(notificationRepo, mailer, emailTemplates, notificationService.DefaultNotifierConfig())
+		go emailNotifier.Run(workersCtx)
+	}
+
 	// GRACEFUL SHUTDOWN
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	fmt.Printf("\n")
 	logger.Logger.Info("shutting down server...")
+	stopWorkers()
+	userSvc.CloseStreams()
 	ctx, cancel = context.WithTimeout(context.Background(), consts.GsTimeout)
 	defer cancel()
+	// HTTP и gRPC останавливаются одновременно в пределах общего таймаута
+	grpcShutdown := make(chan error, 1)
+	if grpcSrv != nil {
+		go func() { grpcShutdown <- grpcSrv.Shutdown(ctx) }()
+	} else {
+		grpcShutdown <- nil
+	}
 	if err = srv.Shutdown(ctx); err != nil {
 		logger.Logger.Fatalw("Shutdown error",
 			"error", err)
 	}
+	if err = <-grpcShutdown; err != nil {
+		logger.Logger.Fatalw("gRPC shutdown error",
+			"error", err)
+	}
 }
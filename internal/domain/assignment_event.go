@@ -0,0 +1,38 @@
+package domain
+
+type AssignmentEventType string
+
+const (
+	// AssignmentEventReassigned - ревьювер заменён другим
+	AssignmentEventReassigned AssignmentEventType = "REASSIGNED"
+	// AssignmentEventRemoved - ревьювер снят без замены
+	AssignmentEventRemoved AssignmentEventType = "REMOVED"
+	// AssignmentEventNoCandidate - замену найти не удалось
+	AssignmentEventNoCandidate AssignmentEventType = "NO_CANDIDATE"
+)
+
+// AssignmentEvent - запись в assignment_events
+type AssignmentEvent struct {
+	PrID          string
+	Type          AssignmentEventType
+	Strategy      AssignmentStrategy
+	OldReviewerID string
+	NewReviewerID string
+}
+
+// NewReassignmentEvent строит событие по переназначению; стратегия берётся из метаданных назначения
+func NewReassignmentEvent(reassignment ReviewerReassignment) AssignmentEvent {
+	event := AssignmentEvent{
+		PrID:          reassignment.PrID,
+		Type:          AssignmentEventRemoved,
+		OldReviewerID: reassignment.OldReviewerID,
+		NewReviewerID: reassignment.NewReviewerID,
+	}
+	if reassignment.NewReviewerID != "" {
+		event.Type = AssignmentEventReassigned
+	}
+	if reassignment.Meta != nil {
+		event.Strategy = reassignment.Meta.Strategy
+	}
+	return event
+}
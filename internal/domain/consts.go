@@ -1,23 +1,58 @@
 package domain
 
-import "github.com/nedokyrill/avito-pr-api/internal/generated"
+import (
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/generated"
+)
 
 const MaxReviewersCount int = 2
 
+// MaxTeamHierarchyDepth ограничивает подъём по иерархии команд (защита от слишком глубоких деревьев)
+const MaxTeamHierarchyDepth int = 10
+
+// DeactivationPlanTTL - сколько превью деактивации можно применить по plan_id
+const DeactivationPlanTTL = time.Hour
+
 const (
 	PullRequestStatusOPEN   PullRequestStatus = generated.PullRequestStatusOPEN
 	PullRequestStatusMERGED PullRequestStatus = generated.PullRequestStatusMERGED
+	PullRequestStatusCLOSED PullRequestStatus = generated.PullRequestStatusCLOSED
 )
 
 const (
 	ErrCreatePRMsg         string = "error with creating pull request"
 	ErrMergePRMsg          string = "error with merging pull request"
+	ErrClosePRMsg          string = "error with closing pull request"
+	ErrReopenPRMsg         string = "error with reopening pull request"
 	ErrReassignReviewerMsg string = "error with reassigning reviewer"
+	ErrPreviewAssignMsg    string = "error with previewing reviewers assignment"
 
-	ErrCreateTeamMsg string = "error with creating team"
-	ErrGetTeamMsg    string = "error with getting team"
+	ErrCreateTeamMsg     string = "error with creating team"
+	ErrGetTeamMsg        string = "error with getting team"
+	ErrGetTeamTreeMsg    string = "error with getting team tree"
+	ErrSetTeamParentMsg  string = "error with setting team parent"
+	ErrSetReviewSLAMsg   string = "error with setting team review SLA"
+	ErrTeamMembershipMsg string = "error with updating team membership"
 
 	ErrSetActiveMsg           string = "error with setting active state"
+	ErrSetPrimaryTeamMsg      string = "error with setting primary team"
 	ErrGetUserReviewsMsg      string = "error with getting user reviews"
+	ErrReviewStreamMsg        string = "error with opening review stream"
 	ErrDeactivatingUsersMsg   string = "error with deactivating users"
+	ErrActivatingUsersMsg     string = "error with activating users"
+	ErrDeactivationPlanMsg    string = "error with deactivation plan"
+
+	ErrGetStatsMsg   string = "error with getting review statistics"
+	ErrGetDigestMsg  string = "error with building team digest"
+	ErrExportDataMsg string = "error with exporting data"
+
+	ErrProcessWebhookMsg string = "error with processing webhook"
+	ErrUserMappingMsg    string = "error with VCS user mapping"
+	ErrSubscriptionMsg   string = "error with event subscriptions"
+	ErrChatNotifyMsg     string = "error with chat notifications"
+	ErrNotifyPrefsMsg    string = "error with notification preferences"
+
+	ErrImportRosterMsg string = "error with importing roster"
+	ErrExportRosterMsg string = "error with exporting roster"
 )
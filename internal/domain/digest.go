@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// DefaultDigestPeriod - период дайджеста, если from не задан
+const DefaultDigestPeriod = 7 * 24 * time.Hour
+
+// DigestTopReviewersLimit - сколько самых активных ревьюверов попадает в дайджест
+const DigestTopReviewersLimit = 5
+
+// DigestReviewer - ревьювер PR команды и число PR, смердженных за период, где он был ревьювером
+type DigestReviewer struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Reviews  int    `json:"reviews"`
+}
+
+// DigestWaitingPR - открытый PR команды на момент построения дайджеста
+type DigestWaitingPR struct {
+	PullRequestID     string    `json:"pull_request_id"`
+	PullRequestName   string    `json:"pull_request_name"`
+	AuthorID          string    `json:"author_id"`
+	CreatedAt         time.Time `json:"created_at"`
+	ReviewerIDs       []string  `json:"reviewer_ids"`
+	NeedMoreReviewers bool      `json:"need_more_reviewers"`
+}
+
+// DigestReassignment - событие из assignment_events за период
+type DigestReassignment struct {
+	PullRequestID string              `json:"pull_request_id"`
+	Type          AssignmentEventType `json:"type"`
+	Strategy      AssignmentStrategy  `json:"strategy,omitempty"`
+	OldReviewerID *string             `json:"old_reviewer_id,omitempty"`
+	NewReviewerID *string             `json:"new_reviewer_id,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+}
+
+// TeamDigest - сводка по PR команды за полуинтервал [From, To). PR относится к основной команде автора
+type TeamDigest struct {
+	TeamName        string               `json:"team_name"`
+	From            time.Time            `json:"from"`
+	To              time.Time            `json:"to"`
+	Opened          int                  `json:"opened"`
+	Merged          int                  `json:"merged"`
+	Closed          int                  `json:"closed"`             // закрыты без мерджа
+	AvgHoursToMerge *float64             `json:"avg_hours_to_merge"` // от создания до мерджа, nil - мерджей не было
+	TopReviewers    []DigestReviewer     `json:"top_reviewers"`
+	Waiting         []DigestWaitingPR    `json:"waiting"`
+	Reassignments   []DigestReassignment `json:"reassignments"`
+}
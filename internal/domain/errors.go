@@ -24,10 +24,29 @@ const (
 	InternalError  ErrorResponseErrorCode = "INTERNAL_ERROR"
 )
 
+// Кастомный 409 для PR, закрытых без мерджа
+const PrClosed ErrorResponseErrorCode = "PR_CLOSED"
+
+// Кастомные 409 для сохранённых планов
+const (
+	PlanAlreadyApplied ErrorResponseErrorCode = "PLAN_ALREADY_APPLIED"
+	PlanStale          ErrorResponseErrorCode = "PLAN_STALE"
+)
+
 // Ошибки между репо и сервис слоями
 const (
 	TeamNotExistsErr string = "team does not exist"
 	NoUsersInTeamErr string = "no users in team"
+	NotTeamMemberErr string = "user is not a member of team"
+
+	PullRequestNotOpenErr string = "pull request is not open"
+
+	ParentTeamNotExistsErr string = "parent team does not exist"
+	TeamHierarchyCycleErr  string = "team cannot be a parent of itself or of its ancestor"
+
+	DeactivationPlanNotExistsErr      string = "deactivation plan does not exist"
+	DeactivationPlanAlreadyAppliedErr string = "deactivation plan is already applied"
+	PlanStaleErr                      string = "plan is stale"
 )
 
 func NewErrorResponse(code ErrorResponseErrorCode, message string) ErrorResponse {
@@ -41,3 +60,25 @@ func NewErrorResponse(code ErrorResponseErrorCode, message string) ErrorResponse
 		},
 	}
 }
+
+// Error - ошибка бизнес-логики с кодом из API. Её возвращают сервисные методы,
+// которые вызываются не только из своих HTTP-хендлеров (например, из SCIM)
+type Error struct {
+	Code    ErrorResponseErrorCode
+	Message string
+}
+
+func NewError(code ErrorResponseErrorCode, message string) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Response() ErrorResponse {
+	return NewErrorResponse(e.Code, e.Message)
+}
@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType - тип доменного события, на который можно подписаться
+type EventType string
+
+const (
+	EventPRCreated          EventType = "pr.created"
+	EventReviewerAssigned   EventType = "reviewer.assigned"
+	EventReviewerReassigned EventType = "reviewer.reassigned"
+	EventPRMerged           EventType = "pr.merged"
+	EventPRClosed           EventType = "pr.closed"
+	EventPRReopened         EventType = "pr.reopened"
+	EventUserDeactivated    EventType = "user.deactivated"
+)
+
+// EventTypes - все типы событий в порядке, в котором они перечисляются в API
+var EventTypes = []EventType{
+	EventPRCreated,
+	EventReviewerAssigned,
+	EventReviewerReassigned,
+	EventPRMerged,
+	EventPRClosed,
+	EventPRReopened,
+	EventUserDeactivated,
+}
+
+func (t EventType) IsValid() bool {
+	for _, eventType := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event - доменное событие в том виде, в котором оно уходит подписчикам. ID стабилен между повторными
+// доставками, по нему получатель отсеивает дубли
+type Event struct {
+	ID         string          `json:"id"`
+	Type       EventType       `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// OutboxEvent - событие из outbox. StreamPosition - позиция в потоке очереди ревью, выдаётся
+// при записи события в outbox
+type OutboxEvent struct {
+	ID             int64
+	Event          Event
+	Attempts       int
+	StreamPosition int64
+}
+
+// NewEvent создаёт событие с новым ID; data сериализуется в JSON
+func NewEvent(eventType EventType, occurredAt time.Time, data any) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		OccurredAt: occurredAt.UTC(),
+		Data:       raw,
+	}, nil
+}
+
+// PullRequestEventData - данные pr.created, pr.merged, pr.closed и pr.reopened
+type PullRequestEventData struct {
+	PullRequestID     string            `json:"pull_request_id"`
+	PullRequestName   string            `json:"pull_request_name"`
+	AuthorID          string            `json:"author_id"`
+	Status            PullRequestStatus `json:"status"`
+	AssignedReviewers []string          `json:"assigned_reviewers"`
+}
+
+// ReviewerEventData - данные reviewer.assigned и reviewer.reassigned. Для reviewer.reassigned
+// OldReviewerID - снятый ревьювер, ReviewerID - замена (пустой, если ревьювер снят без замены)
+type ReviewerEventData struct {
+	PullRequestID string             `json:"pull_request_id"`
+	ReviewerID    string             `json:"reviewer_id,omitempty"`
+	OldReviewerID string             `json:"old_reviewer_id,omitempty"`
+	Strategy      AssignmentStrategy `json:"strategy,omitempty"`
+}
+
+// UserEventData - данные user.deactivated
+type UserEventData struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name,omitempty"`
+}
+
+// NewPullRequestCreatedEvents - pr.created и по reviewer.assigned на каждого назначенного ревьювера
+func NewPullRequestCreatedEvents(pr *PullRequest, strategy AssignmentStrategy, occurredAt time.Time) ([]Event, error) {
+	created, err := NewEvent(EventPRCreated, occurredAt, newPullRequestEventData(pr))
+	if err != nil {
+		return nil, err
+	}
+
+	events := []Event{created}
+	for _, reviewerID := range pr.AssignedReviewers {
+		assigned, err := NewEvent(EventReviewerAssigned, occurredAt, ReviewerEventData{
+			PullRequestID: pr.PullRequestId,
+			ReviewerID:    reviewerID,
+			Strategy:      strategy,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, assigned)
+	}
+
+	return events, nil
+}
+
+func NewPullRequestMergedEvent(pr *PullRequest, occurredAt time.Time) (Event, error) {
+	return NewEvent(EventPRMerged, occurredAt, newPullRequestEventData(pr))
+}
+
+func NewPullRequestClosedEvent(pr *PullRequest, occurredAt time.Time) (Event, error) {
+	return NewEvent(EventPRClosed, occurredAt, newPullRequestEventData(pr))
+}
+
+func NewPullRequestReopenedEvent(pr *PullRequest, occurredAt time.Time) (Event, error) {
+	return NewEvent(EventPRReopened, occurredAt, newPullRequestEventData(pr))
+}
+
+func NewReviewerReassignedEvent(reassignment ReviewerReassignment, occurredAt time.Time) (Event, error) {
+	data := ReviewerEventData{
+		PullRequestID: reassignment.PrID,
+		ReviewerID:    reassignment.NewReviewerID,
+		OldReviewerID: reassignment.OldReviewerID,
+	}
+	if reassignment.Meta != nil {
+		data.Strategy = reassignment.Meta.Strategy
+	}
+
+	return NewEvent(EventReviewerReassigned, occurredAt, data)
+}
+
+// NewUserDeactivatedEvents - user.deactivated на каждого пользователя и reviewer.reassigned на каждое
+// переназначение, сделанное при деактивации
+func NewUserDeactivatedEvents(
+	teamName string,
+	userIDs []string,
+	reassignments []ReviewerReassignment,
+	occurredAt time.Time,
+) ([]Event, error) {
+	events := make([]Event, 0, len(userIDs)+len(reassignments))
+
+	for _, userID := range userIDs {
+		event, err := NewEvent(EventUserDeactivated, occurredAt, UserEventData{UserID: userID, TeamName: teamName})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	reassignedEvents, err := NewReviewerReassignedEvents(reassignments, occurredAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(events, reassignedEvents...), nil
+}
+
+// NewReviewerReassignedEvents - reviewer.reassigned на каждое переназначение
+func NewReviewerReassignedEvents(reassignments []ReviewerReassignment, occurredAt time.Time) ([]Event, error) {
+	events := make([]Event, 0, len(reassignments))
+
+	for _, reassignment := range reassignments {
+		event, err := NewReviewerReassignedEvent(reassignment, occurredAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func newPullRequestEventData(pr *PullRequest) PullRequestEventData {
+	reviewers := pr.AssignedReviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+
+	return PullRequestEventData{
+		PullRequestID:     pr.PullRequestId,
+		PullRequestName:   pr.PullRequestName,
+		AuthorID:          pr.AuthorId,
+		Status:            pr.Status,
+		AssignedReviewers: reviewers,
+	}
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV    ExportFormat = "csv"
+	ExportFormatNDJSON ExportFormat = "ndjson"
+)
+
+// ExportFilter - полуинтервал [From, To); nil - без ограничения с этой стороны
+type ExportFilter struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// PullRequestExportRow - строка выгрузки PR. Команда - основная команда автора
+type PullRequestExportRow struct {
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+	AuthorID          *string    `json:"author_id"`
+	AuthorName        *string    `json:"author_name"`
+	TeamName          *string    `json:"team_name"`
+	Status            string     `json:"status"`
+	NeedMoreReviewers bool       `json:"need_more_reviewers"`
+	ReviewerIDs       []string   `json:"reviewer_ids"`
+	CreatedAt         *time.Time `json:"created_at"`
+	MergedAt          *time.Time `json:"merged_at"`
+}
+
+// ReviewExportRow - строка выгрузки назначений ревьюверов (по строке на текущее назначение)
+type ReviewExportRow struct {
+	PullRequestID      string     `json:"pull_request_id"`
+	PullRequestName    string     `json:"pull_request_name"`
+	AuthorID           *string    `json:"author_id"`
+	TeamName           *string    `json:"team_name"`
+	ReviewerID         string     `json:"reviewer_id"`
+	ReviewerName       string     `json:"reviewer_name"`
+	Status             string     `json:"status"`
+	AssignmentStrategy *string    `json:"assignment_strategy"`
+	AssignedAt         *time.Time `json:"assigned_at"`
+	CreatedAt          *time.Time `json:"created_at"`
+	MergedAt           *time.Time `json:"merged_at"`
+}
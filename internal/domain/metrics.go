@@ -0,0 +1,51 @@
+package domain
+
+// MetricsTeamNone - метка команды для PR, у автора которых нет основной команды
+const MetricsTeamNone = "none"
+
+// MetricsTeamOther - метка, под которой объединяются команды сверх лимита меток
+const MetricsTeamOther = "other"
+
+// TeamHistogram - распределение значений по команде. CumulativeCounts - число значений не больше
+// соответствующей границы бакета (как в Prometheus), значения выше последней границы входят только в Count
+type TeamHistogram struct {
+	TeamName         string
+	Count            uint64
+	Sum              float64
+	CumulativeCounts map[float64]uint64
+}
+
+type TeamEventCount struct {
+	TeamName string
+	Type     AssignmentEventType
+	Strategy AssignmentStrategy
+	Count    uint64
+}
+
+// PRLifecycleSnapshot - агрегаты жизненного цикла PR, посчитанные по БД. Команда PR - основная команда автора
+type PRLifecycleSnapshot struct {
+	TimeToMerge       []TeamHistogram
+	TimeToFirstReview []TeamHistogram
+	Events            []TeamEventCount
+}
+
+// TeamOpenPRCounts - открытые PR команды (по основной команде автора)
+type TeamOpenPRCounts struct {
+	TeamName          string
+	Open              uint64
+	NeedMoreReviewers uint64
+}
+
+// TeamMemberLoad - число открытых ревью активного участника команды. Пользователь из нескольких
+// команд встречается по строке на каждую
+type TeamMemberLoad struct {
+	TeamName    string
+	UserID      string
+	OpenReviews uint64
+}
+
+// ReviewLoadSnapshot - текущая нагрузка ревью, посчитанная по БД
+type ReviewLoadSnapshot struct {
+	OpenPRs     []TeamOpenPRCounts
+	MemberLoads []TeamMemberLoad
+}
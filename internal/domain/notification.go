@@ -0,0 +1,97 @@
+package domain
+
+import "time"
+
+// NotificationKind - вид уведомления ревьюверу в чат
+type NotificationKind string
+
+const (
+	NotificationAssigned   NotificationKind = "ASSIGNED"
+	NotificationUnassigned NotificationKind = "UNASSIGNED"
+	NotificationPRMerged   NotificationKind = "PR_MERGED"
+	// NotificationReminder - ежедневное напоминание об открытых ревью
+	NotificationReminder NotificationKind = "REMINDER"
+)
+
+// NotificationKinds - все виды уведомлений; для каждого задаётся шаблон сообщения
+var NotificationKinds = []NotificationKind{
+	NotificationAssigned,
+	NotificationUnassigned,
+	NotificationPRMerged,
+	NotificationReminder,
+}
+
+// TeamChatChannel - incoming-webhook Slack/Mattermost, куда уходят уведомления участников команды
+// (по основной команде пользователя)
+type TeamChatChannel struct {
+	TeamName string `json:"team_name" binding:"required"`
+	URL      string `json:"url" binding:"required"`
+}
+
+type DeleteTeamChatChannelRequest struct {
+	TeamName string `json:"team_name" binding:"required"`
+}
+
+// UserChatMention - как упомянуть пользователя в сообщении: "<@U024BE7LH>" в Slack, "@login" в Mattermost
+type UserChatMention struct {
+	UserID  string `json:"user_id" binding:"required"`
+	Mention string `json:"mention" binding:"required"`
+}
+
+// NotificationRecipient - получатель уведомления с каналом его основной команды, упоминанием и настройками.
+// ChannelURL пустой, если у команды нет канала; Mention пустой, если упоминание не задано;
+// Preferences пустые, если пользователь не менял настройки
+type NotificationRecipient struct {
+	UserID      string
+	Username    string
+	IsActive    bool
+	ChannelURL  *string
+	Mention     *string
+	Preferences *NotificationPreferences
+}
+
+// EffectivePreferences - настройки получателя или настройки по умолчанию
+func (r NotificationRecipient) EffectivePreferences() NotificationPreferences {
+	if r.Preferences != nil {
+		return *r.Preferences
+	}
+	return DefaultNotificationPreferences(r.UserID)
+}
+
+// ChatNotification - готовое сообщение, которое ставится в очередь отправки
+type ChatNotification struct {
+	EventID string
+	Kind    NotificationKind
+	UserID  string
+	URL     string
+	Text    string
+	// NotBefore - не отправлять раньше окончания тихих часов получателя; nil - сразу
+	NotBefore *time.Time
+}
+
+// NotificationStatus - состояние отправки уведомления
+type NotificationStatus string
+
+const (
+	NotificationPending NotificationStatus = "PENDING"
+	NotificationSent    NotificationStatus = "SENT"
+	// NotificationFailed - попытки исчерпаны, уведомление больше не отправляется
+	NotificationFailed NotificationStatus = "FAILED"
+)
+
+// DueNotification - уведомление, взятое в работу отправщиком
+type DueNotification struct {
+	ID       int64
+	URL      string
+	Text     string
+	Attempts int
+}
+
+// NotificationAttempt - итог попытки отправки
+type NotificationAttempt struct {
+	NotificationID int64
+	Sent           bool
+	Error          string
+	// NextAttemptAt - когда повторить; nil для неуспешной попытки переводит уведомление в FAILED
+	NextAttemptAt *time.Time
+}
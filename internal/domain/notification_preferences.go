@@ -0,0 +1,104 @@
+package domain
+
+import "time"
+
+// NotificationChannel - куда пользователь получает уведомления
+type NotificationChannel string
+
+const (
+	NotificationChannelChat  NotificationChannel = "CHAT"
+	NotificationChannelEmail NotificationChannel = "EMAIL"
+)
+
+func (c NotificationChannel) IsValid() bool {
+	switch c {
+	case NotificationChannelChat, NotificationChannelEmail:
+		return true
+	}
+	return false
+}
+
+// NotificationDelivery - отправлять письмо сразу или собирать в ежедневный дайджест
+type NotificationDelivery string
+
+const (
+	DeliveryImmediate NotificationDelivery = "IMMEDIATE"
+	DeliveryDigest    NotificationDelivery = "DIGEST"
+)
+
+func (d NotificationDelivery) IsValid() bool {
+	switch d {
+	case DeliveryImmediate, DeliveryDigest:
+		return true
+	}
+	return false
+}
+
+// NotificationPreferences - настройки уведомлений пользователя. Часы (дайджест, тихие часы) задаются
+// от 0 до 23 в часовом поясе Timezone; тихие часы могут переходить через полночь (22 - 7)
+type NotificationPreferences struct {
+	UserID          string                `json:"user_id" binding:"required"`
+	Email           *string               `json:"email,omitempty"`
+	Channels        []NotificationChannel `json:"channels" binding:"required"`
+	Delivery        NotificationDelivery  `json:"delivery" binding:"required"`
+	DigestHour      int                   `json:"digest_hour"`
+	QuietHoursStart *int                  `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd   *int                  `json:"quiet_hours_end,omitempty"`
+	Timezone        string                `json:"timezone"`
+}
+
+// DefaultNotificationPreferences - настройки пользователя, который их не менял: только чат, сразу
+func DefaultNotificationPreferences(userID string) NotificationPreferences {
+	return NotificationPreferences{
+		UserID:     userID,
+		Channels:   []NotificationChannel{NotificationChannelChat},
+		Delivery:   DeliveryImmediate,
+		DigestHour: 9,
+		Timezone:   "UTC",
+	}
+}
+
+func (p NotificationPreferences) Allows(channel NotificationChannel) bool {
+	for _, allowed := range p.Channels {
+		if allowed == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailNotification - готовое письмо, которое ставится в очередь отправки. Письмо с Digest
+// отправляется не отдельно, а в составе дайджеста получателя
+type EmailNotification struct {
+	EventID  string
+	Kind     NotificationKind
+	UserID   string
+	Email    string
+	Subject  string
+	TextBody string
+	HTMLBody string
+	Digest   bool
+	// NotBefore - не отправлять раньше (тихие часы, время дайджеста); nil - сразу
+	NotBefore *time.Time
+}
+
+// DueEmail - письмо, взятое в работу отправщиком
+type DueEmail struct {
+	ID       int64
+	UserID   string
+	Email    string
+	Subject  string
+	TextBody string
+	HTMLBody string
+	Digest   bool
+	Attempts int
+}
+
+// EmailAttempt - итог попытки отправки письма; дайджест отправляется одним письмом за несколько записей
+type EmailAttempt struct {
+	EmailIDs []int64
+	Sent     bool
+	Error    string
+	// NextAttemptAt - когда повторить; nil для неуспешной попытки переводит письма в FAILED
+	NextAttemptAt *time.Time
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/nedokyrill/avito-pr-api/internal/generated"
+import (
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/generated"
+)
 
 // Реэкспорт типов из generated для использования в доменной логике
 
@@ -24,3 +28,106 @@ type ReassignReviewerRequest struct {
 	PullRequestID string `json:"pull_request_id" binding:"required"`
 	OldUserID     string `json:"old_user_id" binding:"required"`
 }
+
+// OpenReview - назначение ревьювера на открытый PR (для расчёта нагрузки)
+type OpenReview struct {
+	PrID       string
+	AuthorID   string
+	ReviewerID string
+}
+
+// PendingReview - открытый PR в очереди активного ревьювера с SLA команды автора
+type PendingReview struct {
+	ReviewerID      string
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	CreatedAt       time.Time
+	// ReviewSLAHours - nil, если у команды SLA не задан
+	ReviewSLAHours *int
+}
+
+// PreviewAssignmentRequest - гипотетический PR для превью назначения ревьюеров
+type PreviewAssignmentRequest struct {
+	AuthorID       string   `json:"author_id" binding:"required"`
+	ExcludeUserIDs []string `json:"exclude_user_ids"` // пользователи, которых не нужно рассматривать
+}
+
+type ExclusionReason string
+
+const (
+	ExclusionReasonAuthor   ExclusionReason = "AUTHOR"
+	ExclusionReasonInactive ExclusionReason = "INACTIVE"
+	ExclusionReasonExcluded ExclusionReason = "EXCLUDED"
+	// ExclusionReasonAlreadyAssigned - уже ревьюит этот PR
+	ExclusionReasonAlreadyAssigned ExclusionReason = "ALREADY_ASSIGNED"
+)
+
+// ReviewerCandidate - участник, который может быть выбран ревьюером.
+// Level - уровень иерархии: 0 - команда автора, 1 - её родитель и т.д.
+// Кандидаты с уровня выше рассматриваются, только если на нижних уровнях не хватило людей
+type ReviewerCandidate struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	Level    int    `json:"level"`
+}
+
+type ExcludedReviewer struct {
+	UserID   string          `json:"user_id"`
+	Username string          `json:"username"`
+	TeamName string          `json:"team_name"`
+	Reason   ExclusionReason `json:"reason"`
+}
+
+type AssignmentPreview struct {
+	AuthorID          string              `json:"author_id"`
+	TeamName          string              `json:"team_name"`
+	Candidates        []ReviewerCandidate `json:"candidates"`
+	Excluded          []ExcludedReviewer  `json:"excluded"`
+	SampleSelection   []string            `json:"sample_selection"`
+	NeedMoreReviewers bool                `json:"need_more_reviewers"`
+}
+
+type AssignmentStrategy string
+
+const (
+	// AssignmentStrategyRandom - случайный выбор при создании PR с подъёмом к родительским командам
+	AssignmentStrategyRandom AssignmentStrategy = "RANDOM"
+	// AssignmentStrategyReassign - случайная замена через /pullRequest/reassign
+	AssignmentStrategyReassign AssignmentStrategy = "REASSIGN"
+	// AssignmentStrategyDeactivation - замена деактивируемого ревьювера
+	AssignmentStrategyDeactivation AssignmentStrategy = "DEACTIVATION"
+	// AssignmentStrategyRebalance - перенос ревью на активируемого участника (без случайности)
+	AssignmentStrategyRebalance AssignmentStrategy = "REBALANCE"
+)
+
+// AssignmentMeta - объяснение назначения ревьювера, хранится в pr_reviewers.assignment_meta.
+// Seed заполняется, если выбор был случайным: с тем же seed и тем же составом команд выбор воспроизводится
+type AssignmentMeta struct {
+	Strategy           AssignmentStrategy `json:"strategy"`
+	CandidateCount     int                `json:"candidate_count"`
+	Excluded           []ExcludedReviewer `json:"excluded,omitempty"`
+	Seed               *int64             `json:"seed,omitempty"`
+	ReplacedReviewerID string             `json:"replaced_reviewer_id,omitempty"`
+}
+
+// ReviewerAssignment - назначение ревьювера на PR. Meta пустая у назначений, сделанных до появления метаданных
+type ReviewerAssignment struct {
+	ReviewerID string          `json:"reviewer_id"`
+	AssignedAt *time.Time      `json:"assigned_at"`
+	Meta       *AssignmentMeta `json:"assignment_meta"`
+}
+
+// PullRequestResponse - ответ создания и мерджа PR
+type PullRequestResponse struct {
+	PR          *PullRequest         `json:"pr"`
+	Assignments []ReviewerAssignment `json:"assignments"`
+}
+
+// ReassignReviewerResponse - ответ замены ревьювера
+type ReassignReviewerResponse struct {
+	PR          *PullRequest         `json:"pr"`
+	ReplacedBy  string               `json:"replaced_by"`
+	Assignments []ReviewerAssignment `json:"assignments"`
+}
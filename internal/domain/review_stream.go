@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// ReviewStreamEventType - вид изменения очереди ревью в потоке /users/reviewStream (поле event в SSE)
+type ReviewStreamEventType string
+
+const (
+	ReviewStreamAssigned      ReviewStreamEventType = "assigned"
+	ReviewStreamUnassigned    ReviewStreamEventType = "unassigned"
+	ReviewStreamStatusChanged ReviewStreamEventType = "status_changed"
+)
+
+// ReviewStreamEvent - изменение очереди ревью пользователя. ID - позиция события в потоке outbox, она же
+// id в SSE: клиент присылает её в Last-Event-ID при переподключении
+type ReviewStreamEvent struct {
+	ID              int64                 `json:"-"`
+	Type            ReviewStreamEventType `json:"type"`
+	EventID         string                `json:"event_id"`
+	PullRequestID   string                `json:"pull_request_id"`
+	PullRequestName string                `json:"pull_request_name,omitempty"`
+	AuthorID        string                `json:"author_id,omitempty"`
+	Status          PullRequestStatus     `json:"status,omitempty"`
+	OccurredAt      time.Time             `json:"occurred_at"`
+}
+
+// ReviewStreamSink - транспорт потока очереди ревью (в HTTP - ответ Server-Sent Events). Open вызывается
+// один раз, когда запрос проверен: до него сервис ещё может вернуть ошибку, после - только закрывает поток
+type ReviewStreamSink interface {
+	Open()
+	Send(event ReviewStreamEvent) error
+	Ping() error
+}
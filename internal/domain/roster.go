@@ -0,0 +1,55 @@
+package domain
+
+// Roster - состав команд и участников для массового импорта/экспорта (YAML/CSV)
+type Roster struct {
+	Teams []Team `json:"teams"`
+}
+
+type RosterFormat string
+
+const (
+	RosterFormatYAML RosterFormat = "yaml"
+	RosterFormatCSV  RosterFormat = "csv"
+)
+
+// MaxRosterDocumentSize ограничивает размер импортируемого документа (в байтах)
+const MaxRosterDocumentSize int64 = 10 << 20
+
+type RosterTeamChange struct {
+	TeamName       string  `json:"team_name"`
+	ParentTeamName *string `json:"parent_team_name"`
+}
+
+type RosterMembership struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+}
+
+// RosterImportPlan - разница между документом и текущим состоянием БД.
+// Команды и пользователи не удаляются: пользователи из затронутых команд, которых нет в документе,
+// выходят из них и деактивируются. Открытые ревью деактивируемых пользователей переназначаются
+type RosterImportPlan struct {
+	DryRun             bool                   `json:"dry_run"`
+	CreatedTeams       []RosterTeamChange     `json:"created_teams"`
+	UpdatedTeams       []RosterTeamChange     `json:"updated_teams"`
+	CreatedUsers       []TeamMember           `json:"created_users"`
+	UpdatedUsers       []TeamMember           `json:"updated_users"`
+	AddedMemberships   []RosterMembership     `json:"added_memberships"`
+	RemovedMemberships []RosterMembership     `json:"removed_memberships"`
+	DeactivatedUsers   []string               `json:"deactivated_users"`
+	Reassignments      []ReviewerReassignment `json:"reassignments"`
+
+	// DeactivationTeams - команда из документа, из-за которой пользователь деактивируется (для событий)
+	DeactivationTeams map[string]string `json:"-"`
+	// RosterSnapshot - состав, по которому построен план; при применении он сверяется с БД
+	RosterSnapshot []Team `json:"-"`
+	// ReviewersSnapshot - ревьюверы затронутых PR на момент построения плана (pr_id -> reviewer_ids)
+	ReviewersSnapshot map[string][]string `json:"-"`
+}
+
+func (p *RosterImportPlan) IsEmpty() bool {
+	return len(p.CreatedTeams) == 0 && len(p.UpdatedTeams) == 0 &&
+		len(p.CreatedUsers) == 0 && len(p.UpdatedUsers) == 0 &&
+		len(p.AddedMemberships) == 0 && len(p.RemovedMemberships) == 0 &&
+		len(p.DeactivatedUsers) == 0
+}
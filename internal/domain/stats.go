@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// DefaultStatsPeriod - период статистики, если from не задан
+const DefaultStatsPeriod = 30 * 24 * time.Hour
+
+// StatsFilter - назначения учитываются по assigned_at в полуинтервале [From, To)
+type StatsFilter struct {
+	From     time.Time
+	To       time.Time
+	TeamName *string
+}
+
+// ReviewStats - счётчики ревью. Assigned = Open + Completed + Closed + ReassignedAway, Closed - PR закрыты
+// без мерджа. ReassignedAway считается по assignment_meta замен, поэтому замены до появления метаданных не учитываются
+type ReviewStats struct {
+	Assigned           int      `json:"assigned"`
+	Open               int      `json:"open"`
+	Completed          int      `json:"completed"`
+	Closed             int      `json:"closed"`
+	ReassignedAway     int      `json:"reassigned_away"`
+	MedianHoursToMerge *float64 `json:"median_hours_to_merge"` // от назначения до мерджа, nil - мерджей не было
+}
+
+type UserReviewStats struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	ReviewStats
+}
+
+// TeamReviewStats - сумма по участникам команды; пользователь из нескольких команд учитывается в каждой
+type TeamReviewStats struct {
+	TeamName string `json:"team_name"`
+	ReviewStats
+}
+
+type UserStatsResponse struct {
+	From  time.Time         `json:"from"`
+	To    time.Time         `json:"to"`
+	Users []UserReviewStats `json:"users"`
+}
+
+type TeamStatsResponse struct {
+	From  time.Time         `json:"from"`
+	To    time.Time         `json:"to"`
+	Teams []TeamReviewStats `json:"teams"`
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Subscription - подписка внешней системы на доменные события. Secret используется для подписи
+// доставок и наружу не отдаётся
+type Subscription struct {
+	ID         string      `json:"subscription_id"`
+	URL        string      `json:"url"`
+	Secret     string      `json:"-"`
+	EventTypes []EventType `json:"event_types"`
+	IsActive   bool        `json:"is_active"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type CreateSubscriptionRequest struct {
+	URL        string      `json:"url" binding:"required"`
+	Secret     string      `json:"secret" binding:"required"`
+	EventTypes []EventType `json:"event_types" binding:"required,min=1"`
+}
+
+type DeleteSubscriptionRequest struct {
+	SubscriptionID string `json:"subscription_id" binding:"required"`
+}
+
+type RetryDeliveryRequest struct {
+	DeliveryID int64 `json:"delivery_id" binding:"required"`
+}
+
+// DeliveryStatus - состояние доставки события подписчику
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "PENDING"
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	// DeliveryDead - попытки исчерпаны, доставка ждёт ручного повтора
+	DeliveryDead DeliveryStatus = "DEAD"
+)
+
+func (s DeliveryStatus) IsValid() bool {
+	switch s {
+	case DeliveryPending, DeliveryDelivered, DeliveryDead:
+		return true
+	default:
+		return false
+	}
+}
+
+// SubscriptionDelivery - запись журнала доставок
+type SubscriptionDelivery struct {
+	ID             int64           `json:"delivery_id"`
+	SubscriptionID string          `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      EventType       `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// DeliveryLogFilter - фильтр журнала доставок; пустые поля не ограничивают выборку
+type DeliveryLogFilter struct {
+	SubscriptionID *string
+	Status         *DeliveryStatus
+	Limit          int
+}
+
+// DueDelivery - доставка, взятая диспетчером в работу, вместе с адресом и секретом подписки
+type DueDelivery struct {
+	ID             int64
+	SubscriptionID string
+	URL            string
+	Secret         string
+	EventID        string
+	EventType      EventType
+	Payload        json.RawMessage
+	Attempts       int
+}
+
+// DeliveryAttempt - итог попытки доставки. StatusCode пустой, если ответа не было (таймаут, сеть)
+type DeliveryAttempt struct {
+	DeliveryID int64
+	Delivered  bool
+	StatusCode *int
+	Error      string
+	// NextAttemptAt - когда повторить; nil для неуспешной попытки переводит доставку в DEAD
+	NextAttemptAt *time.Time
+}
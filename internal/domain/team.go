@@ -6,8 +6,25 @@ import "github.com/nedokyrill/avito-pr-api/internal/generated"
 
 type Team = generated.Team
 type TeamMember = generated.TeamMember
+type TeamTreeNode = generated.TeamTreeNode
 
 type DeactivateTeamMembersRequest struct {
 	TeamName string   `json:"team_name" binding:"required"`
 	UserIDs  []string `json:"user_ids"` // если пустой - деактивировать всех
 }
+
+type SetTeamParentRequest struct {
+	TeamName       string  `json:"team_name" binding:"required"`
+	ParentTeamName *string `json:"parent_team_name"` // nil - сделать команду корневой
+}
+
+type TeamMembershipRequest struct {
+	TeamName string `json:"team_name" binding:"required"`
+	UserID   string `json:"user_id" binding:"required"`
+}
+
+// SetReviewSLARequest - сколько часов PR команды может ждать ревью; nil снимает SLA
+type SetReviewSLARequest struct {
+	TeamName       string `json:"team_name" binding:"required"`
+	ReviewSLAHours *int   `json:"review_sla_hours"`
+}
@@ -1,23 +1,78 @@
 package domain
 
-import "github.com/nedokyrill/avito-pr-api/internal/generated"
+import (
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/generated"
+)
 
 // Реэкспорт типов из generated для использования в доменной логике
 
 type User = generated.User
 
+// UserFilter - фильтр списка пользователей, nil-поля не учитываются
+type UserFilter struct {
+	UserID   *string
+	Username *string
+}
+
 type SetIsActiveRequest struct {
 	UserID   string `json:"user_id" binding:"required"`
 	IsActive bool   `json:"is_active"`
 }
 
+type SetPrimaryTeamRequest struct {
+	UserID   string `json:"user_id" binding:"required"`
+	TeamName string `json:"team_name" binding:"required"`
+}
+
+type ActivateTeamMembersRequest struct {
+	TeamName  string   `json:"team_name" binding:"required"`
+	UserIDs   []string `json:"user_ids" binding:"required,min=1"`
+	Rebalance bool     `json:"rebalance"` // перенести часть открытых ревью с самых загруженных участников команды
+}
+
+type ActivateTeamMembersResponse struct {
+	ActivatedUserIDs []string               `json:"activated_user_ids"`
+	Reassignments    []ReviewerReassignment `json:"reassignments"`
+}
+
 type DeactivateTeamMembersResponse struct {
 	DeactivatedUserIDs []string               `json:"deactivated_user_ids"`
 	Reassignments      []ReviewerReassignment `json:"reassignments"`
 }
 
+// DeactivationPlan - превью деактивации: что будет деактивировано и переназначено.
+// Сохраняется в БД и может быть применено по PlanID без пересчёта
+type DeactivationPlan struct {
+	PlanID             string                 `json:"plan_id"`
+	TeamName           string                 `json:"team_name"`
+	DeactivatedUserIDs []string               `json:"deactivated_user_ids"`
+	Reassignments      []ReviewerReassignment `json:"reassignments"`
+	PrsLosingReviewers []PrLosingReviewers    `json:"prs_losing_reviewers"`
+	CreatedAt          time.Time              `json:"created_at"`
+	ExpiresAt          time.Time              `json:"expires_at"`
+	AppliedAt          *time.Time             `json:"applied_at,omitempty"`
+
+	// ReviewersSnapshot - ревьюверы затронутых PR на момент превью (pr_id -> reviewer_ids)
+	ReviewersSnapshot map[string][]string `json:"-"`
+}
+
+// PrLosingReviewers - PR, у которого часть ревьюверов снимается без замены
+type PrLosingReviewers struct {
+	PrID               string   `json:"pr_id"`
+	LostReviewerIDs    []string `json:"lost_reviewer_ids"`
+	RemainingReviewers int      `json:"remaining_reviewers"`
+}
+
+type ApplyDeactivationPlanRequest struct {
+	PlanID string `json:"plan_id" binding:"required"`
+}
+
 type ReviewerReassignment struct {
 	PrID          string `json:"pr_id"`
 	OldReviewerID string `json:"old_reviewer_id"`
 	NewReviewerID string `json:"new_reviewer_id,omitempty"` // пустой = удалить без замены
+
+	Meta *AssignmentMeta `json:"assignment_meta,omitempty"` // объяснение назначения NewReviewerID
 }
@@ -0,0 +1,69 @@
+package domain
+
+// VCSProvider - система контроля версий, присылающая вебхуки
+type VCSProvider string
+
+const (
+	VCSProviderGitHub VCSProvider = "github"
+	VCSProviderGitLab VCSProvider = "gitlab"
+)
+
+// VCSUserMapping - соответствие логина в VCS пользователю сервиса
+type VCSUserMapping struct {
+	Provider VCSProvider `json:"provider" binding:"required"`
+	Login    string      `json:"login" binding:"required"`
+	UserID   string      `json:"user_id" binding:"required"`
+}
+
+// VCSEventAction - операция над PR, в которую переводится событие VCS
+type VCSEventAction string
+
+const (
+	VCSActionOpen   VCSEventAction = "OPEN"
+	VCSActionMerge  VCSEventAction = "MERGE"
+	VCSActionClose  VCSEventAction = "CLOSE"
+	VCSActionIgnore VCSEventAction = "IGNORE"
+)
+
+// VCSPullRequestEvent - событие VCS, приведённое к общему виду. PullRequestID строится из репозитория
+// и номера PR, чтобы не пересекаться между репозиториями и провайдерами
+type VCSPullRequestEvent struct {
+	Provider      VCSProvider
+	DeliveryID    string
+	Event         string
+	Action        VCSEventAction
+	PullRequestID string
+	Title         string
+	AuthorLogin   string
+	// IgnoreReason - почему событие пропущено (для Action == VCSActionIgnore)
+	IgnoreReason string
+}
+
+// WebhookResultStatus - итог обработки доставки
+type WebhookResultStatus string
+
+const (
+	WebhookProcessed WebhookResultStatus = "processed"
+	WebhookIgnored   WebhookResultStatus = "ignored"
+	WebhookDuplicate WebhookResultStatus = "duplicate"
+
+	// WebhookProcessing - доставка забрана и ещё обрабатывается; виден только в webhook_deliveries
+	WebhookProcessing WebhookResultStatus = "processing"
+)
+
+type WebhookResult struct {
+	Status        WebhookResultStatus `json:"status"`
+	Action        VCSEventAction      `json:"action,omitempty"`
+	PullRequestID string              `json:"pull_request_id,omitempty"`
+	Reason        string              `json:"reason,omitempty"`
+}
+
+// WebhookDelivery - запись об обработанной доставке
+type WebhookDelivery struct {
+	Provider      VCSProvider
+	DeliveryID    string
+	Event         string
+	Action        VCSEventAction
+	Result        WebhookResultStatus
+	PullRequestID *string
+}
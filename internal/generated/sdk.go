@@ -24,12 +24,14 @@ const (
 
 // Defines values for PullRequestStatus.
 const (
+	PullRequestStatusCLOSED PullRequestStatus = "CLOSED"
 	PullRequestStatusMERGED PullRequestStatus = "MERGED"
 	PullRequestStatusOPEN   PullRequestStatus = "OPEN"
 )
 
 // Defines values for PullRequestShortStatus.
 const (
+	PullRequestShortStatusCLOSED PullRequestShortStatus = "CLOSED"
 	PullRequestShortStatusMERGED PullRequestShortStatus = "MERGED"
 	PullRequestShortStatusOPEN   PullRequestShortStatus = "OPEN"
 )
@@ -73,8 +75,18 @@ type PullRequestShortStatus string
 
 // Team defines model for Team.
 type Team struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName string `json:"team_name"`
+
+	// ParentTeamName Имя родительской команды (отдел/гильдия), если есть
+	ParentTeamName *string      `json:"parent_team_name,omitempty"`
+	Members        []TeamMember `json:"members"`
+}
+
+// TeamTreeNode defines model for TeamTreeNode.
+type TeamTreeNode struct {
+	TeamName       string         `json:"team_name"`
+	ParentTeamName *string        `json:"parent_team_name,omitempty"`
+	Children       []TeamTreeNode `json:"children"`
 }
 
 // TeamMember defines model for TeamMember.
@@ -88,8 +100,13 @@ type TeamMember struct {
 type User struct {
 	UserId   string `json:"user_id"`
 	Username string `json:"username"`
+
+	// TeamName Основная команда пользователя (если не задана - первая из team_names)
 	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+
+	// TeamNames Все команды, в которых состоит пользователь
+	TeamNames []string `json:"team_names"`
+	IsActive  bool     `json:"is_active"`
 }
 
 // TeamNameQuery defines model for TeamNameQuery.
@@ -122,12 +139,43 @@ type GetTeamGetParams struct {
 	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
 }
 
+// GetTeamTreeParams defines parameters for GetTeamTree.
+type GetTeamTreeParams struct {
+	// TeamName Корень поддерева; если не указан - возвращается всё дерево
+	TeamName *string `form:"team_name,omitempty" json:"team_name,omitempty"`
+}
+
+// PostTeamAddMemberJSONBody defines parameters for PostTeamAddMember.
+type PostTeamAddMemberJSONBody struct {
+	TeamName string `json:"team_name"`
+	UserId   string `json:"user_id"`
+}
+
+// PostTeamRemoveMemberJSONBody defines parameters for PostTeamRemoveMember.
+type PostTeamRemoveMemberJSONBody struct {
+	TeamName string `json:"team_name"`
+	UserId   string `json:"user_id"`
+}
+
+// PostTeamSetParentJSONBody defines parameters for PostTeamSetParent.
+type PostTeamSetParentJSONBody struct {
+	// ParentTeamName null - сделать команду корневой
+	ParentTeamName *string `json:"parent_team_name,omitempty"`
+	TeamName       string  `json:"team_name"`
+}
+
 // GetUsersGetReviewParams defines parameters for GetUsersGetReview.
 type GetUsersGetReviewParams struct {
 	// UserId Идентификатор пользователя
 	UserId UserIdQuery `form:"user_id" json:"user_id"`
 }
 
+// PostUsersSetPrimaryTeamJSONBody defines parameters for PostUsersSetPrimaryTeam.
+type PostUsersSetPrimaryTeamJSONBody struct {
+	TeamName string `json:"team_name"`
+	UserId   string `json:"user_id"`
+}
+
 // PostUsersSetIsActiveJSONBody defines parameters for PostUsersSetIsActive.
 type PostUsersSetIsActiveJSONBody struct {
 	IsActive bool   `json:"is_active"`
@@ -146,5 +194,17 @@ type PostPullRequestReassignJSONRequestBody PostPullRequestReassignJSONBody
 // PostTeamAddJSONRequestBody defines body for PostTeamAdd for application/json ContentType.
 type PostTeamAddJSONRequestBody = Team
 
+// PostTeamAddMemberJSONRequestBody defines body for PostTeamAddMember for application/json ContentType.
+type PostTeamAddMemberJSONRequestBody PostTeamAddMemberJSONBody
+
+// PostTeamRemoveMemberJSONRequestBody defines body for PostTeamRemoveMember for application/json ContentType.
+type PostTeamRemoveMemberJSONRequestBody PostTeamRemoveMemberJSONBody
+
+// PostTeamSetParentJSONRequestBody defines body for PostTeamSetParent for application/json ContentType.
+type PostTeamSetParentJSONRequestBody PostTeamSetParentJSONBody
+
+// PostUsersSetPrimaryTeamJSONRequestBody defines body for PostUsersSetPrimaryTeam for application/json ContentType.
+type PostUsersSetPrimaryTeamJSONRequestBody PostUsersSetPrimaryTeamJSONBody
+
 // PostUsersSetIsActiveJSONRequestBody defines body for PostUsersSetIsActive for application/json ContentType.
 type PostUsersSetIsActiveJSONRequestBody PostUsersSetIsActiveJSONBody
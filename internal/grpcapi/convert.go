@@ -0,0 +1,161 @@
+package grpcapi
+
+import (
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/generated/reviewerpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Преобразования между доменными моделями (общими с REST API) и сообщениями reviewer.v1
+
+func teamFromProto(team *reviewerpb.Team) domain.Team {
+	members := make([]domain.TeamMember, 0, len(team.GetMembers()))
+	for _, member := range team.GetMembers() {
+		members = append(members, domain.TeamMember{
+			UserId:   member.GetUserId(),
+			Username: member.GetUsername(),
+			IsActive: member.GetIsActive(),
+		})
+	}
+
+	return domain.Team{
+		TeamName:       team.GetTeamName(),
+		ParentTeamName: team.ParentTeamName,
+		Members:        members,
+	}
+}
+
+func teamToProto(team *domain.Team) *reviewerpb.Team {
+	if team == nil {
+		return nil
+	}
+
+	members := make([]*reviewerpb.TeamMember, 0, len(team.Members))
+	for _, member := range team.Members {
+		members = append(members, &reviewerpb.TeamMember{
+			UserId:   member.UserId,
+			Username: member.Username,
+			IsActive: member.IsActive,
+		})
+	}
+
+	return &reviewerpb.Team{
+		TeamName:       team.TeamName,
+		ParentTeamName: team.ParentTeamName,
+		Members:        members,
+	}
+}
+
+func teamTreeToProto(nodes []domain.TeamTreeNode) []*reviewerpb.TeamTreeNode {
+	result := make([]*reviewerpb.TeamTreeNode, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, &reviewerpb.TeamTreeNode{
+			TeamName:       node.TeamName,
+			ParentTeamName: node.ParentTeamName,
+			Children:       teamTreeToProto(node.Children),
+		})
+	}
+	return result
+}
+
+func userToProto(user *domain.User) *reviewerpb.User {
+	if user == nil {
+		return nil
+	}
+
+	return &reviewerpb.User{
+		UserId:    user.UserId,
+		Username:  user.Username,
+		TeamName:  user.TeamName,
+		TeamNames: user.TeamNames,
+		IsActive:  user.IsActive,
+	}
+}
+
+func statusToProto(status domain.PullRequestStatus) reviewerpb.PullRequestStatus {
+	switch status {
+	case domain.PullRequestStatusOPEN:
+		return reviewerpb.PullRequestStatus_PULL_REQUEST_STATUS_OPEN
+	case domain.PullRequestStatusMERGED:
+		return reviewerpb.PullRequestStatus_PULL_REQUEST_STATUS_MERGED
+	case domain.PullRequestStatusCLOSED:
+		return reviewerpb.PullRequestStatus_PULL_REQUEST_STATUS_CLOSED
+	default:
+		return reviewerpb.PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+	}
+}
+
+func pullRequestToProto(pr *domain.PullRequest) *reviewerpb.PullRequest {
+	if pr == nil {
+		return nil
+	}
+
+	result := &reviewerpb.PullRequest{
+		PullRequestId:     pr.PullRequestId,
+		PullRequestName:   pr.PullRequestName,
+		AuthorId:          pr.AuthorId,
+		Status:            statusToProto(pr.Status),
+		AssignedReviewers: pr.AssignedReviewers,
+		NeedMoreReviewers: pr.NeedMoreReviewers,
+	}
+	if pr.CreatedAt != nil {
+		result.CreatedAt = timestamppb.New(*pr.CreatedAt)
+	}
+	if pr.MergedAt != nil {
+		result.MergedAt = timestamppb.New(*pr.MergedAt)
+	}
+
+	return result
+}
+
+func pullRequestsShortToProto(prs []domain.PullRequestShort) []*reviewerpb.PullRequestShort {
+	result := make([]*reviewerpb.PullRequestShort, 0, len(prs))
+	for _, pr := range prs {
+		result = append(result, &reviewerpb.PullRequestShort{
+			PullRequestId:   pr.PullRequestId,
+			PullRequestName: pr.PullRequestName,
+			AuthorId:        pr.AuthorId,
+			Status:          statusToProto(pr.Status),
+		})
+	}
+	return result
+}
+
+func assignmentsToProto(assignments []domain.ReviewerAssignment) []*reviewerpb.ReviewerAssignment {
+	result := make([]*reviewerpb.ReviewerAssignment, 0, len(assignments))
+	for _, assignment := range assignments {
+		item := &reviewerpb.ReviewerAssignment{
+			ReviewerId:     assignment.ReviewerID,
+			AssignmentMeta: assignmentMetaToProto(assignment.Meta),
+		}
+		if assignment.AssignedAt != nil {
+			item.AssignedAt = timestamppb.New(*assignment.AssignedAt)
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func assignmentMetaToProto(meta *domain.AssignmentMeta) *reviewerpb.AssignmentMeta {
+	if meta == nil {
+		return nil
+	}
+
+	excluded := make([]*reviewerpb.ExcludedReviewer, 0, len(meta.Excluded))
+	for _, reviewer := range meta.Excluded {
+		excluded = append(excluded, &reviewerpb.ExcludedReviewer{
+			UserId:   reviewer.UserID,
+			Username: reviewer.Username,
+			TeamName: reviewer.TeamName,
+			Reason:   string(reviewer.Reason),
+		})
+	}
+
+	return &reviewerpb.AssignmentMeta{
+		Strategy:           string(meta.Strategy),
+		CandidateCount:     int32(meta.CandidateCount),
+		Excluded:           excluded,
+		Seed:               meta.Seed,
+		ReplacedReviewerId: meta.ReplacedReviewerID,
+	}
+}
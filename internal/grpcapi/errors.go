@@ -0,0 +1,61 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain - домен в google.rpc.ErrorInfo, по нему клиент отличает коды сервиса от чужих
+const errorDomain = "avito-pr-api"
+
+// statusCodes - коды ошибок REST API и соответствующие им статусы gRPC; остальные коды - INVALID_ARGUMENT
+var statusCodes = map[domain.ErrorResponseErrorCode]codes.Code{
+	domain.NotFound:           codes.NotFound,
+	domain.InvalidRequest:     codes.InvalidArgument,
+	domain.TeamExists:         codes.AlreadyExists,
+	domain.PrExists:           codes.AlreadyExists,
+	domain.PrMerged:           codes.FailedPrecondition,
+	domain.PrClosed:           codes.FailedPrecondition,
+	domain.NotAssigned:        codes.FailedPrecondition,
+	domain.NoCandidate:        codes.FailedPrecondition,
+	domain.PlanAlreadyApplied: codes.FailedPrecondition,
+	domain.PlanStale:          codes.FailedPrecondition,
+}
+
+// toStatus переводит ошибку сервисного метода в статус gRPC. *domain.Error отдаётся со своим сообщением
+// и кодом в ErrorInfo.Reason, прочие ошибки логируются и отдаются как INTERNAL с internalMsg
+func toStatus(err error, logMsg, internalMsg string) error {
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) {
+		logger.Logger.Error(logMsg, err)
+		return newStatus(codes.Internal, domain.InternalError, internalMsg)
+	}
+
+	code, ok := statusCodes[domainErr.Code]
+	if !ok {
+		code = codes.InvalidArgument
+	}
+	return newStatus(code, domainErr.Code, domainErr.Message)
+}
+
+// invalidArgument - ошибка валидации запроса, аналог 400 INVALID_REQUEST
+func invalidArgument(message string) error {
+	return newStatus(codes.InvalidArgument, domain.InvalidRequest, message)
+}
+
+func newStatus(code codes.Code, reason domain.ErrorResponseErrorCode, message string) error {
+	st := status.New(code, message)
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: string(reason),
+		Domain: errorDomain,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
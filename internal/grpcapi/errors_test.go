@@ -0,0 +1,39 @@
+package grpcapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+		want domain.ErrorResponseErrorCode
+		msg  string
+	}{
+		{"not found", domain.NewError(domain.NotFound, "team not found"), codes.NotFound, domain.NotFound, "team not found"},
+		{"team exists", domain.NewError(domain.TeamExists, "team already exists"), codes.AlreadyExists, domain.TeamExists, "team already exists"},
+		{"pr merged", domain.NewError(domain.PrMerged, "cannot reassign on merged PR"), codes.FailedPrecondition, domain.PrMerged, "cannot reassign on merged PR"},
+		{"no candidate", domain.NewError(domain.NoCandidate, "no active replacement candidate"), codes.FailedPrecondition, domain.NoCandidate, "no active replacement candidate"},
+		{"wrapped domain error", fmt.Errorf("reassign: %w", domain.NewError(domain.NotAssigned, "reviewer is not assigned")), codes.FailedPrecondition, domain.NotAssigned, "reviewer is not assigned"},
+		{"unmapped domain code", domain.NewError("SOMETHING_NEW", "bad input"), codes.InvalidArgument, "SOMETHING_NEW", "bad input"},
+		{"storage error is hidden", errors.New("connection refused"), codes.Internal, domain.InternalError, domain.ErrMergePRMsg},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := toStatus(tt.err, "error merging PR: ", domain.ErrMergePRMsg)
+
+			requireStatus(t, err, tt.code, tt.want)
+			assert.Equal(t, tt.msg, status.Convert(err).Message())
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/generated/reviewerpb"
+	"google.golang.org/grpc"
+)
+
+// TeamOperations - операции над командами, которые отдаёт gRPC API (реализованы в teamService)
+type TeamOperations interface {
+	CreateTeam(ctx context.Context, team domain.Team) (*domain.Team, error)
+	GetTeam(ctx context.Context, teamName string) (*domain.Team, error)
+	GetTeamTree(ctx context.Context, teamName string) ([]domain.TeamTreeNode, error)
+	SetTeamParent(ctx context.Context, req domain.SetTeamParentRequest) (*domain.Team, error)
+	AddTeamMember(ctx context.Context, req domain.TeamMembershipRequest) (*domain.Team, error)
+	RemoveTeamMember(ctx context.Context, req domain.TeamMembershipRequest) (*domain.Team, error)
+}
+
+// UserOperations - операции над пользователями (реализованы в userService)
+type UserOperations interface {
+	SetIsActive(ctx context.Context, req domain.SetIsActiveRequest) (*domain.User, error)
+	SetPrimaryTeam(ctx context.Context, req domain.SetPrimaryTeamRequest) (*domain.User, error)
+	GetUserReviews(ctx context.Context, userID string) ([]domain.PullRequestShort, error)
+}
+
+// PullRequestOperations - операции над PR (реализованы в pullRequestService)
+type PullRequestOperations interface {
+	CreatePullRequest(ctx context.Context, req domain.CreatePullRequestRequest) (*domain.PullRequestResponse, error)
+	MergePullRequest(ctx context.Context, prID string) (*domain.PullRequestResponse, error)
+	ReassignReviewer(ctx context.Context, req domain.ReassignReviewerRequest) (*domain.ReassignReviewerResponse, error)
+}
+
+// NewServer создаёт gRPC-сервер с проверкой авторизации и восстановлением после паники
+// и регистрирует на нём сервисы команд, пользователей и PR
+func NewServer(teams TeamOperations, users UserOperations, prs PullRequestOperations) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		RecoveryInterceptor(),
+		AuthInterceptor(),
+	))
+
+	reviewerpb.RegisterTeamServiceServer(server, &teamServer{teams: teams})
+	reviewerpb.RegisterUserServiceServer(server, &userServer{users: users})
+	reviewerpb.RegisterPullRequestServiceServer(server, &pullRequestServer{prs: prs})
+
+	return server
+}
@@ -0,0 +1,132 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func init() {
+	logger.Logger = zap.NewNop().Sugar()
+}
+
+type fakeTeamOps struct {
+	addTeam      func(ctx context.Context, team domain.Team) (*domain.Team, error)
+	findTeam     func(ctx context.Context, teamName string) (*domain.Team, error)
+	teamTree     func(ctx context.Context, teamName string) ([]domain.TeamTreeNode, error)
+	moveTeam     func(ctx context.Context, req domain.SetTeamParentRequest) (*domain.Team, error)
+	addMember    func(ctx context.Context, req domain.TeamMembershipRequest) (*domain.Team, error)
+	removeMember func(ctx context.Context, req domain.TeamMembershipRequest) (*domain.Team, error)
+}
+
+func (f *fakeTeamOps) CreateTeam(ctx context.Context, team domain.Team) (*domain.Team, error) {
+	return f.addTeam(ctx, team)
+}
+
+func (f *fakeTeamOps) GetTeam(ctx context.Context, teamName string) (*domain.Team, error) {
+	return f.findTeam(ctx, teamName)
+}
+
+func (f *fakeTeamOps) GetTeamTree(ctx context.Context, teamName string) ([]domain.TeamTreeNode, error) {
+	return f.teamTree(ctx, teamName)
+}
+
+func (f *fakeTeamOps) SetTeamParent(ctx context.Context, req domain.SetTeamParentRequest) (*domain.Team, error) {
+	return f.moveTeam(ctx, req)
+}
+
+func (f *fakeTeamOps) AddTeamMember(ctx context.Context, req domain.TeamMembershipRequest) (*domain.Team, error) {
+	return f.addMember(ctx, req)
+}
+
+func (f *fakeTeamOps) RemoveTeamMember(ctx context.Context, req domain.TeamMembershipRequest) (*domain.Team, error) {
+	return f.removeMember(ctx, req)
+}
+
+type fakeUserOps struct {
+	setActive   func(ctx context.Context, req domain.SetIsActiveRequest) (*domain.User, error)
+	setPrimary  func(ctx context.Context, req domain.SetPrimaryTeamRequest) (*domain.User, error)
+	listReviews func(ctx context.Context, userID string) ([]domain.PullRequestShort, error)
+}
+
+func (f *fakeUserOps) SetIsActive(ctx context.Context, req domain.SetIsActiveRequest) (*domain.User, error) {
+	return f.setActive(ctx, req)
+}
+
+func (f *fakeUserOps) SetPrimaryTeam(ctx context.Context, req domain.SetPrimaryTeamRequest) (*domain.User, error) {
+	return f.setPrimary(ctx, req)
+}
+
+func (f *fakeUserOps) GetUserReviews(ctx context.Context, userID string) ([]domain.PullRequestShort, error) {
+	return f.listReviews(ctx, userID)
+}
+
+type fakePullRequestOps struct {
+	createPR   func(ctx context.Context, req domain.CreatePullRequestRequest) (*domain.PullRequestResponse, error)
+	mergePR    func(ctx context.Context, prID string) (*domain.PullRequestResponse, error)
+	reassignPR func(ctx context.Context, req domain.ReassignReviewerRequest) (*domain.ReassignReviewerResponse, error)
+}
+
+func (f *fakePullRequestOps) CreatePullRequest(ctx context.Context, req domain.CreatePullRequestRequest) (*domain.PullRequestResponse, error) {
+	return f.createPR(ctx, req)
+}
+
+func (f *fakePullRequestOps) MergePullRequest(ctx context.Context, prID string) (*domain.PullRequestResponse, error) {
+	return f.mergePR(ctx, prID)
+}
+
+func (f *fakePullRequestOps) ReassignReviewer(ctx context.Context, req domain.ReassignReviewerRequest) (*domain.ReassignReviewerResponse, error) {
+	return f.reassignPR(ctx, req)
+}
+
+// newTestConn поднимает NewServer поверх bufconn и возвращает соединение клиента.
+// Незаданные операции fake-реализаций не вызываются: тест упадёт на nil-функции
+func newTestConn(t *testing.T, teams TeamOperations, users UserOperations, prs PullRequestOperations) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(teams, users, prs)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func authorizedContext() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token")
+}
+
+// requireStatus проверяет код gRPC и причину в ErrorInfo
+func requireStatus(t *testing.T, err error, code codes.Code, reason domain.ErrorResponseErrorCode) {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, code, st.Code())
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	require.Equal(t, string(reason), info.GetReason())
+	require.Equal(t, errorDomain, info.GetDomain())
+}
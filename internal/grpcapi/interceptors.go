@@ -0,0 +1,37 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthInterceptor - аналог middleware.AuthMiddleware: вызов без метаданных authorization отклоняется
+func AuthInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if len(md.Get("authorization")) == 0 || md.Get("authorization")[0] == "" {
+			return nil, status.Error(codes.Unauthenticated, "authorization metadata is required")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryInterceptor - аналог gin.Recovery: паника в обработчике превращается в INTERNAL, сервер продолжает работу
+func RecoveryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Logger.Errorw("panic in gRPC handler", "method", info.FullMethod, "panic", r)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
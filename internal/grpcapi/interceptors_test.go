@@ -0,0 +1,55 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/generated/reviewerpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	called := false
+	conn := newTestConn(t, &fakeTeamOps{
+		findTeam: func(context.Context, string) (*domain.Team, error) {
+			called = true
+			return &domain.Team{TeamName: "backend"}, nil
+		},
+	}, nil, nil)
+	client := reviewerpb.NewTeamServiceClient(conn)
+
+	t.Run("missing authorization", func(t *testing.T) {
+		_, err := client.GetTeam(context.Background(), &reviewerpb.GetTeamRequest{TeamName: "backend"})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		assert.False(t, called)
+	})
+
+	t.Run("authorized", func(t *testing.T) {
+		team, err := client.GetTeam(authorizedContext(), &reviewerpb.GetTeamRequest{TeamName: "backend"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "backend", team.GetTeamName())
+		assert.True(t, called)
+	})
+}
+
+func TestRecoveryInterceptor(t *testing.T) {
+	conn := newTestConn(t, &fakeTeamOps{
+		findTeam: func(context.Context, string) (*domain.Team, error) {
+			panic("boom")
+		},
+	}, nil, nil)
+	client := reviewerpb.NewTeamServiceClient(conn)
+
+	_, err := client.GetTeam(authorizedContext(), &reviewerpb.GetTeamRequest{TeamName: "backend"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	// сервер пережил панику и отвечает на следующие вызовы
+	_, err = client.GetTeam(context.Background(), &reviewerpb.GetTeamRequest{TeamName: "backend"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
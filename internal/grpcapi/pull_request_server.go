@@ -0,0 +1,78 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/generated/reviewerpb"
+)
+
+type pullRequestServer struct {
+	reviewerpb.UnimplementedPullRequestServiceServer
+	prs PullRequestOperations
+}
+
+func (s *pullRequestServer) CreatePullRequest(
+	ctx context.Context,
+	req *reviewerpb.CreatePullRequestRequest,
+) (*reviewerpb.PullRequestResponse, error) {
+	if req.GetPullRequestId() == "" || req.GetPullRequestName() == "" || req.GetAuthorId() == "" {
+		return nil, invalidArgument("pull_request_id, pull_request_name and author_id are required")
+	}
+
+	resp, err := s.prs.CreatePullRequest(ctx, domain.CreatePullRequestRequest{
+		PullRequestID:   req.GetPullRequestId(),
+		PullRequestName: req.GetPullRequestName(),
+		AuthorID:        req.GetAuthorId(),
+	})
+	if err != nil {
+		return nil, toStatus(err, "error creating PR: ", domain.ErrCreatePRMsg)
+	}
+
+	return &reviewerpb.PullRequestResponse{
+		Pr:          pullRequestToProto(resp.PR),
+		Assignments: assignmentsToProto(resp.Assignments),
+	}, nil
+}
+
+func (s *pullRequestServer) MergePullRequest(
+	ctx context.Context,
+	req *reviewerpb.MergePullRequestRequest,
+) (*reviewerpb.PullRequestResponse, error) {
+	if req.GetPullRequestId() == "" {
+		return nil, invalidArgument("pull_request_id is required")
+	}
+
+	resp, err := s.prs.MergePullRequest(ctx, req.GetPullRequestId())
+	if err != nil {
+		return nil, toStatus(err, "error merging PR: ", domain.ErrMergePRMsg)
+	}
+
+	return &reviewerpb.PullRequestResponse{
+		Pr:          pullRequestToProto(resp.PR),
+		Assignments: assignmentsToProto(resp.Assignments),
+	}, nil
+}
+
+func (s *pullRequestServer) ReassignReviewer(
+	ctx context.Context,
+	req *reviewerpb.ReassignReviewerRequest,
+) (*reviewerpb.ReassignReviewerResponse, error) {
+	if req.GetPullRequestId() == "" || req.GetOldUserId() == "" {
+		return nil, invalidArgument("pull_request_id and old_user_id are required")
+	}
+
+	resp, err := s.prs.ReassignReviewer(ctx, domain.ReassignReviewerRequest{
+		PullRequestID: req.GetPullRequestId(),
+		OldUserID:     req.GetOldUserId(),
+	})
+	if err != nil {
+		return nil, toStatus(err, "error reassigning reviewer: ", domain.ErrReassignReviewerMsg)
+	}
+
+	return &reviewerpb.ReassignReviewerResponse{
+		Pr:          pullRequestToProto(resp.PR),
+		ReplacedBy:  resp.ReplacedBy,
+		Assignments: assignmentsToProto(resp.Assignments),
+	}, nil
+}
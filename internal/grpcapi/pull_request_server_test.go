@@ -0,0 +1,146 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/generated/reviewerpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestPullRequestServer_CreatePullRequest(t *testing.T) {
+	createdAt := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	seed := int64(7)
+	needMore := true
+
+	conn := newTestConn(t, nil, nil, &fakePullRequestOps{
+		createPR: func(_ context.Context, req domain.CreatePullRequestRequest) (*domain.PullRequestResponse, error) {
+			assert.Equal(t, domain.CreatePullRequestRequest{
+				PullRequestID:   "pr-1",
+				PullRequestName: "Add search",
+				AuthorID:        "u1",
+			}, req)
+			return &domain.PullRequestResponse{
+				PR: &domain.PullRequest{
+					PullRequestId:     "pr-1",
+					PullRequestName:   "Add search",
+					AuthorId:          "u1",
+					Status:            domain.PullRequestStatusOPEN,
+					AssignedReviewers: []string{"u2"},
+					NeedMoreReviewers: &needMore,
+					CreatedAt:         &createdAt,
+				},
+				Assignments: []domain.ReviewerAssignment{{
+					ReviewerID: "u2",
+					AssignedAt: &createdAt,
+					Meta: &domain.AssignmentMeta{
+						Strategy:       domain.AssignmentStrategyRandom,
+						CandidateCount: 1,
+						Excluded:       []domain.ExcludedReviewer{{UserID: "u3", Reason: domain.ExclusionReasonInactive}},
+						Seed:           &seed,
+					},
+				}},
+			}, nil
+		},
+	})
+
+	resp, err := reviewerpb.NewPullRequestServiceClient(conn).CreatePullRequest(authorizedContext(), &reviewerpb.CreatePullRequestRequest{
+		PullRequestId:   "pr-1",
+		PullRequestName: "Add search",
+		AuthorId:        "u1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, reviewerpb.PullRequestStatus_PULL_REQUEST_STATUS_OPEN, resp.GetPr().GetStatus())
+	assert.Equal(t, []string{"u2"}, resp.GetPr().GetAssignedReviewers())
+	assert.True(t, resp.GetPr().GetNeedMoreReviewers())
+	assert.True(t, createdAt.Equal(resp.GetPr().GetCreatedAt().AsTime()))
+	assert.Nil(t, resp.GetPr().GetMergedAt())
+	require.Len(t, resp.GetAssignments(), 1)
+	meta := resp.GetAssignments()[0].GetAssignmentMeta()
+	assert.Equal(t, "RANDOM", meta.GetStrategy())
+	assert.Equal(t, int64(7), meta.GetSeed())
+	require.Len(t, meta.GetExcluded(), 1)
+	assert.Equal(t, "INACTIVE", meta.GetExcluded()[0].GetReason())
+}
+
+func TestPullRequestServer_MergePullRequest(t *testing.T) {
+	conn := newTestConn(t, nil, nil, &fakePullRequestOps{
+		mergePR: func(_ context.Context, prID string) (*domain.PullRequestResponse, error) {
+			return nil, domain.NewError(domain.PrClosed, "cannot merge closed PR")
+		},
+	})
+
+	_, err := reviewerpb.NewPullRequestServiceClient(conn).MergePullRequest(authorizedContext(), &reviewerpb.MergePullRequestRequest{
+		PullRequestId: "pr-1",
+	})
+
+	requireStatus(t, err, codes.FailedPrecondition, domain.PrClosed)
+}
+
+func TestPullRequestServer_ReassignReviewer(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		conn := newTestConn(t, nil, nil, &fakePullRequestOps{
+			reassignPR: func(_ context.Context, req domain.ReassignReviewerRequest) (*domain.ReassignReviewerResponse, error) {
+				assert.Equal(t, domain.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: "u2"}, req)
+				return &domain.ReassignReviewerResponse{
+					PR:         &domain.PullRequest{PullRequestId: "pr-1", Status: domain.PullRequestStatusOPEN, AssignedReviewers: []string{"u3"}},
+					ReplacedBy: "u3",
+				}, nil
+			},
+		})
+
+		resp, err := reviewerpb.NewPullRequestServiceClient(conn).ReassignReviewer(authorizedContext(), &reviewerpb.ReassignReviewerRequest{
+			PullRequestId: "pr-1",
+			OldUserId:     "u2",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "u3", resp.GetReplacedBy())
+		assert.Equal(t, []string{"u3"}, resp.GetPr().GetAssignedReviewers())
+	})
+
+	t.Run("merged PR", func(t *testing.T) {
+		conn := newTestConn(t, nil, nil, &fakePullRequestOps{
+			reassignPR: func(context.Context, domain.ReassignReviewerRequest) (*domain.ReassignReviewerResponse, error) {
+				return nil, domain.NewError(domain.PrMerged, "cannot reassign on merged PR")
+			},
+		})
+
+		_, err := reviewerpb.NewPullRequestServiceClient(conn).ReassignReviewer(authorizedContext(), &reviewerpb.ReassignReviewerRequest{
+			PullRequestId: "pr-1",
+			OldUserId:     "u2",
+		})
+
+		requireStatus(t, err, codes.FailedPrecondition, domain.PrMerged)
+	})
+
+	t.Run("no candidate", func(t *testing.T) {
+		conn := newTestConn(t, nil, nil, &fakePullRequestOps{
+			reassignPR: func(context.Context, domain.ReassignReviewerRequest) (*domain.ReassignReviewerResponse, error) {
+				return nil, domain.NewError(domain.NoCandidate, "no active replacement candidate in team")
+			},
+		})
+
+		_, err := reviewerpb.NewPullRequestServiceClient(conn).ReassignReviewer(authorizedContext(), &reviewerpb.ReassignReviewerRequest{
+			PullRequestId: "pr-1",
+			OldUserId:     "u2",
+		})
+
+		requireStatus(t, err, codes.FailedPrecondition, domain.NoCandidate)
+	})
+
+	t.Run("missing old_user_id", func(t *testing.T) {
+		conn := newTestConn(t, nil, nil, &fakePullRequestOps{})
+
+		_, err := reviewerpb.NewPullRequestServiceClient(conn).ReassignReviewer(authorizedContext(), &reviewerpb.ReassignReviewerRequest{
+			PullRequestId: "pr-1",
+		})
+
+		requireStatus(t, err, codes.InvalidArgument, domain.InvalidRequest)
+	})
+}
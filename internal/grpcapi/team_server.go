@@ -0,0 +1,103 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/generated/reviewerpb"
+)
+
+type teamServer struct {
+	reviewerpb.UnimplementedTeamServiceServer
+	teams TeamOperations
+}
+
+func (s *teamServer) AddTeam(ctx context.Context, req *reviewerpb.AddTeamRequest) (*reviewerpb.TeamResponse, error) {
+	if req.GetTeam().GetTeamName() == "" {
+		return nil, invalidArgument("team.team_name is required")
+	}
+
+	team, err := s.teams.CreateTeam(ctx, teamFromProto(req.GetTeam()))
+	if err != nil {
+		return nil, toStatus(err, "error creating team with members: ", domain.ErrCreateTeamMsg)
+	}
+
+	return &reviewerpb.TeamResponse{Team: teamToProto(team)}, nil
+}
+
+func (s *teamServer) GetTeam(ctx context.Context, req *reviewerpb.GetTeamRequest) (*reviewerpb.Team, error) {
+	if req.GetTeamName() == "" {
+		return nil, invalidArgument("team_name is required")
+	}
+
+	team, err := s.teams.GetTeam(ctx, req.GetTeamName())
+	if err != nil {
+		return nil, toStatus(err, "error getting team: ", domain.ErrGetTeamMsg)
+	}
+
+	return teamToProto(team), nil
+}
+
+func (s *teamServer) GetTeamTree(ctx context.Context, req *reviewerpb.GetTeamTreeRequest) (*reviewerpb.GetTeamTreeResponse, error) {
+	roots, err := s.teams.GetTeamTree(ctx, req.GetTeamName())
+	if err != nil {
+		return nil, toStatus(err, "error getting teams hierarchy: ", domain.ErrGetTeamTreeMsg)
+	}
+
+	return &reviewerpb.GetTeamTreeResponse{Teams: teamTreeToProto(roots)}, nil
+}
+
+func (s *teamServer) SetTeamParent(ctx context.Context, req *reviewerpb.SetTeamParentRequest) (*reviewerpb.TeamResponse, error) {
+	if req.GetTeamName() == "" {
+		return nil, invalidArgument("team_name is required")
+	}
+
+	team, err := s.teams.SetTeamParent(ctx, domain.SetTeamParentRequest{
+		TeamName:       req.GetTeamName(),
+		ParentTeamName: req.ParentTeamName,
+	})
+	if err != nil {
+		return nil, toStatus(err, "error setting team parent: ", domain.ErrSetTeamParentMsg)
+	}
+
+	return &reviewerpb.TeamResponse{Team: teamToProto(team)}, nil
+}
+
+func (s *teamServer) AddTeamMember(ctx context.Context, req *reviewerpb.TeamMembershipRequest) (*reviewerpb.TeamResponse, error) {
+	membership, err := membershipFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := s.teams.AddTeamMember(ctx, membership)
+	if err != nil {
+		return nil, toStatus(err, "error adding team member: ", domain.ErrTeamMembershipMsg)
+	}
+
+	return &reviewerpb.TeamResponse{Team: teamToProto(team)}, nil
+}
+
+func (s *teamServer) RemoveTeamMember(ctx context.Context, req *reviewerpb.TeamMembershipRequest) (*reviewerpb.TeamResponse, error) {
+	membership, err := membershipFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := s.teams.RemoveTeamMember(ctx, membership)
+	if err != nil {
+		return nil, toStatus(err, "error removing team member: ", domain.ErrTeamMembershipMsg)
+	}
+
+	return &reviewerpb.TeamResponse{Team: teamToProto(team)}, nil
+}
+
+func membershipFromProto(req *reviewerpb.TeamMembershipRequest) (domain.TeamMembershipRequest, error) {
+	if req.GetTeamName() == "" || req.GetUserId() == "" {
+		return domain.TeamMembershipRequest{}, invalidArgument("team_name and user_id are required")
+	}
+
+	return domain.TeamMembershipRequest{
+		TeamName: req.GetTeamName(),
+		UserID:   req.GetUserId(),
+	}, nil
+}
@@ -0,0 +1,131 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/generated/reviewerpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTeamServer_AddTeam(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var got domain.Team
+		conn := newTestConn(t, &fakeTeamOps{
+			addTeam: func(_ context.Context, team domain.Team) (*domain.Team, error) {
+				got = team
+				return &team, nil
+			},
+		}, nil, nil)
+
+		resp, err := reviewerpb.NewTeamServiceClient(conn).AddTeam(authorizedContext(), &reviewerpb.AddTeamRequest{
+			Team: &reviewerpb.Team{
+				TeamName:       "backend",
+				ParentTeamName: proto.String("platform"),
+				Members: []*reviewerpb.TeamMember{
+					{UserId: "u1", Username: "Alice", IsActive: true},
+				},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "backend", got.TeamName)
+		require.NotNil(t, got.ParentTeamName)
+		assert.Equal(t, "platform", *got.ParentTeamName)
+		assert.Equal(t, []domain.TeamMember{{UserId: "u1", Username: "Alice", IsActive: true}}, got.Members)
+		assert.Equal(t, "platform", resp.GetTeam().GetParentTeamName())
+		require.Len(t, resp.GetTeam().GetMembers(), 1)
+		assert.Equal(t, "Alice", resp.GetTeam().GetMembers()[0].GetUsername())
+	})
+
+	t.Run("team exists", func(t *testing.T) {
+		conn := newTestConn(t, &fakeTeamOps{
+			addTeam: func(context.Context, domain.Team) (*domain.Team, error) {
+				return nil, domain.NewError(domain.TeamExists, "team_name already exists")
+			},
+		}, nil, nil)
+
+		_, err := reviewerpb.NewTeamServiceClient(conn).AddTeam(authorizedContext(), &reviewerpb.AddTeamRequest{
+			Team: &reviewerpb.Team{TeamName: "backend"},
+		})
+
+		requireStatus(t, err, codes.AlreadyExists, domain.TeamExists)
+	})
+
+	t.Run("missing team name", func(t *testing.T) {
+		conn := newTestConn(t, &fakeTeamOps{}, nil, nil)
+
+		_, err := reviewerpb.NewTeamServiceClient(conn).AddTeam(authorizedContext(), &reviewerpb.AddTeamRequest{})
+
+		requireStatus(t, err, codes.InvalidArgument, domain.InvalidRequest)
+	})
+}
+
+func TestTeamServer_GetTeamTree(t *testing.T) {
+	conn := newTestConn(t, &fakeTeamOps{
+		teamTree: func(_ context.Context, teamName string) ([]domain.TeamTreeNode, error) {
+			assert.Equal(t, "platform", teamName)
+			return []domain.TeamTreeNode{{
+				TeamName: "platform",
+				Children: []domain.TeamTreeNode{{TeamName: "backend", ParentTeamName: proto.String("platform")}},
+			}}, nil
+		},
+	}, nil, nil)
+
+	resp, err := reviewerpb.NewTeamServiceClient(conn).GetTeamTree(authorizedContext(), &reviewerpb.GetTeamTreeRequest{
+		TeamName: "platform",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.GetTeams(), 1)
+	require.Len(t, resp.GetTeams()[0].GetChildren(), 1)
+	assert.Equal(t, "backend", resp.GetTeams()[0].GetChildren()[0].GetTeamName())
+}
+
+func TestTeamServer_RemoveTeamMember(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		conn := newTestConn(t, &fakeTeamOps{
+			removeMember: func(_ context.Context, req domain.TeamMembershipRequest) (*domain.Team, error) {
+				assert.Equal(t, domain.TeamMembershipRequest{TeamName: "backend", UserID: "u1"}, req)
+				return nil, domain.NewError(domain.NotFound, "user is not a member of the team")
+			},
+		}, nil, nil)
+
+		_, err := reviewerpb.NewTeamServiceClient(conn).RemoveTeamMember(authorizedContext(), &reviewerpb.TeamMembershipRequest{
+			TeamName: "backend",
+			UserId:   "u1",
+		})
+
+		requireStatus(t, err, codes.NotFound, domain.NotFound)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		conn := newTestConn(t, &fakeTeamOps{
+			removeMember: func(context.Context, domain.TeamMembershipRequest) (*domain.Team, error) {
+				return nil, errors.New("db error")
+			},
+		}, nil, nil)
+
+		_, err := reviewerpb.NewTeamServiceClient(conn).RemoveTeamMember(authorizedContext(), &reviewerpb.TeamMembershipRequest{
+			TeamName: "backend",
+			UserId:   "u1",
+		})
+
+		requireStatus(t, err, codes.Internal, domain.InternalError)
+	})
+
+	t.Run("missing user_id", func(t *testing.T) {
+		conn := newTestConn(t, &fakeTeamOps{}, nil, nil)
+
+		_, err := reviewerpb.NewTeamServiceClient(conn).RemoveTeamMember(authorizedContext(), &reviewerpb.TeamMembershipRequest{
+			TeamName: "backend",
+		})
+
+		requireStatus(t, err, codes.InvalidArgument, domain.InvalidRequest)
+	})
+}
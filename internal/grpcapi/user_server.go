@@ -0,0 +1,61 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/generated/reviewerpb"
+)
+
+type userServer struct {
+	reviewerpb.UnimplementedUserServiceServer
+	users UserOperations
+}
+
+func (s *userServer) SetIsActive(ctx context.Context, req *reviewerpb.SetIsActiveRequest) (*reviewerpb.UserResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, invalidArgument("user_id is required")
+	}
+
+	user, err := s.users.SetIsActive(ctx, domain.SetIsActiveRequest{
+		UserID:   req.GetUserId(),
+		IsActive: req.GetIsActive(),
+	})
+	if err != nil {
+		return nil, toStatus(err, "error setting user active status: ", domain.ErrSetActiveMsg)
+	}
+
+	return &reviewerpb.UserResponse{User: userToProto(user)}, nil
+}
+
+func (s *userServer) SetPrimaryTeam(ctx context.Context, req *reviewerpb.SetPrimaryTeamRequest) (*reviewerpb.UserResponse, error) {
+	if req.GetUserId() == "" || req.GetTeamName() == "" {
+		return nil, invalidArgument("user_id and team_name are required")
+	}
+
+	user, err := s.users.SetPrimaryTeam(ctx, domain.SetPrimaryTeamRequest{
+		UserID:   req.GetUserId(),
+		TeamName: req.GetTeamName(),
+	})
+	if err != nil {
+		return nil, toStatus(err, "error setting primary team: ", domain.ErrSetPrimaryTeamMsg)
+	}
+
+	return &reviewerpb.UserResponse{User: userToProto(user)}, nil
+}
+
+func (s *userServer) GetReview(ctx context.Context, req *reviewerpb.GetReviewRequest) (*reviewerpb.GetReviewResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, invalidArgument("user_id is required")
+	}
+
+	prs, err := s.users.GetUserReviews(ctx, req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err, "error getting user reviews: ", domain.ErrGetUserReviewsMsg)
+	}
+
+	return &reviewerpb.GetReviewResponse{
+		UserId:       req.GetUserId(),
+		PullRequests: pullRequestsShortToProto(prs),
+	}, nil
+}
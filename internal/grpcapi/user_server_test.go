@@ -0,0 +1,68 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/generated/reviewerpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestUserServer_SetIsActive(t *testing.T) {
+	conn := newTestConn(t, nil, &fakeUserOps{
+		setActive: func(_ context.Context, req domain.SetIsActiveRequest) (*domain.User, error) {
+			assert.Equal(t, domain.SetIsActiveRequest{UserID: "u1", IsActive: false}, req)
+			return &domain.User{UserId: "u1", Username: "Alice", TeamName: "backend", TeamNames: []string{"backend"}}, nil
+		},
+	}, nil)
+
+	resp, err := reviewerpb.NewUserServiceClient(conn).SetIsActive(authorizedContext(), &reviewerpb.SetIsActiveRequest{
+		UserId: "u1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "u1", resp.GetUser().GetUserId())
+	assert.False(t, resp.GetUser().GetIsActive())
+	assert.Equal(t, []string{"backend"}, resp.GetUser().GetTeamNames())
+}
+
+func TestUserServer_GetReview(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		conn := newTestConn(t, nil, &fakeUserOps{
+			listReviews: func(_ context.Context, userID string) ([]domain.PullRequestShort, error) {
+				return []domain.PullRequestShort{{
+					PullRequestId:   "pr-1",
+					PullRequestName: "Add search",
+					AuthorId:        "u2",
+					Status:          domain.PullRequestStatusOPEN,
+				}}, nil
+			},
+		}, nil)
+
+		resp, err := reviewerpb.NewUserServiceClient(conn).GetReview(authorizedContext(), &reviewerpb.GetReviewRequest{
+			UserId: "u1",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "u1", resp.GetUserId())
+		require.Len(t, resp.GetPullRequests(), 1)
+		assert.Equal(t, reviewerpb.PullRequestStatus_PULL_REQUEST_STATUS_OPEN, resp.GetPullRequests()[0].GetStatus())
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		conn := newTestConn(t, nil, &fakeUserOps{
+			listReviews: func(context.Context, string) ([]domain.PullRequestShort, error) {
+				return nil, domain.NewError(domain.NotFound, "user not found")
+			},
+		}, nil)
+
+		_, err := reviewerpb.NewUserServiceClient(conn).GetReview(authorizedContext(), &reviewerpb.GetReviewRequest{
+			UserId: "u1",
+		})
+
+		requireStatus(t, err, codes.NotFound, domain.NotFound)
+	})
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// AccessLogMiddleware пишет одну структурированную строку на запрос. Запросы к skipPaths
+// (например, скрейпы /metrics) не логируются
+func AccessLogMiddleware(skipPaths ...string) gin.HandlerFunc {
+	skip := make(map[string]struct{}, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		if _, ok := skip[c.Request.URL.Path]; ok {
+			return
+		}
+
+		fields := []interface{}{
+			"method", c.Request.Method,
+			"route", routeTemplate(c),
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"client_ip", c.ClientIP(),
+			"client_id", clientID(c.GetHeader("Authorization")),
+			"user_agent", c.Request.UserAgent(),
+			"response_size", c.Writer.Size(),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, "errors", c.Errors.String())
+		}
+
+		logger.Logger.Infow("http request", fields...)
+	}
+}
+
+// clientID - короткий отпечаток Authorization: позволяет различать клиентов в логах, не раскрывая токен
+func clientID(authHeader string) string {
+	if authHeader == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(authHeader))
+	return hex.EncodeToString(sum[:4])
+}
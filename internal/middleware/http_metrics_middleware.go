@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/pkg/metrics"
+)
+
+// HTTPMetricsMiddleware пишет http_requests_total и http_request_duration_seconds.
+// Маршрут берётся шаблоном (/team/get, а не конкретный URL), чтобы число меток было ограничено
+func HTTPMetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := routeTemplate(c)
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequestsTotal.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDurationSeconds.WithLabelValues(c.Request.Method, route, status).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+func routeTemplate(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return metrics.HTTPRouteUnmatched
+}
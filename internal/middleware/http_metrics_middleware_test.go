@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/pkg/metrics"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	logger.Logger = zap.NewNop().Sugar()
+}
+
+func newTestRouter() *gin.Engine {
+	router := gin.New()
+	router.Use(AccessLogMiddleware("/metrics"))
+	router.Use(HTTPMetricsMiddleware())
+	router.Use(gin.Recovery())
+
+	router.GET("/team/get", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/users/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	router.GET("/panic", func(c *gin.Context) { panic("boom") })
+	router.GET("/metrics", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestHTTPMetricsMiddleware(t *testing.T) {
+	router := newTestRouter()
+
+	t.Run("labels by route template", func(t *testing.T) {
+		counter := metrics.HTTPRequestsTotal.WithLabelValues(http.MethodGet, "/users/:id", "404")
+		before := testutil.ToFloat64(counter)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/u1", nil))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/u2", nil))
+
+		assert.Equal(t, before+2, testutil.ToFloat64(counter))
+	})
+
+	t.Run("unmatched route", func(t *testing.T) {
+		counter := metrics.HTTPRequestsTotal.WithLabelValues(http.MethodGet, metrics.HTTPRouteUnmatched, "404")
+		before := testutil.ToFloat64(counter)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/no/such/path", nil))
+
+		assert.Equal(t, before+1, testutil.ToFloat64(counter))
+	})
+
+	t.Run("panic is counted as 500", func(t *testing.T) {
+		counter := metrics.HTTPRequestsTotal.WithLabelValues(http.MethodGet, "/panic", "500")
+		before := testutil.ToFloat64(counter)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		assert.Positive(t, testutil.CollectAndCount(metrics.HTTPRequestDurationSeconds))
+	})
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger.Logger = zap.New(core).Sugar()
+	defer func() { logger.Logger = zap.NewNop().Sugar() }()
+
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/team/get?team_name=backend", nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// Скрейп /metrics не логируется
+	entries := logs.All()
+	if !assert.Len(t, entries, 1) {
+		return
+	}
+
+	fields := entries[0].ContextMap()
+	assert.Equal(t, http.MethodGet, fields["method"])
+	assert.Equal(t, "/team/get", fields["route"])
+	assert.EqualValues(t, http.StatusOK, fields["status"])
+	assert.Contains(t, fields, "latency_ms")
+	assert.Equal(t, clientID("Bearer secret-token"), fields["client_id"])
+
+	// Токен в лог не попадает
+	for _, value := range fields {
+		if s, ok := value.(string); ok {
+			assert.NotContains(t, s, "secret-token")
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"google.golang.org/grpc"
+)
+
+// GRPCServer - gRPC API на отдельном порту (GRPC_PORT); работает рядом с APIServer на тех же сервисах
+type GRPCServer struct {
+	grpcServer *grpc.Server
+	addr       string
+}
+
+func NewGRPCServer(grpcServer *grpc.Server) *GRPCServer {
+	return &GRPCServer{
+		grpcServer: grpcServer,
+		addr:       ":" + os.Getenv("GRPC_PORT"),
+	}
+}
+
+func (s *GRPCServer) Start() {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		logger.Logger.Fatal(err)
+	}
+
+	if err = s.grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		logger.Logger.Fatal(err)
+	}
+}
+
+// Shutdown дожидается завершения текущих вызовов (GracefulStop). Если ctx истекает раньше,
+// оставшиеся вызовы обрываются через Stop, как http.Server.Shutdown по таймауту
+func (s *GRPCServer) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		logger.Logger.Info("gRPC shutdown completed before timeout.")
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		<-stopped
+		logger.Logger.Error("timeout shutting down gRPC server")
+		return ctx.Err()
+	}
+}
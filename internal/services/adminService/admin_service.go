@@ -0,0 +1,20 @@
+package adminService
+
+import (
+	"github.com/nedokyrill/avito-pr-api/internal/storage"
+)
+
+type AdminServiceImpl struct {
+	teamRepo        storage.TeamRepositoryInterface
+	prReviewersRepo storage.PrReviewersRepositoryInterface
+}
+
+func NewAdminService(
+	teamRepo storage.TeamRepositoryInterface,
+	prReviewersRepo storage.PrReviewersRepositoryInterface,
+) *AdminServiceImpl {
+	return &AdminServiceImpl{
+		teamRepo:        teamRepo,
+		prReviewersRepo: prReviewersRepo,
+	}
+}
@@ -0,0 +1,46 @@
+package adminService
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// ExportRoster выгружает все команды и участников в том же формате, который принимает ImportRoster
+func (s *AdminServiceImpl) ExportRoster(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	format, err := resolveRosterFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			err.Error(),
+		))
+		return
+	}
+
+	teams, err := s.teamRepo.GetRoster(ctx)
+	if err != nil {
+		logger.Logger.Error("error getting roster: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrExportRosterMsg,
+		))
+		return
+	}
+
+	data, err := encodeRoster(format, &domain.Roster{Teams: teams})
+	if err != nil {
+		logger.Logger.Error("error encoding roster: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrExportRosterMsg,
+		))
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=roster."+string(format))
+	c.Data(http.StatusOK, rosterContentType(format), data)
+}
@@ -0,0 +1,67 @@
+package adminService
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminService_ExportRoster(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+	service := NewAdminService(mockTeamRepo, mocks.NewMockPrReviewersRepositoryInterface(ctrl))
+
+	t.Run("export as csv", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/admin/export?format=csv", nil)
+
+		mockTeamRepo.EXPECT().GetRoster(gomock.Any()).Return(testCurrentRoster(), nil)
+
+		service.ExportRoster(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+		assert.Equal(t, "team_name,parent_team_name,user_id,username,is_active\n"+
+			"Backend,,u1,Alice,true\n"+
+			"Backend,,u2,Bob,true\n"+
+			"Frontend,,u3,Charlie,true\n", w.Body.String())
+	})
+
+	t.Run("export as yaml by default", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/admin/export", nil)
+
+		mockTeamRepo.EXPECT().GetRoster(gomock.Any()).Return(testCurrentRoster(), nil)
+
+		service.ExportRoster(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		roster, err := decodeRoster(domain.RosterFormatYAML, w.Body.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, testCurrentRoster(), roster.Teams)
+	})
+
+	t.Run("error getting roster", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/admin/export", nil)
+
+		mockTeamRepo.EXPECT().GetRoster(gomock.Any()).Return(nil, errors.New("database error"))
+
+		service.ExportRoster(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
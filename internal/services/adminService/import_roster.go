@@ -0,0 +1,319 @@
+package adminService
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// ImportRoster приводит команды из документа (YAML или CSV) к описанному составу.
+// С dry_run=true только возвращает план изменений, ничего не записывая
+func (s *AdminServiceImpl) ImportRoster(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	format, err := resolveRosterFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			err.Error(),
+		))
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, domain.MaxRosterDocumentSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid request body",
+		))
+		return
+	}
+
+	roster, err := decodeRoster(format, data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid roster document: "+err.Error(),
+		))
+		return
+	}
+
+	if err = validateRoster(roster); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			err.Error(),
+		))
+		return
+	}
+
+	current, err := s.teamRepo.GetRoster(ctx)
+	if err != nil {
+		logger.Logger.Error("error getting roster: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrImportRosterMsg,
+		))
+		return
+	}
+
+	plan, err := buildRosterImportPlan(current, roster)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			err.Error(),
+		))
+		return
+	}
+	plan.DryRun = dryRun
+
+	if err = s.planRosterReassignments(ctx, roster, plan); err != nil {
+		logger.Logger.Error("error planning roster reassignments: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrImportRosterMsg,
+		))
+		return
+	}
+
+	if !dryRun && !plan.IsEmpty() {
+		if err = s.teamRepo.ApplyRosterImportPlan(ctx, plan); err != nil {
+			if errors.Is(err, teamStorage.ErrPlanStale) {
+				c.JSON(http.StatusConflict, domain.NewErrorResponse(
+					domain.PlanStale,
+					err.Error(),
+				))
+				return
+			}
+
+			logger.Logger.Error("error applying roster import plan: ", err)
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+				domain.InternalError,
+				domain.ErrImportRosterMsg,
+			))
+			return
+		}
+
+		logger.Logger.Infow("roster imported",
+			"created_teams", len(plan.CreatedTeams),
+			"updated_teams", len(plan.UpdatedTeams),
+			"created_users", len(plan.CreatedUsers),
+			"updated_users", len(plan.UpdatedUsers),
+			"added_memberships", len(plan.AddedMemberships),
+			"removed_memberships", len(plan.RemovedMemberships),
+			"deactivated_users", len(plan.DeactivatedUsers),
+			"reassignments", len(plan.Reassignments))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"plan": plan,
+	})
+}
+
+// validateRoster проверяет документ без обращения к БД: обязательные поля, дубликаты
+// и согласованность данных пользователя, который указан в нескольких командах
+func validateRoster(roster *domain.Roster) error {
+	if len(roster.Teams) == 0 {
+		return errors.New("roster has no teams")
+	}
+
+	teams := make(map[string]struct{}, len(roster.Teams))
+	users := make(map[string]domain.TeamMember)
+
+	for _, team := range roster.Teams {
+		if team.TeamName == "" {
+			return errors.New("team_name is required")
+		}
+		if _, ok := teams[team.TeamName]; ok {
+			return fmt.Errorf("team %q is listed more than once", team.TeamName)
+		}
+		teams[team.TeamName] = struct{}{}
+
+		if team.ParentTeamName != nil && *team.ParentTeamName == team.TeamName {
+			return fmt.Errorf("team %q cannot be its own parent", team.TeamName)
+		}
+
+		members := make(map[string]struct{}, len(team.Members))
+		for _, member := range team.Members {
+			if member.UserId == "" || member.Username == "" {
+				return fmt.Errorf("team %q: user_id and username are required", team.TeamName)
+			}
+			if _, ok := members[member.UserId]; ok {
+				return fmt.Errorf("team %q: user %q is listed more than once", team.TeamName, member.UserId)
+			}
+			members[member.UserId] = struct{}{}
+
+			if seen, ok := users[member.UserId]; ok && seen != member {
+				return fmt.Errorf("user %q has conflicting username or is_active across teams", member.UserId)
+			}
+			users[member.UserId] = member
+		}
+	}
+
+	return nil
+}
+
+// buildRosterImportPlan сравнивает документ с текущим составом. Документ - источник истины
+// только для перечисленных в нём команд: остальные команды не трогаются.
+// Участник, которого убрали из команды, выходит из неё. Если его нет в документе ни в одной команде
+// и он не состоит в командах вне документа, он ещё и деактивируется
+func buildRosterImportPlan(current []domain.Team, roster *domain.Roster) (*domain.RosterImportPlan, error) {
+	plan := &domain.RosterImportPlan{
+		CreatedTeams:       []domain.RosterTeamChange{},
+		UpdatedTeams:       []domain.RosterTeamChange{},
+		CreatedUsers:       []domain.TeamMember{},
+		UpdatedUsers:       []domain.TeamMember{},
+		AddedMemberships:   []domain.RosterMembership{},
+		RemovedMemberships: []domain.RosterMembership{},
+		DeactivatedUsers:   []string{},
+		Reassignments:      []domain.ReviewerReassignment{},
+		DeactivationTeams:  map[string]string{},
+		RosterSnapshot:     current,
+		ReviewersSnapshot:  map[string][]string{},
+	}
+
+	currentTeams := make(map[string]domain.Team, len(current))
+	currentUsers := make(map[string]domain.TeamMember)
+	for _, team := range current {
+		currentTeams[team.TeamName] = team
+		for _, member := range team.Members {
+			currentUsers[member.UserId] = member
+		}
+	}
+
+	rosterTeams := make(map[string]domain.Team, len(roster.Teams))
+	rosterUsers := make(map[string]struct{})
+	for _, team := range roster.Teams {
+		rosterTeams[team.TeamName] = team
+		for _, member := range team.Members {
+			rosterUsers[member.UserId] = struct{}{}
+		}
+	}
+
+	if err := checkRosterHierarchy(currentTeams, rosterTeams); err != nil {
+		return nil, err
+	}
+
+	// Участники команд, не упомянутых в документе: их членство там сохраняется
+	keptElsewhere := make(map[string]struct{})
+	for _, team := range current {
+		if _, ok := rosterTeams[team.TeamName]; ok {
+			continue
+		}
+		for _, member := range team.Members {
+			keptElsewhere[member.UserId] = struct{}{}
+		}
+	}
+
+	seenUsers := make(map[string]struct{})
+	deactivated := make(map[string]struct{})
+
+	for _, team := range roster.Teams {
+		change := domain.RosterTeamChange{TeamName: team.TeamName, ParentTeamName: team.ParentTeamName}
+
+		existing, exists := currentTeams[team.TeamName]
+		if !exists {
+			plan.CreatedTeams = append(plan.CreatedTeams, change)
+		} else if !sameParent(existing.ParentTeamName, team.ParentTeamName) {
+			plan.UpdatedTeams = append(plan.UpdatedTeams, change)
+		}
+
+		existingMembers := make(map[string]struct{}, len(existing.Members))
+		for _, member := range existing.Members {
+			existingMembers[member.UserId] = struct{}{}
+		}
+
+		for _, member := range team.Members {
+			if _, ok := seenUsers[member.UserId]; !ok {
+				seenUsers[member.UserId] = struct{}{}
+
+				if currentUser, ok := currentUsers[member.UserId]; !ok {
+					plan.CreatedUsers = append(plan.CreatedUsers, member)
+				} else if currentUser != member {
+					plan.UpdatedUsers = append(plan.UpdatedUsers, member)
+
+					// Выключение в документе - такая же деактивация, как у пропавших из документа
+					if currentUser.IsActive && !member.IsActive {
+						deactivated[member.UserId] = struct{}{}
+						plan.DeactivatedUsers = append(plan.DeactivatedUsers, member.UserId)
+						plan.DeactivationTeams[member.UserId] = team.TeamName
+					}
+				}
+			}
+
+			if _, ok := existingMembers[member.UserId]; !ok {
+				plan.AddedMemberships = append(plan.AddedMemberships, domain.RosterMembership{
+					TeamName: team.TeamName,
+					UserID:   member.UserId,
+				})
+			}
+		}
+
+		rosterMembers := make(map[string]struct{}, len(team.Members))
+		for _, member := range team.Members {
+			rosterMembers[member.UserId] = struct{}{}
+		}
+
+		for _, member := range existing.Members {
+			if _, ok := rosterMembers[member.UserId]; ok {
+				continue
+			}
+
+			plan.RemovedMemberships = append(plan.RemovedMemberships, domain.RosterMembership{
+				TeamName: team.TeamName,
+				UserID:   member.UserId,
+			})
+
+			_, inRoster := rosterUsers[member.UserId]
+			_, inOtherTeam := keptElsewhere[member.UserId]
+			if inRoster || inOtherTeam {
+				continue
+			}
+
+			if _, ok := deactivated[member.UserId]; !ok && member.IsActive {
+				deactivated[member.UserId] = struct{}{}
+				plan.DeactivatedUsers = append(plan.DeactivatedUsers, member.UserId)
+				plan.DeactivationTeams[member.UserId] = team.TeamName
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// checkRosterHierarchy проверяет, что родители существуют (в БД или в документе)
+// и что итоговая иерархия не содержит циклов
+func checkRosterHierarchy(currentTeams, rosterTeams map[string]domain.Team) error {
+	parents := make(map[string]*string, len(currentTeams)+len(rosterTeams))
+	for name, team := range currentTeams {
+		parents[name] = team.ParentTeamName
+	}
+	for name, team := range rosterTeams {
+		parents[name] = team.ParentTeamName
+	}
+
+	for name, team := range rosterTeams {
+		if team.ParentTeamName != nil {
+			if _, ok := parents[*team.ParentTeamName]; !ok {
+				return fmt.Errorf("team %q: parent team %q not found", name, *team.ParentTeamName)
+			}
+		}
+
+		visited := map[string]struct{}{name: {}}
+		for parent := parents[name]; parent != nil; parent = parents[*parent] {
+			if _, ok := visited[*parent]; ok {
+				return fmt.Errorf("team %q: hierarchy cycle detected", name)
+			}
+			visited[*parent] = struct{}{}
+		}
+	}
+
+	return nil
+}
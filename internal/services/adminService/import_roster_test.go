@@ -0,0 +1,263 @@
+package adminService
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	logger.Logger = zap.NewNop().Sugar()
+}
+
+const testRosterYAML = `
+teams:
+  - team_name: Backend
+    members:
+      - user_id: u1
+        username: Alice
+      - user_id: u3
+        username: Charlie Renamed
+  - team_name: Payments
+    parent_team_name: Backend
+    members:
+      - user_id: u3
+        username: Charlie Renamed
+`
+
+func testCurrentRoster() []domain.Team {
+	return []domain.Team{
+		{
+			TeamName: "Backend",
+			Members: []domain.TeamMember{
+				{UserId: "u1", Username: "Alice", IsActive: true},
+				{UserId: "u2", Username: "Bob", IsActive: true},
+			},
+		},
+		{
+			TeamName: "Frontend",
+			Members: []domain.TeamMember{
+				{UserId: "u3", Username: "Charlie", IsActive: true},
+			},
+		},
+	}
+}
+
+type importResponse struct {
+	Plan domain.RosterImportPlan `json:"plan"`
+}
+
+func TestAdminService_ImportRoster(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+	mockPrReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
+	service := NewAdminService(mockTeamRepo, mockPrReviewersRepo)
+
+	newRequest := func(query, contentType, body string) (*httptest.ResponseRecorder, *gin.Context) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/admin/import"+query, strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", contentType)
+		return w, c
+	}
+
+	t.Run("dry run returns plan without applying", func(t *testing.T) {
+		w, c := newRequest("?dry_run=true", "application/yaml", testRosterYAML)
+
+		mockTeamRepo.EXPECT().GetRoster(gomock.Any()).Return(testCurrentRoster(), nil)
+		mockPrReviewersRepo.EXPECT().
+			GetOpenReviewsByReviewers(gomock.Any(), []string{"u2"}).
+			Return([]domain.OpenReview{{PrID: "pr-1", AuthorID: "u1", ReviewerID: "u2"}}, nil)
+		mockPrReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), "pr-1").Return([]string{"u2"}, nil)
+
+		service.ImportRoster(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response importResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		plan := response.Plan
+		assert.True(t, plan.DryRun)
+		assert.Equal(t, []domain.RosterTeamChange{{TeamName: "Payments", ParentTeamName: strPtr("Backend")}}, plan.CreatedTeams)
+		assert.Empty(t, plan.UpdatedTeams)
+		assert.Empty(t, plan.CreatedUsers)
+		assert.Equal(t, []domain.TeamMember{{UserId: "u3", Username: "Charlie Renamed", IsActive: true}}, plan.UpdatedUsers)
+		assert.ElementsMatch(t, []domain.RosterMembership{
+			{TeamName: "Backend", UserID: "u3"},
+			{TeamName: "Payments", UserID: "u3"},
+		}, plan.AddedMemberships)
+		assert.Equal(t, []domain.RosterMembership{{TeamName: "Backend", UserID: "u2"}}, plan.RemovedMemberships)
+		assert.Equal(t, []string{"u2"}, plan.DeactivatedUsers)
+		// Автор u1 после импорта в Backend, где кроме него активен только u3
+		require.Len(t, plan.Reassignments, 1)
+		assert.Equal(t, "u2", plan.Reassignments[0].OldReviewerID)
+		assert.Equal(t, "u3", plan.Reassignments[0].NewReviewerID)
+	})
+
+	t.Run("apply plan", func(t *testing.T) {
+		w, c := newRequest("", "application/yaml", testRosterYAML)
+
+		mockTeamRepo.EXPECT().GetRoster(gomock.Any()).Return(testCurrentRoster(), nil)
+		mockPrReviewersRepo.EXPECT().GetOpenReviewsByReviewers(gomock.Any(), []string{"u2"}).Return(nil, nil)
+		mockTeamRepo.EXPECT().
+			ApplyRosterImportPlan(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ interface{}, plan *domain.RosterImportPlan) error {
+				assert.False(t, plan.DryRun)
+				assert.Len(t, plan.CreatedTeams, 1)
+				assert.Equal(t, testCurrentRoster(), plan.RosterSnapshot)
+				assert.Equal(t, map[string]string{"u2": "Backend"}, plan.DeactivationTeams)
+				return nil
+			})
+
+		service.ImportRoster(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("csv by content type", func(t *testing.T) {
+		body := "team_name,parent_team_name,user_id,username,is_active\nBackend,,u1,Alice,true\nBackend,,u2,Bob,true\n"
+		w, c := newRequest("?dry_run=true", "text/csv", body)
+
+		mockTeamRepo.EXPECT().GetRoster(gomock.Any()).Return(testCurrentRoster(), nil)
+
+		service.ImportRoster(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response importResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Plan.IsEmpty())
+	})
+
+	t.Run("user moved to another team leaves old team", func(t *testing.T) {
+		body := "teams:\n  - team_name: Backend\n    members:\n      - {user_id: u1, username: Alice}\n" +
+			"  - team_name: Frontend\n    members:\n      - {user_id: u2, username: Bob}\n      - {user_id: u3, username: Charlie}\n"
+		w, c := newRequest("?dry_run=true", "application/yaml", body)
+
+		mockTeamRepo.EXPECT().GetRoster(gomock.Any()).Return(testCurrentRoster(), nil)
+
+		service.ImportRoster(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response importResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []domain.RosterMembership{{TeamName: "Backend", UserID: "u2"}}, response.Plan.RemovedMemberships)
+		assert.Equal(t, []domain.RosterMembership{{TeamName: "Frontend", UserID: "u2"}}, response.Plan.AddedMemberships)
+		assert.Empty(t, response.Plan.DeactivatedUsers)
+	})
+
+	t.Run("unknown parent team", func(t *testing.T) {
+		body := "teams:\n  - team_name: Payments\n    parent_team_name: Nowhere\n    members: []\n"
+		w, c := newRequest("", "application/yaml", body)
+
+		mockTeamRepo.EXPECT().GetRoster(gomock.Any()).Return(testCurrentRoster(), nil)
+
+		service.ImportRoster(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("hierarchy cycle", func(t *testing.T) {
+		body := "teams:\n  - team_name: Backend\n    parent_team_name: Payments\n    members: []\n" +
+			"  - team_name: Payments\n    parent_team_name: Backend\n    members: []\n"
+		w, c := newRequest("", "application/yaml", body)
+
+		mockTeamRepo.EXPECT().GetRoster(gomock.Any()).Return(testCurrentRoster(), nil)
+
+		service.ImportRoster(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		var response domain.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Contains(t, response.Error.Message, "cycle")
+	})
+
+	t.Run("conflicting user data", func(t *testing.T) {
+		body := "teams:\n  - team_name: Backend\n    members:\n      - {user_id: u1, username: Alice}\n" +
+			"  - team_name: Frontend\n    members:\n      - {user_id: u1, username: Alicia}\n"
+		w, c := newRequest("", "application/yaml", body)
+
+		service.ImportRoster(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		w, c := newRequest("", "application/yaml", "teams: [")
+
+		service.ImportRoster(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		w, c := newRequest("?format=xml", "application/xml", "<teams/>")
+
+		service.ImportRoster(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("inactive in document reassigns open reviews without candidate", func(t *testing.T) {
+		body := "teams:\n  - team_name: Frontend\n    members:\n      - {user_id: u3, username: Charlie, is_active: false}\n"
+		w, c := newRequest("?dry_run=true", "application/yaml", body)
+
+		mockTeamRepo.EXPECT().GetRoster(gomock.Any()).Return(testCurrentRoster(), nil)
+		mockPrReviewersRepo.EXPECT().
+			GetOpenReviewsByReviewers(gomock.Any(), []string{"u3"}).
+			Return([]domain.OpenReview{{PrID: "pr-2", AuthorID: "u9", ReviewerID: "u3"}}, nil)
+		mockPrReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), "pr-2").Return([]string{"u3"}, nil)
+
+		service.ImportRoster(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response importResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []string{"u3"}, response.Plan.DeactivatedUsers)
+		require.Len(t, response.Plan.Reassignments, 1)
+		assert.Empty(t, response.Plan.Reassignments[0].NewReviewerID)
+	})
+
+	t.Run("stale plan", func(t *testing.T) {
+		w, c := newRequest("", "application/yaml", testRosterYAML)
+
+		mockTeamRepo.EXPECT().GetRoster(gomock.Any()).Return(testCurrentRoster(), nil)
+		mockPrReviewersRepo.EXPECT().GetOpenReviewsByReviewers(gomock.Any(), []string{"u2"}).Return(nil, nil)
+		mockTeamRepo.EXPECT().
+			ApplyRosterImportPlan(gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("%w: roster has changed, import again", teamStorage.ErrPlanStale))
+
+		service.ImportRoster(c)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("error applying plan", func(t *testing.T) {
+		w, c := newRequest("", "application/yaml", testRosterYAML)
+
+		mockTeamRepo.EXPECT().GetRoster(gomock.Any()).Return(testCurrentRoster(), nil)
+		mockPrReviewersRepo.EXPECT().GetOpenReviewsByReviewers(gomock.Any(), []string{"u2"}).Return(nil, nil)
+		mockTeamRepo.EXPECT().
+			ApplyRosterImportPlan(gomock.Any(), gomock.Any()).
+			Return(errors.New("database error"))
+
+		service.ImportRoster(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,142 @@
+package adminService
+
+import (
+	"context"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils"
+)
+
+// planRosterReassignments дополняет план импорта переназначениями открытых ревью деактивируемых пользователей.
+// Замена ищется среди активных участников команд автора PR в составе после импорта. Если замены нет,
+// ревьювер снимается без неё, а PR остаётся с need_more_reviewers - импорт из-за этого не отклоняется
+func (s *AdminServiceImpl) planRosterReassignments(
+	ctx context.Context,
+	roster *domain.Roster,
+	plan *domain.RosterImportPlan,
+) error {
+	if len(plan.DeactivatedUsers) == 0 {
+		return nil
+	}
+
+	reviews, err := s.prReviewersRepo.GetOpenReviewsByReviewers(ctx, plan.DeactivatedUsers)
+	if err != nil {
+		return err
+	}
+
+	// Ревью упорядочены по PR, поэтому порядок PR в плане стабилен
+	var prIDs []string
+	authors := make(map[string]string)
+	reviewersToReplace := make(map[string][]string)
+	for _, review := range reviews {
+		if _, ok := authors[review.PrID]; !ok {
+			prIDs = append(prIDs, review.PrID)
+			authors[review.PrID] = review.AuthorID
+		}
+		reviewersToReplace[review.PrID] = append(reviewersToReplace[review.PrID], review.ReviewerID)
+	}
+
+	teams := resultingRoster(plan.RosterSnapshot, roster, plan.DeactivatedUsers)
+
+	for _, prID := range prIDs {
+		currentReviewers, err := s.prReviewersRepo.GetAssignedReviewers(ctx, prID)
+		if err != nil {
+			return err
+		}
+		plan.ReviewersSnapshot[prID] = currentReviewers
+
+		authorID := authors[prID]
+		var chains [][]domain.Team
+		for _, team := range teams {
+			for _, member := range team.Members {
+				if member.UserId == authorID {
+					chains = append(chains, []domain.Team{team})
+					break
+				}
+			}
+		}
+		levels := utils.MergeTeamLevels(chains)
+
+		exclude := utils.ExcludeWithReason(plan.DeactivatedUsers, domain.ExclusionReasonInactive)
+		for _, reviewerID := range currentReviewers {
+			if _, ok := exclude[reviewerID]; !ok {
+				exclude[reviewerID] = domain.ExclusionReasonAlreadyAssigned
+			}
+		}
+		candidatePool, exclusions := utils.EvaluateReviewerCandidates(levels, authorID, exclude)
+
+		seed := utils.NewSeed()
+		replacements := utils.RandSelectReviewersWithFallbackSeed(
+			levels,
+			authorID,
+			append(append([]string{}, plan.DeactivatedUsers...), currentReviewers...),
+			len(reviewersToReplace[prID]),
+			seed,
+		)
+
+		for i, reviewerID := range reviewersToReplace[prID] {
+			reassignment := domain.ReviewerReassignment{
+				PrID:          prID,
+				OldReviewerID: reviewerID,
+			}
+			if i < len(replacements) {
+				reassignment.NewReviewerID = replacements[i]
+				reassignment.Meta = &domain.AssignmentMeta{
+					Strategy:           domain.AssignmentStrategyDeactivation,
+					CandidateCount:     len(candidatePool),
+					Excluded:           exclusions,
+					Seed:               &seed,
+					ReplacedReviewerID: reviewerID,
+				}
+			}
+			plan.Reassignments = append(plan.Reassignments, reassignment)
+		}
+	}
+
+	return nil
+}
+
+// resultingRoster - состав команд после импорта: команды из документа - как в документе, остальные - как сейчас.
+// Активность пользователя берётся из документа, деактивируемые пользователи неактивны
+func resultingRoster(current []domain.Team, roster *domain.Roster, deactivatedUsers []string) []domain.Team {
+	active := make(map[string]bool)
+	for _, team := range current {
+		for _, member := range team.Members {
+			active[member.UserId] = member.IsActive
+		}
+	}
+
+	documented := make(map[string]struct{}, len(roster.Teams))
+	for _, team := range roster.Teams {
+		documented[team.TeamName] = struct{}{}
+		for _, member := range team.Members {
+			active[member.UserId] = member.IsActive
+		}
+	}
+
+	for _, userID := range deactivatedUsers {
+		active[userID] = false
+	}
+
+	teams := make([]domain.Team, 0, len(current)+len(roster.Teams))
+	addTeam := func(team domain.Team) {
+		members := make([]domain.TeamMember, 0, len(team.Members))
+		for _, member := range team.Members {
+			member.IsActive = active[member.UserId]
+			members = append(members, member)
+		}
+		team.Members = members
+		teams = append(teams, team)
+	}
+
+	for _, team := range current {
+		if _, ok := documented[team.TeamName]; !ok {
+			addTeam(team)
+		}
+	}
+	for _, team := range roster.Teams {
+		addTeam(team)
+	}
+
+	return teams
+}
@@ -0,0 +1,244 @@
+package adminService
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-yaml"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+)
+
+var csvHeader = []string{"team_name", "parent_team_name", "user_id", "username", "is_active"}
+
+// rosterDocument - представление состава в YAML. is_active необязателен и по умолчанию true,
+// чтобы в Git не приходилось дописывать его каждому участнику
+type rosterDocument struct {
+	Teams []rosterDocumentTeam `yaml:"teams"`
+}
+
+type rosterDocumentTeam struct {
+	TeamName       string                 `yaml:"team_name"`
+	ParentTeamName *string                `yaml:"parent_team_name,omitempty"`
+	Members        []rosterDocumentMember `yaml:"members"`
+}
+
+type rosterDocumentMember struct {
+	UserID   string `yaml:"user_id"`
+	Username string `yaml:"username"`
+	IsActive *bool  `yaml:"is_active,omitempty"`
+}
+
+// resolveRosterFormat берёт формат из query-параметра format, иначе из Content-Type, по умолчанию YAML
+func resolveRosterFormat(c *gin.Context) (domain.RosterFormat, error) {
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		if strings.Contains(c.ContentType(), "csv") {
+			return domain.RosterFormatCSV, nil
+		}
+		return domain.RosterFormatYAML, nil
+	}
+
+	switch domain.RosterFormat(format) {
+	case domain.RosterFormatYAML, domain.RosterFormatCSV:
+		return domain.RosterFormat(format), nil
+	default:
+		return "", fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+func rosterContentType(format domain.RosterFormat) string {
+	if format == domain.RosterFormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/yaml; charset=utf-8"
+}
+
+func decodeRoster(format domain.RosterFormat, data []byte) (*domain.Roster, error) {
+	if format == domain.RosterFormatCSV {
+		return decodeRosterCSV(data)
+	}
+	return decodeRosterYAML(data)
+}
+
+func encodeRoster(format domain.RosterFormat, roster *domain.Roster) ([]byte, error) {
+	if format == domain.RosterFormatCSV {
+		return encodeRosterCSV(roster)
+	}
+	return encodeRosterYAML(roster)
+}
+
+func decodeRosterYAML(data []byte) (*domain.Roster, error) {
+	var doc rosterDocument
+	if err := yaml.UnmarshalWithOptions(data, &doc, yaml.DisallowUnknownField()); err != nil {
+		return nil, err
+	}
+
+	roster := &domain.Roster{Teams: make([]domain.Team, 0, len(doc.Teams))}
+	for _, docTeam := range doc.Teams {
+		team := domain.Team{
+			TeamName:       docTeam.TeamName,
+			ParentTeamName: docTeam.ParentTeamName,
+			Members:        make([]domain.TeamMember, 0, len(docTeam.Members)),
+		}
+		for _, docMember := range docTeam.Members {
+			isActive := true
+			if docMember.IsActive != nil {
+				isActive = *docMember.IsActive
+			}
+			team.Members = append(team.Members, domain.TeamMember{
+				UserId:   docMember.UserID,
+				Username: docMember.Username,
+				IsActive: isActive,
+			})
+		}
+		roster.Teams = append(roster.Teams, team)
+	}
+
+	return roster, nil
+}
+
+func encodeRosterYAML(roster *domain.Roster) ([]byte, error) {
+	doc := rosterDocument{Teams: make([]rosterDocumentTeam, 0, len(roster.Teams))}
+	for _, team := range roster.Teams {
+		docTeam := rosterDocumentTeam{
+			TeamName:       team.TeamName,
+			ParentTeamName: team.ParentTeamName,
+			Members:        make([]rosterDocumentMember, 0, len(team.Members)),
+		}
+		for _, member := range team.Members {
+			isActive := member.IsActive
+			docTeam.Members = append(docTeam.Members, rosterDocumentMember{
+				UserID:   member.UserId,
+				Username: member.Username,
+				IsActive: &isActive,
+			})
+		}
+		doc.Teams = append(doc.Teams, docTeam)
+	}
+
+	return yaml.Marshal(doc)
+}
+
+// decodeRosterCSV читает по строке на участника команды. Строка с пустым user_id задаёт команду без участников
+func decodeRosterCSV(data []byte) (*domain.Roster, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = len(csvHeader)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("empty csv document")
+		}
+		return nil, err
+	}
+	for i, column := range csvHeader {
+		if strings.TrimSpace(header[i]) != column {
+			return nil, fmt.Errorf("unexpected csv header, want %s", strings.Join(csvHeader, ","))
+		}
+	}
+
+	roster := &domain.Roster{Teams: []domain.Team{}}
+	teamIndex := make(map[string]int)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		teamName, parentTeamName, userID, username, isActiveRaw := record[0], record[1], record[2], record[3], record[4]
+
+		var parent *string
+		if parentTeamName != "" {
+			parent = &parentTeamName
+		}
+
+		idx, ok := teamIndex[teamName]
+		if !ok {
+			idx = len(roster.Teams)
+			teamIndex[teamName] = idx
+			roster.Teams = append(roster.Teams, domain.Team{
+				TeamName:       teamName,
+				ParentTeamName: parent,
+				Members:        []domain.TeamMember{},
+			})
+		} else if !sameParent(roster.Teams[idx].ParentTeamName, parent) {
+			return nil, fmt.Errorf("line %d: conflicting parent_team_name for team %q", line, teamName)
+		}
+
+		if userID == "" {
+			continue
+		}
+
+		isActive := true
+		if isActiveRaw != "" {
+			isActive, err = strconv.ParseBool(isActiveRaw)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid is_active value %q", line, isActiveRaw)
+			}
+		}
+
+		roster.Teams[idx].Members = append(roster.Teams[idx].Members, domain.TeamMember{
+			UserId:   userID,
+			Username: username,
+			IsActive: isActive,
+		})
+	}
+
+	return roster, nil
+}
+
+func encodeRosterCSV(roster *domain.Roster) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+
+	for _, team := range roster.Teams {
+		parent := ""
+		if team.ParentTeamName != nil {
+			parent = *team.ParentTeamName
+		}
+
+		if len(team.Members) == 0 {
+			if err := writer.Write([]string{team.TeamName, parent, "", "", ""}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		for _, member := range team.Members {
+			record := []string{team.TeamName, parent, member.UserId, member.Username, strconv.FormatBool(member.IsActive)}
+			if err := writer.Write(record); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func sameParent(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
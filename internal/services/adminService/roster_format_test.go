@@ -0,0 +1,102 @@
+package adminService
+
+import (
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestDecodeRosterYAML(t *testing.T) {
+	t.Run("is_active defaults to true", func(t *testing.T) {
+		data := []byte(`
+teams:
+  - team_name: Payments
+    parent_team_name: Backend
+    members:
+      - user_id: u1
+        username: Alice
+      - user_id: u2
+        username: Bob
+        is_active: false
+`)
+		roster, err := decodeRoster(domain.RosterFormatYAML, data)
+
+		require.NoError(t, err)
+		require.Len(t, roster.Teams, 1)
+		assert.Equal(t, "Backend", *roster.Teams[0].ParentTeamName)
+		assert.True(t, roster.Teams[0].Members[0].IsActive)
+		assert.False(t, roster.Teams[0].Members[1].IsActive)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := decodeRoster(domain.RosterFormatYAML, []byte("teams:\n  - name: Payments\n"))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDecodeRosterCSV(t *testing.T) {
+	t.Run("groups rows by team", func(t *testing.T) {
+		data := []byte("team_name,parent_team_name,user_id,username,is_active\n" +
+			"Backend,,,,\n" +
+			"Payments,Backend,u1,Alice,\n" +
+			"Payments,Backend,u2,Bob,false\n")
+
+		roster, err := decodeRoster(domain.RosterFormatCSV, data)
+
+		require.NoError(t, err)
+		require.Len(t, roster.Teams, 2)
+		assert.Empty(t, roster.Teams[0].Members)
+		assert.Nil(t, roster.Teams[0].ParentTeamName)
+		require.Len(t, roster.Teams[1].Members, 2)
+		assert.True(t, roster.Teams[1].Members[0].IsActive)
+		assert.False(t, roster.Teams[1].Members[1].IsActive)
+	})
+
+	t.Run("conflicting parent", func(t *testing.T) {
+		data := []byte("team_name,parent_team_name,user_id,username,is_active\n" +
+			"Payments,Backend,u1,Alice,true\n" +
+			"Payments,Platform,u2,Bob,true\n")
+
+		_, err := decodeRoster(domain.RosterFormatCSV, data)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		_, err := decodeRoster(domain.RosterFormatCSV, []byte("team,user,name,active,x\n"))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestEncodeRoster_RoundTrip(t *testing.T) {
+	roster := &domain.Roster{Teams: []domain.Team{
+		{TeamName: "Backend", Members: []domain.TeamMember{}},
+		{
+			TeamName:       "Payments",
+			ParentTeamName: strPtr("Backend"),
+			Members: []domain.TeamMember{
+				{UserId: "u1", Username: "Alice", IsActive: true},
+				{UserId: "u2", Username: "Bob", IsActive: false},
+			},
+		},
+	}}
+
+	for _, format := range []domain.RosterFormat{domain.RosterFormatYAML, domain.RosterFormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			data, err := encodeRoster(format, roster)
+			require.NoError(t, err)
+
+			decoded, err := decodeRoster(format, data)
+			require.NoError(t, err)
+			assert.Equal(t, roster, decoded)
+		})
+	}
+}
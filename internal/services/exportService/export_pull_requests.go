@@ -0,0 +1,47 @@
+package exportService
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+)
+
+var pullRequestCSVHeader = []string{
+	"pull_request_id", "pull_request_name", "author_id", "author_name", "team_name", "status",
+	"need_more_reviewers", "reviewer_ids", "created_at", "merged_at",
+}
+
+// ExportPullRequests потоково выгружает PR, созданные за период, в CSV или NDJSON.
+// В CSV ревьюверы перечисляются через ";"
+func (s *ExportServiceImpl) ExportPullRequests(c *gin.Context) {
+	format, filter, err := parseExportRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			err.Error(),
+		))
+		return
+	}
+
+	stream := newExportStream(c, format, "pull_requests", pullRequestCSVHeader)
+
+	err = s.exportRepo.StreamPullRequests(c.Request.Context(), filter, func(row domain.PullRequestExportRow) error {
+		return stream.write([]string{
+			row.PullRequestID,
+			row.PullRequestName,
+			exportString(row.AuthorID),
+			exportString(row.AuthorName),
+			exportString(row.TeamName),
+			row.Status,
+			strconv.FormatBool(row.NeedMoreReviewers),
+			strings.Join(row.ReviewerIDs, ";"),
+			exportTime(row.CreatedAt),
+			exportTime(row.MergedAt),
+		}, row)
+	})
+
+	stream.finish(err)
+}
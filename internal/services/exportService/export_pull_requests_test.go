@@ -0,0 +1,192 @@
+package exportService
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	logger.Logger = zap.NewNop().Sugar()
+}
+
+func newExportContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return c, w
+}
+
+func testPullRequestRows() []domain.PullRequestExportRow {
+	authorID, authorName, teamName := "user-alice", "Alice", "Backend"
+	createdAt := time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC)
+	mergedAt := createdAt.Add(2 * time.Hour)
+
+	return []domain.PullRequestExportRow{
+		{
+			PullRequestID:   "pr-1",
+			PullRequestName: "Add feature, part 1",
+			AuthorID:        &authorID,
+			AuthorName:      &authorName,
+			TeamName:        &teamName,
+			Status:          "MERGED",
+			ReviewerIDs:     []string{"user-bob", "user-carol"},
+			CreatedAt:       &createdAt,
+			MergedAt:        &mergedAt,
+		},
+		{
+			PullRequestID:     "pr-2",
+			PullRequestName:   "Fix bug",
+			AuthorID:          &authorID,
+			AuthorName:        &authorName,
+			Status:            "OPEN",
+			NeedMoreReviewers: true,
+			ReviewerIDs:       []string{},
+			CreatedAt:         &createdAt,
+		},
+	}
+}
+
+// streamPullRequestRows имитирует storage: отдаёт rows в колбэк и возвращает err
+func streamPullRequestRows(
+	rows []domain.PullRequestExportRow,
+	err error,
+) func(context.Context, domain.ExportFilter, func(row domain.PullRequestExportRow) error) error {
+	return func(_ context.Context, _ domain.ExportFilter, fn func(row domain.PullRequestExportRow) error) error {
+		for _, row := range rows {
+			if writeErr := fn(row); writeErr != nil {
+				return writeErr
+			}
+		}
+		return err
+	}
+}
+
+func TestExportService_ExportPullRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExportRepo := mocks.NewMockExportRepositoryInterface(ctrl)
+	service := NewExportService(mockExportRepo)
+
+	t.Run("csv by default", func(t *testing.T) {
+		c, w := newExportContext("/export/pullRequests?from=2025-11-01&to=2025-12-01")
+
+		mockExportRepo.EXPECT().
+			StreamPullRequests(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, filter domain.ExportFilter, fn func(row domain.PullRequestExportRow) error) error {
+				require.NotNil(t, filter.From)
+				require.NotNil(t, filter.To)
+				assert.Equal(t, time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), *filter.From)
+				return streamPullRequestRows(testPullRequestRows(), nil)(ctx, filter, fn)
+			})
+
+		service.ExportPullRequests(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+		assert.Equal(t, "attachment; filename=pull_requests.csv", w.Header().Get("Content-Disposition"))
+		assert.Equal(t,
+			"pull_request_id,pull_request_name,author_id,author_name,team_name,status,need_more_reviewers,reviewer_ids,created_at,merged_at\n"+
+				"pr-1,\"Add feature, part 1\",user-alice,Alice,Backend,MERGED,false,user-bob;user-carol,2025-11-20T10:00:00Z,2025-11-20T12:00:00Z\n"+
+				"pr-2,Fix bug,user-alice,Alice,,OPEN,true,,2025-11-20T10:00:00Z,\n",
+			w.Body.String())
+		assert.Empty(t, w.Result().Trailer.Get(ExportErrorTrailer))
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		c, w := newExportContext("/export/pullRequests?format=ndjson")
+
+		mockExportRepo.EXPECT().
+			StreamPullRequests(gomock.Any(), domain.ExportFilter{}, gomock.Any()).
+			DoAndReturn(streamPullRequestRows(testPullRequestRows(), nil))
+
+		service.ExportPullRequests(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+		scanner := bufio.NewScanner(strings.NewReader(w.Body.String()))
+		var rows []domain.PullRequestExportRow
+		for scanner.Scan() {
+			var row domain.PullRequestExportRow
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &row))
+			rows = append(rows, row)
+		}
+		require.Len(t, rows, 2)
+		assert.Equal(t, []string{"user-bob", "user-carol"}, rows[0].ReviewerIDs)
+		assert.Nil(t, rows[1].MergedAt)
+	})
+
+	t.Run("empty csv has header only", func(t *testing.T) {
+		c, w := newExportContext("/export/pullRequests")
+
+		mockExportRepo.EXPECT().
+			StreamPullRequests(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(streamPullRequestRows(nil, nil))
+
+		service.ExportPullRequests(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, strings.Join(pullRequestCSVHeader, ",")+"\n", w.Body.String())
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		c, w := newExportContext("/export/pullRequests?format=xlsx")
+
+		service.ExportPullRequests(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		c, w := newExportContext("/export/pullRequests?from=2025-12-01&to=2025-11-01")
+
+		service.ExportPullRequests(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("error before first row", func(t *testing.T) {
+		c, w := newExportContext("/export/pullRequests")
+
+		mockExportRepo.EXPECT().
+			StreamPullRequests(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(errors.New("db error"))
+
+		service.ExportPullRequests(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	})
+
+	t.Run("error after first row sets trailer", func(t *testing.T) {
+		c, w := newExportContext("/export/pullRequests")
+
+		mockExportRepo.EXPECT().
+			StreamPullRequests(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(streamPullRequestRows(testPullRequestRows()[:1], errors.New("connection reset")))
+
+		service.ExportPullRequests(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "pr-1,")
+		assert.Equal(t, domain.ErrExportDataMsg, w.Result().Trailer.Get(ExportErrorTrailer))
+	})
+}
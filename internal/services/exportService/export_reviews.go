@@ -0,0 +1,45 @@
+package exportService
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+)
+
+var reviewCSVHeader = []string{
+	"pull_request_id", "pull_request_name", "author_id", "team_name", "reviewer_id", "reviewer_name", "status",
+	"assignment_strategy", "assigned_at", "created_at", "merged_at",
+}
+
+// ExportReviews потоково выгружает назначения ревьюверов, сделанные за период, в CSV или NDJSON
+func (s *ExportServiceImpl) ExportReviews(c *gin.Context) {
+	format, filter, err := parseExportRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			err.Error(),
+		))
+		return
+	}
+
+	stream := newExportStream(c, format, "reviews", reviewCSVHeader)
+
+	err = s.exportRepo.StreamReviews(c.Request.Context(), filter, func(row domain.ReviewExportRow) error {
+		return stream.write([]string{
+			row.PullRequestID,
+			row.PullRequestName,
+			exportString(row.AuthorID),
+			exportString(row.TeamName),
+			row.ReviewerID,
+			row.ReviewerName,
+			row.Status,
+			exportString(row.AssignmentStrategy),
+			exportTime(row.AssignedAt),
+			exportTime(row.CreatedAt),
+			exportTime(row.MergedAt),
+		}, row)
+	})
+
+	stream.finish(err)
+}
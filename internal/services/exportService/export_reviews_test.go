@@ -0,0 +1,80 @@
+package exportService
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportService_ExportReviews(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExportRepo := mocks.NewMockExportRepositoryInterface(ctrl)
+	service := NewExportService(mockExportRepo)
+
+	authorID, teamName, strategy := "user-alice", "Backend", string(domain.AssignmentStrategyRandom)
+	assignedAt := time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC)
+	row := domain.ReviewExportRow{
+		PullRequestID:      "pr-1",
+		PullRequestName:    "Add feature",
+		AuthorID:           &authorID,
+		TeamName:           &teamName,
+		ReviewerID:         "user-bob",
+		ReviewerName:       "Bob",
+		Status:             "OPEN",
+		AssignmentStrategy: &strategy,
+		AssignedAt:         &assignedAt,
+		CreatedAt:          &assignedAt,
+	}
+
+	streamRows := func(_ context.Context, _ domain.ExportFilter, fn func(row domain.ReviewExportRow) error) error {
+		return fn(row)
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		c, w := newExportContext("/export/reviews?to=2025-12-01T00:00:00Z")
+
+		mockExportRepo.EXPECT().
+			StreamReviews(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(streamRows)
+
+		service.ExportReviews(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "attachment; filename=reviews.csv", w.Header().Get("Content-Disposition"))
+		assert.Equal(t,
+			"pull_request_id,pull_request_name,author_id,team_name,reviewer_id,reviewer_name,status,assignment_strategy,assigned_at,created_at,merged_at\n"+
+				"pr-1,Add feature,user-alice,Backend,user-bob,Bob,OPEN,RANDOM,2025-11-20T10:00:00Z,2025-11-20T10:00:00Z,\n",
+			w.Body.String())
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		c, w := newExportContext("/export/reviews?format=ndjson")
+
+		mockExportRepo.EXPECT().
+			StreamReviews(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(streamRows)
+
+		service.ExportReviews(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"reviewer_id":"user-bob"`)
+		assert.Contains(t, w.Body.String(), `"assignment_strategy":"RANDOM"`)
+	})
+
+	t.Run("invalid from", func(t *testing.T) {
+		c, w := newExportContext("/export/reviews?from=yesterday")
+
+		service.ExportReviews(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,17 @@
+package exportService
+
+import (
+	"github.com/nedokyrill/avito-pr-api/internal/storage"
+)
+
+type ExportServiceImpl struct {
+	exportRepo storage.ExportRepositoryInterface
+}
+
+func NewExportService(
+	exportRepo storage.ExportRepositoryInterface,
+) *ExportServiceImpl {
+	return &ExportServiceImpl{
+		exportRepo: exportRepo,
+	}
+}
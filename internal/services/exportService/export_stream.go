@@ -0,0 +1,197 @@
+package exportService
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/consts"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+const (
+	// ExportErrorTrailer - HTTP-трейлер, который выставляется, если выгрузка оборвалась после начала ответа
+	ExportErrorTrailer = "X-Export-Error"
+
+	// exportFlushEvery - раз в сколько строк ответ сбрасывается клиенту
+	exportFlushEvery = 500
+)
+
+// parseExportRequest читает format (csv по умолчанию или ndjson) и необязательные from/to
+func parseExportRequest(c *gin.Context) (domain.ExportFormat, domain.ExportFilter, error) {
+	var filter domain.ExportFilter
+
+	format := domain.ExportFormat(strings.ToLower(c.DefaultQuery("format", string(domain.ExportFormatCSV))))
+	if format != domain.ExportFormatCSV && format != domain.ExportFormatNDJSON {
+		return "", filter, fmt.Errorf("unsupported format %q", format)
+	}
+
+	if raw := c.Query("from"); raw != "" {
+		from, err := utils.ParseTimeParam(raw)
+		if err != nil {
+			return "", filter, errors.New("invalid from: expected RFC3339 or YYYY-MM-DD")
+		}
+		filter.From = &from
+	}
+
+	if raw := c.Query("to"); raw != "" {
+		to, err := utils.ParseTimeParam(raw)
+		if err != nil {
+			return "", filter, errors.New("invalid to: expected RFC3339 or YYYY-MM-DD")
+		}
+		filter.To = &to
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return "", filter, errors.New("from must be before to")
+	}
+
+	return format, filter, nil
+}
+
+// exportStream пишет строки выгрузки прямо в ответ. Заголовки отправляются с первой строкой, поэтому
+// ошибка до неё возвращается обычным JSON с 500. Если ответ уже начат, обрыв помечается трейлером
+// ExportErrorTrailer - клиент должен проверять его, чтобы не принять обрезанную выгрузку за полную
+type exportStream struct {
+	c         *gin.Context
+	format    domain.ExportFormat
+	filename  string
+	csvHeader []string
+
+	csvWriter   *csv.Writer
+	jsonEncoder *json.Encoder
+	started     bool
+	rows        int
+}
+
+func newExportStream(c *gin.Context, format domain.ExportFormat, filename string, csvHeader []string) *exportStream {
+	return &exportStream{
+		c:         c,
+		format:    format,
+		filename:  filename,
+		csvHeader: csvHeader,
+	}
+}
+
+func (s *exportStream) start() error {
+	s.started = true
+
+	contentType := "text/csv; charset=utf-8"
+	if s.format == domain.ExportFormatNDJSON {
+		contentType = "application/x-ndjson"
+	}
+
+	s.c.Header("Content-Type", contentType)
+	s.c.Header("Content-Disposition", "attachment; filename="+s.filename+"."+string(s.format))
+	s.c.Header("Trailer", ExportErrorTrailer)
+	s.c.Status(http.StatusOK)
+	s.c.Writer.WriteHeaderNow()
+
+	if err := s.flush(); err != nil {
+		return err
+	}
+
+	if s.format == domain.ExportFormatNDJSON {
+		s.jsonEncoder = json.NewEncoder(s.c.Writer)
+		return nil
+	}
+
+	s.csvWriter = csv.NewWriter(s.c.Writer)
+	return s.csvWriter.Write(s.csvHeader)
+}
+
+// write пишет одну строку: record для CSV, value для NDJSON
+func (s *exportStream) write(record []string, value any) error {
+	if !s.started {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if s.format == domain.ExportFormatNDJSON {
+		err = s.jsonEncoder.Encode(value)
+	} else {
+		err = s.csvWriter.Write(record)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.rows++
+	if s.rows%exportFlushEvery == 0 {
+		return s.flush()
+	}
+	return nil
+}
+
+// flush сбрасывает буфер клиенту и продлевает дедлайн записи: иначе WriteTimeout сервера
+// обрывал бы выгрузки, которые идут дольше него
+func (s *exportStream) flush() error {
+	if err := http.NewResponseController(s.c.Writer).SetWriteDeadline(time.Now().Add(consts.WriteTimeout)); err != nil &&
+		!errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	if s.csvWriter != nil {
+		s.csvWriter.Flush()
+		if err := s.csvWriter.Error(); err != nil {
+			return err
+		}
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+// finish завершает выгрузку. Пустая выгрузка - это 200 с заголовком CSV (или пустым телом для NDJSON)
+func (s *exportStream) finish(err error) {
+	if err == nil && !s.started {
+		err = s.start()
+	}
+
+	if err == nil {
+		err = s.flush()
+	}
+
+	if err == nil {
+		return
+	}
+
+	logger.Logger.Errorw("error exporting data",
+		"export", s.filename,
+		"rows_written", s.rows,
+		"error", err,
+	)
+
+	if !s.started {
+		s.c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrExportDataMsg,
+		))
+		return
+	}
+
+	_ = s.flush()
+	s.c.Writer.Header().Set(ExportErrorTrailer, domain.ErrExportDataMsg)
+}
+
+func exportString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func exportTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
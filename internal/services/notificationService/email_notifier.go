@@ -0,0 +1,159 @@
+package notificationService
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// EmailNotifier отправляет письма из очереди через Mailer. Письма дайджеста одного получателя,
+// время которых подошло, уходят одним сообщением
+type EmailNotifier struct {
+	notificationRepo storage.NotificationRepositoryInterface
+	mailer           Mailer
+	templates        *EmailTemplates
+	config           NotifierConfig
+	now              func() time.Time
+}
+
+func NewEmailNotifier(
+	notificationRepo storage.NotificationRepositoryInterface,
+	mailer Mailer,
+	templates *EmailTemplates,
+	config NotifierConfig,
+) *EmailNotifier {
+	return &EmailNotifier{
+		notificationRepo: notificationRepo,
+		mailer:           mailer,
+		templates:        templates,
+		config:           config,
+		now:              time.Now,
+	}
+}
+
+// outgoingEmail - сообщение и записи очереди, которые оно закрывает
+type outgoingEmail struct {
+	ids      []int64
+	attempts int
+	message  EmailMessage
+	// err - сообщение не удалось собрать, попытка сразу неуспешна
+	err error
+}
+
+// Run отправляет письма из очереди до отмены ctx
+func (n *EmailNotifier) Run(ctx context.Context) {
+	utils.PollBatches(ctx, "email notifier", n.config.PollInterval, n.config.BatchSize, n.SendDue)
+}
+
+// SendDue забирает одну пачку писем и отправляет их параллельно. Возвращает размер пачки
+func (n *EmailNotifier) SendDue(ctx context.Context) (int, error) {
+	lease := 2 * n.config.RequestTimeout
+
+	emails, err := n.notificationRepo.ClaimDueEmails(ctx, n.config.BatchSize, lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, email := range n.groupEmails(emails) {
+		wg.Add(1)
+		go func(email outgoingEmail) {
+			defer wg.Done()
+			n.notify(ctx, email)
+		}(email)
+	}
+	wg.Wait()
+
+	return len(emails), nil
+}
+
+// groupEmails превращает записи очереди в сообщения: обычное письмо - как есть, письма дайджеста
+// одного получателя - в одно сообщение по шаблону дайджеста
+func (n *EmailNotifier) groupEmails(emails []domain.DueEmail) []outgoingEmail {
+	outgoing := make([]outgoingEmail, 0, len(emails))
+	digests := make(map[string]int)
+	digestItems := make(map[string][]DigestItem)
+
+	for _, email := range emails {
+		if !email.Digest {
+			outgoing = append(outgoing, outgoingEmail{
+				ids:      []int64{email.ID},
+				attempts: email.Attempts,
+				message: EmailMessage{
+					To:      email.Email,
+					Subject: email.Subject,
+					Text:    email.TextBody,
+					HTML:    email.HTMLBody,
+				},
+			})
+			continue
+		}
+
+		index, ok := digests[email.UserID]
+		if !ok {
+			index = len(outgoing)
+			digests[email.UserID] = index
+			outgoing = append(outgoing, outgoingEmail{message: EmailMessage{To: email.Email}})
+		}
+
+		digest := &outgoing[index]
+		digest.ids = append(digest.ids, email.ID)
+		digest.attempts = max(digest.attempts, email.Attempts)
+		digestItems[email.UserID] = append(digestItems[email.UserID], DigestItem{Subject: email.Subject})
+	}
+
+	for userID, index := range digests {
+		rendered, err := n.templates.RenderDigest(DigestData{Items: digestItems[userID]})
+		if err != nil {
+			outgoing[index].err = err
+			continue
+		}
+		outgoing[index].message.Subject = rendered.Subject
+		outgoing[index].message.Text = rendered.Text
+		outgoing[index].message.HTML = rendered.HTML
+	}
+
+	return outgoing
+}
+
+func (n *EmailNotifier) notify(ctx context.Context, email outgoingEmail) {
+	attempt := domain.EmailAttempt{EmailIDs: email.ids}
+
+	err := email.err
+	if err == nil {
+		sendCtx, cancel := context.WithTimeout(ctx, n.config.RequestTimeout)
+		err = n.mailer.Send(sendCtx, email.message)
+		cancel()
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	} else {
+		attempt.Sent = true
+	}
+
+	attemptNumber := email.attempts + 1
+	if !attempt.Sent && attemptNumber < n.config.MaxAttempts {
+		next := n.now().Add(utils.ExponentialBackoff(n.config.BaseBackoff, n.config.MaxBackoff, attemptNumber))
+		attempt.NextAttemptAt = &next
+	}
+
+	if err = n.notificationRepo.RecordEmailAttempt(ctx, attempt); err != nil {
+		// Письма вернутся в очередь по истечении аренды
+		logger.Logger.Error("error recording email attempt: ", err)
+		return
+	}
+
+	if !attempt.Sent {
+		logger.Logger.Warnw("email notification failed",
+			"email_ids", email.ids,
+			"attempt", attemptNumber,
+			"next_attempt_at", attempt.NextAttemptAt,
+			"error", attempt.Error,
+		)
+	}
+}
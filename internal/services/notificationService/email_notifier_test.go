@@ -0,0 +1,133 @@
+package notificationService
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMailer запоминает отправленные письма и отказывает получателям из failTo
+type fakeMailer struct {
+	failTo map[string]bool
+
+	mu       sync.Mutex
+	messages []EmailMessage
+}
+
+func (m *fakeMailer) Send(_ context.Context, message EmailMessage) error {
+	if m.failTo[message.To] {
+		return errors.New("550 mailbox unavailable")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+func newTestEmailNotifier(t *testing.T, repo *mocks.MockNotificationRepositoryInterface, mailer Mailer, now time.Time) *EmailNotifier {
+	templates, err := LoadEmailTemplates("")
+	require.NoError(t, err)
+
+	notifier := NewEmailNotifier(repo, mailer, templates, NotifierConfig{
+		PollInterval:   10 * time.Millisecond,
+		BatchSize:      10,
+		RequestTimeout: time.Second,
+		MaxAttempts:    3,
+		BaseBackoff:    5 * time.Second,
+		MaxBackoff:     time.Minute,
+	})
+	notifier.now = func() time.Time { return now }
+	return notifier
+}
+
+func TestEmailNotifier_SendDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("immediate emails and digest", func(t *testing.T) {
+		mailer := &fakeMailer{}
+		repo := mocks.NewMockNotificationRepositoryInterface(ctrl)
+		repo.EXPECT().ClaimDueEmails(gomock.Any(), 10, 2*time.Second).Return([]domain.DueEmail{
+			{ID: 1, UserID: "user-alice", Email: "alice@example.com", Subject: "Review requested: A", Digest: true},
+			{ID: 2, UserID: "user-alice", Email: "alice@example.com", Subject: "Merged: B", Digest: true},
+			{ID: 3, UserID: "user-bob", Email: "bob@example.com", Subject: "Review requested: C",
+				TextBody: "text", HTMLBody: "<p>html</p>"},
+		}, nil)
+		repo.EXPECT().RecordEmailAttempt(gomock.Any(), domain.EmailAttempt{EmailIDs: []int64{1, 2}, Sent: true}).Return(nil)
+		repo.EXPECT().RecordEmailAttempt(gomock.Any(), domain.EmailAttempt{EmailIDs: []int64{3}, Sent: true}).Return(nil)
+
+		claimed, err := newTestEmailNotifier(t, repo, mailer, now).SendDue(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, claimed)
+		require.Len(t, mailer.messages, 2)
+
+		byRecipient := make(map[string]EmailMessage)
+		for _, message := range mailer.messages {
+			byRecipient[message.To] = message
+		}
+		assert.Equal(t, EmailMessage{To: "bob@example.com", Subject: "Review requested: C", Text: "text", HTML: "<p>html</p>"},
+			byRecipient["bob@example.com"])
+
+		digest := byRecipient["alice@example.com"]
+		assert.Equal(t, "Review digest: 2 update(s)", digest.Subject)
+		assert.Contains(t, digest.Text, "* Review requested: A")
+		assert.Contains(t, digest.Text, "* Merged: B")
+		assert.Contains(t, digest.HTML, "<li>Merged: B</li>")
+	})
+
+	t.Run("failure schedules retry for every email of the message", func(t *testing.T) {
+		mailer := &fakeMailer{failTo: map[string]bool{"alice@example.com": true}}
+		next := now.Add(10 * time.Second)
+		repo := mocks.NewMockNotificationRepositoryInterface(ctrl)
+		repo.EXPECT().ClaimDueEmails(gomock.Any(), gomock.Any(), gomock.Any()).Return([]domain.DueEmail{
+			{ID: 1, UserID: "user-alice", Email: "alice@example.com", Subject: "A", Digest: true, Attempts: 1},
+			{ID: 2, UserID: "user-alice", Email: "alice@example.com", Subject: "B", Digest: true},
+		}, nil)
+		repo.EXPECT().RecordEmailAttempt(gomock.Any(), domain.EmailAttempt{
+			EmailIDs:      []int64{1, 2},
+			Error:         "550 mailbox unavailable",
+			NextAttemptAt: &next,
+		}).Return(nil)
+
+		_, err := newTestEmailNotifier(t, repo, mailer, now).SendDue(context.Background())
+
+		require.NoError(t, err)
+	})
+
+	t.Run("last failed attempt marks emails failed", func(t *testing.T) {
+		mailer := &fakeMailer{failTo: map[string]bool{"bob@example.com": true}}
+		repo := mocks.NewMockNotificationRepositoryInterface(ctrl)
+		repo.EXPECT().ClaimDueEmails(gomock.Any(), gomock.Any(), gomock.Any()).Return([]domain.DueEmail{
+			{ID: 3, UserID: "user-bob", Email: "bob@example.com", Subject: "C", Attempts: 2},
+		}, nil)
+		repo.EXPECT().RecordEmailAttempt(gomock.Any(), domain.EmailAttempt{
+			EmailIDs: []int64{3},
+			Error:    "550 mailbox unavailable",
+		}).Return(nil)
+
+		_, err := newTestEmailNotifier(t, repo, mailer, now).SendDue(context.Background())
+
+		require.NoError(t, err)
+	})
+
+	t.Run("claim error", func(t *testing.T) {
+		repo := mocks.NewMockNotificationRepositoryInterface(ctrl)
+		repo.EXPECT().ClaimDueEmails(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+
+		_, err := newTestEmailNotifier(t, repo, &fakeMailer{}, now).SendDue(context.Background())
+
+		require.Error(t, err)
+	})
+}
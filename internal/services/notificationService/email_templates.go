@@ -0,0 +1,148 @@
+package notificationService
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+)
+
+// defaultEmailTemplates - шаблоны писем по умолчанию; каталог из EMAIL_TEMPLATE_DIR заменяет их целиком
+//
+//go:embed templates/email/*.tmpl
+var defaultEmailTemplates embed.FS
+
+const digestTemplateName = "digest"
+
+// DigestData - данные шаблона дайджеста: по пункту на каждое отложенное письмо
+type DigestData struct {
+	Items []DigestItem
+}
+
+type DigestItem struct {
+	Subject string
+}
+
+// emailTemplate - пара шаблонов письма: <name>.txt.tmpl с блоком "subject" для темы и текстом письма
+// и <name>.html.tmpl с HTML-версией (html/template экранирует подставляемые значения)
+type emailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// EmailTemplates - разобранные шаблоны писем по видам уведомлений и шаблон дайджеста
+type EmailTemplates struct {
+	byKind map[domain.NotificationKind]emailTemplate
+	digest emailTemplate
+}
+
+// LoadEmailTemplates читает шаблоны из каталога dir, а если он не задан - шаблоны по умолчанию
+func LoadEmailTemplates(dir string) (*EmailTemplates, error) {
+	if dir != "" {
+		return ParseEmailTemplates(os.DirFS(dir))
+	}
+
+	fsys, err := fs.Sub(defaultEmailTemplates, "templates/email")
+	if err != nil {
+		return nil, err
+	}
+	return ParseEmailTemplates(fsys)
+}
+
+// ParseEmailTemplates разбирает шаблоны всех видов уведомлений и дайджеста и, как и для чата,
+// проверяет их на пустых данных при старте
+func ParseEmailTemplates(fsys fs.FS) (*EmailTemplates, error) {
+	templates := &EmailTemplates{byKind: make(map[domain.NotificationKind]emailTemplate, len(domain.NotificationKinds))}
+
+	for _, kind := range domain.NotificationKinds {
+		tmpl, err := parseEmailTemplate(fsys, strings.ToLower(string(kind)), MessageData{})
+		if err != nil {
+			return nil, err
+		}
+		templates.byKind[kind] = tmpl
+	}
+
+	digest, err := parseEmailTemplate(fsys, digestTemplateName, DigestData{Items: []DigestItem{{}}})
+	if err != nil {
+		return nil, err
+	}
+	templates.digest = digest
+
+	return templates, nil
+}
+
+func parseEmailTemplate(fsys fs.FS, name string, checkData any) (emailTemplate, error) {
+	var tmpl emailTemplate
+
+	source, err := fs.ReadFile(fsys, name+".txt.tmpl")
+	if err != nil {
+		return tmpl, err
+	}
+	tmpl.text, err = texttemplate.New(name).Option("missingkey=error").Parse(string(source))
+	if err != nil {
+		return tmpl, fmt.Errorf("parse %s text template: %w", name, err)
+	}
+	if tmpl.text.Lookup("subject") == nil {
+		return tmpl, fmt.Errorf("%s text template has no \"subject\" block", name)
+	}
+
+	source, err = fs.ReadFile(fsys, name+".html.tmpl")
+	if err != nil {
+		return tmpl, err
+	}
+	tmpl.html, err = htmltemplate.New(name).Option("missingkey=error").Parse(string(source))
+	if err != nil {
+		return tmpl, fmt.Errorf("parse %s html template: %w", name, err)
+	}
+
+	if _, err = tmpl.render(checkData); err != nil {
+		return tmpl, fmt.Errorf("check %s template: %w", name, err)
+	}
+
+	return tmpl, nil
+}
+
+// RenderedEmail - тема и тело письма в двух вариантах
+type RenderedEmail struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+func (t emailTemplate) render(data any) (RenderedEmail, error) {
+	var subject, text, html strings.Builder
+
+	if err := t.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return RenderedEmail{}, err
+	}
+	if err := t.text.Execute(&text, data); err != nil {
+		return RenderedEmail{}, err
+	}
+	if err := t.html.Execute(&html, data); err != nil {
+		return RenderedEmail{}, err
+	}
+
+	return RenderedEmail{
+		// Перевод строки в теме сломал бы заголовки письма
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+func (t *EmailTemplates) Render(kind domain.NotificationKind, data MessageData) (RenderedEmail, error) {
+	tmpl, ok := t.byKind[kind]
+	if !ok {
+		return RenderedEmail{}, fmt.Errorf("no email template for %s notification", kind)
+	}
+	return tmpl.render(data)
+}
+
+func (t *EmailTemplates) RenderDigest(data DigestData) (RenderedEmail, error) {
+	return t.digest.render(data)
+}
@@ -0,0 +1,78 @@
+package notificationService
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEmailTemplates(t *testing.T) {
+	t.Run("default templates render", func(t *testing.T) {
+		templates, err := LoadEmailTemplates("")
+		require.NoError(t, err)
+
+		rendered, err := templates.Render(domain.NotificationAssigned, MessageData{
+			Username:        "Alice",
+			PullRequestID:   "pr-1",
+			PullRequestName: "Add <search>",
+			AuthorID:        "user-bob",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Review requested: Add <search>", rendered.Subject)
+		assert.Equal(t, "Hi Alice,\n\nyou were assigned to review \"Add <search>\" (pr-1) by user-bob.\n", rendered.Text)
+		// в HTML-версии подставляемые значения экранируются
+		assert.Contains(t, rendered.HTML, "<b>Add &lt;search&gt;</b>")
+	})
+
+	t.Run("templates from directory", func(t *testing.T) {
+		fsys := fstest.MapFS{}
+		for _, name := range []string{"assigned", "unassigned", "pr_merged", "reminder"} {
+			fsys[name+".txt.tmpl"] = &fstest.MapFile{Data: []byte(`{{define "subject"}}` + name + `
+{{.PullRequestID}}{{end}}text`)}
+			fsys[name+".html.tmpl"] = &fstest.MapFile{Data: []byte("<p>html</p>")}
+		}
+		fsys["digest.txt.tmpl"] = &fstest.MapFile{Data: []byte(`{{define "subject"}}digest{{end}}{{range .Items}}{{.Subject}}{{end}}`)}
+		fsys["digest.html.tmpl"] = &fstest.MapFile{Data: []byte("<p>digest</p>")}
+
+		templates, err := ParseEmailTemplates(fsys)
+		require.NoError(t, err)
+
+		rendered, err := templates.Render(domain.NotificationPRMerged, MessageData{PullRequestID: "pr-1"})
+		require.NoError(t, err)
+		// перевод строки в теме схлопывается
+		assert.Equal(t, "pr_merged pr-1", rendered.Subject)
+
+		digest, err := templates.RenderDigest(DigestData{Items: []DigestItem{{Subject: "a"}, {Subject: "b"}}})
+		require.NoError(t, err)
+		assert.Equal(t, "ab", digest.Text)
+	})
+
+	t.Run("missing subject block", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"assigned.txt.tmpl":  &fstest.MapFile{Data: []byte("text")},
+			"assigned.html.tmpl": &fstest.MapFile{Data: []byte("<p>html</p>")},
+		}
+
+		_, err := ParseEmailTemplates(fsys)
+		require.Error(t, err)
+	})
+
+	t.Run("unknown field is rejected at parse time", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"assigned.txt.tmpl":  &fstest.MapFile{Data: []byte(`{{define "subject"}}s{{end}}{{.Reviewer}}`)},
+			"assigned.html.tmpl": &fstest.MapFile{Data: []byte("<p>html</p>")},
+		}
+
+		_, err := ParseEmailTemplates(fsys)
+		require.Error(t, err)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := LoadEmailTemplates(t.TempDir())
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,34 @@
+package notificationService
+
+import (
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/storage"
+)
+
+type NotificationServiceImpl struct {
+	notificationRepo storage.NotificationRepositoryInterface
+	prRepo           storage.PullRequestRepositoryInterface
+	prReviewersRepo  storage.PrReviewersRepositoryInterface
+	templates        *Templates
+	// emailTemplates - nil, если SMTP не настроен: письма тогда не ставятся в очередь
+	emailTemplates *EmailTemplates
+	now            func() time.Time
+}
+
+func NewNotificationService(
+	notificationRepo storage.NotificationRepositoryInterface,
+	prRepo storage.PullRequestRepositoryInterface,
+	prReviewersRepo storage.PrReviewersRepositoryInterface,
+	templates *Templates,
+	emailTemplates *EmailTemplates,
+) *NotificationServiceImpl {
+	return &NotificationServiceImpl{
+		notificationRepo: notificationRepo,
+		prRepo:           prRepo,
+		prReviewersRepo:  prReviewersRepo,
+		templates:        templates,
+		emailTemplates:   emailTemplates,
+		now:              time.Now,
+	}
+}
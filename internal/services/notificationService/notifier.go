@@ -0,0 +1,152 @@
+package notificationService
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// maxErrorBodyBytes - сколько байт ответа чата сохраняется в last_error
+const maxErrorBodyBytes = 512
+
+// NotifierConfig - параметры отправки чата и почты. Попытка N (с единицы) при неуспехе повторяется
+// через BaseBackoff * 2^(N-1), но не позже MaxBackoff; после MaxAttempts уведомление переходит в FAILED
+type NotifierConfig struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	RequestTimeout time.Duration
+	MaxAttempts    int
+	BaseBackoff    time.Duration
+	MaxBackoff     time.Duration
+}
+
+func DefaultNotifierConfig() NotifierConfig {
+	return NotifierConfig{
+		PollInterval:   time.Second,
+		BatchSize:      50,
+		RequestTimeout: 5 * time.Second,
+		MaxAttempts:    5,
+		BaseBackoff:    5 * time.Second,
+		MaxBackoff:     10 * time.Minute,
+	}
+}
+
+// Notifier отправляет уведомления из очереди в incoming-webhook чата (тело {"text": ...},
+// его принимают и Slack, и Mattermost)
+type Notifier struct {
+	notificationRepo storage.NotificationRepositoryInterface
+	client           *http.Client
+	config           NotifierConfig
+	now              func() time.Time
+}
+
+func NewNotifier(
+	notificationRepo storage.NotificationRepositoryInterface,
+	client *http.Client,
+	config NotifierConfig,
+) *Notifier {
+	return &Notifier{
+		notificationRepo: notificationRepo,
+		client:           client,
+		config:           config,
+		now:              time.Now,
+	}
+}
+
+// Run отправляет уведомления из очереди до отмены ctx
+func (n *Notifier) Run(ctx context.Context) {
+	utils.PollBatches(ctx, "chat notifier", n.config.PollInterval, n.config.BatchSize, n.SendDue)
+}
+
+// SendDue забирает одну пачку уведомлений и отправляет их параллельно. Возвращает размер пачки
+func (n *Notifier) SendDue(ctx context.Context) (int, error) {
+	lease := 2 * n.config.RequestTimeout
+
+	notifications, err := n.notificationRepo.ClaimDueNotifications(ctx, n.config.BatchSize, lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, notification := range notifications {
+		wg.Add(1)
+		go func(notification domain.DueNotification) {
+			defer wg.Done()
+			n.notify(ctx, notification)
+		}(notification)
+	}
+	wg.Wait()
+
+	return len(notifications), nil
+}
+
+func (n *Notifier) notify(ctx context.Context, notification domain.DueNotification) {
+	attempt := domain.NotificationAttempt{NotificationID: notification.ID}
+	if err := n.send(ctx, notification); err != nil {
+		attempt.Error = err.Error()
+	} else {
+		attempt.Sent = true
+	}
+
+	attemptNumber := notification.Attempts + 1
+	if !attempt.Sent && attemptNumber < n.config.MaxAttempts {
+		next := n.now().Add(utils.ExponentialBackoff(n.config.BaseBackoff, n.config.MaxBackoff, attemptNumber))
+		attempt.NextAttemptAt = &next
+	}
+
+	if err := n.notificationRepo.RecordNotificationAttempt(ctx, attempt); err != nil {
+		// Уведомление вернётся в очередь по истечении аренды
+		logger.Logger.Error("error recording chat notification attempt: ", err)
+		return
+	}
+
+	if !attempt.Sent {
+		logger.Logger.Warnw("chat notification failed",
+			"notification_id", notification.ID,
+			"attempt", attemptNumber,
+			"next_attempt_at", attempt.NextAttemptAt,
+			"error", attempt.Error,
+		)
+	}
+}
+
+// send выполняет одну попытку; успехом считается любой 2xx
+func (n *Notifier) send(ctx context.Context, notification domain.DueNotification) error {
+	body, err := json.Marshal(map[string]string{"text": notification.Text})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.config.RequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notification.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	return fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+}
@@ -0,0 +1,156 @@
+package notificationService
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	logger.Logger = zap.NewNop().Sugar()
+}
+
+// testChat - httptest-замена incoming-webhook, отвечает заданными статусами по очереди
+type testChat struct {
+	t        *testing.T
+	statuses []int
+
+	mu    sync.Mutex
+	texts []string
+}
+
+func (c *testChat) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	require.NoError(c.t, err)
+
+	var message struct {
+		Text string `json:"text"`
+	}
+	require.NoError(c.t, json.Unmarshal(body, &message))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.texts = append(c.texts, message.Text)
+
+	status := http.StatusOK
+	if len(c.statuses) > 0 {
+		status = c.statuses[0]
+		c.statuses = c.statuses[1:]
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte("invalid_payload"))
+}
+
+func newTestNotifier(repo *mocks.MockNotificationRepositoryInterface, now time.Time) *Notifier {
+	notifier := NewNotifier(repo, http.DefaultClient, NotifierConfig{
+		PollInterval:   10 * time.Millisecond,
+		BatchSize:      10,
+		RequestTimeout: time.Second,
+		MaxAttempts:    3,
+		BaseBackoff:    5 * time.Second,
+		MaxBackoff:     8 * time.Second,
+	})
+	notifier.now = func() time.Time { return now }
+	return notifier
+}
+
+func TestNotifier_SendDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 11, 30, 10, 0, 0, 0, time.UTC)
+
+	t.Run("message is posted and marked sent", func(t *testing.T) {
+		chat := &testChat{t: t}
+		server := httptest.NewServer(chat)
+		defer server.Close()
+
+		repo := mocks.NewMockNotificationRepositoryInterface(ctrl)
+		repo.EXPECT().ClaimDueNotifications(gomock.Any(), 10, 2*time.Second).
+			Return([]domain.DueNotification{{ID: 4, URL: server.URL, Text: "@alice, review pr-1"}}, nil)
+		repo.EXPECT().RecordNotificationAttempt(gomock.Any(), domain.NotificationAttempt{NotificationID: 4, Sent: true}).
+			Return(nil)
+
+		claimed, err := newTestNotifier(repo, now).SendDue(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, claimed)
+		assert.Equal(t, []string{"@alice, review pr-1"}, chat.texts)
+	})
+
+	t.Run("failure schedules retry with backoff", func(t *testing.T) {
+		chat := &testChat{t: t, statuses: []int{http.StatusBadRequest}}
+		server := httptest.NewServer(chat)
+		defer server.Close()
+
+		next := now.Add(8 * time.Second) // 5s * 2^1 = 10s, ограничено MaxBackoff
+		repo := mocks.NewMockNotificationRepositoryInterface(ctrl)
+		repo.EXPECT().ClaimDueNotifications(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]domain.DueNotification{{ID: 4, URL: server.URL, Text: "hi", Attempts: 1}}, nil)
+		repo.EXPECT().RecordNotificationAttempt(gomock.Any(), domain.NotificationAttempt{
+			NotificationID: 4,
+			Error:          "HTTP 400: invalid_payload",
+			NextAttemptAt:  &next,
+		}).Return(nil)
+
+		_, err := newTestNotifier(repo, now).SendDue(context.Background())
+
+		require.NoError(t, err)
+	})
+
+	t.Run("last failed attempt marks notification failed", func(t *testing.T) {
+		chat := &testChat{t: t, statuses: []int{http.StatusInternalServerError}}
+		server := httptest.NewServer(chat)
+		defer server.Close()
+
+		repo := mocks.NewMockNotificationRepositoryInterface(ctrl)
+		repo.EXPECT().ClaimDueNotifications(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]domain.DueNotification{{ID: 4, URL: server.URL, Text: "hi", Attempts: 2}}, nil)
+		repo.EXPECT().RecordNotificationAttempt(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, attempt domain.NotificationAttempt) error {
+				assert.False(t, attempt.Sent)
+				assert.Nil(t, attempt.NextAttemptAt)
+				return nil
+			})
+
+		_, err := newTestNotifier(repo, now).SendDue(context.Background())
+
+		require.NoError(t, err)
+	})
+
+	t.Run("unreachable chat is retried", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		url := server.URL
+		server.Close()
+
+		repo := mocks.NewMockNotificationRepositoryInterface(ctrl)
+		repo.EXPECT().ClaimDueNotifications(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]domain.DueNotification{{ID: 4, URL: url, Text: "hi"}}, nil)
+		repo.EXPECT().RecordNotificationAttempt(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, attempt domain.NotificationAttempt) error {
+				assert.NotEmpty(t, attempt.Error)
+				assert.Equal(t, now.Add(5*time.Second), *attempt.NextAttemptAt)
+				return nil
+			})
+
+		_, err := newTestNotifier(repo, now).SendDue(context.Background())
+
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,133 @@
+package notificationService
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/notificationStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// GetPreferences возвращает настройки уведомлений пользователя (по умолчанию, если он их не менял)
+func (s *NotificationServiceImpl) GetPreferences(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"user_id query parameter is required",
+		))
+		return
+	}
+
+	prefs, err := s.notificationRepo.GetNotificationPreferences(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
+				domain.NotFound,
+				"user not found",
+			))
+			return
+		}
+		logger.Logger.Error("error getting notification preferences: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrNotifyPrefsMsg,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// SetPreferences заменяет настройки уведомлений пользователя целиком
+func (s *NotificationServiceImpl) SetPreferences(c *gin.Context) {
+	var prefs domain.NotificationPreferences
+	if err := c.ShouldBindJSON(&prefs); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid request body",
+		))
+		return
+	}
+
+	if prefs.Timezone == "" {
+		prefs.Timezone = "UTC"
+	}
+	if err := validatePreferences(prefs); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			err.Error(),
+		))
+		return
+	}
+
+	if err := s.notificationRepo.UpsertNotificationPreferences(c.Request.Context(), prefs); err != nil {
+		if errors.Is(err, notificationStorage.ErrUserNotExists) {
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
+				domain.NotFound,
+				"user not found",
+			))
+			return
+		}
+		logger.Logger.Error("error saving notification preferences: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrNotifyPrefsMsg,
+		))
+		return
+	}
+
+	logger.Logger.Infow("notification preferences set", "user_id", prefs.UserID)
+	c.JSON(http.StatusOK, prefs)
+}
+
+func validatePreferences(prefs domain.NotificationPreferences) error {
+	for _, channel := range prefs.Channels {
+		if !channel.IsValid() {
+			return fmt.Errorf("unknown channel %q", channel)
+		}
+	}
+	if !prefs.Delivery.IsValid() {
+		return fmt.Errorf("delivery must be %s or %s", domain.DeliveryImmediate, domain.DeliveryDigest)
+	}
+
+	if prefs.Email != nil {
+		address, err := mail.ParseAddress(*prefs.Email)
+		if err != nil || address.Address != *prefs.Email {
+			return errors.New("email must be a plain address like user@example.com")
+		}
+	} else if prefs.Allows(domain.NotificationChannelEmail) {
+		return errors.New("email is required for EMAIL channel")
+	}
+
+	if !isValidHour(prefs.DigestHour) {
+		return errors.New("digest_hour must be between 0 and 23")
+	}
+	if (prefs.QuietHoursStart == nil) != (prefs.QuietHoursEnd == nil) {
+		return errors.New("quiet_hours_start and quiet_hours_end must be set together")
+	}
+	if prefs.QuietHoursStart != nil {
+		if !isValidHour(*prefs.QuietHoursStart) || !isValidHour(*prefs.QuietHoursEnd) {
+			return errors.New("quiet hours must be between 0 and 23")
+		}
+		if *prefs.QuietHoursStart == *prefs.QuietHoursEnd {
+			return errors.New("quiet_hours_start and quiet_hours_end must differ")
+		}
+	}
+
+	if _, err := time.LoadLocation(prefs.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", prefs.Timezone)
+	}
+
+	return nil
+}
+
+func isValidHour(hour int) bool {
+	return hour >= 0 && hour <= 23
+}
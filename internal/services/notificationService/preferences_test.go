@@ -0,0 +1,122 @@
+package notificationService
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/notificationStorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationService_GetPreferences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, notificationRepo, _ := newTestService(t, ctrl)
+
+	t.Run("success", func(t *testing.T) {
+		prefs := domain.DefaultNotificationPreferences("user-alice")
+		notificationRepo.EXPECT().GetNotificationPreferences(gomock.Any(), "user-alice").Return(&prefs, nil)
+
+		c, w := newJSONContext(http.MethodGet, "/notifications/preferences?user_id=user-alice", "")
+		service.GetPreferences(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"user_id":"user-alice","channels":["CHAT"],"delivery":"IMMEDIATE","digest_hour":9,"timezone":"UTC"}`,
+			w.Body.String())
+	})
+
+	t.Run("missing user_id", func(t *testing.T) {
+		c, w := newJSONContext(http.MethodGet, "/notifications/preferences", "")
+		service.GetPreferences(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		notificationRepo.EXPECT().GetNotificationPreferences(gomock.Any(), "user-x").Return(nil, pgx.ErrNoRows)
+
+		c, w := newJSONContext(http.MethodGet, "/notifications/preferences?user_id=user-x", "")
+		service.GetPreferences(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		notificationRepo.EXPECT().GetNotificationPreferences(gomock.Any(), "user-alice").Return(nil, errors.New("db error"))
+
+		c, w := newJSONContext(http.MethodGet, "/notifications/preferences?user_id=user-alice", "")
+		service.GetPreferences(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestNotificationService_SetPreferences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, notificationRepo, _ := newTestService(t, ctrl)
+
+	t.Run("success", func(t *testing.T) {
+		email := "alice@example.com"
+		quietStart, quietEnd := 22, 7
+		notificationRepo.EXPECT().UpsertNotificationPreferences(gomock.Any(), domain.NotificationPreferences{
+			UserID:          "user-alice",
+			Email:           &email,
+			Channels:        []domain.NotificationChannel{domain.NotificationChannelEmail},
+			Delivery:        domain.DeliveryDigest,
+			DigestHour:      9,
+			QuietHoursStart: &quietStart,
+			QuietHoursEnd:   &quietEnd,
+			Timezone:        "UTC",
+		}).Return(nil)
+
+		body := `{"user_id":"user-alice","email":"alice@example.com","channels":["EMAIL"],"delivery":"DIGEST",` +
+			`"digest_hour":9,"quiet_hours_start":22,"quiet_hours_end":7}`
+		c, w := newJSONContext(http.MethodPost, "/notifications/preferences", body)
+		service.SetPreferences(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"timezone":"UTC"`)
+	})
+
+	invalid := []struct {
+		name string
+		body string
+	}{
+		{"missing channels", `{"user_id":"user-alice","delivery":"IMMEDIATE"}`},
+		{"unknown channel", `{"user_id":"user-alice","channels":["SMS"],"delivery":"IMMEDIATE"}`},
+		{"unknown delivery", `{"user_id":"user-alice","channels":[],"delivery":"WEEKLY"}`},
+		{"email channel without address", `{"user_id":"user-alice","channels":["EMAIL"],"delivery":"IMMEDIATE"}`},
+		{"invalid email", `{"user_id":"user-alice","email":"Alice <alice@example.com>","channels":[],"delivery":"IMMEDIATE"}`},
+		{"digest hour out of range", `{"user_id":"user-alice","channels":[],"delivery":"DIGEST","digest_hour":24}`},
+		{"only quiet hours start", `{"user_id":"user-alice","channels":[],"delivery":"IMMEDIATE","quiet_hours_start":22}`},
+		{"empty quiet hours", `{"user_id":"user-alice","channels":[],"delivery":"IMMEDIATE","quiet_hours_start":5,"quiet_hours_end":5}`},
+		{"unknown timezone", `{"user_id":"user-alice","channels":[],"delivery":"IMMEDIATE","timezone":"Mars/Olympus"}`},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newJSONContext(http.MethodPost, "/notifications/preferences", tt.body)
+			service.SetPreferences(c)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+
+	t.Run("user not found", func(t *testing.T) {
+		notificationRepo.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).
+			Return(notificationStorage.ErrUserNotExists)
+
+		c, w := newJSONContext(http.MethodPost, "/notifications/preferences",
+			`{"user_id":"user-x","channels":["CHAT"],"delivery":"IMMEDIATE"}`)
+		service.SetPreferences(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
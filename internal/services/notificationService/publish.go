@@ -0,0 +1,218 @@
+package notificationService
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// notificationTarget - кому и о чём сообщить по событию
+type notificationTarget struct {
+	kind   domain.NotificationKind
+	userID string
+}
+
+// eventNotifications - разобранное событие: PR, о котором речь, и получатели уведомлений
+type eventNotifications struct {
+	data    MessageData
+	targets []notificationTarget
+}
+
+// Publish превращает события в уведомления ревьюверам и ставит их в очереди отправки чата и почты;
+// отправка идёт в Notifier и EmailNotifier, поэтому на время обработки запросов она не влияет.
+// Уведомления получают только активные пользователи по каналам из их настроек: в чат - если у основной
+// команды задан канал, на почту - если указан адрес
+func (s *NotificationServiceImpl) Publish(ctx context.Context, events ...domain.Event) error {
+	for _, event := range events {
+		parsed, err := parseEvent(event)
+		if err != nil {
+			// Повтор не поможет: событие пропускается, чтобы не блокировать остальные
+			logger.Logger.Errorw("error parsing event for notifications",
+				"event_id", event.ID,
+				"event_type", event.Type,
+				"error", err,
+			)
+			continue
+		}
+		if len(parsed.targets) == 0 {
+			continue
+		}
+
+		chat, emails, err := s.buildNotifications(ctx, event.ID, parsed)
+		if err != nil {
+			return err
+		}
+
+		if len(chat) > 0 {
+			if err = s.notificationRepo.EnqueueNotifications(ctx, chat); err != nil {
+				return err
+			}
+		}
+		if len(emails) > 0 {
+			if err = s.notificationRepo.EnqueueEmails(ctx, emails); err != nil {
+				return err
+			}
+		}
+
+		if len(chat) > 0 || len(emails) > 0 {
+			logger.Logger.Debugw("notifications enqueued",
+				"event_id", event.ID,
+				"event_type", event.Type,
+				"chat", len(chat),
+				"emails", len(emails),
+			)
+		}
+	}
+
+	return nil
+}
+
+// parseEvent определяет получателей: назначенный ревьювер, снятый ревьювер или все ревьюверы смердженного PR
+func parseEvent(event domain.Event) (eventNotifications, error) {
+	var parsed eventNotifications
+
+	switch event.Type {
+	case domain.EventReviewerAssigned, domain.EventReviewerReassigned:
+		var data domain.ReviewerEventData
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return parsed, err
+		}
+
+		parsed.data = MessageData{PullRequestID: data.PullRequestID, Strategy: data.Strategy}
+		if data.OldReviewerID != "" {
+			parsed.targets = append(parsed.targets, notificationTarget{domain.NotificationUnassigned, data.OldReviewerID})
+		}
+		if data.ReviewerID != "" {
+			parsed.targets = append(parsed.targets, notificationTarget{domain.NotificationAssigned, data.ReviewerID})
+		}
+
+	case domain.EventPRMerged:
+		var data domain.PullRequestEventData
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return parsed, err
+		}
+
+		parsed.data = MessageData{
+			PullRequestID:   data.PullRequestID,
+			PullRequestName: data.PullRequestName,
+			AuthorID:        data.AuthorID,
+		}
+		for _, reviewerID := range data.AssignedReviewers {
+			parsed.targets = append(parsed.targets, notificationTarget{domain.NotificationPRMerged, reviewerID})
+		}
+	}
+
+	return parsed, nil
+}
+
+func (s *NotificationServiceImpl) buildNotifications(
+	ctx context.Context,
+	eventID string,
+	parsed eventNotifications,
+) ([]domain.ChatNotification, []domain.EmailNotification, error) {
+	userIDs := make([]string, 0, len(parsed.targets))
+	for _, target := range parsed.targets {
+		userIDs = append(userIDs, target.userID)
+	}
+
+	recipients, err := s.notificationRepo.GetNotificationRecipients(ctx, userIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byUserID := make(map[string]domain.NotificationRecipient, len(recipients))
+	for _, recipient := range recipients {
+		if recipient.IsActive && (s.wantsChat(recipient) || s.wantsEmail(recipient)) {
+			byUserID[recipient.UserID] = recipient
+		}
+	}
+	if len(byUserID) == 0 {
+		return nil, nil, nil
+	}
+
+	data := parsed.data
+	// В событиях о ревьюверах нет названия PR и автора - берём их из PR
+	if data.PullRequestName == "" {
+		pr, err := s.prRepo.GetPullRequestByID(ctx, data.PullRequestID)
+		switch {
+		case err == nil:
+			data.PullRequestName = pr.PullRequestName
+			data.AuthorID = pr.AuthorId
+		case errors.Is(err, pgx.ErrNoRows):
+			data.PullRequestName = data.PullRequestID
+		default:
+			return nil, nil, err
+		}
+	}
+
+	now := s.now()
+	chat := make([]domain.ChatNotification, 0, len(parsed.targets))
+	emails := make([]domain.EmailNotification, 0)
+	for _, target := range parsed.targets {
+		recipient, ok := byUserID[target.userID]
+		if !ok {
+			continue
+		}
+		prefs := recipient.EffectivePreferences()
+
+		data.UserID = recipient.UserID
+		data.Username = recipient.Username
+		data.Mention = recipient.Username
+		if recipient.Mention != nil {
+			data.Mention = *recipient.Mention
+		}
+
+		if s.wantsChat(recipient) {
+			text, err := s.templates.Render(target.kind, data)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			chat = append(chat, domain.ChatNotification{
+				EventID:   eventID,
+				Kind:      target.kind,
+				UserID:    recipient.UserID,
+				URL:       *recipient.ChannelURL,
+				Text:      text,
+				NotBefore: deliverAt(prefs, now, false),
+			})
+		}
+
+		if s.wantsEmail(recipient) {
+			rendered, err := s.emailTemplates.Render(target.kind, data)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			digest := prefs.Delivery == domain.DeliveryDigest
+			emails = append(emails, domain.EmailNotification{
+				EventID:   eventID,
+				Kind:      target.kind,
+				UserID:    recipient.UserID,
+				Email:     *prefs.Email,
+				Subject:   rendered.Subject,
+				TextBody:  rendered.Text,
+				HTMLBody:  rendered.HTML,
+				Digest:    digest,
+				NotBefore: deliverAt(prefs, now, digest),
+			})
+		}
+	}
+
+	return chat, emails, nil
+}
+
+// wantsChat - у команды есть канал и пользователь не отключил чат
+func (s *NotificationServiceImpl) wantsChat(recipient domain.NotificationRecipient) bool {
+	return recipient.ChannelURL != nil && recipient.EffectivePreferences().Allows(domain.NotificationChannelChat)
+}
+
+// wantsEmail - почта настроена, у пользователя есть адрес и он включил письма
+func (s *NotificationServiceImpl) wantsEmail(recipient domain.NotificationRecipient) bool {
+	prefs := recipient.EffectivePreferences()
+	return s.emailTemplates != nil && prefs.Email != nil && prefs.Allows(domain.NotificationChannelEmail)
+}
@@ -0,0 +1,293 @@
+package notificationService
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChannelURL = "https://hooks.slack.com/services/T000/B000/XXX"
+
+// testNow - 13:00 по Москве
+var testNow = time.Date(2025, 11, 30, 10, 0, 0, 0, time.UTC)
+
+func newTestService(t *testing.T, ctrl *gomock.Controller) (
+	*NotificationServiceImpl,
+	*mocks.MockNotificationRepositoryInterface,
+	*mocks.MockPullRequestRepositoryInterface,
+) {
+	service, notificationRepo, prRepo, _ := newTestServiceWithReviewers(t, ctrl)
+	return service, notificationRepo, prRepo
+}
+
+func newTestServiceWithReviewers(t *testing.T, ctrl *gomock.Controller) (
+	*NotificationServiceImpl,
+	*mocks.MockNotificationRepositoryInterface,
+	*mocks.MockPullRequestRepositoryInterface,
+	*mocks.MockPrReviewersRepositoryInterface,
+) {
+	templates, err := ParseTemplates(map[domain.NotificationKind]string{
+		domain.NotificationAssigned:   "{{.Mention}} assigned to {{.PullRequestName}} by {{.AuthorID}}",
+		domain.NotificationUnassigned: "{{.Mention}} unassigned from {{.PullRequestName}}",
+		domain.NotificationPRMerged:   "{{.Mention}} {{.PullRequestName}} merged",
+		domain.NotificationReminder: "{{.Mention}}: {{range .PendingReviews}}" +
+			"[{{.PullRequestID}} {{.Age}}{{if .OverSLA}} SLA{{end}}]{{end}}",
+	})
+	require.NoError(t, err)
+
+	emailTemplates, err := LoadEmailTemplates("")
+	require.NoError(t, err)
+
+	notificationRepo := mocks.NewMockNotificationRepositoryInterface(ctrl)
+	prRepo := mocks.NewMockPullRequestRepositoryInterface(ctrl)
+	prReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
+	service := NewNotificationService(notificationRepo, prRepo, prReviewersRepo, templates, emailTemplates)
+	service.now = func() time.Time { return testNow }
+	return service, notificationRepo, prRepo, prReviewersRepo
+}
+
+func recipient(userID, username string, isActive bool, url, mention *string) domain.NotificationRecipient {
+	return domain.NotificationRecipient{
+		UserID:     userID,
+		Username:   username,
+		IsActive:   isActive,
+		ChannelURL: url,
+		Mention:    mention,
+	}
+}
+
+func TestNotificationService_Publish(t *testing.T) {
+	ctx := context.Background()
+	now := testNow
+	channelURL := testChannelURL
+	aliceMention := "<@U024BE7LH>"
+
+	t.Run("reassignment notifies old and new reviewer", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, notificationRepo, prRepo := newTestService(t, ctrl)
+		event, err := domain.NewReviewerReassignedEvent(domain.ReviewerReassignment{
+			PrID:          "pr-1",
+			OldReviewerID: "user-bob",
+			NewReviewerID: "user-alice",
+		}, now)
+		require.NoError(t, err)
+
+		notificationRepo.EXPECT().GetNotificationRecipients(gomock.Any(), []string{"user-bob", "user-alice"}).
+			Return([]domain.NotificationRecipient{
+				recipient("user-alice", "Alice", true, &channelURL, &aliceMention),
+				recipient("user-bob", "Bob", true, &channelURL, nil),
+			}, nil)
+		prRepo.EXPECT().GetPullRequestByID(gomock.Any(), "pr-1").
+			Return(&domain.PullRequest{PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "user-carol"}, nil)
+		notificationRepo.EXPECT().EnqueueNotifications(gomock.Any(), []domain.ChatNotification{
+			{EventID: event.ID, Kind: domain.NotificationUnassigned, UserID: "user-bob", URL: testChannelURL,
+				Text: "Bob unassigned from Add search"},
+			{EventID: event.ID, Kind: domain.NotificationAssigned, UserID: "user-alice", URL: testChannelURL,
+				Text: "<@U024BE7LH> assigned to Add search by user-carol"},
+		}).Return(nil)
+
+		require.NoError(t, service.Publish(ctx, event))
+	})
+
+	t.Run("merge notifies every reviewer with a channel", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, notificationRepo, _ := newTestService(t, ctrl)
+		event, err := domain.NewPullRequestMergedEvent(&domain.PullRequest{
+			PullRequestId:     "pr-1",
+			PullRequestName:   "Add search",
+			AuthorId:          "user-carol",
+			Status:            domain.PullRequestStatusMERGED,
+			AssignedReviewers: []string{"user-alice", "user-bob", "user-dave"},
+		}, now)
+		require.NoError(t, err)
+
+		// bob неактивен, у команды dave нет канала - им уведомления не нужны
+		notificationRepo.EXPECT().GetNotificationRecipients(gomock.Any(), []string{"user-alice", "user-bob", "user-dave"}).
+			Return([]domain.NotificationRecipient{
+				recipient("user-alice", "Alice", true, &channelURL, &aliceMention),
+				recipient("user-bob", "Bob", false, &channelURL, nil),
+				recipient("user-dave", "Dave", true, nil, nil),
+			}, nil)
+		notificationRepo.EXPECT().EnqueueNotifications(gomock.Any(), []domain.ChatNotification{
+			{EventID: event.ID, Kind: domain.NotificationPRMerged, UserID: "user-alice", URL: testChannelURL,
+				Text: "<@U024BE7LH> Add search merged"},
+		}).Return(nil)
+
+		require.NoError(t, service.Publish(ctx, event))
+	})
+
+	t.Run("nobody to notify", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, notificationRepo, _ := newTestService(t, ctrl)
+		event, err := domain.NewEvent(domain.EventReviewerAssigned, now, domain.ReviewerEventData{
+			PullRequestID: "pr-1",
+			ReviewerID:    "user-dave",
+		})
+		require.NoError(t, err)
+
+		notificationRepo.EXPECT().GetNotificationRecipients(gomock.Any(), []string{"user-dave"}).
+			Return([]domain.NotificationRecipient{recipient("user-dave", "Dave", true, nil, nil)}, nil)
+
+		require.NoError(t, service.Publish(ctx, event))
+	})
+
+	t.Run("deleted PR falls back to its ID", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, notificationRepo, prRepo := newTestService(t, ctrl)
+		event, err := domain.NewEvent(domain.EventReviewerAssigned, now, domain.ReviewerEventData{
+			PullRequestID: "pr-1",
+			ReviewerID:    "user-alice",
+		})
+		require.NoError(t, err)
+
+		notificationRepo.EXPECT().GetNotificationRecipients(gomock.Any(), gomock.Any()).
+			Return([]domain.NotificationRecipient{recipient("user-alice", "Alice", true, &channelURL, nil)}, nil)
+		prRepo.EXPECT().GetPullRequestByID(gomock.Any(), "pr-1").Return(nil, pgx.ErrNoRows)
+		notificationRepo.EXPECT().EnqueueNotifications(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, notifications []domain.ChatNotification) error {
+				require.Len(t, notifications, 1)
+				assert.Equal(t, "Alice assigned to pr-1 by ", notifications[0].Text)
+				return nil
+			})
+
+		require.NoError(t, service.Publish(ctx, event))
+	})
+
+	t.Run("events without reviewers and malformed events are skipped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, _, _ := newTestService(t, ctrl)
+		deactivated, err := domain.NewEvent(domain.EventUserDeactivated, now, domain.UserEventData{UserID: "user-bob"})
+		require.NoError(t, err)
+		malformed := domain.Event{ID: "event-x", Type: domain.EventPRMerged, Data: []byte(`"oops"`)}
+
+		require.NoError(t, service.Publish(ctx, deactivated, malformed))
+	})
+
+	t.Run("email preferences", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, notificationRepo, _ := newTestService(t, ctrl)
+		event, err := domain.NewPullRequestMergedEvent(&domain.PullRequest{
+			PullRequestId:     "pr-1",
+			PullRequestName:   "Add search",
+			AuthorId:          "user-carol",
+			Status:            domain.PullRequestStatusMERGED,
+			AssignedReviewers: []string{"user-alice", "user-bob"},
+		}, now)
+		require.NoError(t, err)
+
+		aliceEmail, bobEmail := "alice@example.com", "bob@example.com"
+		quietStart, quietEnd := 12, 18
+		// alice: только почта, сразу, но сейчас у неё тихие часы
+		alicePrefs := &domain.NotificationPreferences{
+			UserID:          "user-alice",
+			Email:           &aliceEmail,
+			Channels:        []domain.NotificationChannel{domain.NotificationChannelEmail},
+			Delivery:        domain.DeliveryImmediate,
+			QuietHoursStart: &quietStart,
+			QuietHoursEnd:   &quietEnd,
+			Timezone:        "Europe/Moscow",
+		}
+		// bob: чат и дайджест на почту в 9 утра по UTC
+		bobPrefs := &domain.NotificationPreferences{
+			UserID:     "user-bob",
+			Email:      &bobEmail,
+			Channels:   []domain.NotificationChannel{domain.NotificationChannelChat, domain.NotificationChannelEmail},
+			Delivery:   domain.DeliveryDigest,
+			DigestHour: 9,
+			Timezone:   "UTC",
+		}
+		alice := recipient("user-alice", "Alice", true, &channelURL, nil)
+		alice.Preferences = alicePrefs
+		bob := recipient("user-bob", "Bob", true, &channelURL, nil)
+		bob.Preferences = bobPrefs
+
+		notificationRepo.EXPECT().GetNotificationRecipients(gomock.Any(), gomock.Any()).
+			Return([]domain.NotificationRecipient{alice, bob}, nil)
+		notificationRepo.EXPECT().EnqueueNotifications(gomock.Any(), []domain.ChatNotification{
+			{EventID: event.ID, Kind: domain.NotificationPRMerged, UserID: "user-bob", URL: testChannelURL,
+				Text: "Bob Add search merged"},
+		}).Return(nil)
+		notificationRepo.EXPECT().EnqueueEmails(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, emails []domain.EmailNotification) error {
+				require.Len(t, emails, 2)
+
+				assert.Equal(t, "alice@example.com", emails[0].Email)
+				assert.Equal(t, "Merged: Add search", emails[0].Subject)
+				assert.Contains(t, emails[0].TextBody, "Hi Alice,")
+				assert.False(t, emails[0].Digest)
+				assert.Equal(t, time.Date(2025, 11, 30, 15, 0, 0, 0, time.UTC), *emails[0].NotBefore)
+
+				assert.Equal(t, "bob@example.com", emails[1].Email)
+				assert.True(t, emails[1].Digest)
+				assert.Equal(t, time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC), *emails[1].NotBefore)
+				return nil
+			})
+
+		require.NoError(t, service.Publish(ctx, event))
+	})
+
+	t.Run("emails are not enqueued without SMTP", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, notificationRepo, _ := newTestService(t, ctrl)
+		service.emailTemplates = nil
+		event, err := domain.NewEvent(domain.EventReviewerAssigned, now, domain.ReviewerEventData{
+			PullRequestID: "pr-1",
+			ReviewerID:    "user-alice",
+		})
+		require.NoError(t, err)
+
+		email := "alice@example.com"
+		alice := recipient("user-alice", "Alice", true, nil, nil)
+		alice.Preferences = &domain.NotificationPreferences{
+			UserID:   "user-alice",
+			Email:    &email,
+			Channels: []domain.NotificationChannel{domain.NotificationChannelEmail},
+			Delivery: domain.DeliveryImmediate,
+			Timezone: "UTC",
+		}
+
+		notificationRepo.EXPECT().GetNotificationRecipients(gomock.Any(), gomock.Any()).
+			Return([]domain.NotificationRecipient{alice}, nil)
+
+		require.NoError(t, service.Publish(ctx, event))
+	})
+
+	t.Run("storage error is returned for retry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, notificationRepo, _ := newTestService(t, ctrl)
+		event, err := domain.NewEvent(domain.EventReviewerAssigned, now, domain.ReviewerEventData{
+			PullRequestID: "pr-1",
+			ReviewerID:    "user-alice",
+		})
+		require.NoError(t, err)
+
+		notificationRepo.EXPECT().GetNotificationRecipients(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("db error"))
+
+		require.Error(t, service.Publish(ctx, event))
+	})
+}
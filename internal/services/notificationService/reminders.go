@@ -0,0 +1,200 @@
+package notificationService
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// reminderEventPrefix - напоминания не связаны с событием outbox, ключом служит дата у получателя:
+// уникальность (event_id, user_id, kind) в очередях не даёт отправить второе напоминание за день,
+// сколько бы экземпляров сервиса ни запускали задачу
+const reminderEventPrefix = "reminder:"
+
+// SendReminders ставит в очереди чата и почты ежедневное напоминание каждому активному ревьюверу
+// с открытыми PR: список от самого старого PR, просроченные по SLA команды автора выделены.
+// Напоминание уходит, когда у получателя наступил час дайджеста из настроек; повторный вызов в тот же
+// день ничего не добавляет. Возвращает число ревьюверов, которым напоминание было поставлено в очередь
+func (s *NotificationServiceImpl) SendReminders(ctx context.Context) (int, error) {
+	reviews, err := s.prReviewersRepo.GetPendingReviews(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(reviews) == 0 {
+		return 0, nil
+	}
+
+	// отзывы уже отсортированы по ревьюверу и возрасту PR
+	byReviewer := make(map[string][]domain.PendingReview)
+	reviewerIDs := make([]string, 0)
+	for _, review := range reviews {
+		if _, ok := byReviewer[review.ReviewerID]; !ok {
+			reviewerIDs = append(reviewerIDs, review.ReviewerID)
+		}
+		byReviewer[review.ReviewerID] = append(byReviewer[review.ReviewerID], review)
+	}
+
+	recipients, err := s.notificationRepo.GetNotificationRecipients(ctx, reviewerIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.now()
+	chat := make([]domain.ChatNotification, 0)
+	emails := make([]domain.EmailNotification, 0)
+	reminded := 0
+	for _, recipient := range recipients {
+		if !recipient.IsActive {
+			continue
+		}
+		wantsChat, wantsEmail := s.wantsChat(recipient), s.wantsEmail(recipient)
+		if !wantsChat && !wantsEmail {
+			continue
+		}
+
+		prefs := recipient.EffectivePreferences()
+		local := now.In(userLocation(prefs))
+		if local.Hour() < prefs.DigestHour {
+			continue
+		}
+		eventID := reminderEventPrefix + local.Format(time.DateOnly)
+
+		data := MessageData{
+			Mention:        recipient.Username,
+			Username:       recipient.Username,
+			UserID:         recipient.UserID,
+			PendingReviews: pendingReviewItems(byReviewer[recipient.UserID], now),
+		}
+		if recipient.Mention != nil {
+			data.Mention = *recipient.Mention
+		}
+
+		if wantsChat {
+			text, err := s.templates.Render(domain.NotificationReminder, data)
+			if err != nil {
+				return 0, err
+			}
+			chat = append(chat, domain.ChatNotification{
+				EventID:   eventID,
+				Kind:      domain.NotificationReminder,
+				UserID:    recipient.UserID,
+				URL:       *recipient.ChannelURL,
+				Text:      text,
+				NotBefore: deliverAt(prefs, now, false),
+			})
+		}
+
+		if wantsEmail {
+			rendered, err := s.emailTemplates.Render(domain.NotificationReminder, data)
+			if err != nil {
+				return 0, err
+			}
+			// напоминание само по себе ежедневное, в дайджест оно не складывается
+			emails = append(emails, domain.EmailNotification{
+				EventID:   eventID,
+				Kind:      domain.NotificationReminder,
+				UserID:    recipient.UserID,
+				Email:     *prefs.Email,
+				Subject:   rendered.Subject,
+				TextBody:  rendered.Text,
+				HTMLBody:  rendered.HTML,
+				NotBefore: deliverAt(prefs, now, false),
+			})
+		}
+
+		reminded++
+	}
+
+	if len(chat) > 0 {
+		if err = s.notificationRepo.EnqueueNotifications(ctx, chat); err != nil {
+			return 0, err
+		}
+	}
+	if len(emails) > 0 {
+		if err = s.notificationRepo.EnqueueEmails(ctx, emails); err != nil {
+			return 0, err
+		}
+	}
+
+	return reminded, nil
+}
+
+func pendingReviewItems(reviews []domain.PendingReview, now time.Time) []PendingReviewItem {
+	items := make([]PendingReviewItem, 0, len(reviews))
+	for _, review := range reviews {
+		age := now.Sub(review.CreatedAt)
+		items = append(items, PendingReviewItem{
+			PullRequestID:   review.PullRequestID,
+			PullRequestName: review.PullRequestName,
+			AuthorID:        review.AuthorID,
+			Age:             formatAge(age),
+			OverSLA:         review.ReviewSLAHours != nil && age > time.Duration(*review.ReviewSLAHours)*time.Hour,
+		})
+	}
+	return items
+}
+
+// formatAge - возраст PR с точностью до часа: "3d 4h", "5h", "<1h"
+func formatAge(age time.Duration) string {
+	hours := int(age.Hours())
+	switch {
+	case hours >= 24:
+		return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return "<1h"
+	}
+}
+
+// ReminderConfig - как часто проверять, не пора ли кому-то отправить напоминание
+type ReminderConfig struct {
+	CheckInterval time.Duration
+}
+
+func DefaultReminderConfig() ReminderConfig {
+	return ReminderConfig{
+		CheckInterval: 5 * time.Minute,
+	}
+}
+
+// Reminder - фоновая задача ежедневных напоминаний; безопасно запускать на каждом экземпляре сервиса
+type Reminder struct {
+	service *NotificationServiceImpl
+	config  ReminderConfig
+}
+
+func NewReminder(service *NotificationServiceImpl, config ReminderConfig) *Reminder {
+	return &Reminder{
+		service: service,
+		config:  config,
+	}
+}
+
+// Run проверяет очереди ревьюверов сразу и затем раз в CheckInterval до отмены ctx
+func (r *Reminder) Run(ctx context.Context) {
+	logger.Logger.Infow("review reminder started", "check_interval", r.config.CheckInterval)
+
+	ticker := time.NewTicker(r.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		reminded, err := r.service.SendReminders(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			logger.Logger.Error("error sending review reminders: ", err)
+		case reminded > 0:
+			logger.Logger.Debugw("review reminders enqueued", "reviewers", reminded)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Logger.Info("review reminder stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package notificationService
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationService_SendReminders(t *testing.T) {
+	ctx := context.Background()
+	channelURL := testChannelURL
+	sla := 24
+
+	reviews := []domain.PendingReview{
+		{ReviewerID: "user-alice", PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "user-carol",
+			CreatedAt: testNow.Add(-50 * time.Hour), ReviewSLAHours: &sla},
+		{ReviewerID: "user-alice", PullRequestID: "pr-2", PullRequestName: "Fix login", AuthorID: "user-carol",
+			CreatedAt: testNow.Add(-3 * time.Hour), ReviewSLAHours: &sla},
+		{ReviewerID: "user-bob", PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "user-carol",
+			CreatedAt: testNow.Add(-50 * time.Hour)},
+	}
+
+	t.Run("reminds active reviewers once a day", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, notificationRepo, _, prReviewersRepo := newTestServiceWithReviewers(t, ctrl)
+		prReviewersRepo.EXPECT().GetPendingReviews(gomock.Any()).Return(reviews, nil)
+		notificationRepo.EXPECT().GetNotificationRecipients(gomock.Any(), []string{"user-alice", "user-bob"}).
+			Return([]domain.NotificationRecipient{
+				recipient("user-alice", "Alice", true, &channelURL, nil),
+				recipient("user-bob", "Bob", false, &channelURL, nil),
+			}, nil)
+		notificationRepo.EXPECT().EnqueueNotifications(gomock.Any(), []domain.ChatNotification{
+			{EventID: "reminder:2025-11-30", Kind: domain.NotificationReminder, UserID: "user-alice",
+				URL: testChannelURL, Text: "Alice: [pr-1 2d 2h SLA][pr-2 3h]"},
+		}).Return(nil)
+
+		reminded, err := service.SendReminders(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, reminded)
+	})
+
+	t.Run("waits for the digest hour in the user's timezone", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, notificationRepo, _, prReviewersRepo := newTestServiceWithReviewers(t, ctrl)
+		aliceEmail := "alice@example.com"
+		alice := recipient("user-alice", "Alice", true, nil, nil)
+		// 13:00 по Москве - ещё рано для часа дайджеста 14
+		alice.Preferences = &domain.NotificationPreferences{
+			UserID:     "user-alice",
+			Email:      &aliceEmail,
+			Channels:   []domain.NotificationChannel{domain.NotificationChannelEmail},
+			Delivery:   domain.DeliveryImmediate,
+			DigestHour: 14,
+			Timezone:   "Europe/Moscow",
+		}
+		prReviewersRepo.EXPECT().GetPendingReviews(gomock.Any()).Return(reviews[:2], nil)
+		notificationRepo.EXPECT().GetNotificationRecipients(gomock.Any(), []string{"user-alice"}).
+			Return([]domain.NotificationRecipient{alice}, nil)
+
+		reminded, err := service.SendReminders(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, reminded)
+	})
+
+	t.Run("email reminder", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, notificationRepo, _, prReviewersRepo := newTestServiceWithReviewers(t, ctrl)
+		aliceEmail := "alice@example.com"
+		alice := recipient("user-alice", "Alice", true, nil, nil)
+		alice.Preferences = &domain.NotificationPreferences{
+			UserID:     "user-alice",
+			Email:      &aliceEmail,
+			Channels:   []domain.NotificationChannel{domain.NotificationChannelEmail},
+			Delivery:   domain.DeliveryDigest,
+			DigestHour: 9,
+			Timezone:   "Europe/Moscow",
+		}
+		prReviewersRepo.EXPECT().GetPendingReviews(gomock.Any()).Return(reviews[:2], nil)
+		notificationRepo.EXPECT().GetNotificationRecipients(gomock.Any(), []string{"user-alice"}).
+			Return([]domain.NotificationRecipient{alice}, nil)
+		notificationRepo.EXPECT().EnqueueEmails(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, emails []domain.EmailNotification) error {
+				require.Len(t, emails, 1)
+				assert.Equal(t, "reminder:2025-11-30", emails[0].EventID)
+				assert.Equal(t, aliceEmail, emails[0].Email)
+				assert.False(t, emails[0].Digest)
+				assert.Contains(t, emails[0].TextBody, "Add search")
+				assert.Contains(t, emails[0].TextBody, "Fix login")
+				return nil
+			})
+
+		reminded, err := service.SendReminders(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, reminded)
+	})
+
+	t.Run("no pending reviews", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, _, _, prReviewersRepo := newTestServiceWithReviewers(t, ctrl)
+		prReviewersRepo.EXPECT().GetPendingReviews(gomock.Any()).Return(nil, nil)
+
+		reminded, err := service.SendReminders(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, reminded)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, _, _, prReviewersRepo := newTestServiceWithReviewers(t, ctrl)
+		prReviewersRepo.EXPECT().GetPendingReviews(gomock.Any()).Return(nil, errors.New("db error"))
+
+		_, err := service.SendReminders(ctx)
+
+		require.Error(t, err)
+	})
+}
+
+func TestFormatAge(t *testing.T) {
+	assert.Equal(t, "<1h", formatAge(30*time.Minute))
+	assert.Equal(t, "5h", formatAge(5*time.Hour+59*time.Minute))
+	assert.Equal(t, "3d 4h", formatAge(76*time.Hour))
+}
@@ -0,0 +1,67 @@
+package notificationService
+
+import (
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+)
+
+// deliverAt - когда можно отправить уведомление с учётом настроек получателя: письма дайджеста ждут
+// ближайшего часа дайджеста, а всё, что попадает в тихие часы, откладывается до их окончания.
+// nil - отправлять сразу
+func deliverAt(prefs domain.NotificationPreferences, now time.Time, digest bool) *time.Time {
+	local := now.In(userLocation(prefs))
+	at := local
+	if digest {
+		at = nextHour(local, prefs.DigestHour)
+	}
+	if end, ok := quietHoursEnd(prefs, at); ok {
+		at = end
+	}
+
+	if at.Equal(local) {
+		return nil
+	}
+	at = at.UTC()
+	return &at
+}
+
+// quietHoursEnd возвращает окончание тихих часов, если t в них попадает
+func quietHoursEnd(prefs domain.NotificationPreferences, t time.Time) (time.Time, bool) {
+	if prefs.QuietHoursStart == nil || prefs.QuietHoursEnd == nil {
+		return t, false
+	}
+
+	start, end, hour := *prefs.QuietHoursStart, *prefs.QuietHoursEnd, t.Hour()
+
+	var quiet bool
+	if start <= end {
+		quiet = hour >= start && hour < end
+	} else {
+		// тихие часы переходят через полночь, например 22 - 7
+		quiet = hour >= start || hour < end
+	}
+	if !quiet {
+		return t, false
+	}
+
+	return nextHour(t, end), true
+}
+
+// nextHour - ближайшее после t начало часа hour в часовом поясе t
+func nextHour(t time.Time, hour int) time.Time {
+	next := time.Date(t.Year(), t.Month(), t.Day(), hour, 0, 0, 0, t.Location())
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// userLocation - часовой пояс из настроек; некорректный пояс (сохранённый до проверки) считается UTC
+func userLocation(prefs domain.NotificationPreferences) *time.Location {
+	location, err := time.LoadLocation(prefs.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
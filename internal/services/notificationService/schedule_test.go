@@ -0,0 +1,83 @@
+package notificationService
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliverAt(t *testing.T) {
+	hour := func(h int) *int { return &h }
+	at := func(t time.Time) *time.Time { return &t }
+
+	quiet := domain.DefaultNotificationPreferences("user-alice")
+	quiet.QuietHoursStart, quiet.QuietHoursEnd = hour(22), hour(7)
+	quiet.Timezone = "Europe/Moscow" // UTC+3
+
+	tests := []struct {
+		name   string
+		prefs  domain.NotificationPreferences
+		now    time.Time
+		digest bool
+		want   *time.Time
+	}{
+		{
+			name:  "no quiet hours - immediately",
+			prefs: domain.DefaultNotificationPreferences("user-alice"),
+			now:   time.Date(2025, 12, 1, 23, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "outside quiet hours - immediately",
+			prefs: quiet,
+			now:   time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC), // 15:00 MSK
+		},
+		{
+			name:  "late evening is deferred to next morning",
+			prefs: quiet,
+			now:   time.Date(2025, 12, 1, 20, 0, 0, 0, time.UTC), // 23:00 MSK
+			want:  at(time.Date(2025, 12, 2, 4, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:  "early morning is deferred to the same morning",
+			prefs: quiet,
+			now:   time.Date(2025, 12, 1, 1, 0, 0, 0, time.UTC), // 04:00 MSK
+			want:  at(time.Date(2025, 12, 1, 4, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:   "digest waits for digest hour",
+			prefs:  quiet,
+			now:    time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC), // 15:00 MSK
+			digest: true,
+			want:   at(time.Date(2025, 12, 2, 6, 0, 0, 0, time.UTC)), // 09:00 MSK
+		},
+		{
+			name: "digest hour inside quiet hours is deferred",
+			prefs: func() domain.NotificationPreferences {
+				prefs := quiet
+				prefs.DigestHour = 23
+				return prefs
+			}(),
+			now:    time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC),
+			digest: true,
+			want:   at(time.Date(2025, 12, 2, 4, 0, 0, 0, time.UTC)),
+		},
+		{
+			name: "quiet hours within a day",
+			prefs: func() domain.NotificationPreferences {
+				prefs := domain.DefaultNotificationPreferences("user-alice")
+				prefs.QuietHoursStart, prefs.QuietHoursEnd = hour(12), hour(14)
+				return prefs
+			}(),
+			now:  time.Date(2025, 12, 1, 13, 15, 0, 0, time.UTC),
+			want: at(time.Date(2025, 12, 1, 14, 0, 0, 0, time.UTC)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, deliverAt(tt.prefs, tt.now, tt.digest))
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package notificationService
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTP - минимальный локальный SMTP-сервер: принимает письма и запоминает их
+type fakeSMTP struct {
+	listener   net.Listener
+	rejectRcpt bool
+
+	mu       sync.Mutex
+	messages []fakeMail
+}
+
+type fakeMail struct {
+	from string
+	to   string
+	data string
+}
+
+func newFakeSMTP(t *testing.T, rejectRcpt bool) *fakeSMTP {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeSMTP{listener: listener, rejectRcpt: rejectRcpt}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *fakeSMTP) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeSMTP) serve(netConn net.Conn) {
+	conn := textproto.NewConn(netConn)
+	defer conn.Close()
+
+	var current fakeMail
+	_ = conn.PrintfLine("220 fake ESMTP")
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			_ = conn.PrintfLine("250-fake\r\n250 8BITMIME")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			current = fakeMail{from: addressArg(line)}
+			_ = conn.PrintfLine("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			if s.rejectRcpt {
+				_ = conn.PrintfLine("550 mailbox unavailable")
+				continue
+			}
+			current.to = addressArg(line)
+			_ = conn.PrintfLine("250 OK")
+		case command == "DATA":
+			_ = conn.PrintfLine("354 go ahead")
+			data, err := conn.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			current.data = string(data)
+			s.mu.Lock()
+			s.messages = append(s.messages, current)
+			s.mu.Unlock()
+			_ = conn.PrintfLine("250 queued")
+		case command == "QUIT":
+			_ = conn.PrintfLine("221 bye")
+			return
+		default:
+			_ = conn.PrintfLine("250 OK")
+		}
+	}
+}
+
+// addressArg достаёт адрес из "MAIL FROM:<addr> BODY=8BITMIME"
+func addressArg(line string) string {
+	start, end := strings.Index(line, "<"), strings.Index(line, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return line[start+1 : end]
+}
+
+func (s *fakeSMTP) received() []fakeMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]fakeMail(nil), s.messages...)
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	message := EmailMessage{
+		To:      "alice@example.com",
+		Subject: "Review requested: Поиск",
+		Text:    "Hi Alice,\n\nplease review pr-1.",
+		HTML:    "<p>Hi Alice,</p><p>please review <b>pr-1</b>.</p>",
+	}
+
+	t.Run("message is delivered as multipart/alternative", func(t *testing.T) {
+		server := newFakeSMTP(t, false)
+		mailer := NewSMTPMailer(SMTPConfig{Addr: server.addr(), From: "reviews@example.com"})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, mailer.Send(ctx, message))
+
+		received := server.received()
+		require.Len(t, received, 1)
+		assert.Equal(t, "reviews@example.com", received[0].from)
+		assert.Equal(t, "alice@example.com", received[0].to)
+
+		msg, err := mail.ReadMessage(strings.NewReader(received[0].data))
+		require.NoError(t, err)
+
+		subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		require.NoError(t, err)
+		assert.Equal(t, "Review requested: Поиск", subject)
+
+		mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		assert.Equal(t, "multipart/alternative", mediaType)
+
+		parts := multipart.NewReader(msg.Body, params["boundary"])
+		var bodies []string
+		for {
+			part, err := parts.NextPart()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			// NextPart сам снимает quoted-printable
+			content, err := io.ReadAll(part)
+			require.NoError(t, err)
+			bodies = append(bodies, string(content))
+		}
+		assert.Equal(t, []string{message.Text, message.HTML}, bodies)
+	})
+
+	t.Run("rejected recipient", func(t *testing.T) {
+		server := newFakeSMTP(t, true)
+		mailer := NewSMTPMailer(SMTPConfig{Addr: server.addr(), From: "reviews@example.com"})
+
+		err := mailer.Send(context.Background(), message)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "550")
+		assert.Empty(t, server.received())
+	})
+
+	t.Run("server unavailable", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		err = NewSMTPMailer(SMTPConfig{Addr: addr, From: "reviews@example.com"}).Send(context.Background(), message)
+
+		require.Error(t, err)
+	})
+}
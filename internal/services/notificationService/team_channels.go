@@ -0,0 +1,105 @@
+package notificationService
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/notificationStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// SetTeamChannel задаёт incoming-webhook, в который уходят уведомления участников команды
+func (s *NotificationServiceImpl) SetTeamChannel(c *gin.Context) {
+	var channel domain.TeamChatChannel
+	if err := c.ShouldBindJSON(&channel); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid request body",
+		))
+		return
+	}
+
+	if !isValidWebhookURL(channel.URL) {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"url must be an absolute http or https URL",
+		))
+		return
+	}
+
+	if err := s.notificationRepo.UpsertTeamChannel(c.Request.Context(), channel); err != nil {
+		if errors.Is(err, notificationStorage.ErrTeamNotExists) {
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
+				domain.NotFound,
+				"team not found",
+			))
+			return
+		}
+		logger.Logger.Error("error saving team chat channel: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrChatNotifyMsg,
+		))
+		return
+	}
+
+	logger.Logger.Infow("team chat channel set", "team_name", channel.TeamName)
+	c.JSON(http.StatusOK, channel)
+}
+
+func (s *NotificationServiceImpl) ListTeamChannels(c *gin.Context) {
+	channels, err := s.notificationRepo.ListTeamChannels(c.Request.Context())
+	if err != nil {
+		logger.Logger.Error("error listing team chat channels: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrChatNotifyMsg,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"channels": channels})
+}
+
+// DeleteTeamChannel отключает уведомления команды. Уже поставленные в очередь сообщения будут отправлены
+func (s *NotificationServiceImpl) DeleteTeamChannel(c *gin.Context) {
+	var req domain.DeleteTeamChatChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid request body",
+		))
+		return
+	}
+
+	if err := s.notificationRepo.DeleteTeamChannel(c.Request.Context(), req.TeamName); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
+				domain.NotFound,
+				"team chat channel not found",
+			))
+			return
+		}
+		logger.Logger.Error("error deleting team chat channel: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrChatNotifyMsg,
+		))
+		return
+	}
+
+	logger.Logger.Infow("team chat channel deleted", "team_name", req.TeamName)
+	c.JSON(http.StatusOK, gin.H{"team_name": req.TeamName})
+}
+
+func isValidWebhookURL(raw string) bool {
+	target, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (target.Scheme == "http" || target.Scheme == "https") && target.Host != ""
+}
@@ -0,0 +1,135 @@
+package notificationService
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/notificationStorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newJSONContext(method, path, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, path, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c, w
+}
+
+func TestNotificationService_SetTeamChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, notificationRepo, _ := newTestService(t, ctrl)
+	channel := domain.TeamChatChannel{TeamName: "Backend", URL: testChannelURL}
+	body := `{"team_name":"Backend","url":"` + testChannelURL + `"}`
+
+	t.Run("success", func(t *testing.T) {
+		notificationRepo.EXPECT().UpsertTeamChannel(gomock.Any(), channel).Return(nil)
+
+		c, w := newJSONContext(http.MethodPost, "/notifications/teamChannels", body)
+		service.SetTeamChannel(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, body, w.Body.String())
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		c, w := newJSONContext(http.MethodPost, "/notifications/teamChannels", `{"team_name":"Backend","url":"hooks.slack.com"}`)
+		service.SetTeamChannel(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("missing team name", func(t *testing.T) {
+		c, w := newJSONContext(http.MethodPost, "/notifications/teamChannels", `{"url":"`+testChannelURL+`"}`)
+		service.SetTeamChannel(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		notificationRepo.EXPECT().UpsertTeamChannel(gomock.Any(), channel).Return(notificationStorage.ErrTeamNotExists)
+
+		c, w := newJSONContext(http.MethodPost, "/notifications/teamChannels", body)
+		service.SetTeamChannel(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		notificationRepo.EXPECT().UpsertTeamChannel(gomock.Any(), channel).Return(errors.New("db error"))
+
+		c, w := newJSONContext(http.MethodPost, "/notifications/teamChannels", body)
+		service.SetTeamChannel(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestNotificationService_ListTeamChannels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, notificationRepo, _ := newTestService(t, ctrl)
+
+	t.Run("success", func(t *testing.T) {
+		notificationRepo.EXPECT().ListTeamChannels(gomock.Any()).
+			Return([]domain.TeamChatChannel{{TeamName: "Backend", URL: testChannelURL}}, nil)
+
+		c, w := newJSONContext(http.MethodGet, "/notifications/teamChannels", "")
+		service.ListTeamChannels(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"channels":[{"team_name":"Backend","url":"`+testChannelURL+`"}]}`, w.Body.String())
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		notificationRepo.EXPECT().ListTeamChannels(gomock.Any()).Return(nil, errors.New("db error"))
+
+		c, w := newJSONContext(http.MethodGet, "/notifications/teamChannels", "")
+		service.ListTeamChannels(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestNotificationService_DeleteTeamChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, notificationRepo, _ := newTestService(t, ctrl)
+
+	t.Run("success", func(t *testing.T) {
+		notificationRepo.EXPECT().DeleteTeamChannel(gomock.Any(), "Backend").Return(nil)
+
+		c, w := newJSONContext(http.MethodPost, "/notifications/teamChannels/delete", `{"team_name":"Backend"}`)
+		service.DeleteTeamChannel(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("channel not found", func(t *testing.T) {
+		notificationRepo.EXPECT().DeleteTeamChannel(gomock.Any(), "Backend").Return(pgx.ErrNoRows)
+
+		c, w := newJSONContext(http.MethodPost, "/notifications/teamChannels/delete", `{"team_name":"Backend"}`)
+		service.DeleteTeamChannel(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid request body", func(t *testing.T) {
+		c, w := newJSONContext(http.MethodPost, "/notifications/teamChannels/delete", `{}`)
+		service.DeleteTeamChannel(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,108 @@
+package notificationService
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+)
+
+// TemplateEnvPrefix - шаблон вида уведомления можно переопределить переменной окружения
+// TemplateEnvPrefix + вид, например CHAT_TEMPLATE_ASSIGNED
+const TemplateEnvPrefix = "CHAT_TEMPLATE_"
+
+// MessageData - данные, доступные в шаблоне сообщения
+type MessageData struct {
+	// Mention - упоминание получателя; если оно не задано - имя пользователя
+	Mention string
+	// Username - имя получателя, в письмах вместо упоминания
+	Username        string
+	UserID          string
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	Strategy        domain.AssignmentStrategy
+	// PendingReviews - очередь ревьювера, только в напоминании
+	PendingReviews []PendingReviewItem
+}
+
+// PendingReviewItem - PR в напоминании; Age - сколько PR открыт, например "2d 5h"
+type PendingReviewItem struct {
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	Age             string
+	OverSLA         bool
+}
+
+// DefaultTemplates - шаблоны сообщений по умолчанию в разметке Slack (Mattermost её тоже понимает)
+func DefaultTemplates() map[domain.NotificationKind]string {
+	return map[domain.NotificationKind]string{
+		domain.NotificationAssigned: "{{.Mention}}, you were assigned to review " +
+			"*{{.PullRequestName}}* (`{{.PullRequestID}}`) by {{.AuthorID}}",
+		domain.NotificationUnassigned: "{{.Mention}}, you are no longer a reviewer of " +
+			"*{{.PullRequestName}}* (`{{.PullRequestID}}`)",
+		domain.NotificationPRMerged: "{{.Mention}}, *{{.PullRequestName}}* (`{{.PullRequestID}}`) " +
+			"you reviewed has been merged",
+		domain.NotificationReminder: "{{.Mention}}, you have {{len .PendingReviews}} pending review(s):" +
+			"{{range .PendingReviews}}\n• {{if .OverSLA}}:warning: {{end}}*{{.PullRequestName}}* " +
+			"(`{{.PullRequestID}}`) by {{.AuthorID}}, open {{.Age}}{{if .OverSLA}}, past SLA{{end}}{{end}}",
+	}
+}
+
+// TemplatesFromEnv - шаблоны по умолчанию, переопределённые непустыми переменными окружения
+func TemplatesFromEnv(getenv func(string) string) map[domain.NotificationKind]string {
+	sources := DefaultTemplates()
+	for _, kind := range domain.NotificationKinds {
+		if value := getenv(TemplateEnvPrefix + string(kind)); value != "" {
+			sources[kind] = value
+		}
+	}
+	return sources
+}
+
+// Templates - разобранные шаблоны сообщений по видам уведомлений
+type Templates struct {
+	byKind map[domain.NotificationKind]*template.Template
+}
+
+// ParseTemplates разбирает шаблон каждого вида уведомления и проверяет его на пустых данных,
+// чтобы ошибка в шаблоне (например, неизвестное поле) обнаружилась при старте, а не при отправке
+func ParseTemplates(sources map[domain.NotificationKind]string) (*Templates, error) {
+	templates := &Templates{byKind: make(map[domain.NotificationKind]*template.Template, len(sources))}
+
+	for _, kind := range domain.NotificationKinds {
+		source, ok := sources[kind]
+		if !ok {
+			return nil, fmt.Errorf("no template for %s notification", kind)
+		}
+
+		tmpl, err := template.New(string(kind)).Option("missingkey=error").Parse(source)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s template: %w", kind, err)
+		}
+		if err = tmpl.Execute(io.Discard, MessageData{}); err != nil {
+			return nil, fmt.Errorf("check %s template: %w", kind, err)
+		}
+
+		templates.byKind[kind] = tmpl
+	}
+
+	return templates, nil
+}
+
+func (t *Templates) Render(kind domain.NotificationKind, data MessageData) (string, error) {
+	tmpl, ok := t.byKind[kind]
+	if !ok {
+		return "", fmt.Errorf("no template for %s notification", kind)
+	}
+
+	var text strings.Builder
+	if err := tmpl.Execute(&text, data); err != nil {
+		return "", err
+	}
+
+	return text.String(), nil
+}
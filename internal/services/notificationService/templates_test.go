@@ -0,0 +1,63 @@
+package notificationService
+
+import (
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTemplates(t *testing.T) {
+	t.Run("default templates render", func(t *testing.T) {
+		templates, err := ParseTemplates(DefaultTemplates())
+		require.NoError(t, err)
+
+		text, err := templates.Render(domain.NotificationAssigned, MessageData{
+			Mention:         "<@U024BE7LH>",
+			PullRequestID:   "org/repo#42",
+			PullRequestName: "Add search",
+			AuthorID:        "user-bob",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "<@U024BE7LH>, you were assigned to review *Add search* (`org/repo#42`) by user-bob", text)
+	})
+
+	t.Run("env overrides default", func(t *testing.T) {
+		env := map[string]string{"CHAT_TEMPLATE_PR_MERGED": "merged: {{.PullRequestID}}"}
+		sources := TemplatesFromEnv(func(key string) string { return env[key] })
+
+		templates, err := ParseTemplates(sources)
+		require.NoError(t, err)
+
+		text, err := templates.Render(domain.NotificationPRMerged, MessageData{PullRequestID: "pr-1"})
+		require.NoError(t, err)
+		assert.Equal(t, "merged: pr-1", text)
+		assert.Equal(t, DefaultTemplates()[domain.NotificationAssigned], sources[domain.NotificationAssigned])
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		sources := DefaultTemplates()
+		sources[domain.NotificationAssigned] = "{{.Mention"
+
+		_, err := ParseTemplates(sources)
+		require.Error(t, err)
+	})
+
+	t.Run("unknown field is rejected at parse time", func(t *testing.T) {
+		sources := DefaultTemplates()
+		sources[domain.NotificationUnassigned] = "{{.Reviewer}} unassigned"
+
+		_, err := ParseTemplates(sources)
+		require.Error(t, err)
+	})
+
+	t.Run("missing template", func(t *testing.T) {
+		sources := DefaultTemplates()
+		delete(sources, domain.NotificationPRMerged)
+
+		_, err := ParseTemplates(sources)
+		require.Error(t, err)
+	})
+}
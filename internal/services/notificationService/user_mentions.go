@@ -0,0 +1,55 @@
+package notificationService
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/notificationStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// SetUserMention задаёт, как упоминать пользователя в сообщениях (или меняет упоминание)
+func (s *NotificationServiceImpl) SetUserMention(c *gin.Context) {
+	var mention domain.UserChatMention
+	if err := c.ShouldBindJSON(&mention); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid request body",
+		))
+		return
+	}
+
+	if err := s.notificationRepo.UpsertUserMention(c.Request.Context(), mention); err != nil {
+		if errors.Is(err, notificationStorage.ErrUserNotExists) {
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
+				domain.NotFound,
+				"user not found",
+			))
+			return
+		}
+		logger.Logger.Error("error saving user chat mention: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrChatNotifyMsg,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, mention)
+}
+
+func (s *NotificationServiceImpl) ListUserMentions(c *gin.Context) {
+	mentions, err := s.notificationRepo.ListUserMentions(c.Request.Context())
+	if err != nil {
+		logger.Logger.Error("error listing user chat mentions: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrChatNotifyMsg,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"mentions": mentions})
+}
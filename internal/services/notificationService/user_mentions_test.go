@@ -0,0 +1,84 @@
+package notificationService
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/notificationStorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationService_SetUserMention(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, notificationRepo, _ := newTestService(t, ctrl)
+	mention := domain.UserChatMention{UserID: "user-alice", Mention: "@alice"}
+	body := `{"user_id":"user-alice","mention":"@alice"}`
+
+	t.Run("success", func(t *testing.T) {
+		notificationRepo.EXPECT().UpsertUserMention(gomock.Any(), mention).Return(nil)
+
+		c, w := newJSONContext(http.MethodPost, "/notifications/userMentions", body)
+		service.SetUserMention(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, body, w.Body.String())
+	})
+
+	t.Run("invalid request body", func(t *testing.T) {
+		c, w := newJSONContext(http.MethodPost, "/notifications/userMentions", `{"user_id":"user-alice"}`)
+		service.SetUserMention(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		notificationRepo.EXPECT().UpsertUserMention(gomock.Any(), mention).Return(notificationStorage.ErrUserNotExists)
+
+		c, w := newJSONContext(http.MethodPost, "/notifications/userMentions", body)
+		service.SetUserMention(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		notificationRepo.EXPECT().UpsertUserMention(gomock.Any(), mention).Return(errors.New("db error"))
+
+		c, w := newJSONContext(http.MethodPost, "/notifications/userMentions", body)
+		service.SetUserMention(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestNotificationService_ListUserMentions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, notificationRepo, _ := newTestService(t, ctrl)
+
+	t.Run("success", func(t *testing.T) {
+		notificationRepo.EXPECT().ListUserMentions(gomock.Any()).
+			Return([]domain.UserChatMention{{UserID: "user-alice", Mention: "@alice"}}, nil)
+
+		c, w := newJSONContext(http.MethodGet, "/notifications/userMentions", "")
+		service.ListUserMentions(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"mentions":[{"user_id":"user-alice","mention":"@alice"}]}`, w.Body.String())
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		notificationRepo.EXPECT().ListUserMentions(gomock.Any()).Return(nil, errors.New("db error"))
+
+		c, w := newJSONContext(http.MethodGet, "/notifications/userMentions", "")
+		service.ListUserMentions(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
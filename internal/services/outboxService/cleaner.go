@@ -0,0 +1,62 @@
+package outboxService
+
+import (
+	"context"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/storage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// CleanerConfig - параметры очистки outbox. Retention - сколько хранится опубликованное событие: столько же
+// клиент потока очереди ревью может переподключиться с Last-Event-ID без пропуска событий
+type CleanerConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Retention    time.Duration
+}
+
+func DefaultCleanerConfig() CleanerConfig {
+	return CleanerConfig{
+		PollInterval: time.Hour,
+		BatchSize:    1000,
+		Retention:    7 * 24 * time.Hour,
+	}
+}
+
+// Cleaner удаляет из outbox опубликованные события старше Retention; неопубликованные остаются до публикации
+type Cleaner struct {
+	outboxRepo storage.OutboxRepositoryInterface
+	config     CleanerConfig
+	now        func() time.Time
+}
+
+func NewCleaner(outboxRepo storage.OutboxRepositoryInterface, config CleanerConfig) *Cleaner {
+	return &Cleaner{
+		outboxRepo: outboxRepo,
+		config:     config,
+		now:        time.Now,
+	}
+}
+
+// Run очищает outbox до отмены ctx
+func (c *Cleaner) Run(ctx context.Context) {
+	utils.PollBatches(ctx, "outbox cleaner", c.config.PollInterval, c.config.BatchSize, c.DeleteExpired)
+}
+
+// DeleteExpired удаляет одну пачку устаревших событий. Возвращает размер пачки
+func (c *Cleaner) DeleteExpired(ctx context.Context) (int, error) {
+	before := c.now().Add(-c.config.Retention)
+
+	deleted, err := c.outboxRepo.DeletePublishedEvents(ctx, before, c.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	if deleted > 0 {
+		logger.Logger.Infow("outbox events deleted", "deleted", deleted, "published_before", before)
+	}
+
+	return deleted, nil
+}
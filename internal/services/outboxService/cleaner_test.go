@@ -0,0 +1,80 @@
+package outboxService
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCleaner(repo *mocks.MockOutboxRepositoryInterface, now time.Time) *Cleaner {
+	cleaner := NewCleaner(repo, CleanerConfig{
+		PollInterval: 10 * time.Millisecond,
+		BatchSize:    100,
+		Retention:    24 * time.Hour,
+	})
+	cleaner.now = func() time.Time { return now }
+	return cleaner
+}
+
+func TestCleaner_DeleteExpired(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 12, 5, 10, 0, 0, 0, time.UTC)
+
+	t.Run("deletes events published before retention", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+
+		repo.EXPECT().DeletePublishedEvents(gomock.Any(), now.Add(-24*time.Hour), 100).Return(37, nil)
+
+		deleted, err := newTestCleaner(repo, now).DeleteExpired(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 37, deleted)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+
+		repo.EXPECT().DeletePublishedEvents(gomock.Any(), gomock.Any(), 100).Return(0, errors.New("db error"))
+
+		_, err := newTestCleaner(repo, now).DeleteExpired(ctx)
+
+		require.Error(t, err)
+	})
+}
+
+func TestCleaner_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+	now := time.Date(2025, 12, 5, 10, 0, 0, 0, time.UTC)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Полная пачка забирается сразу следующей, неполная завершает проход до следующего тика
+	gomock.InOrder(
+		repo.EXPECT().DeletePublishedEvents(gomock.Any(), gomock.Any(), 100).Return(100, nil),
+		repo.EXPECT().DeletePublishedEvents(gomock.Any(), gomock.Any(), 100).
+			DoAndReturn(func(context.Context, time.Time, int) (int, error) {
+				cancel()
+				return 5, nil
+			}),
+	)
+
+	done := make(chan struct{})
+	go func() {
+		newTestCleaner(repo, now).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("cleaner did not stop after cancel")
+	}
+}
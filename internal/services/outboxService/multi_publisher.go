@@ -0,0 +1,30 @@
+package outboxService
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+)
+
+// multiPublisher раздаёт события нескольким получателям. Ошибка любого из них возвращается ретранслятору,
+// и событие публикуется повторно всем получателям - это безопасно, так как каждый идемпотентен по Event.ID
+type multiPublisher struct {
+	publishers []Publisher
+}
+
+// NewMultiPublisher объединяет получателей в один Publisher. Публикация идёт во всех получателей,
+// даже если кто-то из них упал, чтобы сбой одного не задерживал остальных
+func NewMultiPublisher(publishers ...Publisher) Publisher {
+	return &multiPublisher{publishers: publishers}
+}
+
+func (p *multiPublisher) Publish(ctx context.Context, events ...domain.Event) error {
+	var errs []error
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, events...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
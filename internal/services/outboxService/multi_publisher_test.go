@@ -0,0 +1,34 @@
+package outboxService
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiPublisher_Publish(t *testing.T) {
+	events := []domain.Event{{ID: "event-1"}, {ID: "event-2"}}
+
+	t.Run("events reach every publisher", func(t *testing.T) {
+		first, second := &fakePublisher{}, &fakePublisher{}
+
+		err := NewMultiPublisher(first, second).Publish(context.Background(), events...)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"event-1", "event-2"}, first.ids())
+		assert.Equal(t, []string{"event-1", "event-2"}, second.ids())
+	})
+
+	t.Run("failing publisher does not block the others", func(t *testing.T) {
+		failing := &fakePublisher{failOn: map[string]bool{"event-1": true}}
+		healthy := &fakePublisher{}
+
+		err := NewMultiPublisher(failing, healthy).Publish(context.Background(), events...)
+
+		require.Error(t, err)
+		assert.Equal(t, []string{"event-1", "event-2"}, healthy.ids())
+	})
+}
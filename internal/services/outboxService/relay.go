@@ -0,0 +1,106 @@
+package outboxService
+
+import (
+	"context"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// Publisher - получатель событий из outbox (очередь подписок, брокер и т.п.). Одно и то же событие
+// может прийти повторно, поэтому Publish должен быть идемпотентным по Event.ID
+type Publisher interface {
+	Publish(ctx context.Context, events ...domain.Event) error
+}
+
+// RelayConfig - параметры ретранслятора. Lease - на сколько событие скрывается от других экземпляров,
+// пока идёт публикация; неудачная публикация N (с единицы) повторяется через BaseBackoff * 2^(N-1),
+// но не позже MaxBackoff. Попытки не ограничены: событие из outbox не теряется
+type RelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Lease        time.Duration
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+func DefaultRelayConfig() RelayConfig {
+	return RelayConfig{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		Lease:        30 * time.Second,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   5 * time.Minute,
+	}
+}
+
+// Relay переносит события из outbox в Publisher с семантикой at-least-once: событие отмечается
+// опубликованным только после успешного Publish, а при падении между ними будет опубликовано ещё раз
+type Relay struct {
+	outboxRepo storage.OutboxRepositoryInterface
+	publisher  Publisher
+	config     RelayConfig
+	now        func() time.Time
+}
+
+func NewRelay(outboxRepo storage.OutboxRepositoryInterface, publisher Publisher, config RelayConfig) *Relay {
+	return &Relay{
+		outboxRepo: outboxRepo,
+		publisher:  publisher,
+		config:     config,
+		now:        time.Now,
+	}
+}
+
+// Run переносит события из outbox до отмены ctx
+func (r *Relay) Run(ctx context.Context) {
+	utils.PollBatches(ctx, "outbox relay", r.config.PollInterval, r.config.BatchSize, r.RelayPending)
+}
+
+// RelayPending забирает одну пачку событий и публикует их по порядку записи. Возвращает размер пачки
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	events, err := r.outboxRepo.ClaimPendingEvents(ctx, r.config.BatchSize, r.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	published := make([]int64, 0, len(events))
+	for _, event := range events {
+		if err = r.publisher.Publish(ctx, event.Event); err != nil {
+			r.recordFailure(ctx, event, err)
+			continue
+		}
+		published = append(published, event.ID)
+	}
+
+	if len(published) > 0 {
+		// Если отметка не сохранится, события опубликуются повторно после истечения аренды
+		if err = r.outboxRepo.MarkEventsPublished(ctx, published); err != nil {
+			return len(events), err
+		}
+	}
+
+	return len(events), nil
+}
+
+func (r *Relay) recordFailure(ctx context.Context, event domain.OutboxEvent, publishErr error) {
+	attempt := event.Attempts + 1
+	next := r.now().Add(utils.ExponentialBackoff(r.config.BaseBackoff, r.config.MaxBackoff, attempt))
+
+	logger.Logger.Warnw("error publishing outbox event, retry scheduled",
+		"outbox_id", event.ID,
+		"event_id", event.Event.ID,
+		"event_type", event.Event.Type,
+		"attempt", attempt,
+		"next_attempt_at", next,
+		"error", publishErr,
+	)
+
+	if err := r.outboxRepo.RecordPublishFailure(ctx, event.ID, publishErr.Error(), next); err != nil {
+		// Событие вернётся в очередь по истечении аренды
+		logger.Logger.Error("error recording outbox publish failure: ", err)
+	}
+}
@@ -0,0 +1,211 @@
+package outboxService
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger.Logger = zap.NewNop().Sugar()
+}
+
+// fakePublisher запоминает опубликованные события и падает на событиях из failOn
+type fakePublisher struct {
+	mu     sync.Mutex
+	events []domain.Event
+	failOn map[string]bool
+}
+
+func (p *fakePublisher) Publish(_ context.Context, events ...domain.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, event := range events {
+		if p.failOn[event.ID] {
+			return errors.New("publisher unavailable")
+		}
+		p.events = append(p.events, event)
+	}
+	return nil
+}
+
+func (p *fakePublisher) ids() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := make([]string, 0, len(p.events))
+	for _, event := range p.events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func newTestRelay(repo *mocks.MockOutboxRepositoryInterface, publisher Publisher, now time.Time) *Relay {
+	relay := NewRelay(repo, publisher, RelayConfig{
+		PollInterval: 10 * time.Millisecond,
+		BatchSize:    2,
+		Lease:        30 * time.Second,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   5 * time.Second,
+	})
+	relay.now = func() time.Time { return now }
+	return relay
+}
+
+func outboxEvent(id int64, eventID string, attempts int) domain.OutboxEvent {
+	return domain.OutboxEvent{
+		ID:       id,
+		Attempts: attempts,
+		Event: domain.Event{
+			ID:   eventID,
+			Type: domain.EventPRCreated,
+			Data: []byte(`{"pull_request_id":"pr-1"}`),
+		},
+	}
+}
+
+func TestRelay_RelayPending(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 11, 29, 10, 0, 0, 0, time.UTC)
+
+	t.Run("publishes events in order and marks them published", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+		publisher := &fakePublisher{}
+		relay := newTestRelay(repo, publisher, now)
+
+		repo.EXPECT().ClaimPendingEvents(gomock.Any(), 2, 30*time.Second).
+			Return([]domain.OutboxEvent{outboxEvent(1, "event-1", 0), outboxEvent(2, "event-2", 0)}, nil)
+		repo.EXPECT().MarkEventsPublished(gomock.Any(), []int64{1, 2}).Return(nil)
+
+		claimed, err := relay.RelayPending(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, claimed)
+		assert.Equal(t, []string{"event-1", "event-2"}, publisher.ids())
+	})
+
+	t.Run("failed event is rescheduled with backoff, others are published", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+		publisher := &fakePublisher{failOn: map[string]bool{"event-1": true}}
+		relay := newTestRelay(repo, publisher, now)
+
+		repo.EXPECT().ClaimPendingEvents(gomock.Any(), 2, 30*time.Second).
+			Return([]domain.OutboxEvent{outboxEvent(1, "event-1", 2), outboxEvent(2, "event-2", 0)}, nil)
+		// третья попытка: 1s * 2^2 = 4s
+		repo.EXPECT().RecordPublishFailure(gomock.Any(), int64(1), "publisher unavailable", now.Add(4*time.Second)).Return(nil)
+		repo.EXPECT().MarkEventsPublished(gomock.Any(), []int64{2}).Return(nil)
+
+		claimed, err := relay.RelayPending(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, claimed)
+		assert.Equal(t, []string{"event-2"}, publisher.ids())
+	})
+
+	t.Run("backoff is capped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+		publisher := &fakePublisher{failOn: map[string]bool{"event-1": true}}
+		relay := newTestRelay(repo, publisher, now)
+
+		repo.EXPECT().ClaimPendingEvents(gomock.Any(), 2, 30*time.Second).
+			Return([]domain.OutboxEvent{outboxEvent(1, "event-1", 10)}, nil)
+		repo.EXPECT().RecordPublishFailure(gomock.Any(), int64(1), gomock.Any(), now.Add(5*time.Second)).Return(nil)
+
+		_, err := relay.RelayPending(ctx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("mark published error is returned", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+		publisher := &fakePublisher{}
+		relay := newTestRelay(repo, publisher, now)
+
+		repo.EXPECT().ClaimPendingEvents(gomock.Any(), 2, 30*time.Second).
+			Return([]domain.OutboxEvent{outboxEvent(1, "event-1", 0)}, nil)
+		repo.EXPECT().MarkEventsPublished(gomock.Any(), []int64{1}).Return(errors.New("db error"))
+
+		_, err := relay.RelayPending(ctx)
+
+		require.Error(t, err)
+		assert.Equal(t, []string{"event-1"}, publisher.ids())
+	})
+
+	t.Run("claim error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+		relay := newTestRelay(repo, &fakePublisher{}, now)
+
+		repo.EXPECT().ClaimPendingEvents(gomock.Any(), 2, 30*time.Second).Return(nil, errors.New("db error"))
+
+		claimed, err := relay.RelayPending(ctx)
+
+		require.Error(t, err)
+		assert.Equal(t, 0, claimed)
+	})
+}
+
+func TestRelay_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+	publisher := &fakePublisher{}
+	relay := newTestRelay(repo, publisher, time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Полная пачка забирается сразу следующей, неполная завершает проход до следующего тика
+	gomock.InOrder(
+		repo.EXPECT().ClaimPendingEvents(gomock.Any(), 2, 30*time.Second).
+			Return([]domain.OutboxEvent{outboxEvent(1, "event-1", 0), outboxEvent(2, "event-2", 0)}, nil),
+		repo.EXPECT().MarkEventsPublished(gomock.Any(), []int64{1, 2}).Return(nil),
+		repo.EXPECT().ClaimPendingEvents(gomock.Any(), 2, 30*time.Second).
+			Return([]domain.OutboxEvent{outboxEvent(3, "event-3", 0)}, nil),
+		repo.EXPECT().MarkEventsPublished(gomock.Any(), []int64{3}).DoAndReturn(
+			func(context.Context, []int64) error {
+				cancel()
+				return nil
+			}),
+	)
+	repo.EXPECT().ClaimPendingEvents(gomock.Any(), 2, 30*time.Second).Return(nil, nil).AnyTimes()
+
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("relay did not stop after context cancel")
+	}
+
+	assert.Equal(t, []string{"event-1", "event-2", "event-3"}, publisher.ids())
+}
@@ -0,0 +1,40 @@
+package pullRequestService
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// ClosePullRequest - идемпотентное закрытие PR без мерджа (используется вебхуками VCS). Назначения ревьюверов
+// сохраняются, но закрытый PR не считается в их нагрузке. Смердженный PR закрыть нельзя - PR_MERGED
+func (s *PullRequestServiceImpl) ClosePullRequest(ctx context.Context, prID string) (*domain.PullRequestResponse, error) {
+	pr, err := s.prRepo.GetPullRequestByID(ctx, prID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewError(domain.NotFound, "PR not found")
+		}
+		return nil, err
+	}
+
+	switch pr.Status {
+	case domain.PullRequestStatusMERGED:
+		return nil, domain.NewError(domain.PrMerged, "cannot close merged PR")
+	case domain.PullRequestStatusOPEN:
+		if err = s.prRepo.ClosePullRequest(ctx, prID); err != nil {
+			return nil, err
+		}
+		pr.Status = domain.PullRequestStatusCLOSED
+	}
+
+	response, err := s.pullRequestResponse(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Infow("PR closed successfully", "pr_id", prID)
+	return response, nil
+}
@@ -0,0 +1,88 @@
+package pullRequestService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestService_ClosePullRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPrRepo := mocks.NewMockPullRequestRepositoryInterface(ctrl)
+	mockPrReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+
+	service := NewPullRequestService(mockPrRepo, mockPrReviewersRepo, mockUserRepo, mockTeamRepo)
+	ctx := context.Background()
+
+	newPR := func(status domain.PullRequestStatus) *domain.PullRequest {
+		return &domain.PullRequest{
+			PullRequestId:   testStrID,
+			PullRequestName: "Add feature",
+			AuthorId:        testStrID,
+			Status:          status,
+		}
+	}
+
+	t.Run("close open PR", func(t *testing.T) {
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), testStrID).Return(newPR(domain.PullRequestStatusOPEN), nil)
+		mockPrRepo.EXPECT().ClosePullRequest(gomock.Any(), testStrID).Return(nil)
+		mockPrReviewersRepo.EXPECT().GetReviewerAssignments(gomock.Any(), testStrID).
+			Return([]domain.ReviewerAssignment{{ReviewerID: "u2"}}, nil)
+
+		response, err := service.ClosePullRequest(ctx, testStrID)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.PullRequestStatusCLOSED, response.PR.Status)
+		assert.Equal(t, []string{"u2"}, response.PR.AssignedReviewers)
+	})
+
+	t.Run("already closed PR is returned as is", func(t *testing.T) {
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), testStrID).Return(newPR(domain.PullRequestStatusCLOSED), nil)
+		mockPrReviewersRepo.EXPECT().GetReviewerAssignments(gomock.Any(), testStrID).Return([]domain.ReviewerAssignment{}, nil)
+
+		response, err := service.ClosePullRequest(ctx, testStrID)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.PullRequestStatusCLOSED, response.PR.Status)
+	})
+
+	t.Run("merged PR cannot be closed", func(t *testing.T) {
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), testStrID).Return(newPR(domain.PullRequestStatusMERGED), nil)
+
+		_, err := service.ClosePullRequest(ctx, testStrID)
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.PrMerged, domainErr.Code)
+	})
+
+	t.Run("PR not found", func(t *testing.T) {
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), testStrID).Return(nil, pgx.ErrNoRows)
+
+		_, err := service.ClosePullRequest(ctx, testStrID)
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
+	})
+
+	t.Run("error closing PR", func(t *testing.T) {
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), testStrID).Return(newPR(domain.PullRequestStatusOPEN), nil)
+		mockPrRepo.EXPECT().ClosePullRequest(gomock.Any(), testStrID).Return(errors.New("db error"))
+
+		_, err := service.ClosePullRequest(ctx, testStrID)
+
+		require.Error(t, err)
+	})
+}
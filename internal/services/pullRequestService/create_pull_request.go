@@ -1,11 +1,10 @@
 package pullRequestService
 
 import (
+	"context"
 	"errors"
-	"net/http"
 	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
@@ -14,51 +13,22 @@ import (
 	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
 )
 
-func (s *PullRequestServiceImpl) CreatePullRequest(c *gin.Context) {
-	ctx := c.Request.Context()
-
-	var req domain.CreatePullRequestRequest
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
-			domain.InvalidRequest,
-			"invalid request body",
-		))
-		return
-	}
-
+// CreatePullRequest - общий путь создания PR с назначением ревьюеров (используется и вебхуками VCS).
+// Ошибки валидации возвращаются как *domain.Error, остальные - ошибки хранилища
+func (s *PullRequestServiceImpl) CreatePullRequest(ctx context.Context, req domain.CreatePullRequestRequest) (*domain.PullRequestResponse, error) {
 	author, err := s.userRepo.GetUserByID(ctx, req.AuthorID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
-				domain.NotFound,
-				"author not found",
-			))
-			return
+			return nil, domain.NewError(domain.NotFound, "author not found")
 		}
-		logger.Logger.Error("error getting author: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrCreatePRMsg,
-		))
-		return
+		return nil, err
 	}
 
-	team, err := s.teamRepo.GetTeamByName(ctx, author.TeamName)
+	// Все команды автора и их предки: ревьюеры добираются из родительских команд,
+	// только если в командах автора не хватило кандидатов
+	teams, err := s.getAuthorReviewTeams(ctx, author)
 	if err != nil {
-		if errors.Is(err, teamStorage.ErrTeamNotExists) {
-			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
-				domain.NotFound,
-				"team not found",
-			))
-			return
-		}
-		logger.Logger.Error("error getting team: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrCreatePRMsg,
-		))
-		return
+		return nil, err
 	}
 
 	now := time.Now()
@@ -72,32 +42,75 @@ func (s *PullRequestServiceImpl) CreatePullRequest(c *gin.Context) {
 		MergedAt:          nil,
 	}
 
-	reviewers := utils.RandSelectReviewers(team.Members, req.AuthorID, domain.MaxReviewersCount)
+	// Seed и пул кандидатов сохраняются вместе с назначением, чтобы выбор можно было объяснить и воспроизвести
+	seed := utils.NewSeed()
+	candidates, excluded := utils.EvaluateReviewerCandidates(teams, req.AuthorID, nil)
+	meta := &domain.AssignmentMeta{
+		Strategy:       domain.AssignmentStrategyRandom,
+		CandidateCount: len(candidates),
+		Excluded:       excluded,
+		Seed:           &seed,
+	}
+
+	reviewers := utils.RandSelectReviewersWithFallbackSeed(teams, req.AuthorID, nil, domain.MaxReviewersCount, seed)
 	needMore := len(reviewers) < domain.MaxReviewersCount
 
-	err = s.prRepo.CreatePullRequestWithReviewers(ctx, pr, reviewers, needMore)
+	err = s.prRepo.CreatePullRequestWithReviewers(ctx, pr, reviewers, needMore, meta)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			c.JSON(http.StatusConflict, domain.NewErrorResponse(
-				domain.PrExists,
-				"PR id already exists",
-			))
-			return
+			return nil, domain.NewError(domain.PrExists, "PR id already exists")
 		}
-		logger.Logger.Error("error creating PR with reviewers: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrCreatePRMsg,
-		))
-		return
+		return nil, err
 	}
 
 	pr.AssignedReviewers = reviewers
 	pr.NeedMoreReviewers = &needMore
 
+	assignments := make([]domain.ReviewerAssignment, 0, len(reviewers))
+	for _, reviewerID := range reviewers {
+		assignments = append(assignments, domain.ReviewerAssignment{
+			ReviewerID: reviewerID,
+			AssignedAt: &now,
+			Meta:       meta,
+		})
+	}
+
 	logger.Logger.Infow("PR created successfully", "pr_id", req.PullRequestID, "reviewers_count", len(reviewers))
-	c.JSON(http.StatusCreated, gin.H{
-		"pr": pr,
-	})
+	return &domain.PullRequestResponse{
+		PR:          pr,
+		Assignments: assignments,
+	}, nil
+}
+
+// getAuthorReviewTeams собирает уровни кандидатов для PR автора: на нижнем уровне - участники всех команд,
+// в которых он состоит, выше - их предки. Основная команда идёт первой, пользователь без команд - NOT_FOUND
+func (s *PullRequestServiceImpl) getAuthorReviewTeams(ctx context.Context, author *domain.User) ([]domain.Team, error) {
+	teamNames := make([]string, 0, len(author.TeamNames)+1)
+	if author.TeamName != "" {
+		teamNames = append(teamNames, author.TeamName)
+	}
+	for _, teamName := range author.TeamNames {
+		if !utils.Contains(teamNames, teamName) {
+			teamNames = append(teamNames, teamName)
+		}
+	}
+
+	if len(teamNames) == 0 {
+		return nil, domain.NewError(domain.NotFound, "team not found")
+	}
+
+	chains := make([][]domain.Team, 0, len(teamNames))
+	for _, teamName := range teamNames {
+		chain, err := s.teamRepo.GetTeamWithAncestors(ctx, teamName)
+		if err != nil {
+			if errors.Is(err, teamStorage.ErrTeamNotExists) {
+				return nil, domain.NewError(domain.NotFound, "team not found")
+			}
+			return nil, err
+		}
+		chains = append(chains, chain)
+	}
+
+	return utils.MergeTeamLevels(chains), nil
 }
@@ -1,14 +1,10 @@
 package pullRequestService
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"net/http"
-	"net/http/httptest"
-	"strings"
 	"testing"
 
-	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -21,7 +17,6 @@ import (
 )
 
 func init() {
-	gin.SetMode(gin.TestMode)
 	logger.Logger = zap.NewNop().Sugar()
 }
 
@@ -35,6 +30,7 @@ func TestPullRequestService_CreatePullRequest(t *testing.T) {
 	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
 
 	service := NewPullRequestService(mockPrRepo, mockPrReviewersRepo, mockUserRepo, mockTeamRepo)
+	ctx := context.Background()
 
 	t.Run("successfully create PR with reviewers", func(t *testing.T) {
 		prID := testStrID
@@ -58,61 +54,41 @@ func TestPullRequestService_CreatePullRequest(t *testing.T) {
 			},
 		}
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `",
-			"pull_request_name": "Add feature",
-			"author_id": "` + authorID + `"
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+		req := domain.CreatePullRequestRequest{
+			PullRequestID:   prID,
+			PullRequestName: "Add feature",
+			AuthorID:        authorID,
+		}
 
 		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), authorID).Return(author, nil)
-		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(team, nil)
-		mockPrRepo.EXPECT().CreatePullRequestWithReviewers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockTeamRepo.EXPECT().GetTeamWithAncestors(gomock.Any(), "Backend").Return([]domain.Team{*team}, nil)
+		mockPrRepo.EXPECT().CreatePullRequestWithReviewers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 
-		service.CreatePullRequest(c)
+		response, err := service.CreatePullRequest(ctx, req)
 
-		require.Equal(t, http.StatusCreated, w.Code)
-		var response map[string]interface{}
-		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Contains(t, response, "pr")
-	})
-
-	t.Run("invalid request body", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests", strings.NewReader("invalid json"))
-		c.Request.Header.Set("Content-Type", "application/json")
-
-		service.CreatePullRequest(c)
-
-		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.NotNil(t, response.PR)
+		assert.Equal(t, prID, response.PR.PullRequestId)
+		assert.Equal(t, domain.PullRequestStatusOPEN, response.PR.Status)
 	})
 
 	t.Run("author not found", func(t *testing.T) {
 		prID := testStrID
 		authorID := testStrID
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `",
-			"pull_request_name": "Add feature",
-			"author_id": "` + authorID + `"
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+		req := domain.CreatePullRequestRequest{
+			PullRequestID:   prID,
+			PullRequestName: "Add feature",
+			AuthorID:        authorID,
+		}
 
 		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), authorID).Return(nil, pgx.ErrNoRows)
 
-		service.CreatePullRequest(c)
+		_, err := service.CreatePullRequest(ctx, req)
 
-		require.Equal(t, http.StatusNotFound, w.Code)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
 	})
 
 	t.Run("error getting team", func(t *testing.T) {
@@ -126,23 +102,18 @@ func TestPullRequestService_CreatePullRequest(t *testing.T) {
 			IsActive: true,
 		}
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `",
-			"pull_request_name": "Add feature",
-			"author_id": "` + authorID + `"
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+		req := domain.CreatePullRequestRequest{
+			PullRequestID:   prID,
+			PullRequestName: "Add feature",
+			AuthorID:        authorID,
+		}
 
 		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), authorID).Return(author, nil)
-		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(nil, errors.New("db error"))
+		mockTeamRepo.EXPECT().GetTeamWithAncestors(gomock.Any(), "Backend").Return(nil, errors.New("db error"))
 
-		service.CreatePullRequest(c)
+		_, err := service.CreatePullRequest(ctx, req)
 
-		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Error(t, err)
 	})
 
 	t.Run("PR already exists", func(t *testing.T) {
@@ -165,26 +136,23 @@ func TestPullRequestService_CreatePullRequest(t *testing.T) {
 			},
 		}
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `",
-			"pull_request_name": "Add feature",
-			"author_id": "` + authorID + `"
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+		req := domain.CreatePullRequestRequest{
+			PullRequestID:   prID,
+			PullRequestName: "Add feature",
+			AuthorID:        authorID,
+		}
 
 		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), authorID).Return(author, nil)
-		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(team, nil)
+		mockTeamRepo.EXPECT().GetTeamWithAncestors(gomock.Any(), "Backend").Return([]domain.Team{*team}, nil)
 
 		pgErr := &pgconn.PgError{Code: "23505"}
-		mockPrRepo.EXPECT().CreatePullRequestWithReviewers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(pgErr)
+		mockPrRepo.EXPECT().CreatePullRequestWithReviewers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(pgErr)
 
-		service.CreatePullRequest(c)
+		_, err := service.CreatePullRequest(ctx, req)
 
-		require.Equal(t, http.StatusConflict, w.Code)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.PrExists, domainErr.Code)
 	})
 
 	t.Run("error creating PR with reviewers", func(t *testing.T) {
@@ -207,23 +175,122 @@ func TestPullRequestService_CreatePullRequest(t *testing.T) {
 			},
 		}
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `",
-			"pull_request_name": "Add feature",
-			"author_id": "` + authorID + `"
-		}`
+		req := domain.CreatePullRequestRequest{
+			PullRequestID:   prID,
+			PullRequestName: "Add feature",
+			AuthorID:        authorID,
+		}
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), authorID).Return(author, nil)
+		mockTeamRepo.EXPECT().GetTeamWithAncestors(gomock.Any(), "Backend").Return([]domain.Team{*team}, nil)
+		mockPrRepo.EXPECT().CreatePullRequestWithReviewers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+
+		_, err := service.CreatePullRequest(ctx, req)
+
+		require.Error(t, err)
+	})
+}
+
+func TestPullRequestService_CreatePullRequestParentFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPrRepo := mocks.NewMockPullRequestRepositoryInterface(ctrl)
+	mockPrReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+
+	service := NewPullRequestService(mockPrRepo, mockPrReviewersRepo, mockUserRepo, mockTeamRepo)
+	ctx := context.Background()
+
+	t.Run("second reviewer taken from parent team", func(t *testing.T) {
+		authorID := "user-alice"
+		squadMemberID := "user-bob"
+		guildMemberID := "user-dave"
+
+		author := &domain.User{UserId: authorID, Username: "Alice", TeamName: "Payments", IsActive: true}
+
+		teams := []domain.Team{
+			{TeamName: "Payments", Members: []domain.TeamMember{
+				{UserId: authorID, Username: "Alice", IsActive: true},
+				{UserId: squadMemberID, Username: "Bob", IsActive: true},
+			}},
+			{TeamName: "Backend", Members: []domain.TeamMember{
+				{UserId: guildMemberID, Username: "Dave", IsActive: true},
+			}},
+		}
+
+		req := domain.CreatePullRequestRequest{
+			PullRequestID:   "pr-1",
+			PullRequestName: "Add feature",
+			AuthorID:        authorID,
+		}
+
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), authorID).Return(author, nil)
+		mockTeamRepo.EXPECT().GetTeamWithAncestors(gomock.Any(), "Payments").Return(teams, nil)
+		mockPrRepo.EXPECT().
+			CreatePullRequestWithReviewers(gomock.Any(), gomock.Any(), []string{squadMemberID, guildMemberID}, false, gomock.Any()).
+			DoAndReturn(func(_ interface{}, _ *domain.PullRequest, _ []string, _ bool, meta *domain.AssignmentMeta) error {
+				// Метаданные назначения: весь пул по иерархии, автор исключён, seed сохранён
+				require.NotNil(t, meta)
+				assert.Equal(t, domain.AssignmentStrategyRandom, meta.Strategy)
+				assert.Equal(t, 2, meta.CandidateCount)
+				require.Len(t, meta.Excluded, 1)
+				assert.Equal(t, domain.ExclusionReasonAuthor, meta.Excluded[0].Reason)
+				assert.NotNil(t, meta.Seed)
+				return nil
+			})
+
+		response, err := service.CreatePullRequest(ctx, req)
+
+		require.NoError(t, err)
+		require.Len(t, response.Assignments, 2)
+		require.NotNil(t, response.Assignments[0].Meta)
+		assert.Equal(t, domain.AssignmentStrategyRandom, response.Assignments[0].Meta.Strategy)
+	})
+
+	t.Run("reviewers taken from all author teams before parent team", func(t *testing.T) {
+		authorID := "user-alice"
+		backendMemberID := "user-bob"
+		mobileMemberID := "user-carol"
+
+		author := &domain.User{
+			UserId:    authorID,
+			Username:  "Alice",
+			TeamName:  "Backend",
+			TeamNames: []string{"Backend", "Mobile"},
+			IsActive:  true,
+		}
+
+		req := domain.CreatePullRequestRequest{
+			PullRequestID:   "pr-2",
+			PullRequestName: "Add feature",
+			AuthorID:        authorID,
+		}
 
 		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), authorID).Return(author, nil)
-		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(team, nil)
-		mockPrRepo.EXPECT().CreatePullRequestWithReviewers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+		mockTeamRepo.EXPECT().GetTeamWithAncestors(gomock.Any(), "Backend").Return([]domain.Team{
+			{TeamName: "Backend", Members: []domain.TeamMember{
+				{UserId: authorID, Username: "Alice", IsActive: true},
+				{UserId: backendMemberID, Username: "Bob", IsActive: true},
+			}},
+			{TeamName: "Engineering", Members: []domain.TeamMember{
+				{UserId: "user-dave", Username: "Dave", IsActive: true},
+			}},
+		}, nil)
+		mockTeamRepo.EXPECT().GetTeamWithAncestors(gomock.Any(), "Mobile").Return([]domain.Team{
+			{TeamName: "Mobile", Members: []domain.TeamMember{
+				{UserId: authorID, Username: "Alice", IsActive: true},
+				{UserId: mobileMemberID, Username: "Carol", IsActive: true},
+			}},
+		}, nil)
+		mockPrRepo.EXPECT().
+			CreatePullRequestWithReviewers(gomock.Any(), gomock.Any(), gomock.Any(), false, gomock.Any()).
+			Return(nil)
 
-		service.CreatePullRequest(c)
+		response, err := service.CreatePullRequest(ctx, req)
 
-		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{backendMemberID, mobileMemberID}, response.PR.AssignedReviewers)
 	})
 }
@@ -1,87 +1,73 @@
 package pullRequestService
 
 import (
+	"context"
 	"errors"
-	"net/http"
 	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/pullRequestStorage"
 	"github.com/nedokyrill/avito-pr-api/pkg/metrics"
 	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
 )
 
-func (s *PullRequestServiceImpl) MergePullRequest(c *gin.Context) {
-	ctx := c.Request.Context()
-
-	var req domain.MergePullRequestRequest
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
-			domain.InvalidRequest,
-			"invalid request body",
-		))
-		return
-	}
-
-	pr, err := s.prRepo.GetPullRequestByID(ctx, req.PullRequestID)
+// MergePullRequest - идемпотентный мердж PR (используется и вебхуками VCS): уже смердженный PR возвращается как есть,
+// закрытый без мерджа - PR_CLOSED
+func (s *PullRequestServiceImpl) MergePullRequest(ctx context.Context, prID string) (*domain.PullRequestResponse, error) {
+	pr, err := s.getPullRequest(ctx, prID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
-				domain.NotFound,
-				"PR not found",
-			))
-			return
-		}
-		logger.Logger.Error("error getting PR: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrMergePRMsg,
-		))
-		return
+		return nil, err
 	}
 
 	if pr.Status == domain.PullRequestStatusOPEN {
-		err = s.prRepo.MergePullRequest(ctx, req.PullRequestID)
-		if err != nil {
-			logger.Logger.Error("error merging PR: ", err)
-			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-				domain.InternalError,
-				domain.ErrMergePRMsg,
-			))
-			return
-		}
-
-		now := time.Now()
-		pr.MergedAt = &now
-		pr.Status = domain.PullRequestStatusMERGED
+		err = s.prRepo.MergePullRequest(ctx, prID)
+		switch {
+		case errors.Is(err, pullRequestStorage.ErrPullRequestNotOpen):
+			// PR успели смерджить или закрыть параллельно - отвечаем по его актуальному статусу
+			if pr, err = s.getPullRequest(ctx, prID); err != nil {
+				return nil, err
+			}
+		case err != nil:
+			return nil, err
+		default:
+			now := time.Now()
+			pr.MergedAt = &now
+			pr.Status = domain.PullRequestStatusMERGED
 
-		// Записываем метрику времени жизни PR (от создания до мерджа)
-		if pr.CreatedAt != nil {
-			lifecycleDuration := now.Sub(*pr.CreatedAt)
-			lifecycleHours := lifecycleDuration.Hours()
-			metrics.PRLifecycleDurationHours.Observe(lifecycleHours)
-			logger.Logger.Infow("PR lifecycle metric recorded",
-				"pr_id", req.PullRequestID,
-				"lifecycle_hours", lifecycleHours,
-			)
+			// Записываем метрику времени жизни PR (от создания до мерджа)
+			if pr.CreatedAt != nil {
+				lifecycleDuration := now.Sub(*pr.CreatedAt)
+				lifecycleHours := lifecycleDuration.Hours()
+				metrics.PRLifecycleDurationHours.Observe(lifecycleHours)
+				logger.Logger.Infow("PR lifecycle metric recorded",
+					"pr_id", prID,
+					"lifecycle_hours", lifecycleHours,
+				)
+			}
 		}
 	}
 
-	reviewers, err := s.prReviewersRepo.GetAssignedReviewers(ctx, req.PullRequestID)
+	if pr.Status == domain.PullRequestStatusCLOSED {
+		return nil, domain.NewError(domain.PrClosed, "cannot merge closed PR")
+	}
+
+	response, err := s.pullRequestResponse(ctx, pr)
 	if err != nil {
-		logger.Logger.Error("error getting reviewers: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrMergePRMsg,
-		))
-		return
+		return nil, err
 	}
-	pr.AssignedReviewers = reviewers
 
-	logger.Logger.Infow("PR merged successfully", "pr_id", req.PullRequestID)
-	c.JSON(http.StatusOK, gin.H{
-		"pr": pr,
-	})
+	logger.Logger.Infow("PR merged successfully", "pr_id", prID)
+	return response, nil
+}
+
+func (s *PullRequestServiceImpl) getPullRequest(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	pr, err := s.prRepo.GetPullRequestByID(ctx, prID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewError(domain.NotFound, "PR not found")
+		}
+		return nil, err
+	}
+	return pr, nil
 }
@@ -1,19 +1,16 @@
 package pullRequestService
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"net/http"
-	"net/http/httptest"
-	"strings"
 	"testing"
 	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/jackc/pgx/v5"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
 	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/pullRequestStorage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -28,6 +25,7 @@ func TestPullRequestService_MergePullRequest(t *testing.T) {
 	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
 
 	service := NewPullRequestService(mockPrRepo, mockPrReviewersRepo, mockUserRepo, mockTeamRepo)
+	ctx := context.Background()
 
 	t.Run("successfully merge PR", func(t *testing.T) {
 		prID := testStrID
@@ -40,56 +38,27 @@ func TestPullRequestService_MergePullRequest(t *testing.T) {
 			Status:          domain.PullRequestStatusOPEN,
 		}
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `"
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests/merge", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
-
 		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(pr, nil)
 		mockPrRepo.EXPECT().MergePullRequest(gomock.Any(), prID).Return(nil)
-		mockPrReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), prID).Return([]string{}, nil)
+		mockPrReviewersRepo.EXPECT().GetReviewerAssignments(gomock.Any(), prID).Return([]domain.ReviewerAssignment{}, nil)
 
-		service.MergePullRequest(c)
+		response, err := service.MergePullRequest(ctx, prID)
 
-		require.Equal(t, http.StatusOK, w.Code)
-		var response map[string]interface{}
-		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Contains(t, response, "pr")
-	})
-
-	t.Run("invalid request body", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests/merge", strings.NewReader("invalid"))
-		c.Request.Header.Set("Content-Type", "application/json")
-
-		service.MergePullRequest(c)
-
-		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, domain.PullRequestStatusMERGED, response.PR.Status)
+		assert.NotNil(t, response.PR.MergedAt)
 	})
 
 	t.Run("PR not found", func(t *testing.T) {
 		prID := testStrID
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `"
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests/merge", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
-
 		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(nil, pgx.ErrNoRows)
 
-		service.MergePullRequest(c)
+		_, err := service.MergePullRequest(ctx, prID)
 
-		require.Equal(t, http.StatusNotFound, w.Code)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
 	})
 
 	t.Run("error getting reviewers", func(t *testing.T) {
@@ -104,22 +73,13 @@ func TestPullRequestService_MergePullRequest(t *testing.T) {
 			CreatedAt:       &createdAt,
 		}
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `"
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests/merge", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
-
 		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(pr, nil)
 		mockPrRepo.EXPECT().MergePullRequest(gomock.Any(), prID).Return(nil)
-		mockPrReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), prID).Return(nil, errors.New("db error"))
+		mockPrReviewersRepo.EXPECT().GetReviewerAssignments(gomock.Any(), prID).Return(nil, errors.New("db error"))
 
-		service.MergePullRequest(c)
+		_, err := service.MergePullRequest(ctx, prID)
 
-		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Error(t, err)
 	})
 
 	t.Run("error merging PR", func(t *testing.T) {
@@ -134,21 +94,12 @@ func TestPullRequestService_MergePullRequest(t *testing.T) {
 			CreatedAt:       &createdAt,
 		}
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `"
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests/merge", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
-
 		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(pr, nil)
 		mockPrRepo.EXPECT().MergePullRequest(gomock.Any(), prID).Return(errors.New("db error"))
 
-		service.MergePullRequest(c)
+		_, err := service.MergePullRequest(ctx, prID)
 
-		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Error(t, err)
 	})
 
 	t.Run("PR already merged", func(t *testing.T) {
@@ -162,21 +113,83 @@ func TestPullRequestService_MergePullRequest(t *testing.T) {
 			Status:          domain.PullRequestStatusMERGED,
 		}
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `"
-		}`
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(pr, nil)
+		mockPrReviewersRepo.EXPECT().GetReviewerAssignments(gomock.Any(), prID).Return([]domain.ReviewerAssignment{}, nil)
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests/merge", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+		response, err := service.MergePullRequest(ctx, prID)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.PullRequestStatusMERGED, response.PR.Status)
+	})
+
+	t.Run("PR merged concurrently - returned as is", func(t *testing.T) {
+		prID := testStrID
+		createdAt := time.Now().Add(-2 * time.Hour)
+		mergedAt := time.Now().Add(-time.Minute)
+
+		openPR := &domain.PullRequest{
+			PullRequestId:   prID,
+			PullRequestName: "Add feature",
+			AuthorId:        testStrID,
+			Status:          domain.PullRequestStatusOPEN,
+			CreatedAt:       &createdAt,
+		}
+		mergedPR := *openPR
+		mergedPR.Status = domain.PullRequestStatusMERGED
+		mergedPR.MergedAt = &mergedAt
+
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(openPR, nil)
+		mockPrRepo.EXPECT().MergePullRequest(gomock.Any(), prID).Return(pullRequestStorage.ErrPullRequestNotOpen)
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(&mergedPR, nil)
+		mockPrReviewersRepo.EXPECT().GetReviewerAssignments(gomock.Any(), prID).Return([]domain.ReviewerAssignment{}, nil)
+
+		response, err := service.MergePullRequest(ctx, prID)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.PullRequestStatusMERGED, response.PR.Status)
+		require.NotNil(t, response.PR.MergedAt)
+		assert.True(t, response.PR.MergedAt.Equal(mergedAt))
+	})
+
+	t.Run("PR closed concurrently - PR_CLOSED", func(t *testing.T) {
+		prID := testStrID
+
+		openPR := &domain.PullRequest{
+			PullRequestId:   prID,
+			PullRequestName: "Add feature",
+			AuthorId:        testStrID,
+			Status:          domain.PullRequestStatusOPEN,
+		}
+		closedPR := *openPR
+		closedPR.Status = domain.PullRequestStatusCLOSED
+
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(openPR, nil)
+		mockPrRepo.EXPECT().MergePullRequest(gomock.Any(), prID).Return(pullRequestStorage.ErrPullRequestNotOpen)
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(&closedPR, nil)
+
+		_, err := service.MergePullRequest(ctx, prID)
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.PrClosed, domainErr.Code)
+	})
+
+	t.Run("PR closed without merge", func(t *testing.T) {
+		prID := testStrID
+
+		pr := &domain.PullRequest{
+			PullRequestId:   prID,
+			PullRequestName: "Add feature",
+			AuthorId:        testStrID,
+			Status:          domain.PullRequestStatusCLOSED,
+		}
 
 		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(pr, nil)
-		mockPrReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), prID).Return([]string{}, nil)
 
-		service.MergePullRequest(c)
+		_, err := service.MergePullRequest(ctx, prID)
 
-		require.Equal(t, http.StatusOK, w.Code)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.PrClosed, domainErr.Code)
 	})
 }
-
@@ -0,0 +1,47 @@
+package pullRequestService
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils"
+)
+
+// PreviewAssignment показывает, как были бы выбраны ревьюеры для PR автора, ничего не создавая:
+// пул кандидатов по уровням иерархии команд, исключённых участников с причиной и пример выбора
+func (s *PullRequestServiceImpl) PreviewAssignment(
+	ctx context.Context,
+	req domain.PreviewAssignmentRequest,
+) (*domain.AssignmentPreview, error) {
+	author, err := s.userRepo.GetUserByID(ctx, req.AuthorID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewError(domain.NotFound, "author not found")
+		}
+		return nil, err
+	}
+
+	// Тот же набор команд, что и при создании PR
+	teams, err := s.getAuthorReviewTeams(ctx, author)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, excluded := utils.EvaluateReviewerCandidates(
+		teams,
+		req.AuthorID,
+		utils.ExcludeWithReason(req.ExcludeUserIDs, domain.ExclusionReasonExcluded),
+	)
+	sample := utils.RandSelectReviewersWithFallback(teams, req.AuthorID, req.ExcludeUserIDs, domain.MaxReviewersCount)
+
+	return &domain.AssignmentPreview{
+		AuthorID:          req.AuthorID,
+		TeamName:          author.TeamName,
+		Candidates:        candidates,
+		Excluded:          excluded,
+		SampleSelection:   sample,
+		NeedMoreReviewers: len(sample) < domain.MaxReviewersCount,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package pullRequestService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestService_PreviewAssignment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPrRepo := mocks.NewMockPullRequestRepositoryInterface(ctrl)
+	mockPrReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+
+	service := NewPullRequestService(mockPrRepo, mockPrReviewersRepo, mockUserRepo, mockTeamRepo)
+	ctx := context.Background()
+
+	author := &domain.User{
+		UserId:   "u1",
+		Username: "Alice",
+		TeamName: "Backend",
+		IsActive: true,
+	}
+
+	t.Run("returns candidates, exclusions and sample without creating PR", func(t *testing.T) {
+		teams := []domain.Team{
+			{
+				TeamName: "Backend",
+				Members: []domain.TeamMember{
+					{UserId: "u1", Username: "Alice", IsActive: true},
+					{UserId: "u2", Username: "Bob", IsActive: true},
+					{UserId: "u3", Username: "Charlie", IsActive: false},
+					{UserId: "u4", Username: "David", IsActive: true},
+				},
+			},
+			{
+				TeamName: "Platform",
+				Members: []domain.TeamMember{
+					{UserId: "u5", Username: "Eve", IsActive: true},
+				},
+			},
+		}
+
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(author, nil)
+		mockTeamRepo.EXPECT().GetTeamWithAncestors(gomock.Any(), "Backend").Return(teams, nil)
+
+		response, err := service.PreviewAssignment(ctx, domain.PreviewAssignmentRequest{
+			AuthorID:       "u1",
+			ExcludeUserIDs: []string{"u4"},
+		})
+
+		require.NoError(t, err)
+
+		require.Len(t, response.Candidates, 2)
+		assert.Equal(t, "u2", response.Candidates[0].UserID)
+		assert.Equal(t, "u5", response.Candidates[1].UserID)
+		assert.Equal(t, 1, response.Candidates[1].Level)
+
+		reasons := make(map[string]domain.ExclusionReason)
+		for _, e := range response.Excluded {
+			reasons[e.UserID] = e.Reason
+		}
+		assert.Equal(t, map[string]domain.ExclusionReason{
+			"u1": domain.ExclusionReasonAuthor,
+			"u3": domain.ExclusionReasonInactive,
+			"u4": domain.ExclusionReasonExcluded,
+		}, reasons)
+
+		assert.ElementsMatch(t, []string{"u2", "u5"}, response.SampleSelection)
+		assert.False(t, response.NeedMoreReviewers)
+	})
+
+	t.Run("author not found", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), "missing").Return(nil, pgx.ErrNoRows)
+
+		_, err := service.PreviewAssignment(ctx, domain.PreviewAssignmentRequest{AuthorID: "missing"})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
+	})
+
+	t.Run("error getting team", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(author, nil)
+		mockTeamRepo.EXPECT().GetTeamWithAncestors(gomock.Any(), "Backend").Return(nil, errors.New("db error"))
+
+		_, err := service.PreviewAssignment(ctx, domain.PreviewAssignmentRequest{AuthorID: "u1"})
+
+		require.Error(t, err)
+	})
+}
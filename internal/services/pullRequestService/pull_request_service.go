@@ -1,6 +1,9 @@
 package pullRequestService
 
 import (
+	"context"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
 	"github.com/nedokyrill/avito-pr-api/internal/storage"
 )
 
@@ -24,3 +27,25 @@ func NewPullRequestService(
 		teamRepo:        teamRepo,
 	}
 }
+
+func assignedReviewerIDs(assignments []domain.ReviewerAssignment) []string {
+	reviewerIDs := make([]string, 0, len(assignments))
+	for _, assignment := range assignments {
+		reviewerIDs = append(reviewerIDs, assignment.ReviewerID)
+	}
+	return reviewerIDs
+}
+
+// pullRequestResponse дополняет PR текущими назначениями ревьюверов
+func (s *PullRequestServiceImpl) pullRequestResponse(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequestResponse, error) {
+	assignments, err := s.prReviewersRepo.GetReviewerAssignments(ctx, pr.PullRequestId)
+	if err != nil {
+		return nil, err
+	}
+	pr.AssignedReviewers = assignedReviewerIDs(assignments)
+
+	return &domain.PullRequestResponse{
+		PR:          pr,
+		Assignments: assignments,
+	}, nil
+}
@@ -1,159 +1,159 @@
 package pullRequestService
 
 import (
+	"context"
 	"errors"
 	"math/rand"
-	"net/http"
-	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
 	"github.com/nedokyrill/avito-pr-api/pkg/utils"
 	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
 )
 
-func (s *PullRequestServiceImpl) ReassignReviewer(c *gin.Context) {
-	ctx := c.Request.Context()
-
-	var req domain.ReassignReviewerRequest
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
-			domain.InvalidRequest,
-			"invalid request body",
-		))
-		return
-	}
-
+// ReassignReviewer заменяет ревьювера случайным активным участником его команды (используется и gRPC API).
+// Если замены нет, PR помечается как нуждающийся в ревьюверах и возвращается NO_CANDIDATE
+func (s *PullRequestServiceImpl) ReassignReviewer(ctx context.Context, req domain.ReassignReviewerRequest) (*domain.ReassignReviewerResponse, error) {
 	pr, err := s.prRepo.GetPullRequestByID(ctx, req.PullRequestID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
-				domain.NotFound,
-				"PR not found",
-			))
-			return
+			return nil, domain.NewError(domain.NotFound, "PR not found")
 		}
-		logger.Logger.Error("error getting PR: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrReassignReviewerMsg,
-		))
-		return
+		return nil, err
 	}
 
 	if pr.Status == domain.PullRequestStatusMERGED {
-		c.JSON(http.StatusConflict, domain.NewErrorResponse(
-			domain.PrMerged,
-			"cannot reassign on merged PR",
-		))
-		return
+		return nil, domain.NewError(domain.PrMerged, "cannot reassign on merged PR")
+	}
+
+	if pr.Status == domain.PullRequestStatusCLOSED {
+		return nil, domain.NewError(domain.PrClosed, "cannot reassign on closed PR")
 	}
 
 	assignedReviewers, err := s.prReviewersRepo.GetAssignedReviewers(ctx, req.PullRequestID)
 	if err != nil {
-		logger.Logger.Error("error getting assigned reviewers: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrReassignReviewerMsg,
-		))
-		return
+		return nil, err
 	}
 
 	if !utils.Contains(assignedReviewers, req.OldUserID) {
-		c.JSON(http.StatusConflict, domain.NewErrorResponse(
-			domain.NotAssigned,
-			"reviewer is not assigned to this PR",
-		))
-		return
+		return nil, domain.NewError(domain.NotAssigned, "reviewer is not assigned to this PR")
 	}
 
 	oldUser, err := s.userRepo.GetUserByID(ctx, req.OldUserID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
-				domain.NotFound,
-				"user not found",
-			))
-			return
+			return nil, domain.NewError(domain.NotFound, "user not found")
 		}
-		logger.Logger.Error("error getting user: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrReassignReviewerMsg,
-		))
-		return
+		return nil, err
 	}
 
-	team, err := s.teamRepo.GetTeamByName(ctx, oldUser.TeamName)
+	reviewTeamName, err := s.resolveReviewTeam(ctx, oldUser, pr.AuthorId)
 	if err != nil {
-		logger.Logger.Error("error getting team: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrReassignReviewerMsg,
-		))
-		return
+		return nil, err
 	}
 
+	teams, err := s.teamRepo.GetTeamWithAncestors(ctx, reviewTeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Кандидаты ищутся в команде заменяемого ревьювера, к родительским командам
+	// поднимаемся только если на текущем уровне замены нет
+	evaluated, excluded := utils.EvaluateReviewerCandidates(
+		teams,
+		pr.AuthorId,
+		utils.ExcludeWithReason(assignedReviewers, domain.ExclusionReasonAlreadyAssigned),
+	)
 	var candidates []string
-	for _, member := range team.Members {
-		if member.IsActive && member.UserId != pr.AuthorId && !utils.Contains(assignedReviewers, member.UserId) {
-			candidates = append(candidates, member.UserId)
+	for _, candidate := range evaluated {
+		if candidate.Level != evaluated[0].Level {
+			break
 		}
+		candidates = append(candidates, candidate.UserID)
 	}
 
 	if len(candidates) == 0 {
 		err = s.prRepo.SetNeedMoreReviewers(ctx, req.PullRequestID, true)
 		if err != nil {
-			logger.Logger.Error("error setting need_more_reviewers flag: ", err)
-			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-				domain.InternalError,
-				domain.ErrReassignReviewerMsg,
-			))
-			return
+			return nil, err
 		}
 
-		c.JSON(http.StatusConflict, domain.NewErrorResponse(
-			domain.NoCandidate,
-			"no active replacement candidate in team",
-		))
-		return
+		// Событие нужно только для истории и метрик, его потеря не должна ломать ответ
+		err = s.prReviewersRepo.RecordAssignmentEvent(ctx, domain.AssignmentEvent{
+			PrID:          req.PullRequestID,
+			Type:          domain.AssignmentEventNoCandidate,
+			Strategy:      domain.AssignmentStrategyReassign,
+			OldReviewerID: req.OldUserID,
+		})
+		if err != nil {
+			logger.Logger.Error("error recording no candidate event: ", err)
+		}
+
+		return nil, domain.NewError(domain.NoCandidate, "no active replacement candidate in team")
 	}
 
+	seed := utils.NewSeed()
 	//nolint:gosec // G404: math/rand достаточно для случайного выбора ревьюеров
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	rng := rand.New(rand.NewSource(seed))
 	newReviewerID := candidates[rng.Intn(len(candidates))]
 
-	err = s.prReviewersRepo.ReassignReviewerAtomic(ctx, req.PullRequestID, req.OldUserID, newReviewerID)
+	meta := &domain.AssignmentMeta{
+		Strategy:           domain.AssignmentStrategyReassign,
+		CandidateCount:     len(candidates),
+		Excluded:           excluded,
+		Seed:               &seed,
+		ReplacedReviewerID: req.OldUserID,
+	}
+
+	err = s.prReviewersRepo.ReassignReviewerAtomic(ctx, req.PullRequestID, req.OldUserID, newReviewerID, meta)
 	if err != nil {
-		logger.Logger.Error("error reassigning reviewer: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrReassignReviewerMsg,
-		))
-		return
+		return nil, err
 	}
 
-	updatedReviewers, err := s.prReviewersRepo.GetAssignedReviewers(ctx, req.PullRequestID)
+	assignments, err := s.prReviewersRepo.GetReviewerAssignments(ctx, req.PullRequestID)
 	if err != nil {
-		logger.Logger.Error("error getting updated reviewers: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrReassignReviewerMsg,
-		))
-		return
+		return nil, err
 	}
 
-	pr.AssignedReviewers = updatedReviewers
+	pr.AssignedReviewers = assignedReviewerIDs(assignments)
 
 	logger.Logger.Infow("reviewer reassigned successfully",
 		"pr_id", req.PullRequestID,
 		"old_user_id", req.OldUserID,
 		"new_user_id", newReviewerID,
 	)
-	c.JSON(http.StatusOK, gin.H{
-		"pr":          pr,
-		"replaced_by": newReviewerID,
-	})
+	return &domain.ReassignReviewerResponse{
+		PR:          pr,
+		ReplacedBy:  newReviewerID,
+		Assignments: assignments,
+	}, nil
+}
+
+// resolveReviewTeam определяет, из какой команды искать замену ревьюверу. Ревьювер может состоять
+// в нескольких командах - тогда берётся та из них, в которой состоит и автор PR (ревьювер назначался
+// именно как её участник), иначе основная команда ревьювера
+func (s *PullRequestServiceImpl) resolveReviewTeam(ctx context.Context, reviewer *domain.User, authorID string) (string, error) {
+	if len(reviewer.TeamNames) <= 1 {
+		return reviewer.TeamName, nil
+	}
+
+	author, err := s.userRepo.GetUserByID(ctx, authorID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return reviewer.TeamName, nil
+		}
+		return "", err
+	}
+
+	if utils.Contains(reviewer.TeamNames, author.TeamName) {
+		return author.TeamName, nil
+	}
+
+	for _, teamName := range author.TeamNames {
+		if utils.Contains(reviewer.TeamNames, teamName) {
+			return teamName, nil
+		}
+	}
+
+	return reviewer.TeamName, nil
 }
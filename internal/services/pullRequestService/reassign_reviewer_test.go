@@ -1,17 +1,15 @@
 package pullRequestService
 
 import (
+	"context"
 	"errors"
-	"net/http"
-	"net/http/httptest"
-	"strings"
 	"testing"
 
-	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/jackc/pgx/v5"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
 	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -27,6 +25,7 @@ func TestPullRequestService_ReassignReviewer(t *testing.T) {
 	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
 
 	service := NewPullRequestService(mockPrRepo, mockPrReviewersRepo, mockUserRepo, mockTeamRepo)
+	ctx := context.Background()
 
 	t.Run("successfully reassign reviewer", func(t *testing.T) {
 		prID := "pr-123"
@@ -57,58 +56,40 @@ func TestPullRequestService_ReassignReviewer(t *testing.T) {
 			},
 		}
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `",
-			"old_user_id": "` + oldReviewerID + `"
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests/reassign", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+		req := domain.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     oldReviewerID,
+		}
 
 		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(pr, nil)
 		mockPrReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), prID).Return([]string{oldReviewerID}, nil)
 		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), oldReviewerID).Return(oldReviewer, nil)
-		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(team, nil)
-		mockPrReviewersRepo.EXPECT().ReassignReviewerAtomic(gomock.Any(), prID, oldReviewerID, gomock.Any()).Return(nil)
-		mockPrReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), prID).Return([]string{newReviewerID}, nil)
+		mockTeamRepo.EXPECT().GetTeamWithAncestors(gomock.Any(), "Backend").Return([]domain.Team{*team}, nil)
+		mockPrReviewersRepo.EXPECT().ReassignReviewerAtomic(gomock.Any(), prID, oldReviewerID, gomock.Any(), gomock.Any()).Return(nil)
+		mockPrReviewersRepo.EXPECT().GetReviewerAssignments(gomock.Any(), prID).Return([]domain.ReviewerAssignment{{ReviewerID: newReviewerID}}, nil)
 
-		service.ReassignReviewer(c)
+		response, err := service.ReassignReviewer(ctx, req)
 
-		require.Equal(t, http.StatusOK, w.Code)
-	})
-
-	t.Run("invalid request body", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests/reassign", strings.NewReader("invalid"))
-		c.Request.Header.Set("Content-Type", "application/json")
-
-		service.ReassignReviewer(c)
-
-		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.NoError(t, err)
+		assert.Equal(t, newReviewerID, response.ReplacedBy)
 	})
 
 	t.Run("PR not found", func(t *testing.T) {
 		prID := testStrID
 		reviewerID := testStrID
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `",
-			"old_user_id": "` + reviewerID + `"
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests/reassign", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+		req := domain.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     reviewerID,
+		}
 
 		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(nil, pgx.ErrNoRows)
 
-		service.ReassignReviewer(c)
+		_, err := service.ReassignReviewer(ctx, req)
 
-		require.Equal(t, http.StatusNotFound, w.Code)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
 	})
 
 	t.Run("PR already merged", func(t *testing.T) {
@@ -121,21 +102,41 @@ func TestPullRequestService_ReassignReviewer(t *testing.T) {
 			Status:          domain.PullRequestStatusMERGED,
 		}
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `",
-			"old_user_id": "` + reviewerID + `"
-		}`
+		req := domain.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     reviewerID,
+		}
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests/reassign", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(pr, nil)
+
+		_, err := service.ReassignReviewer(ctx, req)
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.PrMerged, domainErr.Code)
+	})
+
+	t.Run("PR closed", func(t *testing.T) {
+		prID := testStrID
+
+		pr := &domain.PullRequest{
+			PullRequestId:   prID,
+			PullRequestName: "Add feature",
+			Status:          domain.PullRequestStatusCLOSED,
+		}
+
+		req := domain.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     testStrID,
+		}
 
 		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(pr, nil)
 
-		service.ReassignReviewer(c)
+		_, err := service.ReassignReviewer(ctx, req)
 
-		require.Equal(t, http.StatusConflict, w.Code)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.PrClosed, domainErr.Code)
 	})
 
 	t.Run("reviewer not assigned", func(t *testing.T) {
@@ -149,22 +150,19 @@ func TestPullRequestService_ReassignReviewer(t *testing.T) {
 			Status:          domain.PullRequestStatusOPEN,
 		}
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `",
-			"old_user_id": "` + reviewerID + `"
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests/reassign", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+		req := domain.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     reviewerID,
+		}
 
 		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(pr, nil)
 		mockPrReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), prID).Return([]string{otherReviewerID}, nil)
 
-		service.ReassignReviewer(c)
+		_, err := service.ReassignReviewer(ctx, req)
 
-		require.Equal(t, http.StatusConflict, w.Code)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotAssigned, domainErr.Code)
 	})
 
 	t.Run("error getting user", func(t *testing.T) {
@@ -177,23 +175,18 @@ func TestPullRequestService_ReassignReviewer(t *testing.T) {
 			Status:          domain.PullRequestStatusOPEN,
 		}
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `",
-			"old_user_id": "` + reviewerID + `"
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests/reassign", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+		req := domain.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     reviewerID,
+		}
 
 		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(pr, nil)
 		mockPrReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), prID).Return([]string{reviewerID}, nil)
 		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), reviewerID).Return(nil, errors.New("db error"))
 
-		service.ReassignReviewer(c)
+		_, err := service.ReassignReviewer(ctx, req)
 
-		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Error(t, err)
 	})
 
 	t.Run("error reassigning reviewer", func(t *testing.T) {
@@ -225,24 +218,206 @@ func TestPullRequestService_ReassignReviewer(t *testing.T) {
 			},
 		}
 
-		requestBody := `{
-			"pull_request_id": "` + prID + `",
-			"old_user_id": "` + oldReviewerID + `"
-		}`
+		req := domain.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     oldReviewerID,
+		}
+
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(pr, nil)
+		mockPrReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), prID).Return([]string{oldReviewerID}, nil)
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), oldReviewerID).Return(oldReviewer, nil)
+		mockTeamRepo.EXPECT().GetTeamWithAncestors(gomock.Any(), "Backend").Return([]domain.Team{*team}, nil)
+		mockPrReviewersRepo.EXPECT().ReassignReviewerAtomic(gomock.Any(), prID, oldReviewerID, gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+
+		_, err := service.ReassignReviewer(ctx, req)
+
+		require.Error(t, err)
+	})
+
+	t.Run("no candidate is recorded as assignment event", func(t *testing.T) {
+		prID := "pr-790"
+		oldReviewerID := "user-eve"
+		authorID := "user-grace"
+
+		pr := &domain.PullRequest{
+			PullRequestId:   prID,
+			PullRequestName: "Add feature",
+			AuthorId:        authorID,
+			Status:          domain.PullRequestStatusOPEN,
+		}
+
+		oldReviewer := &domain.User{
+			UserId:   oldReviewerID,
+			Username: "Eve",
+			TeamName: "Backend",
+			IsActive: true,
+		}
+
+		team := &domain.Team{
+			TeamName: "Backend",
+			Members: []domain.TeamMember{
+				{UserId: authorID, Username: "Grace", IsActive: true},
+				{UserId: oldReviewerID, Username: "Eve", IsActive: true},
+			},
+		}
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/pull-requests/reassign", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+		req := domain.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     oldReviewerID,
+		}
 
 		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(pr, nil)
 		mockPrReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), prID).Return([]string{oldReviewerID}, nil)
 		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), oldReviewerID).Return(oldReviewer, nil)
-		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(team, nil)
-		mockPrReviewersRepo.EXPECT().ReassignReviewerAtomic(gomock.Any(), prID, oldReviewerID, gomock.Any()).Return(errors.New("db error"))
+		mockTeamRepo.EXPECT().GetTeamWithAncestors(gomock.Any(), "Backend").Return([]domain.Team{*team}, nil)
+		mockPrRepo.EXPECT().SetNeedMoreReviewers(gomock.Any(), prID, true).Return(nil)
+		mockPrReviewersRepo.EXPECT().RecordAssignmentEvent(gomock.Any(), domain.AssignmentEvent{
+			PrID:          prID,
+			Type:          domain.AssignmentEventNoCandidate,
+			Strategy:      domain.AssignmentStrategyReassign,
+			OldReviewerID: oldReviewerID,
+		}).Return(nil)
+
+		_, err := service.ReassignReviewer(ctx, req)
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NoCandidate, domainErr.Code)
+	})
+}
 
-		service.ReassignReviewer(c)
+func TestPullRequestService_ReassignReviewerParentFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-		require.Equal(t, http.StatusInternalServerError, w.Code)
+	mockPrRepo := mocks.NewMockPullRequestRepositoryInterface(ctrl)
+	mockPrReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+
+	service := NewPullRequestService(mockPrRepo, mockPrReviewersRepo, mockUserRepo, mockTeamRepo)
+	ctx := context.Background()
+
+	t.Run("replacement taken from parent team when squad has no candidates", func(t *testing.T) {
+		prID := "pr-123"
+		authorID := "user-alice"
+		oldReviewerID := "user-bob"
+		guildMemberID := "user-dave"
+
+		pr := &domain.PullRequest{
+			PullRequestId:   prID,
+			PullRequestName: "Add feature",
+			AuthorId:        authorID,
+			Status:          domain.PullRequestStatusOPEN,
+		}
+
+		oldReviewer := &domain.User{UserId: oldReviewerID, Username: "Bob", TeamName: "Payments", IsActive: true}
+
+		teams := []domain.Team{
+			{TeamName: "Payments", Members: []domain.TeamMember{
+				{UserId: authorID, Username: "Alice", IsActive: true},
+				{UserId: oldReviewerID, Username: "Bob", IsActive: true},
+			}},
+			{TeamName: "Backend", Members: []domain.TeamMember{
+				{UserId: guildMemberID, Username: "Dave", IsActive: true},
+			}},
+		}
+
+		req := domain.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     oldReviewerID,
+		}
+
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(pr, nil)
+		mockPrReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), prID).Return([]string{oldReviewerID}, nil)
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), oldReviewerID).Return(oldReviewer, nil)
+		mockTeamRepo.EXPECT().GetTeamWithAncestors(gomock.Any(), "Payments").Return(teams, nil)
+		mockPrReviewersRepo.EXPECT().ReassignReviewerAtomic(gomock.Any(), prID, oldReviewerID, guildMemberID, gomock.Any()).Return(nil)
+		mockPrReviewersRepo.EXPECT().GetReviewerAssignments(gomock.Any(), prID).Return([]domain.ReviewerAssignment{{ReviewerID: guildMemberID}}, nil)
+
+		response, err := service.ReassignReviewer(ctx, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, guildMemberID, response.ReplacedBy)
+	})
+}
+
+func TestPullRequestService_ReassignReviewerMultiTeam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPrRepo := mocks.NewMockPullRequestRepositoryInterface(ctrl)
+	mockPrReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+
+	service := NewPullRequestService(mockPrRepo, mockPrReviewersRepo, mockUserRepo, mockTeamRepo)
+	ctx := context.Background()
+
+	t.Run("replacement searched in the team shared with the author", func(t *testing.T) {
+		prID := "pr-123"
+		authorID := "user-alice"
+		oldReviewerID := "user-bob"
+		newReviewerID := "user-erin"
+
+		pr := &domain.PullRequest{
+			PullRequestId:   prID,
+			PullRequestName: "Add feature",
+			AuthorId:        authorID,
+			Status:          domain.PullRequestStatusOPEN,
+		}
+
+		// Bob основной командой числится в Backend, но ревьюит и для Payments
+		oldReviewer := &domain.User{
+			UserId:    oldReviewerID,
+			Username:  "Bob",
+			TeamName:  "Backend",
+			TeamNames: []string{"Backend", "Payments"},
+			IsActive:  true,
+		}
+		author := &domain.User{
+			UserId:    authorID,
+			Username:  "Alice",
+			TeamName:  "Payments",
+			TeamNames: []string{"Payments"},
+			IsActive:  true,
+		}
+
+		teams := []domain.Team{
+			{TeamName: "Payments", Members: []domain.TeamMember{
+				{UserId: authorID, Username: "Alice", IsActive: true},
+				{UserId: oldReviewerID, Username: "Bob", IsActive: true},
+				{UserId: newReviewerID, Username: "Erin", IsActive: true},
+			}},
+		}
+
+		req := domain.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     oldReviewerID,
+		}
+
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), prID).Return(pr, nil)
+		mockPrReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), prID).Return([]string{oldReviewerID}, nil)
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), oldReviewerID).Return(oldReviewer, nil)
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), authorID).Return(author, nil)
+		mockTeamRepo.EXPECT().GetTeamWithAncestors(gomock.Any(), "Payments").Return(teams, nil)
+		mockPrReviewersRepo.EXPECT().ReassignReviewerAtomic(gomock.Any(), prID, oldReviewerID, newReviewerID, gomock.Any()).
+			DoAndReturn(func(_ interface{}, _, _, _ string, meta *domain.AssignmentMeta) error {
+				require.NotNil(t, meta)
+				assert.Equal(t, domain.AssignmentStrategyReassign, meta.Strategy)
+				assert.Equal(t, 1, meta.CandidateCount)
+				assert.Equal(t, oldReviewerID, meta.ReplacedReviewerID)
+				assert.ElementsMatch(t, []domain.ExcludedReviewer{
+					{UserID: authorID, Username: "Alice", TeamName: "Payments", Reason: domain.ExclusionReasonAuthor},
+					{UserID: oldReviewerID, Username: "Bob", TeamName: "Payments", Reason: domain.ExclusionReasonAlreadyAssigned},
+				}, meta.Excluded)
+				return nil
+			})
+		mockPrReviewersRepo.EXPECT().GetReviewerAssignments(gomock.Any(), prID).Return([]domain.ReviewerAssignment{{ReviewerID: newReviewerID}}, nil)
+
+		response, err := service.ReassignReviewer(ctx, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, newReviewerID, response.ReplacedBy)
 	})
 }
@@ -0,0 +1,40 @@
+package pullRequestService
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// ReopenPullRequest - идемпотентное повторное открытие закрытого PR (используется вебхуками VCS): ревьюверы остаются
+// прежними, открытый PR возвращается как есть, смердженный - PR_MERGED
+func (s *PullRequestServiceImpl) ReopenPullRequest(ctx context.Context, prID string) (*domain.PullRequestResponse, error) {
+	pr, err := s.prRepo.GetPullRequestByID(ctx, prID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewError(domain.NotFound, "PR not found")
+		}
+		return nil, err
+	}
+
+	switch pr.Status {
+	case domain.PullRequestStatusMERGED:
+		return nil, domain.NewError(domain.PrMerged, "cannot reopen merged PR")
+	case domain.PullRequestStatusCLOSED:
+		if err = s.prRepo.ReopenPullRequest(ctx, prID); err != nil {
+			return nil, err
+		}
+		pr.Status = domain.PullRequestStatusOPEN
+	}
+
+	response, err := s.pullRequestResponse(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Infow("PR reopened successfully", "pr_id", prID)
+	return response, nil
+}
@@ -0,0 +1,75 @@
+package pullRequestService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestService_ReopenPullRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPrRepo := mocks.NewMockPullRequestRepositoryInterface(ctrl)
+	mockPrReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+
+	service := NewPullRequestService(mockPrRepo, mockPrReviewersRepo, mockUserRepo, mockTeamRepo)
+	ctx := context.Background()
+
+	newPR := func(status domain.PullRequestStatus) *domain.PullRequest {
+		return &domain.PullRequest{
+			PullRequestId:   testStrID,
+			PullRequestName: "Add feature",
+			AuthorId:        testStrID,
+			Status:          status,
+		}
+	}
+
+	t.Run("reopen closed PR", func(t *testing.T) {
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), testStrID).Return(newPR(domain.PullRequestStatusCLOSED), nil)
+		mockPrRepo.EXPECT().ReopenPullRequest(gomock.Any(), testStrID).Return(nil)
+		mockPrReviewersRepo.EXPECT().GetReviewerAssignments(gomock.Any(), testStrID).Return([]domain.ReviewerAssignment{}, nil)
+
+		response, err := service.ReopenPullRequest(ctx, testStrID)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.PullRequestStatusOPEN, response.PR.Status)
+	})
+
+	t.Run("open PR is returned as is", func(t *testing.T) {
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), testStrID).Return(newPR(domain.PullRequestStatusOPEN), nil)
+		mockPrReviewersRepo.EXPECT().GetReviewerAssignments(gomock.Any(), testStrID).Return([]domain.ReviewerAssignment{}, nil)
+
+		response, err := service.ReopenPullRequest(ctx, testStrID)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.PullRequestStatusOPEN, response.PR.Status)
+	})
+
+	t.Run("merged PR cannot be reopened", func(t *testing.T) {
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), testStrID).Return(newPR(domain.PullRequestStatusMERGED), nil)
+
+		_, err := service.ReopenPullRequest(ctx, testStrID)
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.PrMerged, domainErr.Code)
+	})
+
+	t.Run("error reopening PR", func(t *testing.T) {
+		mockPrRepo.EXPECT().GetPullRequestByID(gomock.Any(), testStrID).Return(newPR(domain.PullRequestStatusCLOSED), nil)
+		mockPrRepo.EXPECT().ReopenPullRequest(gomock.Any(), testStrID).Return(errors.New("db error"))
+
+		_, err := service.ReopenPullRequest(ctx, testStrID)
+
+		require.Error(t, err)
+	})
+}
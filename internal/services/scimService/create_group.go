@@ -0,0 +1,68 @@
+package scimService
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// CreateGroup - POST /scim/v2/Groups. Создаёт корневую команду, участники должны уже существовать
+func (s *ScimServiceImpl) CreateGroup(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.ScimGroup
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeScimError(c, http.StatusBadRequest, scimTypeInvalidSyntax, "invalid request body")
+		return
+	}
+
+	if req.DisplayName == "" {
+		writeScimError(c, http.StatusBadRequest, scimTypeInvalidValue, "displayName is required")
+		return
+	}
+
+	members := make([]string, 0, len(req.Members))
+	for _, ref := range req.Members {
+		user, err := s.getUser(ctx, ref.Value)
+		if err != nil {
+			writeScimServiceError(c, err, "error getting user: ")
+			return
+		}
+		members = append(members, user.UserId)
+	}
+
+	_, err := s.teamRepo.CreateTeamWithMembers(ctx, req.DisplayName, nil, nil)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			writeScimError(c, http.StatusConflict, scimTypeUniqueness, "group "+req.DisplayName+" already exists")
+			return
+		}
+		logger.Logger.Error("error creating team: ", err)
+		writeScimError(c, http.StatusInternalServerError, "", "internal error")
+		return
+	}
+
+	// участники уже существуют, поэтому добавляются в команду без изменения их имени и активности
+	for _, userID := range members {
+		if err = s.teamRepo.AddTeamMember(ctx, req.DisplayName, userID); err != nil {
+			logger.Logger.Error("error adding team member: ", err)
+			writeScimError(c, http.StatusInternalServerError, "", "internal error")
+			return
+		}
+	}
+
+	team, err := s.getTeam(ctx, req.DisplayName)
+	if err != nil {
+		writeScimServiceError(c, err, "error getting team: ")
+		return
+	}
+
+	logger.Logger.Infow("scim group created", "team_name", team.TeamName, "members_count", len(team.Members))
+	c.Header("Location", scimGroupsPath+team.TeamName)
+	writeScim(c, http.StatusCreated, toScimGroup(team))
+}
@@ -0,0 +1,42 @@
+package scimService
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimService_CreateGroup(t *testing.T) {
+	t.Run("successfully create group", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		body := `{"displayName":"Backend","members":[{"value":"u1"}]}`
+		w, c := newScimContext(http.MethodPost, "/scim/v2/Groups", body, "")
+
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(testUser(true), nil)
+		deps.teamRepo.EXPECT().
+			CreateTeamWithMembers(gomock.Any(), "Backend", gomock.Nil(), gomock.Nil()).
+			Return(uuid.New(), nil)
+		deps.teamRepo.EXPECT().AddTeamMember(gomock.Any(), "Backend", "u1").Return(nil)
+		deps.teamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(testTeam(), nil)
+
+		deps.service.CreateGroup(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("unknown member", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		body := `{"displayName":"Backend","members":[{"value":"ghost"}]}`
+		w, c := newScimContext(http.MethodPost, "/scim/v2/Groups", body, "")
+
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "ghost").Return(nil, pgx.ErrNoRows)
+
+		deps.service.CreateGroup(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
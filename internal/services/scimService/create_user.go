@@ -0,0 +1,54 @@
+package scimService
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// CreateUser - POST /scim/v2/Users. userName становится user_id, пользователь создаётся без команды
+func (s *ScimServiceImpl) CreateUser(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.ScimUser
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeScimError(c, http.StatusBadRequest, scimTypeInvalidSyntax, "invalid request body")
+		return
+	}
+
+	if req.UserName == "" {
+		writeScimError(c, http.StatusBadRequest, scimTypeInvalidValue, "userName is required")
+		return
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	err := s.userRepo.CreateUser(ctx, req.UserName, scimUserName(&req), active)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			writeScimError(c, http.StatusConflict, scimTypeUniqueness, "user "+req.UserName+" already exists")
+			return
+		}
+		logger.Logger.Error("error creating user: ", err)
+		writeScimError(c, http.StatusInternalServerError, "", "internal error")
+		return
+	}
+
+	user, err := s.getUser(ctx, req.UserName)
+	if err != nil {
+		writeScimServiceError(c, err, "error getting user: ")
+		return
+	}
+
+	logger.Logger.Infow("scim user created", "user_id", user.UserId)
+	c.Header("Location", scimUsersPath+user.UserId)
+	writeScim(c, http.StatusCreated, toScimUser(user))
+}
@@ -0,0 +1,50 @@
+package scimService
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimService_CreateUser(t *testing.T) {
+	deps := newScimTestDeps(t)
+
+	t.Run("successfully create user", func(t *testing.T) {
+		body := `{"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],"userName":"u1",
+			"name":{"givenName":"Alice","familyName":"Smith"},"active":true}`
+		w, c := newScimContext(http.MethodPost, "/scim/v2/Users", body, "")
+
+		deps.userRepo.EXPECT().CreateUser(gomock.Any(), "u1", "Alice Smith", true).Return(nil)
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(testUser(true), nil)
+
+		deps.service.CreateUser(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "/scim/v2/Users/u1", w.Header().Get("Location"))
+	})
+
+	t.Run("user already exists", func(t *testing.T) {
+		w, c := newScimContext(http.MethodPost, "/scim/v2/Users", `{"userName":"u1"}`, "")
+
+		deps.userRepo.EXPECT().
+			CreateUser(gomock.Any(), "u1", "u1", true).
+			Return(&pgconn.PgError{Code: "23505"})
+
+		deps.service.CreateUser(c)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, scimTypeUniqueness, decodeScimError(t, w).ScimType)
+	})
+
+	t.Run("userName is required", func(t *testing.T) {
+		w, c := newScimContext(http.MethodPost, "/scim/v2/Users", `{"displayName":"Alice"}`, "")
+
+		deps.service.CreateUser(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
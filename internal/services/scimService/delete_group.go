@@ -0,0 +1,18 @@
+package scimService
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteGroup - DELETE /scim/v2/Groups/:id. Команды не удаляются: на них завязаны иерархия
+// и история PR, состав команды можно очистить через PATCH/PUT
+func (s *ScimServiceImpl) DeleteGroup(c *gin.Context) {
+	if _, err := s.getTeam(c.Request.Context(), c.Param("id")); err != nil {
+		writeScimServiceError(c, err, "error getting team: ")
+		return
+	}
+
+	writeScimError(c, http.StatusBadRequest, scimTypeMutability, "groups cannot be deleted, remove members instead")
+}
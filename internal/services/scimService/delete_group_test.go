@@ -0,0 +1,22 @@
+package scimService
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimService_DeleteGroup(t *testing.T) {
+	deps := newScimTestDeps(t)
+	w, c := newScimContext(http.MethodDelete, "/scim/v2/Groups/Backend", "", "Backend")
+
+	deps.teamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(testTeam(), nil)
+
+	deps.service.DeleteGroup(c)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, scimTypeMutability, decodeScimError(t, w).ScimType)
+}
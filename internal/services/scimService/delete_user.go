@@ -0,0 +1,29 @@
+package scimService
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// DeleteUser - DELETE /scim/v2/Users/:id. Пользователь не удаляется, а деактивируется:
+// на него ссылаются PR и история ревью
+func (s *ScimServiceImpl) DeleteUser(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, err := s.getUser(ctx, c.Param("id"))
+	if err != nil {
+		writeScimServiceError(c, err, "error getting user: ")
+		return
+	}
+
+	if err = s.setUserActive(ctx, user, false); err != nil {
+		writeScimServiceError(c, err, "error deactivating user: ")
+		return
+	}
+
+	logger.Logger.Infow("scim user deleted (deactivated)", "user_id", user.UserId)
+	c.Status(http.StatusNoContent)
+	c.Writer.WriteHeaderNow()
+}
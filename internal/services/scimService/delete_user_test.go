@@ -0,0 +1,39 @@
+package scimService
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimService_DeleteUser(t *testing.T) {
+	t.Run("team member is deactivated with reassignment", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		w, c := newScimContext(http.MethodDelete, "/scim/v2/Users/u1", "", "u1")
+
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(testUser(true), nil)
+
+		deps.service.DeleteUser(c)
+
+		require.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, []string{"u1"}, deps.deactivator.userIDs)
+	})
+
+	t.Run("user without team goes through the same path", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		w, c := newScimContext(http.MethodDelete, "/scim/v2/Users/u1", "", "u1")
+
+		user := testUser(true)
+		user.TeamName = ""
+		user.TeamNames = []string{}
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(user, nil)
+
+		deps.service.DeleteUser(c)
+
+		require.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, []string{"u1"}, deps.deactivator.userIDs)
+	})
+}
@@ -0,0 +1,18 @@
+package scimService
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetGroup - GET /scim/v2/Groups/:id
+func (s *ScimServiceImpl) GetGroup(c *gin.Context) {
+	team, err := s.getTeam(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeScimServiceError(c, err, "error getting team: ")
+		return
+	}
+
+	writeScim(c, http.StatusOK, toScimGroup(team))
+}
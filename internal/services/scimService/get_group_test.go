@@ -0,0 +1,41 @@
+package scimService
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimService_GetGroup(t *testing.T) {
+	deps := newScimTestDeps(t)
+
+	t.Run("successfully get group", func(t *testing.T) {
+		w, c := newScimContext(http.MethodGet, "/scim/v2/Groups/Backend", "", "Backend")
+
+		deps.teamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(testTeam(), nil)
+
+		deps.service.GetGroup(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var group domain.ScimGroup
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &group))
+		assert.Equal(t, "Backend", group.ID)
+		assert.Len(t, group.Members, 2)
+	})
+
+	t.Run("group not found", func(t *testing.T) {
+		w, c := newScimContext(http.MethodGet, "/scim/v2/Groups/Nope", "", "Nope")
+
+		deps.teamRepo.EXPECT().GetTeamByName(gomock.Any(), "Nope").Return(nil, teamStorage.ErrTeamNotExists)
+
+		deps.service.GetGroup(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,47 @@
+package scimService
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+)
+
+type supported struct {
+	Supported bool `json:"supported"`
+}
+
+type filterSupport struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type bulkSupport struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type authenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// GetServiceProviderConfig - GET /scim/v2/ServiceProviderConfig, по нему IdP узнают возможности сервера
+func (s *ScimServiceImpl) GetServiceProviderConfig(c *gin.Context) {
+	writeScim(c, http.StatusOK, gin.H{
+		"schemas":        []string{domain.ScimSchemaServiceProviderConfig},
+		"patch":          supported{Supported: true},
+		"bulk":           bulkSupport{Supported: false},
+		"filter":         filterSupport{Supported: true, MaxResults: domain.ScimMaxCount},
+		"changePassword": supported{Supported: false},
+		"sort":           supported{Supported: false},
+		"etag":           supported{Supported: false},
+		"authenticationSchemes": []authenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "OAuth Bearer Token",
+			Description: "Authentication via the Authorization header",
+		}},
+	})
+}
@@ -0,0 +1,22 @@
+package scimService
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimService_GetServiceProviderConfig(t *testing.T) {
+	deps := newScimTestDeps(t)
+	w, c := newScimContext(http.MethodGet, "/scim/v2/ServiceProviderConfig", "", "")
+
+	deps.service.GetServiceProviderConfig(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var config map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &config))
+	assert.Equal(t, map[string]interface{}{"supported": true}, config["patch"])
+}
@@ -0,0 +1,18 @@
+package scimService
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetUser - GET /scim/v2/Users/:id
+func (s *ScimServiceImpl) GetUser(c *gin.Context) {
+	user, err := s.getUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeScimServiceError(c, err, "error getting user: ")
+		return
+	}
+
+	writeScim(c, http.StatusOK, toScimUser(user))
+}
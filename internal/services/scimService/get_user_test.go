@@ -0,0 +1,45 @@
+package scimService
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimService_GetUser(t *testing.T) {
+	deps := newScimTestDeps(t)
+
+	t.Run("successfully get user", func(t *testing.T) {
+		w, c := newScimContext(http.MethodGet, "/scim/v2/Users/u1", "", "u1")
+
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(testUser(true), nil)
+
+		deps.service.GetUser(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var user domain.ScimUser
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+		assert.Equal(t, "u1", user.UserName)
+		assert.Equal(t, "Alice", user.DisplayName)
+		require.NotNil(t, user.Active)
+		assert.True(t, *user.Active)
+		assert.Equal(t, []domain.ScimReference{{Value: "Backend", Display: "Backend"}}, user.Groups)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		w, c := newScimContext(http.MethodGet, "/scim/v2/Users/nobody", "", "nobody")
+
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "nobody").Return(nil, pgx.ErrNoRows)
+
+		deps.service.GetUser(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "404", decodeScimError(t, w).Status)
+	})
+}
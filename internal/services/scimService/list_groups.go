@@ -0,0 +1,53 @@
+package scimService
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// ListGroups - GET /scim/v2/Groups. Фильтры: displayName/id eq (оба - team_name).
+// Команд немного, поэтому фильтрация и пагинация делаются в памяти
+func (s *ScimServiceImpl) ListGroups(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var teamName *string
+	if rawFilter := c.Query("filter"); rawFilter != "" {
+		attr, value, err := parseScimFilter(rawFilter)
+		if err != nil {
+			writeScimError(c, http.StatusBadRequest, scimTypeInvalidFilter, err.Error())
+			return
+		}
+
+		if attr != "displayname" && attr != "id" {
+			writeScimError(c, http.StatusBadRequest, scimTypeInvalidFilter, "filtering by "+attr+" is not supported")
+			return
+		}
+		teamName = &value
+	}
+
+	startIndex, count := parseScimPagination(c)
+
+	teams, err := s.teamRepo.GetRoster(ctx)
+	if err != nil {
+		logger.Logger.Error("error getting teams: ", err)
+		writeScimError(c, http.StatusInternalServerError, "", "internal error")
+		return
+	}
+
+	matched := make([]domain.Team, 0, len(teams))
+	for _, team := range teams {
+		if teamName == nil || team.TeamName == *teamName {
+			matched = append(matched, team)
+		}
+	}
+
+	resources := make([]interface{}, 0, count)
+	for i := startIndex - 1; i < len(matched) && len(resources) < count; i++ {
+		resources = append(resources, toScimGroup(&matched[i]))
+	}
+
+	writeScim(c, http.StatusOK, newScimListResponse(resources, len(matched), startIndex))
+}
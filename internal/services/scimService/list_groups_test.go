@@ -0,0 +1,48 @@
+package scimService
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimService_ListGroups(t *testing.T) {
+	deps := newScimTestDeps(t)
+	teams := []domain.Team{*testTeam(), {TeamName: "Frontend", Members: []domain.TeamMember{}}}
+
+	t.Run("filter by displayName", func(t *testing.T) {
+		filter := url.QueryEscape(`displayName eq "Frontend"`)
+		w, c := newScimContext(http.MethodGet, "/scim/v2/Groups?filter="+filter, "", "")
+
+		deps.teamRepo.EXPECT().GetRoster(gomock.Any()).Return(teams, nil)
+
+		deps.service.ListGroups(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response domain.ScimListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 1, response.TotalResults)
+		require.Len(t, response.Resources, 1)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		w, c := newScimContext(http.MethodGet, "/scim/v2/Groups?startIndex=2&count=5", "", "")
+
+		deps.teamRepo.EXPECT().GetRoster(gomock.Any()).Return(teams, nil)
+
+		deps.service.ListGroups(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response domain.ScimListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.TotalResults)
+		assert.Equal(t, 2, response.StartIndex)
+		assert.Equal(t, 1, response.ItemsPerPage)
+	})
+}
@@ -0,0 +1,49 @@
+package scimService
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// ListUsers - GET /scim/v2/Users. Фильтры: userName/id eq (оба - user_id) и displayName eq
+func (s *ScimServiceImpl) ListUsers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var filter domain.UserFilter
+	if rawFilter := c.Query("filter"); rawFilter != "" {
+		attr, value, err := parseScimFilter(rawFilter)
+		if err != nil {
+			writeScimError(c, http.StatusBadRequest, scimTypeInvalidFilter, err.Error())
+			return
+		}
+
+		switch attr {
+		case "username", "id":
+			filter.UserID = &value
+		case "displayname":
+			filter.Username = &value
+		default:
+			writeScimError(c, http.StatusBadRequest, scimTypeInvalidFilter, "filtering by "+attr+" is not supported")
+			return
+		}
+	}
+
+	startIndex, count := parseScimPagination(c)
+
+	users, total, err := s.userRepo.ListUsers(ctx, filter, startIndex-1, count)
+	if err != nil {
+		logger.Logger.Error("error listing users: ", err)
+		writeScimError(c, http.StatusInternalServerError, "", "internal error")
+		return
+	}
+
+	resources := make([]interface{}, 0, len(users))
+	for i := range users {
+		resources = append(resources, toScimUser(&users[i]))
+	}
+
+	writeScim(c, http.StatusOK, newScimListResponse(resources, total, startIndex))
+}
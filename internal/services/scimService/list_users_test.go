@@ -0,0 +1,60 @@
+package scimService
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimService_ListUsers(t *testing.T) {
+	deps := newScimTestDeps(t)
+
+	t.Run("filter by userName", func(t *testing.T) {
+		filter := url.QueryEscape(`userName eq "u1"`)
+		w, c := newScimContext(http.MethodGet, "/scim/v2/Users?filter="+filter+"&startIndex=1&count=10", "", "")
+
+		userID := "u1"
+		deps.userRepo.EXPECT().
+			ListUsers(gomock.Any(), domain.UserFilter{UserID: &userID}, 0, 10).
+			Return([]domain.User{*testUser(true)}, 1, nil)
+
+		deps.service.ListUsers(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, domain.ScimContentType, w.Header().Get("Content-Type"))
+		var response domain.ScimListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 1, response.TotalResults)
+		assert.Equal(t, 1, response.ItemsPerPage)
+		require.Len(t, response.Resources, 1)
+	})
+
+	t.Run("unsupported filter attribute", func(t *testing.T) {
+		filter := url.QueryEscape(`emails eq "a@b.c"`)
+		w, c := newScimContext(http.MethodGet, "/scim/v2/Users?filter="+filter, "", "")
+
+		deps.service.ListUsers(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, scimTypeInvalidFilter, decodeScimError(t, w).ScimType)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		w, c := newScimContext(http.MethodGet, "/scim/v2/Users", "", "")
+
+		deps.userRepo.EXPECT().
+			ListUsers(gomock.Any(), domain.UserFilter{}, 0, domain.ScimDefaultCount).
+			Return(nil, 0, errors.New("database error"))
+
+		deps.service.ListUsers(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
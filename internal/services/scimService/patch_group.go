@@ -0,0 +1,143 @@
+package scimService
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+)
+
+// PatchGroup - PATCH /scim/v2/Groups/:id. Поддерживаются add/remove/replace участников
+// (path "members" со списком в value и remove по path `members[value eq "id"]`)
+func (s *ScimServiceImpl) PatchGroup(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.ScimPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeScimError(c, http.StatusBadRequest, scimTypeInvalidSyntax, "invalid request body")
+		return
+	}
+
+	team, err := s.getTeam(ctx, c.Param("id"))
+	if err != nil {
+		writeScimServiceError(c, err, "error getting team: ")
+		return
+	}
+
+	add, remove, err := parseGroupPatch(team, req.Operations)
+	if err != nil {
+		writeScimError(c, http.StatusBadRequest, scimTypeInvalidPath, err.Error())
+		return
+	}
+
+	if err = s.updateGroupMembers(ctx, team, add, remove); err != nil {
+		writeScimServiceError(c, err, "error updating team members: ")
+		return
+	}
+
+	team, err = s.getTeam(ctx, team.TeamName)
+	if err != nil {
+		writeScimServiceError(c, err, "error getting team: ")
+		return
+	}
+
+	writeScim(c, http.StatusOK, toScimGroup(team))
+}
+
+// parseGroupPatch сводит операции к спискам добавляемых и удаляемых участников
+func parseGroupPatch(team *domain.Team, operations []domain.ScimPatchOperation) (add, remove []string, err error) {
+	for _, op := range operations {
+		opName := strings.ToLower(op.Op)
+		path := strings.ToLower(op.Path)
+
+		// Без path value - объект с атрибутами группы (так шлёт Okta при replace)
+		if path == "" {
+			if opName != "replace" && opName != "add" {
+				return nil, nil, domain.NewError(domain.InvalidRequest, "path is required for "+op.Op)
+			}
+
+			var values map[string]json.RawMessage
+			if err = json.Unmarshal(op.Value, &values); err != nil {
+				return nil, nil, domain.NewError(domain.InvalidRequest, "value must be an object when path is omitted")
+			}
+
+			for attr, value := range values {
+				opAdd, opRemove, err := patchGroupAttribute(team, opName, strings.ToLower(attr), value)
+				if err != nil {
+					return nil, nil, err
+				}
+				add, remove = append(add, opAdd...), append(remove, opRemove...)
+			}
+			continue
+		}
+
+		if match := scimMemberPathRegexp.FindStringSubmatch(op.Path); match != nil {
+			if opName != "remove" {
+				return nil, nil, domain.NewError(domain.InvalidRequest, "filtered members path is supported only for remove")
+			}
+
+			attr, value, err := parseScimFilter(match[1])
+			if err != nil || attr != "value" {
+				return nil, nil, domain.NewError(domain.InvalidRequest, "unsupported members filter "+match[1])
+			}
+			remove = append(remove, value)
+			continue
+		}
+
+		opAdd, opRemove, err := patchGroupAttribute(team, opName, path, op.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+		add, remove = append(add, opAdd...), append(remove, opRemove...)
+	}
+
+	return add, remove, nil
+}
+
+func patchGroupAttribute(team *domain.Team, op, attr string, value json.RawMessage) (add, remove []string, err error) {
+	switch attr {
+	case "displayname":
+		var name string
+		if err = json.Unmarshal(value, &name); err != nil || name != team.TeamName {
+			return nil, nil, domain.NewError(domain.InvalidRequest, "displayName cannot be changed")
+		}
+		return nil, nil, nil
+	case "externalid":
+		return nil, nil, nil
+	case "members":
+	default:
+		return nil, nil, domain.NewError(domain.InvalidRequest, "unsupported attribute "+attr)
+	}
+
+	var refs []domain.ScimReference
+	if len(value) > 0 {
+		if err = json.Unmarshal(value, &refs); err != nil {
+			return nil, nil, domain.NewError(domain.InvalidRequest, "members must be a list of {\"value\": ...}")
+		}
+	}
+
+	switch op {
+	case "add":
+		for _, ref := range refs {
+			add = append(add, ref.Value)
+		}
+		return add, nil, nil
+	case "remove":
+		// remove без value по path "members" удаляет всех участников
+		if len(refs) == 0 {
+			_, remove = diffGroupMembers(team, nil)
+			return nil, remove, nil
+		}
+		for _, ref := range refs {
+			remove = append(remove, ref.Value)
+		}
+		return nil, remove, nil
+	case "replace":
+		add, remove = diffGroupMembers(team, refs)
+		return add, remove, nil
+	default:
+		return nil, nil, domain.NewError(domain.InvalidRequest, "unsupported operation "+op)
+	}
+}
@@ -0,0 +1,60 @@
+package scimService
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimService_PatchGroup(t *testing.T) {
+	t.Run("add and remove members", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		body := `{"Operations":[
+			{"op":"add","path":"members","value":[{"value":"u3"}]},
+			{"op":"remove","path":"members[value eq \"u2\"]"}]}`
+		w, c := newScimContext(http.MethodPatch, "/scim/v2/Groups/Backend", body, "Backend")
+
+		deps.teamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(testTeam(), nil)
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u3").Return(&domain.User{UserId: "u3"}, nil)
+		deps.teamRepo.EXPECT().
+			ApplyRosterImportPlan(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ interface{}, plan *domain.RosterImportPlan) error {
+				assert.Equal(t, []domain.RosterMembership{{TeamName: "Backend", UserID: "u3"}}, plan.AddedMemberships)
+				assert.Equal(t, []domain.RosterMembership{{TeamName: "Backend", UserID: "u2"}}, plan.RemovedMemberships)
+				return nil
+			})
+		deps.teamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(testTeam(), nil)
+
+		deps.service.PatchGroup(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("adding existing member is a no-op", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		body := `{"Operations":[{"op":"add","path":"members","value":[{"value":"u1"}]}]}`
+		w, c := newScimContext(http.MethodPatch, "/scim/v2/Groups/Backend", body, "Backend")
+
+		deps.teamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(testTeam(), nil).Times(2)
+
+		deps.service.PatchGroup(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("rename is rejected", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		body := `{"Operations":[{"op":"replace","value":{"displayName":"Platform"}}]}`
+		w, c := newScimContext(http.MethodPatch, "/scim/v2/Groups/Backend", body, "Backend")
+
+		deps.teamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(testTeam(), nil)
+
+		deps.service.PatchGroup(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,161 @@
+package scimService
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// scimUserPatch - итоговые изменения пользователя после разбора всех операций PATCH
+type scimUserPatch struct {
+	name   *string
+	active *bool
+}
+
+// PatchUser - PATCH /scim/v2/Users/:id. Поддерживаются add/replace атрибутов active, displayName
+// и name.* как с path (Azure AD), так и объектом в value без path (Okta)
+func (s *ScimServiceImpl) PatchUser(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.ScimPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeScimError(c, http.StatusBadRequest, scimTypeInvalidSyntax, "invalid request body")
+		return
+	}
+
+	patch, err := parseUserPatch(req.Operations)
+	if err != nil {
+		writeScimError(c, http.StatusBadRequest, scimTypeInvalidPath, err.Error())
+		return
+	}
+
+	user, err := s.getUser(ctx, c.Param("id"))
+	if err != nil {
+		writeScimServiceError(c, err, "error getting user: ")
+		return
+	}
+
+	if patch.name != nil && *patch.name != user.Username {
+		if err = s.userRepo.SetUserName(ctx, user.UserId, *patch.name); err != nil {
+			logger.Logger.Error("error setting user name: ", err)
+			writeScimError(c, http.StatusInternalServerError, "", "internal error")
+			return
+		}
+	}
+
+	if patch.active != nil {
+		if err = s.setUserActive(ctx, user, *patch.active); err != nil {
+			writeScimServiceError(c, err, "error setting user active: ")
+			return
+		}
+	}
+
+	user, err = s.getUser(ctx, user.UserId)
+	if err != nil {
+		writeScimServiceError(c, err, "error getting user: ")
+		return
+	}
+
+	writeScim(c, http.StatusOK, toScimUser(user))
+}
+
+func parseUserPatch(operations []domain.ScimPatchOperation) (*scimUserPatch, error) {
+	patch := &scimUserPatch{}
+
+	for _, op := range operations {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+		default:
+			return nil, domain.NewError(domain.InvalidRequest, "unsupported operation "+op.Op)
+		}
+
+		if op.Path != "" {
+			if err := patch.apply(op.Path, op.Value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &values); err != nil {
+			return nil, domain.NewError(domain.InvalidRequest, "value must be an object when path is omitted")
+		}
+		for attr, value := range values {
+			if err := patch.apply(attr, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return patch, nil
+}
+
+func (p *scimUserPatch) apply(attr string, value json.RawMessage) error {
+	switch strings.ToLower(attr) {
+	case "active":
+		active, err := parseScimBool(value)
+		if err != nil {
+			return domain.NewError(domain.InvalidRequest, "active: "+err.Error())
+		}
+		p.active = &active
+	case "displayname", "name.formatted":
+		var name string
+		if err := json.Unmarshal(value, &name); err != nil || name == "" {
+			return domain.NewError(domain.InvalidRequest, attr+": non-empty string expected")
+		}
+		p.name = &name
+	case "name":
+		var name domain.ScimName
+		if err := json.Unmarshal(value, &name); err != nil {
+			return domain.NewError(domain.InvalidRequest, "name: object expected")
+		}
+		fullName := name.Formatted
+		if fullName == "" {
+			fullName = strings.TrimSpace(name.GivenName + " " + name.FamilyName)
+		}
+		if fullName != "" {
+			p.name = &fullName
+		}
+	case "name.givenname", "name.familyname":
+		// Имя хранится одной строкой, части имени без formatted не меняем
+	default:
+		if isIgnoredScimAttribute(attr) {
+			return nil
+		}
+		return domain.NewError(domain.InvalidRequest, "unsupported attribute "+attr)
+	}
+
+	return nil
+}
+
+// Атрибуты, которые IdP шлют по умолчанию, но которые сервис не хранит - их изменения молча игнорируем
+var ignoredScimAttributes = map[string]struct{}{
+	"externalid":        {},
+	"emails":            {},
+	"phonenumbers":      {},
+	"addresses":         {},
+	"title":             {},
+	"usertype":          {},
+	"nickname":          {},
+	"profileurl":        {},
+	"preferredlanguage": {},
+	"locale":            {},
+	"timezone":          {},
+}
+
+func isIgnoredScimAttribute(attr string) bool {
+	attr = strings.ToLower(attr)
+	// Атрибуты расширений (например, enterprise User) адресуются полным URN схемы
+	if strings.HasPrefix(attr, "urn:") {
+		return true
+	}
+	if idx := strings.IndexAny(attr, "[."); idx >= 0 {
+		attr = attr[:idx]
+	}
+	_, ok := ignoredScimAttributes[attr]
+	return ok
+}
@@ -0,0 +1,68 @@
+package scimService
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimService_PatchUser(t *testing.T) {
+	t.Run("azure style string active with path", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		body := `{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[
+			{"op":"Replace","path":"active","value":"False"},
+			{"op":"Replace","path":"emails[type eq \"work\"].value","value":"alice@example.com"}]}`
+		w, c := newScimContext(http.MethodPatch, "/scim/v2/Users/u1", body, "u1")
+
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(testUser(true), nil)
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(testUser(false), nil)
+
+		deps.service.PatchUser(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"u1"}, deps.deactivator.userIDs)
+	})
+
+	t.Run("okta style value object", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		body := `{"Operations":[{"op":"replace","value":{"active":true,"displayName":"Alice Smith"}}]}`
+		w, c := newScimContext(http.MethodPatch, "/scim/v2/Users/u1", body, "u1")
+
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(testUser(false), nil)
+		deps.userRepo.EXPECT().SetUserName(gomock.Any(), "u1", "Alice Smith").Return(nil)
+		deps.userRepo.EXPECT().SetUserIsActive(gomock.Any(), "u1", true).Return(nil)
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(testUser(true), nil)
+
+		deps.service.PatchUser(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Nil(t, deps.deactivator.userIDs)
+	})
+
+	t.Run("deactivation error", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		deps.deactivator.err = errors.New("database error")
+		body := `{"Operations":[{"op":"replace","path":"active","value":false}]}`
+		w, c := newScimContext(http.MethodPatch, "/scim/v2/Users/u1", body, "u1")
+
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(testUser(true), nil)
+
+		deps.service.PatchUser(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("unsupported operation", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		body := `{"Operations":[{"op":"remove","path":"displayName"}]}`
+		w, c := newScimContext(http.MethodPatch, "/scim/v2/Users/u1", body, "u1")
+
+		deps.service.PatchUser(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,44 @@
+package scimService
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+)
+
+// ReplaceGroup - PUT /scim/v2/Groups/:id. Заменяет состав команды, переименование не поддерживается
+func (s *ScimServiceImpl) ReplaceGroup(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.ScimGroup
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeScimError(c, http.StatusBadRequest, scimTypeInvalidSyntax, "invalid request body")
+		return
+	}
+
+	team, err := s.getTeam(ctx, c.Param("id"))
+	if err != nil {
+		writeScimServiceError(c, err, "error getting team: ")
+		return
+	}
+
+	if req.DisplayName != "" && req.DisplayName != team.TeamName {
+		writeScimError(c, http.StatusBadRequest, scimTypeMutability, "displayName cannot be changed")
+		return
+	}
+
+	add, remove := diffGroupMembers(team, req.Members)
+	if err = s.updateGroupMembers(ctx, team, add, remove); err != nil {
+		writeScimServiceError(c, err, "error updating team members: ")
+		return
+	}
+
+	team, err = s.getTeam(ctx, team.TeamName)
+	if err != nil {
+		writeScimServiceError(c, err, "error getting team: ")
+		return
+	}
+
+	writeScim(c, http.StatusOK, toScimGroup(team))
+}
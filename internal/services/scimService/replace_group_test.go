@@ -0,0 +1,47 @@
+package scimService
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimService_ReplaceGroup(t *testing.T) {
+	t.Run("replace members", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		body := `{"displayName":"Backend","members":[{"value":"u1"},{"value":"u3"}]}`
+		w, c := newScimContext(http.MethodPut, "/scim/v2/Groups/Backend", body, "Backend")
+
+		deps.teamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(testTeam(), nil)
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u3").Return(&domain.User{UserId: "u3"}, nil)
+		deps.teamRepo.EXPECT().
+			ApplyRosterImportPlan(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ interface{}, plan *domain.RosterImportPlan) error {
+				assert.Equal(t, []domain.RosterMembership{{TeamName: "Backend", UserID: "u3"}}, plan.AddedMemberships)
+				assert.Equal(t, []domain.RosterMembership{{TeamName: "Backend", UserID: "u2"}}, plan.RemovedMemberships)
+				assert.Empty(t, plan.DeactivatedUsers)
+				return nil
+			})
+		deps.teamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(testTeam(), nil)
+
+		deps.service.ReplaceGroup(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("rename is rejected", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		w, c := newScimContext(http.MethodPut, "/scim/v2/Groups/Backend", `{"displayName":"Platform"}`, "Backend")
+
+		deps.teamRepo.EXPECT().GetTeamByName(gomock.Any(), "Backend").Return(testTeam(), nil)
+
+		deps.service.ReplaceGroup(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, scimTypeMutability, decodeScimError(t, w).ScimType)
+	})
+}
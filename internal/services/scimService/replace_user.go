@@ -0,0 +1,55 @@
+package scimService
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// ReplaceUser - PUT /scim/v2/Users/:id. Меняются имя и активность, userName (user_id) неизменяем
+func (s *ScimServiceImpl) ReplaceUser(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.ScimUser
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeScimError(c, http.StatusBadRequest, scimTypeInvalidSyntax, "invalid request body")
+		return
+	}
+
+	user, err := s.getUser(ctx, c.Param("id"))
+	if err != nil {
+		writeScimServiceError(c, err, "error getting user: ")
+		return
+	}
+
+	if req.UserName != "" && req.UserName != user.UserId {
+		writeScimError(c, http.StatusBadRequest, scimTypeMutability, "userName cannot be changed")
+		return
+	}
+	req.UserName = user.UserId
+
+	if name := scimUserName(&req); name != user.Username {
+		if err = s.userRepo.SetUserName(ctx, user.UserId, name); err != nil {
+			logger.Logger.Error("error setting user name: ", err)
+			writeScimError(c, http.StatusInternalServerError, "", "internal error")
+			return
+		}
+	}
+
+	if req.Active != nil {
+		if err = s.setUserActive(ctx, user, *req.Active); err != nil {
+			writeScimServiceError(c, err, "error setting user active: ")
+			return
+		}
+	}
+
+	user, err = s.getUser(ctx, user.UserId)
+	if err != nil {
+		writeScimServiceError(c, err, "error getting user: ")
+		return
+	}
+
+	writeScim(c, http.StatusOK, toScimUser(user))
+}
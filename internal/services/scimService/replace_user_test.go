@@ -0,0 +1,41 @@
+package scimService
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimService_ReplaceUser(t *testing.T) {
+	t.Run("rename and deactivate through reassignment path", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		body := `{"userName":"u1","displayName":"Alice Smith","active":false}`
+		w, c := newScimContext(http.MethodPut, "/scim/v2/Users/u1", body, "u1")
+
+		gomock.InOrder(
+			deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(testUser(true), nil),
+			deps.userRepo.EXPECT().SetUserName(gomock.Any(), "u1", "Alice Smith").Return(nil),
+			deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(testUser(false), nil),
+		)
+
+		deps.service.ReplaceUser(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"u1"}, deps.deactivator.userIDs)
+	})
+
+	t.Run("userName cannot be changed", func(t *testing.T) {
+		deps := newScimTestDeps(t)
+		w, c := newScimContext(http.MethodPut, "/scim/v2/Users/u1", `{"userName":"u2"}`, "u1")
+
+		deps.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(testUser(true), nil)
+
+		deps.service.ReplaceUser(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, scimTypeMutability, decodeScimError(t, w).ScimType)
+	})
+}
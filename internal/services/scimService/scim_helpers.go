@@ -0,0 +1,276 @@
+package scimService
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+const (
+	scimUsersPath  = "/scim/v2/Users/"
+	scimGroupsPath = "/scim/v2/Groups/"
+)
+
+// Типы ошибок SCIM (RFC 7644, 3.12)
+const (
+	scimTypeInvalidFilter = "invalidFilter"
+	scimTypeInvalidValue  = "invalidValue"
+	scimTypeInvalidPath   = "invalidPath"
+	scimTypeUniqueness    = "uniqueness"
+	scimTypeMutability    = "mutability"
+	scimTypeInvalidSyntax = "invalidSyntax"
+)
+
+// Поддерживается только то, что шлют распространённые IdP: `attr eq "value"`
+var scimFilterRegexp = regexp.MustCompile(`(?i)^\s*([a-z][a-z0-9._]*)\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+var scimMemberPathRegexp = regexp.MustCompile(`(?i)^members\[(.+)\]$`)
+
+func writeScim(c *gin.Context, status int, body interface{}) {
+	c.Header("Content-Type", domain.ScimContentType)
+	c.JSON(status, body)
+}
+
+func writeScimError(c *gin.Context, status int, scimType, detail string) {
+	writeScim(c, status, domain.ScimError{
+		Schemas:  []string{domain.ScimSchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+// writeScimServiceError отдаёт ошибки бизнес-логики как 400/404, остальные логирует и отдаёт 500
+func writeScimServiceError(c *gin.Context, err error, logMsg string) {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		status := http.StatusBadRequest
+		if domainErr.Code == domain.NotFound {
+			status = http.StatusNotFound
+		}
+		writeScimError(c, status, "", domainErr.Message)
+		return
+	}
+
+	logger.Logger.Error(logMsg, err)
+	writeScimError(c, http.StatusInternalServerError, "", "internal error")
+}
+
+// parseScimFilter разбирает фильтр вида `userName eq "alice"`, имя атрибута приводится к нижнему регистру
+func parseScimFilter(filter string) (attr, value string, err error) {
+	match := scimFilterRegexp.FindStringSubmatch(filter)
+	if match == nil {
+		return "", "", fmt.Errorf("unsupported filter %q, only `attribute eq \"value\"` is supported", filter)
+	}
+
+	value, err = strconv.Unquote(`"` + match[2] + `"`)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid filter value in %q", filter)
+	}
+
+	return strings.ToLower(match[1]), value, nil
+}
+
+// parseScimPagination возвращает startIndex (с 1) и count из query-параметров
+func parseScimPagination(c *gin.Context) (startIndex, count int) {
+	startIndex, err := strconv.Atoi(c.Query("startIndex"))
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+
+	count, err = strconv.Atoi(c.Query("count"))
+	if err != nil {
+		count = domain.ScimDefaultCount
+	}
+	if count < 0 {
+		count = 0
+	}
+	if count > domain.ScimMaxCount {
+		count = domain.ScimMaxCount
+	}
+
+	return startIndex, count
+}
+
+func newScimListResponse(resources []interface{}, total, startIndex int) domain.ScimListResponse {
+	return domain.ScimListResponse{
+		Schemas:      []string{domain.ScimSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+func toScimUser(user *domain.User) domain.ScimUser {
+	active := user.IsActive
+
+	groups := make([]domain.ScimReference, 0, len(user.TeamNames))
+	for _, teamName := range user.TeamNames {
+		groups = append(groups, domain.ScimReference{Value: teamName, Display: teamName})
+	}
+
+	return domain.ScimUser{
+		Schemas:     []string{domain.ScimSchemaUser},
+		ID:          user.UserId,
+		UserName:    user.UserId,
+		DisplayName: user.Username,
+		Name:        &domain.ScimName{Formatted: user.Username},
+		Active:      &active,
+		Groups:      groups,
+		Meta: &domain.ScimMeta{
+			ResourceType: "User",
+			Location:     scimUsersPath + user.UserId,
+		},
+	}
+}
+
+func toScimGroup(team *domain.Team) domain.ScimGroup {
+	members := make([]domain.ScimReference, 0, len(team.Members))
+	for _, member := range team.Members {
+		members = append(members, domain.ScimReference{Value: member.UserId, Display: member.Username})
+	}
+
+	return domain.ScimGroup{
+		Schemas:     []string{domain.ScimSchemaGroup},
+		ID:          team.TeamName,
+		DisplayName: team.TeamName,
+		Members:     members,
+		Meta: &domain.ScimMeta{
+			ResourceType: "Group",
+			Location:     scimGroupsPath + team.TeamName,
+		},
+	}
+}
+
+// scimUserName выбирает отображаемое имя: displayName, затем name, затем userName
+func scimUserName(user *domain.ScimUser) string {
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	if user.Name != nil {
+		if user.Name.Formatted != "" {
+			return user.Name.Formatted
+		}
+		if fullName := strings.TrimSpace(user.Name.GivenName + " " + user.Name.FamilyName); fullName != "" {
+			return fullName
+		}
+	}
+	return user.UserName
+}
+
+// parseScimBool принимает как JSON bool, так и строки "True"/"False" (так шлёт Azure AD)
+func parseScimBool(raw json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b, nil
+	}
+
+	var str string
+	if err := json.Unmarshal(raw, &str); err != nil {
+		return false, errors.New("boolean value expected")
+	}
+
+	return strconv.ParseBool(strings.ToLower(str))
+}
+
+// setUserActive меняет активность пользователя. Деактивация из IdP всегда проходит: пользователь
+// выключается глобально, его открытые ревью переназначаются, где есть кандидат (см. userService.DeactivateUser)
+func (s *ScimServiceImpl) setUserActive(ctx context.Context, user *domain.User, active bool) error {
+	if user.IsActive == active {
+		return nil
+	}
+
+	if active {
+		return s.userRepo.SetUserIsActive(ctx, user.UserId, true)
+	}
+
+	_, err := s.deactivator.DeactivateUser(ctx, user.UserId)
+	return err
+}
+
+// getUser возвращает пользователя или *domain.Error с кодом NotFound
+func (s *ScimServiceImpl) getUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewError(domain.NotFound, "user "+userID+" not found")
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// updateGroupMembers добавляет и удаляет участников команды одной транзакцией.
+// Уже состоящие (или уже отсутствующие) пользователи пропускаются
+func (s *ScimServiceImpl) updateGroupMembers(ctx context.Context, team *domain.Team, add, remove []string) error {
+	current := make(map[string]struct{}, len(team.Members))
+	for _, member := range team.Members {
+		current[member.UserId] = struct{}{}
+	}
+
+	plan := &domain.RosterImportPlan{}
+	for _, userID := range add {
+		if _, ok := current[userID]; ok {
+			continue
+		}
+		if _, err := s.getUser(ctx, userID); err != nil {
+			return err
+		}
+		current[userID] = struct{}{}
+		plan.AddedMemberships = append(plan.AddedMemberships, domain.RosterMembership{TeamName: team.TeamName, UserID: userID})
+	}
+	for _, userID := range remove {
+		if _, ok := current[userID]; !ok {
+			continue
+		}
+		delete(current, userID)
+		plan.RemovedMemberships = append(plan.RemovedMemberships, domain.RosterMembership{TeamName: team.TeamName, UserID: userID})
+	}
+
+	if plan.IsEmpty() {
+		return nil
+	}
+
+	return s.teamRepo.ApplyRosterImportPlan(ctx, plan)
+}
+
+// diffGroupMembers считает, кого добавить и кого удалить, чтобы состав стал равен desired
+func diffGroupMembers(team *domain.Team, desired []domain.ScimReference) (add, remove []string) {
+	desiredSet := make(map[string]struct{}, len(desired))
+	for _, ref := range desired {
+		desiredSet[ref.Value] = struct{}{}
+		add = append(add, ref.Value)
+	}
+
+	for _, member := range team.Members {
+		if _, ok := desiredSet[member.UserId]; !ok {
+			remove = append(remove, member.UserId)
+		}
+	}
+
+	return add, remove
+}
+
+// getTeam возвращает команду или *domain.Error с кодом NotFound
+func (s *ScimServiceImpl) getTeam(ctx context.Context, teamName string) (*domain.Team, error) {
+	team, err := s.teamRepo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, teamStorage.ErrTeamNotExists) {
+			return nil, domain.NewError(domain.NotFound, "group "+teamName+" not found")
+		}
+		return nil, err
+	}
+	return team, nil
+}
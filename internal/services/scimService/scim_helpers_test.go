@@ -0,0 +1,125 @@
+package scimService
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	logger.Logger = zap.NewNop().Sugar()
+}
+
+type fakeDeactivator struct {
+	userIDs []string
+	err     error
+}
+
+func (f *fakeDeactivator) DeactivateUser(
+	_ context.Context,
+	userID string,
+) (*domain.DeactivateTeamMembersResponse, error) {
+	f.userIDs = append(f.userIDs, userID)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &domain.DeactivateTeamMembersResponse{DeactivatedUserIDs: []string{userID}}, nil
+}
+
+type scimTestDeps struct {
+	userRepo    *mocks.MockUserRepositoryInterface
+	teamRepo    *mocks.MockTeamRepositoryInterface
+	deactivator *fakeDeactivator
+	service     *ScimServiceImpl
+}
+
+func newScimTestDeps(t *testing.T) *scimTestDeps {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	deps := &scimTestDeps{
+		userRepo:    mocks.NewMockUserRepositoryInterface(ctrl),
+		teamRepo:    mocks.NewMockTeamRepositoryInterface(ctrl),
+		deactivator: &fakeDeactivator{},
+	}
+	deps.service = NewScimService(deps.userRepo, deps.teamRepo, deps.deactivator)
+	return deps
+}
+
+func newScimContext(method, target, body, id string) (*httptest.ResponseRecorder, *gin.Context) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", domain.ScimContentType)
+	if id != "" {
+		c.Params = gin.Params{{Key: "id", Value: id}}
+	}
+	return w, c
+}
+
+func decodeScimError(t *testing.T, w *httptest.ResponseRecorder) domain.ScimError {
+	var scimErr domain.ScimError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &scimErr))
+	return scimErr
+}
+
+func testUser(active bool) *domain.User {
+	return &domain.User{
+		UserId:    "u1",
+		Username:  "Alice",
+		TeamName:  "Backend",
+		TeamNames: []string{"Backend"},
+		IsActive:  active,
+	}
+}
+
+func testTeam() *domain.Team {
+	return &domain.Team{
+		TeamName: "Backend",
+		Members: []domain.TeamMember{
+			{UserId: "u1", Username: "Alice", IsActive: true},
+			{UserId: "u2", Username: "Bob", IsActive: true},
+		},
+	}
+}
+
+func TestParseScimFilter(t *testing.T) {
+	attr, value, err := parseScimFilter(`userName eq "alice@example.com"`)
+	require.NoError(t, err)
+	assert.Equal(t, "username", attr)
+	assert.Equal(t, "alice@example.com", value)
+
+	attr, value, err = parseScimFilter(`displayName EQ "Team \"A\""`)
+	require.NoError(t, err)
+	assert.Equal(t, "displayname", attr)
+	assert.Equal(t, `Team "A"`, value)
+
+	_, _, err = parseScimFilter(`userName sw "al"`)
+	assert.Error(t, err)
+
+	_, _, err = parseScimFilter(`userName eq "a" and active eq true`)
+	assert.Error(t, err)
+}
+
+func TestParseScimBool(t *testing.T) {
+	for raw, want := range map[string]bool{`true`: true, `false`: false, `"True"`: true, `"False"`: false} {
+		got, err := parseScimBool(json.RawMessage(raw))
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, got, raw)
+	}
+
+	_, err := parseScimBool(json.RawMessage(`"maybe"`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,31 @@
+package scimService
+
+import (
+	"context"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage"
+)
+
+// MemberDeactivator - деактивация пользователя с переназначением его ревью (реализована в userService)
+type MemberDeactivator interface {
+	DeactivateUser(ctx context.Context, userID string) (*domain.DeactivateTeamMembersResponse, error)
+}
+
+type ScimServiceImpl struct {
+	userRepo    storage.UserRepositoryInterface
+	teamRepo    storage.TeamRepositoryInterface
+	deactivator MemberDeactivator
+}
+
+func NewScimService(
+	userRepo storage.UserRepositoryInterface,
+	teamRepo storage.TeamRepositoryInterface,
+	deactivator MemberDeactivator,
+) *ScimServiceImpl {
+	return &ScimServiceImpl{
+		userRepo:    userRepo,
+		teamRepo:    teamRepo,
+		deactivator: deactivator,
+	}
+}
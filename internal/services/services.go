@@ -1,22 +1,105 @@
 package services
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
 )
 
+// TeamService, UserService и PullRequestService не зависят от транспорта: принимают типизированные
+// запросы и возвращают результат или ошибку (*domain.Error - ошибка запроса, остальное - внутренняя).
+// HTTP-обработчики для них живут в internal/api, их же используют gRPC API, SCIM и вебхуки VCS
 type TeamService interface {
-	CreateTeam(c *gin.Context)
-	GetTeam(c *gin.Context)
+	CreateTeam(ctx context.Context, team domain.Team) (*domain.Team, error)
+	GetTeam(ctx context.Context, teamName string) (*domain.Team, error)
+	GetTeamTree(ctx context.Context, teamName string) ([]domain.TeamTreeNode, error)
+	SetTeamParent(ctx context.Context, req domain.SetTeamParentRequest) (*domain.Team, error)
+	SetReviewSLA(ctx context.Context, req domain.SetReviewSLARequest) error
+	AddTeamMember(ctx context.Context, req domain.TeamMembershipRequest) (*domain.Team, error)
+	RemoveTeamMember(ctx context.Context, req domain.TeamMembershipRequest) (*domain.Team, error)
 }
 
 type UserService interface {
-	SetIsActive(c *gin.Context)
-	GetUserReviews(c *gin.Context)
-	DeactivateTeamMembers(c *gin.Context)
+	SetIsActive(ctx context.Context, req domain.SetIsActiveRequest) (*domain.User, error)
+	SetPrimaryTeam(ctx context.Context, req domain.SetPrimaryTeamRequest) (*domain.User, error)
+	GetUserReviews(ctx context.Context, userID string) ([]domain.PullRequestShort, error)
+	StreamReviews(ctx context.Context, userID string, lastEventID *int64, sink domain.ReviewStreamSink) error
+	DeactivateTeamMembers(
+		ctx context.Context,
+		req domain.DeactivateTeamMembersRequest,
+	) (*domain.DeactivateTeamMembersResponse, error)
+	PreviewDeactivateTeamMembers(ctx context.Context, req domain.DeactivateTeamMembersRequest) (*domain.DeactivationPlan, error)
+	ApplyDeactivationPlan(
+		ctx context.Context,
+		req domain.ApplyDeactivationPlanRequest,
+	) (*domain.DeactivateTeamMembersResponse, error)
+	ActivateTeamMembers(
+		ctx context.Context,
+		req domain.ActivateTeamMembersRequest,
+	) (*domain.ActivateTeamMembersResponse, error)
 }
 
 type PullRequestService interface {
-	CreatePullRequest(c *gin.Context)
-	MergePullRequest(c *gin.Context)
-	ReassignReviewer(c *gin.Context)
+	CreatePullRequest(ctx context.Context, req domain.CreatePullRequestRequest) (*domain.PullRequestResponse, error)
+	MergePullRequest(ctx context.Context, prID string) (*domain.PullRequestResponse, error)
+	ReassignReviewer(ctx context.Context, req domain.ReassignReviewerRequest) (*domain.ReassignReviewerResponse, error)
+	PreviewAssignment(ctx context.Context, req domain.PreviewAssignmentRequest) (*domain.AssignmentPreview, error)
+}
+
+type AdminService interface {
+	ImportRoster(c *gin.Context)
+	ExportRoster(c *gin.Context)
+}
+
+type ScimService interface {
+	ListUsers(c *gin.Context)
+	GetUser(c *gin.Context)
+	CreateUser(c *gin.Context)
+	ReplaceUser(c *gin.Context)
+	PatchUser(c *gin.Context)
+	DeleteUser(c *gin.Context)
+	ListGroups(c *gin.Context)
+	GetGroup(c *gin.Context)
+	CreateGroup(c *gin.Context)
+	ReplaceGroup(c *gin.Context)
+	PatchGroup(c *gin.Context)
+	DeleteGroup(c *gin.Context)
+	GetServiceProviderConfig(c *gin.Context)
+}
+
+type StatsService interface {
+	GetUserStats(c *gin.Context)
+	GetTeamStats(c *gin.Context)
+	GetTeamDigest(c *gin.Context)
+}
+
+type ExportService interface {
+	ExportPullRequests(c *gin.Context)
+	ExportReviews(c *gin.Context)
+}
+
+type WebhookService interface {
+	HandleGitHubWebhook(c *gin.Context)
+	HandleGitLabWebhook(c *gin.Context)
+	SetUserMapping(c *gin.Context)
+	ListUserMappings(c *gin.Context)
+}
+
+type SubscriptionService interface {
+	CreateSubscription(c *gin.Context)
+	ListSubscriptions(c *gin.Context)
+	DeleteSubscription(c *gin.Context)
+	ListDeliveries(c *gin.Context)
+	RetryDelivery(c *gin.Context)
+}
+
+type NotificationService interface {
+	SetTeamChannel(c *gin.Context)
+	ListTeamChannels(c *gin.Context)
+	DeleteTeamChannel(c *gin.Context)
+	SetUserMention(c *gin.Context)
+	ListUserMentions(c *gin.Context)
+	GetPreferences(c *gin.Context)
+	SetPreferences(c *gin.Context)
 }
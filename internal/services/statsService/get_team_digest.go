@@ -0,0 +1,72 @@
+package statsService
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+const (
+	digestFormatJSON     = "json"
+	digestFormatMarkdown = "markdown"
+)
+
+// GetTeamDigest - недельная сводка по PR команды: format=json (по умолчанию) или format=markdown
+func (s *StatsServiceImpl) GetTeamDigest(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filter, err := parseStatsFilter(c, domain.DefaultDigestPeriod)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			err.Error(),
+		))
+		return
+	}
+
+	if filter.TeamName == nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"team_name is required",
+		))
+		return
+	}
+
+	format := c.DefaultQuery("format", digestFormatJSON)
+	if format != digestFormatJSON && format != digestFormatMarkdown {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid format: expected json or markdown",
+		))
+		return
+	}
+
+	digest, err := s.statsRepo.GetTeamDigest(ctx, *filter.TeamName, filter.From, filter.To)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
+				domain.NotFound,
+				"team not found",
+			))
+			return
+		}
+		logger.Logger.Error("error building team digest: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrGetDigestMsg,
+		))
+		return
+	}
+
+	if format == digestFormatMarkdown {
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(utils.RenderTeamDigestMarkdown(digest)))
+		return
+	}
+
+	c.JSON(http.StatusOK, digest)
+}
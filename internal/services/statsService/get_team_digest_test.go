@@ -0,0 +1,96 @@
+package statsService
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsService_GetTeamDigest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStatsRepo := mocks.NewMockStatsRepositoryInterface(ctrl)
+	service := NewStatsService(mockStatsRepo)
+
+	to := time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC)
+
+	t.Run("json digest for the last week by default", func(t *testing.T) {
+		c, w := newStatsContext("/stats/digest?team_name=Backend&to=2025-11-24")
+
+		mockStatsRepo.EXPECT().
+			GetTeamDigest(gomock.Any(), "Backend", to.Add(-domain.DefaultDigestPeriod), to).
+			Return(&domain.TeamDigest{TeamName: "Backend", Opened: 3, Merged: 1}, nil)
+
+		service.GetTeamDigest(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response domain.TeamDigest
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 3, response.Opened)
+		assert.Equal(t, 1, response.Merged)
+	})
+
+	t.Run("markdown digest", func(t *testing.T) {
+		c, w := newStatsContext("/stats/digest?team_name=Backend&format=markdown")
+
+		mockStatsRepo.EXPECT().
+			GetTeamDigest(gomock.Any(), "Backend", gomock.Any(), gomock.Any()).
+			Return(&domain.TeamDigest{TeamName: "Backend"}, nil)
+
+		service.GetTeamDigest(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/markdown")
+		assert.Contains(t, w.Body.String(), "# Review digest: Backend")
+	})
+
+	t.Run("team_name is required", func(t *testing.T) {
+		c, w := newStatsContext("/stats/digest")
+
+		service.GetTeamDigest(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		c, w := newStatsContext("/stats/digest?team_name=Backend&format=pdf")
+
+		service.GetTeamDigest(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		c, w := newStatsContext("/stats/digest?team_name=Unknown")
+
+		mockStatsRepo.EXPECT().
+			GetTeamDigest(gomock.Any(), "Unknown", gomock.Any(), gomock.Any()).
+			Return(nil, pgx.ErrNoRows)
+
+		service.GetTeamDigest(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		c, w := newStatsContext("/stats/digest?team_name=Backend")
+
+		mockStatsRepo.EXPECT().
+			GetTeamDigest(gomock.Any(), "Backend", gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("db error"))
+
+		service.GetTeamDigest(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
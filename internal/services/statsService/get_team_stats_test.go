@@ -0,0 +1,62 @@
+package statsService
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsService_GetTeamStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStatsRepo := mocks.NewMockStatsRepositoryInterface(ctrl)
+	service := NewStatsService(mockStatsRepo)
+
+	t.Run("successfully get team stats", func(t *testing.T) {
+		c, w := newStatsContext("/stats/teams?from=2025-11-01")
+
+		mockStatsRepo.EXPECT().
+			GetTeamReviewStats(gomock.Any(), gomock.Any()).
+			Return([]domain.TeamReviewStats{
+				{TeamName: "Backend", ReviewStats: domain.ReviewStats{Assigned: 5, Open: 2, Completed: 2, ReassignedAway: 1}},
+				{TeamName: "Frontend", ReviewStats: domain.ReviewStats{}},
+			}, nil)
+
+		service.GetTeamStats(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response domain.TeamStatsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Teams, 2)
+		assert.Equal(t, 1, response.Teams[0].ReassignedAway)
+		assert.Nil(t, response.Teams[1].MedianHoursToMerge)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		c, w := newStatsContext("/stats/teams?to=not-a-date")
+
+		service.GetTeamStats(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		c, w := newStatsContext("/stats/teams")
+
+		mockStatsRepo.EXPECT().
+			GetTeamReviewStats(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("db error"))
+
+		service.GetTeamStats(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,38 @@
+package statsService
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+func (s *StatsServiceImpl) GetUserStats(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filter, err := parseStatsFilter(c, domain.DefaultStatsPeriod)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			err.Error(),
+		))
+		return
+	}
+
+	stats, err := s.statsRepo.GetUserReviewStats(ctx, filter)
+	if err != nil {
+		logger.Logger.Error("error getting user review stats: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrGetStatsMsg,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.UserStatsResponse{
+		From:  filter.From,
+		To:    filter.To,
+		Users: stats,
+	})
+}
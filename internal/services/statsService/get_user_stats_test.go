@@ -0,0 +1,116 @@
+package statsService
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	logger.Logger = zap.NewNop().Sugar()
+}
+
+func newStatsContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return c, w
+}
+
+func TestStatsService_GetUserStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStatsRepo := mocks.NewMockStatsRepositoryInterface(ctrl)
+	service := NewStatsService(mockStatsRepo)
+
+	t.Run("successfully get stats for explicit range", func(t *testing.T) {
+		c, w := newStatsContext("/stats/users?from=2025-11-01&to=2025-11-08T00:00:00Z&team_name=Backend")
+
+		median := 3.0
+		mockStatsRepo.EXPECT().
+			GetUserReviewStats(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ interface{}, filter domain.StatsFilter) ([]domain.UserReviewStats, error) {
+				assert.Equal(t, time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), filter.From)
+				assert.Equal(t, time.Date(2025, 11, 8, 0, 0, 0, 0, time.UTC), filter.To)
+				require.NotNil(t, filter.TeamName)
+				assert.Equal(t, "Backend", *filter.TeamName)
+				return []domain.UserReviewStats{{
+					UserID:   "u1",
+					Username: "Alice",
+					ReviewStats: domain.ReviewStats{
+						Assigned:           3,
+						Open:               1,
+						Completed:          2,
+						MedianHoursToMerge: &median,
+					},
+				}}, nil
+			})
+
+		service.GetUserStats(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response domain.UserStatsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Users, 1)
+		assert.Equal(t, "u1", response.Users[0].UserID)
+		assert.Equal(t, 2, response.Users[0].Completed)
+	})
+
+	t.Run("default range is last period", func(t *testing.T) {
+		c, w := newStatsContext("/stats/users")
+
+		mockStatsRepo.EXPECT().
+			GetUserReviewStats(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ interface{}, filter domain.StatsFilter) ([]domain.UserReviewStats, error) {
+				assert.Equal(t, domain.DefaultStatsPeriod, filter.To.Sub(filter.From))
+				assert.Nil(t, filter.TeamName)
+				return []domain.UserReviewStats{}, nil
+			})
+
+		service.GetUserStats(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		c, w := newStatsContext("/stats/users?from=2025-11-08&to=2025-11-01")
+
+		service.GetUserStats(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid date format", func(t *testing.T) {
+		c, w := newStatsContext("/stats/users?from=yesterday")
+
+		service.GetUserStats(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		c, w := newStatsContext("/stats/users")
+
+		mockStatsRepo.EXPECT().
+			GetUserReviewStats(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("db error"))
+
+		service.GetUserStats(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
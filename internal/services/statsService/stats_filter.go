@@ -0,0 +1,43 @@
+package statsService
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils"
+)
+
+// parseStatsFilter читает from/to (RFC3339 или YYYY-MM-DD) и team_name из query.
+// По умолчанию to - текущий момент, from - to минус defaultPeriod
+func parseStatsFilter(c *gin.Context, defaultPeriod time.Duration) (domain.StatsFilter, error) {
+	filter := domain.StatsFilter{To: time.Now()}
+
+	if raw := c.Query("to"); raw != "" {
+		to, err := utils.ParseTimeParam(raw)
+		if err != nil {
+			return filter, errors.New("invalid to: expected RFC3339 or YYYY-MM-DD")
+		}
+		filter.To = to
+	}
+
+	filter.From = filter.To.Add(-defaultPeriod)
+	if raw := c.Query("from"); raw != "" {
+		from, err := utils.ParseTimeParam(raw)
+		if err != nil {
+			return filter, errors.New("invalid from: expected RFC3339 or YYYY-MM-DD")
+		}
+		filter.From = from
+	}
+
+	if !filter.From.Before(filter.To) {
+		return filter, errors.New("from must be before to")
+	}
+
+	if teamName := c.Query("team_name"); teamName != "" {
+		filter.TeamName = &teamName
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,17 @@
+package statsService
+
+import (
+	"github.com/nedokyrill/avito-pr-api/internal/storage"
+)
+
+type StatsServiceImpl struct {
+	statsRepo storage.StatsRepositoryInterface
+}
+
+func NewStatsService(
+	statsRepo storage.StatsRepositoryInterface,
+) *StatsServiceImpl {
+	return &StatsServiceImpl{
+		statsRepo: statsRepo,
+	}
+}
@@ -0,0 +1,78 @@
+package subscriptionService
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+func (s *SubscriptionServiceImpl) CreateSubscription(c *gin.Context) {
+	var req domain.CreateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid request body",
+		))
+		return
+	}
+
+	if !isValidTargetURL(req.URL) {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"url must be an absolute http or https URL",
+		))
+		return
+	}
+
+	eventTypes := make([]domain.EventType, 0, len(req.EventTypes))
+	seen := make(map[domain.EventType]struct{}, len(req.EventTypes))
+	for _, eventType := range req.EventTypes {
+		if !eventType.IsValid() {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+				domain.InvalidRequest,
+				"unknown event type "+string(eventType),
+			))
+			return
+		}
+		if _, ok := seen[eventType]; ok {
+			continue
+		}
+		seen[eventType] = struct{}{}
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	subscription := &domain.Subscription{
+		ID:         uuid.NewString(),
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: eventTypes,
+		IsActive:   true,
+	}
+
+	if err := s.subscriptionRepo.CreateSubscription(c.Request.Context(), subscription); err != nil {
+		logger.Logger.Error("error creating subscription: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrSubscriptionMsg,
+		))
+		return
+	}
+
+	logger.Logger.Infow("subscription created",
+		"subscription_id", subscription.ID,
+		"event_types", subscription.EventTypes,
+	)
+	c.JSON(http.StatusCreated, gin.H{"subscription": subscription})
+}
+
+func isValidTargetURL(raw string) bool {
+	target, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (target.Scheme == "http" || target.Scheme == "https") && target.Host != ""
+}
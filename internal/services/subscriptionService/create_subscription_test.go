@@ -0,0 +1,101 @@
+package subscriptionService
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newJSONContext(method, path, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, path, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c, w
+}
+
+func TestSubscriptionService_CreateSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockSubscriptionRepositoryInterface(ctrl)
+	service := NewSubscriptionService(mockRepo)
+
+	t.Run("successfully create subscription", func(t *testing.T) {
+		mockRepo.EXPECT().CreateSubscription(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, subscription *domain.Subscription) error {
+				assert.NotEmpty(t, subscription.ID)
+				assert.Equal(t, "s3cr3t", subscription.Secret)
+				assert.True(t, subscription.IsActive)
+				// повторы типов схлопываются
+				assert.Equal(t, []domain.EventType{domain.EventPRCreated, domain.EventPRMerged}, subscription.EventTypes)
+				return nil
+			})
+
+		c, w := newJSONContext(http.MethodPost, "/subscriptions/create", `{
+			"url": "https://hooks.example.com/pr",
+			"secret": "s3cr3t",
+			"event_types": ["pr.created", "pr.merged", "pr.created"]
+		}`)
+
+		service.CreateSubscription(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.NotContains(t, w.Body.String(), "s3cr3t")
+
+		var response struct {
+			Subscription domain.Subscription `json:"subscription"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "https://hooks.example.com/pr", response.Subscription.URL)
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		c, w := newJSONContext(http.MethodPost, "/subscriptions/create",
+			`{"url": "ftp://hooks.example.com", "secret": "s", "event_types": ["pr.created"]}`)
+
+		service.CreateSubscription(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unknown event type", func(t *testing.T) {
+		c, w := newJSONContext(http.MethodPost, "/subscriptions/create",
+			`{"url": "https://hooks.example.com", "secret": "s", "event_types": ["pr.reviewed"]}`)
+
+		service.CreateSubscription(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("no event types", func(t *testing.T) {
+		c, w := newJSONContext(http.MethodPost, "/subscriptions/create",
+			`{"url": "https://hooks.example.com", "secret": "s", "event_types": []}`)
+
+		service.CreateSubscription(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		mockRepo.EXPECT().CreateSubscription(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+
+		c, w := newJSONContext(http.MethodPost, "/subscriptions/create",
+			`{"url": "https://hooks.example.com", "secret": "s", "event_types": ["pr.merged"]}`)
+
+		service.CreateSubscription(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
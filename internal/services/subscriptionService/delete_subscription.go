@@ -0,0 +1,42 @@
+package subscriptionService
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// DeleteSubscription удаляет подписку; недоставленные события ей больше не отправляются
+func (s *SubscriptionServiceImpl) DeleteSubscription(c *gin.Context) {
+	var req domain.DeleteSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid request body",
+		))
+		return
+	}
+
+	if err := s.subscriptionRepo.DeleteSubscription(c.Request.Context(), req.SubscriptionID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
+				domain.NotFound,
+				"subscription not found",
+			))
+			return
+		}
+		logger.Logger.Error("error deleting subscription: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrSubscriptionMsg,
+		))
+		return
+	}
+
+	logger.Logger.Infow("subscription deleted", "subscription_id", req.SubscriptionID)
+	c.JSON(http.StatusOK, gin.H{"subscription_id": req.SubscriptionID})
+}
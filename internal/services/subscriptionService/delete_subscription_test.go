@@ -0,0 +1,58 @@
+package subscriptionService
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionService_DeleteSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockSubscriptionRepositoryInterface(ctrl)
+	service := NewSubscriptionService(mockRepo)
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.EXPECT().DeleteSubscription(gomock.Any(), "sub-1").Return(nil)
+
+		c, w := newJSONContext(http.MethodPost, "/subscriptions/delete", `{"subscription_id": "sub-1"}`)
+
+		service.DeleteSubscription(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("subscription not found", func(t *testing.T) {
+		mockRepo.EXPECT().DeleteSubscription(gomock.Any(), "sub-1").Return(pgx.ErrNoRows)
+
+		c, w := newJSONContext(http.MethodPost, "/subscriptions/delete", `{"subscription_id": "sub-1"}`)
+
+		service.DeleteSubscription(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid request body", func(t *testing.T) {
+		c, w := newJSONContext(http.MethodPost, "/subscriptions/delete", `{}`)
+
+		service.DeleteSubscription(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		mockRepo.EXPECT().DeleteSubscription(gomock.Any(), "sub-1").Return(errors.New("db error"))
+
+		c, w := newJSONContext(http.MethodPost, "/subscriptions/delete", `{"subscription_id": "sub-1"}`)
+
+		service.DeleteSubscription(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,183 @@
+package subscriptionService
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature-256"
+	signaturePrefix = "sha256="
+
+	// maxErrorBodyBytes - сколько байт ответа подписчика сохраняется в last_error
+	maxErrorBodyBytes = 512
+)
+
+// DispatcherConfig - параметры отправки. Попытка N (с единицы) при неуспехе повторяется через
+// BaseBackoff * 2^(N-1), но не позже MaxBackoff; после MaxAttempts доставка переходит в DEAD
+type DispatcherConfig struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	RequestTimeout time.Duration
+	MaxAttempts    int
+	BaseBackoff    time.Duration
+	MaxBackoff     time.Duration
+}
+
+func DefaultDispatcherConfig() DispatcherConfig {
+	return DispatcherConfig{
+		PollInterval:   time.Second,
+		BatchSize:      50,
+		RequestTimeout: 10 * time.Second,
+		MaxAttempts:    8,
+		BaseBackoff:    10 * time.Second,
+		MaxBackoff:     time.Hour,
+	}
+}
+
+// Dispatcher разбирает очередь subscription_deliveries и отправляет подписанные доставки.
+// Несколько экземпляров сервиса могут работать одновременно: доставки забираются с SKIP LOCKED
+type Dispatcher struct {
+	subscriptionRepo storage.SubscriptionRepositoryInterface
+	client           *http.Client
+	config           DispatcherConfig
+	now              func() time.Time
+}
+
+func NewDispatcher(
+	subscriptionRepo storage.SubscriptionRepositoryInterface,
+	client *http.Client,
+	config DispatcherConfig,
+) *Dispatcher {
+	return &Dispatcher{
+		subscriptionRepo: subscriptionRepo,
+		client:           client,
+		config:           config,
+		now:              time.Now,
+	}
+}
+
+// Run доставляет события из очереди подписчикам до отмены ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+	utils.PollBatches(ctx, "subscription dispatcher", d.config.PollInterval, d.config.BatchSize, d.DispatchDue)
+}
+
+// DispatchDue забирает одну пачку доставок и отправляет их параллельно. Возвращает размер пачки
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	// Аренда покрывает попытку с запасом: если процесс упадёт, доставка вернётся в очередь после неё
+	lease := 2 * d.config.RequestTimeout
+
+	deliveries, err := d.subscriptionRepo.ClaimDueDeliveries(ctx, d.config.BatchSize, lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery domain.DueDelivery) {
+			defer wg.Done()
+			d.dispatch(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, delivery domain.DueDelivery) {
+	attempt := d.send(ctx, delivery)
+
+	attemptNumber := delivery.Attempts + 1
+	if !attempt.Delivered && attemptNumber < d.config.MaxAttempts {
+		next := d.now().Add(utils.ExponentialBackoff(d.config.BaseBackoff, d.config.MaxBackoff, attemptNumber))
+		attempt.NextAttemptAt = &next
+	}
+
+	if err := d.subscriptionRepo.RecordAttempt(ctx, attempt); err != nil {
+		// Доставка вернётся в очередь по истечении аренды
+		logger.Logger.Error("error recording delivery attempt: ", err)
+		return
+	}
+
+	switch {
+	case attempt.Delivered:
+		logger.Logger.Debugw("delivery sent", "delivery_id", delivery.ID, "event_type", delivery.EventType)
+	case attempt.NextAttemptAt == nil:
+		logger.Logger.Warnw("delivery moved to dead letter",
+			"delivery_id", delivery.ID,
+			"subscription_id", delivery.SubscriptionID,
+			"attempts", attemptNumber,
+			"error", attempt.Error,
+		)
+	default:
+		logger.Logger.Infow("delivery failed, retry scheduled",
+			"delivery_id", delivery.ID,
+			"subscription_id", delivery.SubscriptionID,
+			"attempt", attemptNumber,
+			"next_attempt_at", attempt.NextAttemptAt,
+			"error", attempt.Error,
+		)
+	}
+}
+
+// send выполняет одну попытку; успехом считается любой 2xx
+func (d *Dispatcher) send(ctx context.Context, delivery domain.DueDelivery) domain.DeliveryAttempt {
+	attempt := domain.DeliveryAttempt{DeliveryID: delivery.ID}
+
+	ctx, cancel := context.WithTimeout(ctx, d.config.RequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", string(delivery.EventType))
+	req.Header.Set("X-Webhook-Event-Id", delivery.EventID)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	statusCode := resp.StatusCode
+	attempt.StatusCode = &statusCode
+
+	if statusCode >= 200 && statusCode < 300 {
+		attempt.Delivered = true
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return attempt
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	attempt.Error = fmt.Sprintf("HTTP %d: %s", statusCode, bytes.TrimSpace(body))
+	return attempt
+}
+
+// Sign - подпись доставки: "sha256=" + hex(HMAC-SHA256(secret, body)). Получатель проверяет
+// заголовок X-Webhook-Signature-256 так же, как подпись вебхуков GitHub
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,242 @@
+package subscriptionService
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	logger.Logger = zap.NewNop().Sugar()
+}
+
+// testReceiver - httptest-получатель, который проверяет подпись и отвечает заданными статусами по очереди
+type testReceiver struct {
+	t        *testing.T
+	secret   string
+	statuses []int
+
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *testReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	require.NoError(r.t, err)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+
+	if req.Header.Get(SignatureHeader) != Sign(r.secret, body) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status = r.statuses[0]
+		r.statuses = r.statuses[1:]
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte("receiver says hi"))
+}
+
+func newTestDispatcher(repo *mocks.MockSubscriptionRepositoryInterface, now time.Time) *Dispatcher {
+	dispatcher := NewDispatcher(repo, http.DefaultClient, DispatcherConfig{
+		PollInterval:   10 * time.Millisecond,
+		BatchSize:      10,
+		RequestTimeout: time.Second,
+		MaxAttempts:    3,
+		BaseBackoff:    10 * time.Second,
+		MaxBackoff:     15 * time.Second,
+	})
+	dispatcher.now = func() time.Time { return now }
+	return dispatcher
+}
+
+func TestDispatcher_DispatchDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 11, 28, 10, 0, 0, 0, time.UTC)
+	payload := []byte(`{"id":"event-1","type":"pr.created","data":{"pull_request_id":"pr-1"}}`)
+
+	newDelivery := func(url string, attempts int) domain.DueDelivery {
+		return domain.DueDelivery{
+			ID:             7,
+			SubscriptionID: "sub-1",
+			URL:            url,
+			Secret:         "s3cr3t",
+			EventID:        "event-1",
+			EventType:      domain.EventPRCreated,
+			Payload:        payload,
+			Attempts:       attempts,
+		}
+	}
+
+	t.Run("signed delivery is marked delivered", func(t *testing.T) {
+		receiver := &testReceiver{t: t, secret: "s3cr3t"}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		repo := mocks.NewMockSubscriptionRepositoryInterface(ctrl)
+		repo.EXPECT().ClaimDueDeliveries(gomock.Any(), 10, 2*time.Second).
+			Return([]domain.DueDelivery{newDelivery(server.URL, 0)}, nil)
+		repo.EXPECT().RecordAttempt(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, attempt domain.DeliveryAttempt) error {
+				assert.True(t, attempt.Delivered)
+				require.NotNil(t, attempt.StatusCode)
+				assert.Equal(t, http.StatusOK, *attempt.StatusCode)
+				return nil
+			})
+
+		claimed, err := newTestDispatcher(repo, now).DispatchDue(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, claimed)
+		require.Len(t, receiver.requests, 1)
+		assert.Equal(t, payload, receiver.bodies[0])
+		assert.Equal(t, "pr.created", receiver.requests[0].Header.Get("X-Webhook-Event"))
+		assert.Equal(t, "event-1", receiver.requests[0].Header.Get("X-Webhook-Event-Id"))
+		assert.Equal(t, "7", receiver.requests[0].Header.Get("X-Webhook-Delivery"))
+	})
+
+	t.Run("failure schedules retry with exponential backoff", func(t *testing.T) {
+		receiver := &testReceiver{t: t, secret: "s3cr3t", statuses: []int{http.StatusServiceUnavailable}}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		repo := mocks.NewMockSubscriptionRepositoryInterface(ctrl)
+		repo.EXPECT().ClaimDueDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]domain.DueDelivery{newDelivery(server.URL, 1)}, nil)
+		repo.EXPECT().RecordAttempt(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, attempt domain.DeliveryAttempt) error {
+				assert.False(t, attempt.Delivered)
+				assert.Equal(t, http.StatusServiceUnavailable, *attempt.StatusCode)
+				assert.Equal(t, "HTTP 503: receiver says hi", attempt.Error)
+				// вторая попытка: 10s * 2^1 = 20s, ограничено MaxBackoff
+				require.NotNil(t, attempt.NextAttemptAt)
+				assert.Equal(t, now.Add(15*time.Second), *attempt.NextAttemptAt)
+				return nil
+			})
+
+		_, err := newTestDispatcher(repo, now).DispatchDue(context.Background())
+
+		require.NoError(t, err)
+	})
+
+	t.Run("last failed attempt moves delivery to dead letter", func(t *testing.T) {
+		receiver := &testReceiver{t: t, secret: "s3cr3t", statuses: []int{http.StatusInternalServerError}}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		repo := mocks.NewMockSubscriptionRepositoryInterface(ctrl)
+		repo.EXPECT().ClaimDueDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]domain.DueDelivery{newDelivery(server.URL, 2)}, nil)
+		repo.EXPECT().RecordAttempt(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, attempt domain.DeliveryAttempt) error {
+				assert.False(t, attempt.Delivered)
+				assert.Nil(t, attempt.NextAttemptAt)
+				return nil
+			})
+
+		_, err := newTestDispatcher(repo, now).DispatchDue(context.Background())
+
+		require.NoError(t, err)
+	})
+
+	t.Run("wrong secret is rejected by receiver", func(t *testing.T) {
+		receiver := &testReceiver{t: t, secret: "other"}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		repo := mocks.NewMockSubscriptionRepositoryInterface(ctrl)
+		repo.EXPECT().ClaimDueDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]domain.DueDelivery{newDelivery(server.URL, 0)}, nil)
+		repo.EXPECT().RecordAttempt(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, attempt domain.DeliveryAttempt) error {
+				assert.Equal(t, http.StatusUnauthorized, *attempt.StatusCode)
+				assert.Equal(t, now.Add(10*time.Second), *attempt.NextAttemptAt)
+				return nil
+			})
+
+		_, err := newTestDispatcher(repo, now).DispatchDue(context.Background())
+
+		require.NoError(t, err)
+	})
+
+	t.Run("unreachable receiver is retried", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		url := server.URL
+		server.Close()
+
+		repo := mocks.NewMockSubscriptionRepositoryInterface(ctrl)
+		repo.EXPECT().ClaimDueDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]domain.DueDelivery{newDelivery(url, 0)}, nil)
+		repo.EXPECT().RecordAttempt(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, attempt domain.DeliveryAttempt) error {
+				assert.Nil(t, attempt.StatusCode)
+				assert.NotEmpty(t, attempt.Error)
+				assert.NotNil(t, attempt.NextAttemptAt)
+				return nil
+			})
+
+		_, err := newTestDispatcher(repo, now).DispatchDue(context.Background())
+
+		require.NoError(t, err)
+	})
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	receiver := &testReceiver{t: t, secret: "s3cr3t"}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	repo := mocks.NewMockSubscriptionRepositoryInterface(ctrl)
+	repo.EXPECT().ClaimDueDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]domain.DueDelivery{{ID: 1, URL: server.URL, Secret: "s3cr3t", Payload: []byte(`{}`)}}, nil)
+	repo.EXPECT().RecordAttempt(gomock.Any(), gomock.Any()).Return(nil)
+	// Дальше очередь пуста; после второго опроса диспетчер останавливается
+	repo.EXPECT().ClaimDueDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, int, time.Duration) ([]domain.DueDelivery, error) {
+			cancel()
+			return nil, nil
+		})
+	repo.EXPECT().ClaimDueDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	done := make(chan struct{})
+	go func() {
+		newTestDispatcher(repo, time.Now()).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("dispatcher did not stop")
+	}
+	assert.Len(t, receiver.requests, 1)
+}
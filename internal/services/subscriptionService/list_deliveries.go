@@ -0,0 +1,60 @@
+package subscriptionService
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+const (
+	defaultDeliveryLogLimit = 50
+	maxDeliveryLogLimit     = 500
+)
+
+// ListDeliveries - журнал доставок: ?subscription_id=, ?status=PENDING|DELIVERED|DEAD, ?limit= (по умолчанию 50)
+func (s *SubscriptionServiceImpl) ListDeliveries(c *gin.Context) {
+	filter := domain.DeliveryLogFilter{Limit: defaultDeliveryLogLimit}
+
+	if subscriptionID := c.Query("subscription_id"); subscriptionID != "" {
+		filter.SubscriptionID = &subscriptionID
+	}
+
+	if raw := c.Query("status"); raw != "" {
+		status := domain.DeliveryStatus(raw)
+		if !status.IsValid() {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+				domain.InvalidRequest,
+				"status must be PENDING, DELIVERED or DEAD",
+			))
+			return
+		}
+		filter.Status = &status
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > maxDeliveryLogLimit {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+				domain.InvalidRequest,
+				"limit must be between 1 and "+strconv.Itoa(maxDeliveryLogLimit),
+			))
+			return
+		}
+		filter.Limit = limit
+	}
+
+	deliveries, err := s.subscriptionRepo.ListDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		logger.Logger.Error("error listing deliveries: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrSubscriptionMsg,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
@@ -0,0 +1,78 @@
+package subscriptionService
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionService_ListDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockSubscriptionRepositoryInterface(ctrl)
+	service := NewSubscriptionService(mockRepo)
+
+	t.Run("filters are passed to storage", func(t *testing.T) {
+		mockRepo.EXPECT().ListDeliveries(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, filter domain.DeliveryLogFilter) ([]domain.SubscriptionDelivery, error) {
+				require.NotNil(t, filter.SubscriptionID)
+				assert.Equal(t, "sub-1", *filter.SubscriptionID)
+				require.NotNil(t, filter.Status)
+				assert.Equal(t, domain.DeliveryDead, *filter.Status)
+				assert.Equal(t, 10, filter.Limit)
+				return []domain.SubscriptionDelivery{{ID: 7, Status: domain.DeliveryDead}}, nil
+			})
+
+		c, w := newJSONContext(http.MethodGet, "/subscriptions/deliveries?subscription_id=sub-1&status=DEAD&limit=10", "")
+
+		service.ListDeliveries(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"delivery_id":7`)
+	})
+
+	t.Run("default limit", func(t *testing.T) {
+		mockRepo.EXPECT().ListDeliveries(gomock.Any(), domain.DeliveryLogFilter{Limit: defaultDeliveryLogLimit}).
+			Return([]domain.SubscriptionDelivery{}, nil)
+
+		c, w := newJSONContext(http.MethodGet, "/subscriptions/deliveries", "")
+
+		service.ListDeliveries(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		c, w := newJSONContext(http.MethodGet, "/subscriptions/deliveries?status=LOST", "")
+
+		service.ListDeliveries(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		c, w := newJSONContext(http.MethodGet, "/subscriptions/deliveries?limit=100000", "")
+
+		service.ListDeliveries(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		mockRepo.EXPECT().ListDeliveries(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+
+		c, w := newJSONContext(http.MethodGet, "/subscriptions/deliveries", "")
+
+		service.ListDeliveries(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
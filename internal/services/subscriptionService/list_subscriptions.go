@@ -0,0 +1,23 @@
+package subscriptionService
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+func (s *SubscriptionServiceImpl) ListSubscriptions(c *gin.Context) {
+	subscriptions, err := s.subscriptionRepo.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		logger.Logger.Error("error listing subscriptions: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrSubscriptionMsg,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subscriptions": subscriptions})
+}
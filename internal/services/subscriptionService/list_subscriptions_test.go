@@ -0,0 +1,49 @@
+package subscriptionService
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionService_ListSubscriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockSubscriptionRepositoryInterface(ctrl)
+	service := NewSubscriptionService(mockRepo)
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.EXPECT().ListSubscriptions(gomock.Any()).Return([]domain.Subscription{{
+			ID:         "sub-1",
+			URL:        "https://hooks.example.com/pr",
+			Secret:     "s3cr3t",
+			EventTypes: []domain.EventType{domain.EventUserDeactivated},
+			IsActive:   true,
+		}}, nil)
+
+		c, w := newJSONContext(http.MethodGet, "/subscriptions/list", "")
+
+		service.ListSubscriptions(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "user.deactivated")
+		assert.NotContains(t, w.Body.String(), "s3cr3t")
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		mockRepo.EXPECT().ListSubscriptions(gomock.Any()).Return(nil, errors.New("db error"))
+
+		c, w := newJSONContext(http.MethodGet, "/subscriptions/list", "")
+
+		service.ListSubscriptions(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
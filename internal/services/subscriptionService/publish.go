@@ -0,0 +1,28 @@
+package subscriptionService
+
+import (
+	"context"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// Publish ставит события в очередь доставки подписчикам; сама доставка идёт в Dispatcher
+func (s *SubscriptionServiceImpl) Publish(ctx context.Context, events ...domain.Event) error {
+	for _, event := range events {
+		enqueued, err := s.subscriptionRepo.EnqueueEvent(ctx, event)
+		if err != nil {
+			return err
+		}
+
+		if enqueued > 0 {
+			logger.Logger.Debugw("event enqueued for subscribers",
+				"event_id", event.ID,
+				"event_type", event.Type,
+				"deliveries", enqueued,
+			)
+		}
+	}
+
+	return nil
+}
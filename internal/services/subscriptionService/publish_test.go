@@ -0,0 +1,41 @@
+package subscriptionService
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionService_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockSubscriptionRepositoryInterface(ctrl)
+	service := NewSubscriptionService(mockRepo)
+
+	created, err := domain.NewEvent(domain.EventPRCreated, time.Now(), domain.PullRequestEventData{PullRequestID: "pr-1"})
+	require.NoError(t, err)
+	merged, err := domain.NewEvent(domain.EventPRMerged, time.Now(), domain.PullRequestEventData{PullRequestID: "pr-1"})
+	require.NoError(t, err)
+
+	t.Run("every event is enqueued", func(t *testing.T) {
+		gomock.InOrder(
+			mockRepo.EXPECT().EnqueueEvent(gomock.Any(), created).Return(int64(2), nil),
+			mockRepo.EXPECT().EnqueueEvent(gomock.Any(), merged).Return(int64(0), nil),
+		)
+
+		require.NoError(t, service.Publish(context.Background(), created, merged))
+	})
+
+	t.Run("storage error stops publishing", func(t *testing.T) {
+		mockRepo.EXPECT().EnqueueEvent(gomock.Any(), created).Return(int64(0), errors.New("db error"))
+
+		require.Error(t, service.Publish(context.Background(), created, merged))
+	})
+}
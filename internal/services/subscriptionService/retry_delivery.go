@@ -0,0 +1,42 @@
+package subscriptionService
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// RetryDelivery возвращает доставку из DEAD в очередь с новым набором попыток
+func (s *SubscriptionServiceImpl) RetryDelivery(c *gin.Context) {
+	var req domain.RetryDeliveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid request body",
+		))
+		return
+	}
+
+	if err := s.subscriptionRepo.RequeueDeadDelivery(c.Request.Context(), req.DeliveryID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
+				domain.NotFound,
+				"dead delivery not found",
+			))
+			return
+		}
+		logger.Logger.Error("error requeueing delivery: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrSubscriptionMsg,
+		))
+		return
+	}
+
+	logger.Logger.Infow("dead delivery requeued", "delivery_id", req.DeliveryID)
+	c.JSON(http.StatusOK, gin.H{"delivery_id": req.DeliveryID, "status": domain.DeliveryPending})
+}
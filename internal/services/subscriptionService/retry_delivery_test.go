@@ -0,0 +1,47 @@
+package subscriptionService
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionService_RetryDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockSubscriptionRepositoryInterface(ctrl)
+	service := NewSubscriptionService(mockRepo)
+
+	t.Run("dead delivery is requeued", func(t *testing.T) {
+		mockRepo.EXPECT().RequeueDeadDelivery(gomock.Any(), int64(7)).Return(nil)
+
+		c, w := newJSONContext(http.MethodPost, "/subscriptions/deliveries/retry", `{"delivery_id": 7}`)
+
+		service.RetryDelivery(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("delivery is not dead", func(t *testing.T) {
+		mockRepo.EXPECT().RequeueDeadDelivery(gomock.Any(), int64(7)).Return(pgx.ErrNoRows)
+
+		c, w := newJSONContext(http.MethodPost, "/subscriptions/deliveries/retry", `{"delivery_id": 7}`)
+
+		service.RetryDelivery(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid request body", func(t *testing.T) {
+		c, w := newJSONContext(http.MethodPost, "/subscriptions/deliveries/retry", `{"delivery_id": "x"}`)
+
+		service.RetryDelivery(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,15 @@
+package subscriptionService
+
+import (
+	"github.com/nedokyrill/avito-pr-api/internal/storage"
+)
+
+type SubscriptionServiceImpl struct {
+	subscriptionRepo storage.SubscriptionRepositoryInterface
+}
+
+func NewSubscriptionService(subscriptionRepo storage.SubscriptionRepositoryInterface) *SubscriptionServiceImpl {
+	return &SubscriptionServiceImpl{
+		subscriptionRepo: subscriptionRepo,
+	}
+}
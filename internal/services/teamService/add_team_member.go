@@ -0,0 +1,39 @@
+package teamService
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// AddTeamMember добавляет существующего пользователя в команду. Пользователь может состоять
+// в нескольких командах и будет кандидатом в ревьюеры в каждой из них
+func (s *TeamServiceImpl) AddTeamMember(ctx context.Context, req domain.TeamMembershipRequest) (*domain.Team, error) {
+	_, err := s.userRepo.GetUserByID(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewError(domain.NotFound, "user not found")
+		}
+		return nil, err
+	}
+
+	err = s.teamRepo.AddTeamMember(ctx, req.TeamName, req.UserID)
+	if err != nil {
+		if errors.Is(err, teamStorage.ErrTeamNotExists) {
+			return nil, domain.NewError(domain.NotFound, "team not found")
+		}
+		return nil, err
+	}
+
+	team, err := s.teamRepo.GetTeamByName(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Infow("team member added", "team_name", req.TeamName, "user_id", req.UserID)
+	return team, nil
+}
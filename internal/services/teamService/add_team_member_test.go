@@ -0,0 +1,80 @@
+package teamService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamService_AddTeamMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	service := NewTeamService(mockTeamRepo, mockUserRepo)
+	ctx := context.Background()
+
+	req := domain.TeamMembershipRequest{TeamName: "Payments", UserID: "user-bob"}
+
+	t.Run("successfully add member", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetUserByID(gomock.Any(), "user-bob").
+			Return(&domain.User{UserId: "user-bob", TeamName: "Backend"}, nil)
+		mockTeamRepo.EXPECT().AddTeamMember(gomock.Any(), "Payments", "user-bob").Return(nil)
+		mockTeamRepo.EXPECT().
+			GetTeamByName(gomock.Any(), "Payments").
+			Return(&domain.Team{TeamName: "Payments", Members: []domain.TeamMember{{UserId: "user-bob"}}}, nil)
+
+		team, err := service.AddTeamMember(ctx, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, []domain.TeamMember{{UserId: "user-bob"}}, team.Members)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), "user-bob").Return(nil, pgx.ErrNoRows)
+
+		_, err := service.AddTeamMember(ctx, req)
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetUserByID(gomock.Any(), "user-bob").
+			Return(&domain.User{UserId: "user-bob"}, nil)
+		mockTeamRepo.EXPECT().
+			AddTeamMember(gomock.Any(), "Payments", "user-bob").
+			Return(teamStorage.ErrTeamNotExists)
+
+		_, err := service.AddTeamMember(ctx, req)
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
+	})
+
+	t.Run("error adding member", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetUserByID(gomock.Any(), "user-bob").
+			Return(&domain.User{UserId: "user-bob"}, nil)
+		mockTeamRepo.EXPECT().
+			AddTeamMember(gomock.Any(), "Payments", "user-bob").
+			Return(errors.New("db error"))
+
+		_, err := service.AddTeamMember(ctx, req)
+
+		require.Error(t, err)
+	})
+}
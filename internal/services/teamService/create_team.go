@@ -1,48 +1,39 @@
 package teamService
 
 import (
+	"context"
 	"errors"
-	"net/http"
 
-	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
 	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
 )
 
-func (s *TeamServiceImpl) CreateTeam(c *gin.Context) {
-	ctx := c.Request.Context()
-
-	var team domain.Team
-
-	if err := c.ShouldBindJSON(&team); err != nil {
-		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
-			domain.InvalidRequest,
-			"invalid request body",
-		))
-		return
+// CreateTeam создаёт команду с участниками. Пустой ParentTeamName делает команду корневой,
+// занятое имя команды - TEAM_EXISTS
+func (s *TeamServiceImpl) CreateTeam(ctx context.Context, team domain.Team) (*domain.Team, error) {
+	if team.ParentTeamName != nil && *team.ParentTeamName != "" {
+		_, err := s.teamRepo.GetTeamByName(ctx, *team.ParentTeamName)
+		if err != nil {
+			if errors.Is(err, teamStorage.ErrTeamNotExists) {
+				return nil, domain.NewError(domain.NotFound, "parent team not found")
+			}
+			return nil, err
+		}
+	} else {
+		team.ParentTeamName = nil
 	}
 
-	_, err := s.teamRepo.CreateTeamWithMembers(ctx, team.TeamName, team.Members)
+	_, err := s.teamRepo.CreateTeamWithMembers(ctx, team.TeamName, team.ParentTeamName, team.Members)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
-				domain.TeamExists,
-				"team_name already exists",
-			))
-			return
+			return nil, domain.NewError(domain.TeamExists, "team_name already exists")
 		}
-		logger.Logger.Error("error creating team with members: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrCreateTeamMsg,
-		))
-		return
+		return nil, err
 	}
 
 	logger.Logger.Infow("team created successfully", "team_name", team.TeamName)
-	c.JSON(http.StatusCreated, gin.H{
-		"team": team,
-	})
+	return &team, nil
 }
@@ -1,18 +1,16 @@
 package teamService
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"net/http"
-	"net/http/httptest"
-	"strings"
 	"testing"
 
-	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
 	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
 	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,7 +18,6 @@ import (
 )
 
 func init() {
-	gin.SetMode(gin.TestMode)
 	logger.Logger = zap.NewNop().Sugar()
 }
 
@@ -31,94 +28,111 @@ func TestTeamService_CreateTeam(t *testing.T) {
 	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
 	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
 	service := NewTeamService(mockTeamRepo, mockUserRepo)
+	ctx := context.Background()
 
 	t.Run("successfully create team with members", func(t *testing.T) {
-		teamID := uuid.New()
-		user1ID := "user-alice-1"
-		user2ID := "user-bob-1"
-
-		requestBody := `{
-			"team_name": "Backend Team",
-			"members": [
-				{"user_id": "` + user1ID + `", "username": "Alice", "is_active": true},
-				{"user_id": "` + user2ID + `", "username": "Bob", "is_active": true}
-			]
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/teams", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+		team := domain.Team{
+			TeamName: "Backend Team",
+			Members: []domain.TeamMember{
+				{UserId: "user-alice-1", Username: "Alice", IsActive: true},
+				{UserId: "user-bob-1", Username: "Bob", IsActive: true},
+			},
+		}
 
 		mockTeamRepo.EXPECT().
-			CreateTeamWithMembers(gomock.Any(), "Backend Team", gomock.Any()).
-			Return(teamID, nil)
+			CreateTeamWithMembers(gomock.Any(), "Backend Team", gomock.Nil(), team.Members).
+			Return(uuid.New(), nil)
 
-		service.CreateTeam(c)
+		created, err := service.CreateTeam(ctx, team)
 
-		require.Equal(t, http.StatusCreated, w.Code)
-		var response map[string]interface{}
-		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Contains(t, response, "team")
+		assert.Equal(t, "Backend Team", created.TeamName)
+		assert.Len(t, created.Members, 2)
 	})
 
-	t.Run("invalid request body", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/teams", strings.NewReader("invalid json"))
-		c.Request.Header.Set("Content-Type", "application/json")
+	t.Run("empty parent makes team a root", func(t *testing.T) {
+		empty := ""
+
+		mockTeamRepo.EXPECT().
+			CreateTeamWithMembers(gomock.Any(), "Backend Team", gomock.Nil(), gomock.Any()).
+			Return(uuid.New(), nil)
 
-		service.CreateTeam(c)
+		created, err := service.CreateTeam(ctx, domain.Team{TeamName: "Backend Team", ParentTeamName: &empty})
 
-		require.Equal(t, http.StatusBadRequest, w.Code)
-		var response domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Equal(t, domain.InvalidRequest, response.Error.Code)
+		assert.Nil(t, created.ParentTeamName)
 	})
 
 	t.Run("team already exists", func(t *testing.T) {
-		requestBody := `{
-			"team_name": "Existing Team",
-			"members": []
-		}`
+		mockTeamRepo.EXPECT().
+			CreateTeamWithMembers(gomock.Any(), "Existing Team", gomock.Nil(), gomock.Any()).
+			Return(uuid.Nil, &pgconn.PgError{Code: "23505"})
+
+		_, err := service.CreateTeam(ctx, domain.Team{TeamName: "Existing Team"})
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/teams", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.TeamExists, domainErr.Code)
+	})
 
+	t.Run("error creating team with members", func(t *testing.T) {
 		mockTeamRepo.EXPECT().
-			CreateTeamWithMembers(gomock.Any(), "Existing Team", gomock.Any()).
-			Return(uuid.Nil, errors.New("duplicate key error"))
+			CreateTeamWithMembers(gomock.Any(), "Backend Team", gomock.Nil(), gomock.Any()).
+			Return(uuid.Nil, errors.New("database error"))
 
-		service.CreateTeam(c)
+		_, err := service.CreateTeam(ctx, domain.Team{TeamName: "Backend Team"})
 
-		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Error(t, err)
+		var domainErr *domain.Error
+		assert.False(t, errors.As(err, &domainErr))
 	})
+}
 
-	t.Run("error creating team with members", func(t *testing.T) {
-		userID := "user-alice-2"
+func TestTeamService_CreateTeamWithParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-		requestBody := `{
-			"team_name": "Backend Team",
-			"members": [
-				{"user_id": "` + userID + `", "username": "Alice", "is_active": true}
-			]
-		}`
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	service := NewTeamService(mockTeamRepo, mockUserRepo)
+	ctx := context.Background()
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/teams", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+	team := domain.Team{
+		TeamName:       "Payments Squad",
+		ParentTeamName: strPtr("Backend Guild"),
+		Members: []domain.TeamMember{
+			{UserId: "user-alice-1", Username: "Alice", IsActive: true},
+		},
+	}
 
+	t.Run("successfully create team under parent", func(t *testing.T) {
 		mockTeamRepo.EXPECT().
-			CreateTeamWithMembers(gomock.Any(), "Backend Team", gomock.Any()).
-			Return(uuid.Nil, errors.New("database error"))
+			GetTeamByName(gomock.Any(), "Backend Guild").
+			Return(&domain.Team{TeamName: "Backend Guild"}, nil)
+		mockTeamRepo.EXPECT().
+			CreateTeamWithMembers(gomock.Any(), "Payments Squad", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ interface{}, _ string, parent *string, _ []domain.TeamMember) (uuid.UUID, error) {
+				require.NotNil(t, parent)
+				assert.Equal(t, "Backend Guild", *parent)
+				return uuid.New(), nil
+			})
+
+		created, err := service.CreateTeam(ctx, team)
+
+		require.NoError(t, err)
+		require.NotNil(t, created.ParentTeamName)
+		assert.Equal(t, "Backend Guild", *created.ParentTeamName)
+	})
+
+	t.Run("parent team not found", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			GetTeamByName(gomock.Any(), "Backend Guild").
+			Return(nil, teamStorage.ErrTeamNotExists)
 
-		service.CreateTeam(c)
+		_, err := service.CreateTeam(ctx, team)
 
-		require.Equal(t, http.StatusInternalServerError, w.Code)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
 	})
 }
@@ -1,13 +1,10 @@
 package teamService
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"net/http"
-	"net/http/httptest"
 	"testing"
 
-	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
 	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
@@ -23,6 +20,7 @@ func TestTeamService_GetTeam(t *testing.T) {
 	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
 	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
 	service := NewTeamService(mockTeamRepo, mockUserRepo)
+	ctx := context.Background()
 
 	t.Run("successfully get team", func(t *testing.T) {
 		team := &domain.Team{
@@ -33,72 +31,38 @@ func TestTeamService_GetTeam(t *testing.T) {
 			},
 		}
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/teams?team_name=Backend%20Team", nil)
-
 		mockTeamRepo.EXPECT().
 			GetTeamByName(gomock.Any(), "Backend Team").
 			Return(team, nil)
 
-		service.GetTeam(c)
+		response, err := service.GetTeam(ctx, "Backend Team")
 
-		require.Equal(t, http.StatusOK, w.Code)
-		var response domain.Team
-		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 		assert.Equal(t, "Backend Team", response.TeamName)
 		assert.Len(t, response.Members, 2)
 	})
 
-	t.Run("missing team_name parameter", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/teams", nil)
-
-		service.GetTeam(c)
-
-		require.Equal(t, http.StatusBadRequest, w.Code)
-		var response domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		require.NoError(t, err)
-		assert.Equal(t, domain.InvalidRequest, response.Error.Code)
-	})
-
 	t.Run("team not found", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/teams?team_name=NonExistent", nil)
-
 		mockTeamRepo.EXPECT().
 			GetTeamByName(gomock.Any(), "NonExistent").
 			Return(nil, teamStorage.ErrTeamNotExists)
 
-		service.GetTeam(c)
+		_, err := service.GetTeam(ctx, "NonExistent")
 
-		require.Equal(t, http.StatusNotFound, w.Code)
-		var response domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		require.NoError(t, err)
-		assert.Equal(t, domain.NotFound, response.Error.Code)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
 	})
 
-	t.Run("internal server error", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/teams?team_name=Backend%20Team", nil)
-
+	t.Run("storage error", func(t *testing.T) {
 		mockTeamRepo.EXPECT().
 			GetTeamByName(gomock.Any(), "Backend Team").
 			Return(nil, errors.New("database connection error"))
 
-		service.GetTeam(c)
+		_, err := service.GetTeam(ctx, "Backend Team")
 
-		require.Equal(t, http.StatusInternalServerError, w.Code)
-		var response domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		require.NoError(t, err)
-		assert.Equal(t, domain.InternalError, response.Error.Code)
+		require.Error(t, err)
+		var domainErr *domain.Error
+		assert.False(t, errors.As(err, &domainErr))
 	})
 }
-
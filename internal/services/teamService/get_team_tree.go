@@ -0,0 +1,70 @@
+package teamService
+
+import (
+	"context"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// GetTeamTree возвращает иерархию команд. С пустым teamName - всё дерево (список корневых команд),
+// иначе - поддерево с корнем в указанной команде
+func (s *TeamServiceImpl) GetTeamTree(ctx context.Context, teamName string) ([]domain.TeamTreeNode, error) {
+	nodes, err := s.teamRepo.GetTeamsHierarchy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roots, found := buildTeamTree(nodes, teamName)
+	if !found {
+		return nil, domain.NewError(domain.NotFound, "team not found")
+	}
+
+	logger.Logger.Infow("team tree retrieved successfully", "team_name", teamName)
+	return roots, nil
+}
+
+// buildTeamTree собирает дерево из плоского списка команд. Если rootName пустой,
+// возвращаются все корневые команды, иначе - единственное поддерево с корнем rootName
+func buildTeamTree(nodes []domain.TeamTreeNode, rootName string) ([]domain.TeamTreeNode, bool) {
+	childrenByParent := make(map[string][]string)
+	nodeByName := make(map[string]domain.TeamTreeNode, len(nodes))
+	var rootNames []string
+
+	for _, node := range nodes {
+		nodeByName[node.TeamName] = node
+		if node.ParentTeamName == nil {
+			rootNames = append(rootNames, node.TeamName)
+			continue
+		}
+		childrenByParent[*node.ParentTeamName] = append(childrenByParent[*node.ParentTeamName], node.TeamName)
+	}
+
+	var build func(name string, depth int) domain.TeamTreeNode
+	build = func(name string, depth int) domain.TeamTreeNode {
+		node := nodeByName[name]
+		node.Children = []domain.TeamTreeNode{}
+		// ограничение глубины защищает от зацикливания при некорректных данных
+		if depth >= domain.MaxTeamHierarchyDepth {
+			return node
+		}
+		for _, childName := range childrenByParent[name] {
+			node.Children = append(node.Children, build(childName, depth+1))
+		}
+		return node
+	}
+
+	if rootName != "" {
+		if _, ok := nodeByName[rootName]; !ok {
+			return nil, false
+		}
+		return []domain.TeamTreeNode{build(rootName, 0)}, true
+	}
+
+	roots := make([]domain.TeamTreeNode, 0, len(rootNames))
+	for _, name := range rootNames {
+		roots = append(roots, build(name, 0))
+	}
+
+	return roots, true
+}
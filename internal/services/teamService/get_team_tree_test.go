@@ -0,0 +1,81 @@
+package teamService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestTeamService_GetTeamTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	service := NewTeamService(mockTeamRepo, mockUserRepo)
+	ctx := context.Background()
+
+	hierarchy := []domain.TeamTreeNode{
+		{TeamName: "Backend", ParentTeamName: strPtr("Engineering")},
+		{TeamName: "Design"},
+		{TeamName: "Engineering"},
+		{TeamName: "Payments", ParentTeamName: strPtr("Backend")},
+	}
+
+	t.Run("successfully get whole tree", func(t *testing.T) {
+		mockTeamRepo.EXPECT().GetTeamsHierarchy(gomock.Any()).Return(hierarchy, nil)
+
+		teams, err := service.GetTeamTree(ctx, "")
+
+		require.NoError(t, err)
+		require.Len(t, teams, 2)
+		assert.Equal(t, "Design", teams[0].TeamName)
+		assert.Empty(t, teams[0].Children)
+		assert.Equal(t, "Engineering", teams[1].TeamName)
+		require.Len(t, teams[1].Children, 1)
+		assert.Equal(t, "Backend", teams[1].Children[0].TeamName)
+		require.Len(t, teams[1].Children[0].Children, 1)
+		assert.Equal(t, "Payments", teams[1].Children[0].Children[0].TeamName)
+	})
+
+	t.Run("successfully get subtree", func(t *testing.T) {
+		mockTeamRepo.EXPECT().GetTeamsHierarchy(gomock.Any()).Return(hierarchy, nil)
+
+		teams, err := service.GetTeamTree(ctx, "Backend")
+
+		require.NoError(t, err)
+		require.Len(t, teams, 1)
+		assert.Equal(t, "Backend", teams[0].TeamName)
+		require.NotNil(t, teams[0].ParentTeamName)
+		assert.Equal(t, "Engineering", *teams[0].ParentTeamName)
+		require.Len(t, teams[0].Children, 1)
+	})
+
+	t.Run("subtree root not found", func(t *testing.T) {
+		mockTeamRepo.EXPECT().GetTeamsHierarchy(gomock.Any()).Return(hierarchy, nil)
+
+		_, err := service.GetTeamTree(ctx, "NonExistent")
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
+	})
+
+	t.Run("error getting hierarchy", func(t *testing.T) {
+		mockTeamRepo.EXPECT().GetTeamsHierarchy(gomock.Any()).Return(nil, errors.New("db error"))
+
+		_, err := service.GetTeamTree(ctx, "")
+
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,30 @@
+package teamService
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// RemoveTeamMember исключает пользователя из команды. Если команда была основной,
+// основной становится самая ранняя из оставшихся команд пользователя
+func (s *TeamServiceImpl) RemoveTeamMember(ctx context.Context, req domain.TeamMembershipRequest) (*domain.Team, error) {
+	err := s.teamRepo.RemoveTeamMember(ctx, req.TeamName, req.UserID)
+	if err != nil {
+		if errors.Is(err, teamStorage.ErrNotTeamMember) {
+			return nil, domain.NewError(domain.NotFound, "user "+req.UserID+" is not a member of team "+req.TeamName)
+		}
+		return nil, err
+	}
+
+	team, err := s.teamRepo.GetTeamByName(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Infow("team member removed", "team_name", req.TeamName, "user_id", req.UserID)
+	return team, nil
+}
@@ -0,0 +1,60 @@
+package teamService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamService_RemoveTeamMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	service := NewTeamService(mockTeamRepo, mockUserRepo)
+	ctx := context.Background()
+
+	req := domain.TeamMembershipRequest{TeamName: "Payments", UserID: "user-bob"}
+
+	t.Run("successfully remove member", func(t *testing.T) {
+		mockTeamRepo.EXPECT().RemoveTeamMember(gomock.Any(), "Payments", "user-bob").Return(nil)
+		mockTeamRepo.EXPECT().
+			GetTeamByName(gomock.Any(), "Payments").
+			Return(&domain.Team{TeamName: "Payments"}, nil)
+
+		team, err := service.RemoveTeamMember(ctx, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Payments", team.TeamName)
+	})
+
+	t.Run("user is not a member", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			RemoveTeamMember(gomock.Any(), "Payments", "user-bob").
+			Return(teamStorage.ErrNotTeamMember)
+
+		_, err := service.RemoveTeamMember(ctx, req)
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
+	})
+
+	t.Run("error removing member", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			RemoveTeamMember(gomock.Any(), "Payments", "user-bob").
+			Return(errors.New("db error"))
+
+		_, err := service.RemoveTeamMember(ctx, req)
+
+		require.Error(t, err)
+	})
+}
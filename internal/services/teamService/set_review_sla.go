@@ -0,0 +1,29 @@
+package teamService
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// SetReviewSLA задаёт, сколько часов PR команды может ждать ревью; просроченные PR выделяются
+// в ежедневных напоминаниях ревьюверам. nil снимает SLA
+func (s *TeamServiceImpl) SetReviewSLA(ctx context.Context, req domain.SetReviewSLARequest) error {
+	if req.ReviewSLAHours != nil && *req.ReviewSLAHours <= 0 {
+		return domain.NewError(domain.InvalidRequest, "review_sla_hours must be positive")
+	}
+
+	err := s.teamRepo.SetReviewSLA(ctx, req.TeamName, req.ReviewSLAHours)
+	if err != nil {
+		if errors.Is(err, teamStorage.ErrTeamNotExists) {
+			return domain.NewError(domain.NotFound, "team not found")
+		}
+		return err
+	}
+
+	logger.Logger.Infow("team review SLA updated", "team_name", req.TeamName, "review_sla_hours", req.ReviewSLAHours)
+	return nil
+}
@@ -0,0 +1,77 @@
+package teamService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamService_SetReviewSLA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	service := NewTeamService(mockTeamRepo, mockUserRepo)
+	ctx := context.Background()
+
+	intPtr := func(v int) *int { return &v }
+
+	t.Run("successfully set SLA", func(t *testing.T) {
+		hours := 24
+		mockTeamRepo.EXPECT().
+			SetReviewSLA(gomock.Any(), "Payments", &hours).
+			Return(nil)
+
+		err := service.SetReviewSLA(ctx, domain.SetReviewSLARequest{TeamName: "Payments", ReviewSLAHours: &hours})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("successfully clear SLA", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			SetReviewSLA(gomock.Any(), "Payments", gomock.Nil()).
+			Return(nil)
+
+		err := service.SetReviewSLA(ctx, domain.SetReviewSLARequest{TeamName: "Payments"})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("non-positive SLA", func(t *testing.T) {
+		err := service.SetReviewSLA(ctx, domain.SetReviewSLARequest{TeamName: "Payments", ReviewSLAHours: intPtr(0)})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.InvalidRequest, domainErr.Code)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			SetReviewSLA(gomock.Any(), "Unknown", gomock.Any()).
+			Return(teamStorage.ErrTeamNotExists)
+
+		err := service.SetReviewSLA(ctx, domain.SetReviewSLARequest{TeamName: "Unknown", ReviewSLAHours: intPtr(24)})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			SetReviewSLA(gomock.Any(), "Payments", gomock.Any()).
+			Return(errors.New("database error"))
+
+		err := service.SetReviewSLA(ctx, domain.SetReviewSLARequest{TeamName: "Payments", ReviewSLAHours: intPtr(24)})
+
+		require.Error(t, err)
+	})
+}
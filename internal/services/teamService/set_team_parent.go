@@ -0,0 +1,40 @@
+package teamService
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// SetTeamParent переносит команду под другую родительскую команду (или делает её корневой).
+// Хранилище в одной транзакции проверяет, что новый родитель не является самой командой или её потомком,
+// иначе в иерархии образовался бы цикл
+func (s *TeamServiceImpl) SetTeamParent(ctx context.Context, req domain.SetTeamParentRequest) (*domain.Team, error) {
+	if req.ParentTeamName != nil && *req.ParentTeamName == "" {
+		req.ParentTeamName = nil
+	}
+
+	err := s.teamRepo.SetTeamParent(ctx, req.TeamName, req.ParentTeamName)
+	if err != nil {
+		switch {
+		case errors.Is(err, teamStorage.ErrTeamNotExists):
+			return nil, domain.NewError(domain.NotFound, "team not found")
+		case errors.Is(err, teamStorage.ErrParentTeamNotExists):
+			return nil, domain.NewError(domain.NotFound, "parent team not found")
+		case errors.Is(err, teamStorage.ErrTeamHierarchyCycle):
+			return nil, domain.NewError(domain.InvalidRequest, "team cannot be a parent of itself or of its ancestor")
+		}
+		return nil, err
+	}
+
+	team, err := s.teamRepo.GetTeamByName(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Infow("team parent updated", "team_name", req.TeamName, "parent_team_name", req.ParentTeamName)
+	return team, nil
+}
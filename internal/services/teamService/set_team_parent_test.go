@@ -0,0 +1,136 @@
+package teamService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamService_SetTeamParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	service := NewTeamService(mockTeamRepo, mockUserRepo)
+	ctx := context.Background()
+
+	t.Run("successfully set parent", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			SetTeamParent(gomock.Any(), "Payments", gomock.Any()).
+			Return(nil)
+		mockTeamRepo.EXPECT().
+			GetTeamByName(gomock.Any(), "Payments").
+			Return(&domain.Team{TeamName: "Payments", ParentTeamName: strPtr("Backend")}, nil)
+
+		team, err := service.SetTeamParent(ctx, domain.SetTeamParentRequest{
+			TeamName:       "Payments",
+			ParentTeamName: strPtr("Backend"),
+		})
+
+		require.NoError(t, err)
+		require.NotNil(t, team.ParentTeamName)
+		assert.Equal(t, "Backend", *team.ParentTeamName)
+	})
+
+	t.Run("successfully detach from parent", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			SetTeamParent(gomock.Any(), "Payments", gomock.Nil()).
+			Return(nil)
+		mockTeamRepo.EXPECT().
+			GetTeamByName(gomock.Any(), "Payments").
+			Return(&domain.Team{TeamName: "Payments"}, nil)
+
+		team, err := service.SetTeamParent(ctx, domain.SetTeamParentRequest{TeamName: "Payments"})
+
+		require.NoError(t, err)
+		assert.Nil(t, team.ParentTeamName)
+	})
+
+	t.Run("empty parent detaches too", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			SetTeamParent(gomock.Any(), "Payments", gomock.Nil()).
+			Return(nil)
+		mockTeamRepo.EXPECT().
+			GetTeamByName(gomock.Any(), "Payments").
+			Return(&domain.Team{TeamName: "Payments"}, nil)
+
+		_, err := service.SetTeamParent(ctx, domain.SetTeamParentRequest{TeamName: "Payments", ParentTeamName: strPtr("")})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("cycle in hierarchy", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			SetTeamParent(gomock.Any(), "Engineering", gomock.Any()).
+			Return(teamStorage.ErrTeamHierarchyCycle)
+
+		_, err := service.SetTeamParent(ctx, domain.SetTeamParentRequest{
+			TeamName:       "Engineering",
+			ParentTeamName: strPtr("Payments"),
+		})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.InvalidRequest, domainErr.Code)
+	})
+
+	t.Run("team cannot be its own parent", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			SetTeamParent(gomock.Any(), "Backend", gomock.Any()).
+			Return(teamStorage.ErrTeamHierarchyCycle)
+
+		_, err := service.SetTeamParent(ctx, domain.SetTeamParentRequest{
+			TeamName:       "Backend",
+			ParentTeamName: strPtr("Backend"),
+		})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.InvalidRequest, domainErr.Code)
+	})
+
+	t.Run("parent team not found", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			SetTeamParent(gomock.Any(), "Payments", gomock.Any()).
+			Return(teamStorage.ErrParentTeamNotExists)
+
+		_, err := service.SetTeamParent(ctx, domain.SetTeamParentRequest{
+			TeamName:       "Payments",
+			ParentTeamName: strPtr("NonExistent"),
+		})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			SetTeamParent(gomock.Any(), "NonExistent", gomock.Nil()).
+			Return(teamStorage.ErrTeamNotExists)
+
+		_, err := service.SetTeamParent(ctx, domain.SetTeamParentRequest{TeamName: "NonExistent"})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
+	})
+
+	t.Run("error setting parent", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			SetTeamParent(gomock.Any(), "Payments", gomock.Nil()).
+			Return(errors.New("db error"))
+
+		_, err := service.SetTeamParent(ctx, domain.SetTeamParentRequest{TeamName: "Payments"})
+
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,123 @@
+package userService
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// maxActivateTeamMembersAttempts - сколько раз пересчитывается ребаланс, если ревью доноров
+// успели измениться между расчётом и применением
+const maxActivateTeamMembersAttempts = 3
+
+// ActivateTeamMembers - обратная операция к DeactivateTeamMembers. С rebalance=true вернувшимся
+// участникам переносятся открытые ревью с самых загруженных активных участников команды,
+// пока разница в нагрузке больше одного ревью
+func (s *UserServiceImpl) ActivateTeamMembers(
+	ctx context.Context,
+	req domain.ActivateTeamMembersRequest,
+) (*domain.ActivateTeamMembersResponse, error) {
+	team, err := s.teamRepo.GetTeamByName(ctx, req.TeamName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, teamStorage.ErrTeamNotExists) {
+			return nil, domain.NewError(domain.NotFound, "team not found")
+		}
+		return nil, err
+	}
+
+	// Активируемые пользователи должны быть участниками команды
+	activating := make(map[string]struct{}, len(req.UserIDs))
+	for _, userID := range req.UserIDs {
+		activating[userID] = struct{}{}
+	}
+
+	teamMemberIDs := make(map[string]struct{}, len(team.Members))
+	for _, member := range team.Members {
+		teamMemberIDs[member.UserId] = struct{}{}
+	}
+
+	for userID := range activating {
+		if _, ok := teamMemberIDs[userID]; !ok {
+			return nil, domain.NewError(domain.InvalidRequest, "user "+userID+" is not a member of team "+req.TeamName)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		reassignments, err := s.planRebalance(ctx, team, req)
+		if err != nil {
+			return nil, err
+		}
+
+		activatedUserIDs, err := s.teamRepo.ActivateTeamMembers(ctx, req.TeamName, req.UserIDs, reassignments)
+		if err != nil {
+			if errors.Is(err, teamStorage.ErrPlanStale) {
+				if attempt < maxActivateTeamMembersAttempts {
+					continue
+				}
+				return nil, domain.NewError(domain.PlanStale, err.Error()+", try again")
+			}
+			return nil, err
+		}
+
+		logger.Logger.Infow("team members activated",
+			"team_name", req.TeamName,
+			"activated_count", len(activatedUserIDs),
+			"reassignments_count", len(reassignments),
+		)
+
+		return &domain.ActivateTeamMembersResponse{
+			ActivatedUserIDs: activatedUserIDs,
+			Reassignments:    reassignments,
+		}, nil
+	}
+}
+
+// planRebalance считает переносы открытых ревью на активируемых участников (пусто без rebalance)
+func (s *UserServiceImpl) planRebalance(
+	ctx context.Context,
+	team *domain.Team,
+	req domain.ActivateTeamMembersRequest,
+) ([]domain.ReviewerReassignment, error) {
+	reassignments := make([]domain.ReviewerReassignment, 0)
+	if !req.Rebalance {
+		return reassignments, nil
+	}
+
+	activating := make(map[string]struct{}, len(req.UserIDs))
+	for _, userID := range req.UserIDs {
+		activating[userID] = struct{}{}
+	}
+
+	// Доноры - участники, которые уже активны; получатели - активируемые
+	donors := make([]string, 0, len(team.Members))
+	for _, member := range team.Members {
+		if _, ok := activating[member.UserId]; !ok && member.IsActive {
+			donors = append(donors, member.UserId)
+		}
+	}
+
+	reviewerIDs := make([]string, 0, len(donors)+len(req.UserIDs))
+	reviewerIDs = append(reviewerIDs, donors...)
+	reviewerIDs = append(reviewerIDs, req.UserIDs...)
+
+	reviews, err := s.prReviewersRepo.GetOpenReviewsByReviewers(ctx, reviewerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reassignments = utils.RebalanceReviews(reviews, donors, req.UserIDs)
+	for i := range reassignments {
+		reassignments[i].Meta = &domain.AssignmentMeta{
+			Strategy:           domain.AssignmentStrategyRebalance,
+			CandidateCount:     len(req.UserIDs),
+			ReplacedReviewerID: reassignments[i].OldReviewerID,
+		}
+	}
+
+	return reassignments, nil
+}
@@ -0,0 +1,177 @@
+package userService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserService_ActivateTeamMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockPrReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+	service := NewUserService(mockUserRepo, mockPrReviewersRepo, mockTeamRepo, nil)
+	ctx := context.Background()
+
+	team := &domain.Team{
+		TeamName: testTeamNameBackend,
+		Members: []domain.TeamMember{
+			{UserId: testUserID1, Username: "Bob", IsActive: false},
+			{UserId: testUserID2, Username: "Charlie", IsActive: true},
+			{UserId: testUserID3, Username: "Alice", IsActive: true},
+		},
+	}
+
+	t.Run("activate with rebalance", func(t *testing.T) {
+		reviews := []domain.OpenReview{
+			{PrID: "pr-1", AuthorID: "author", ReviewerID: testUserID2},
+			{PrID: "pr-2", AuthorID: "author", ReviewerID: testUserID2},
+			{PrID: "pr-3", AuthorID: "author", ReviewerID: testUserID2},
+		}
+
+		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), testTeamNameBackend).Return(team, nil)
+		mockPrReviewersRepo.EXPECT().
+			GetOpenReviewsByReviewers(gomock.Any(), []string{testUserID2, testUserID3, testUserID1}).
+			Return(reviews, nil)
+		mockTeamRepo.EXPECT().
+			ActivateTeamMembers(gomock.Any(), testTeamNameBackend, []string{testUserID1}, gomock.Len(1)).
+			Return([]string{testUserID1}, nil)
+
+		response, err := service.ActivateTeamMembers(ctx, domain.ActivateTeamMembersRequest{
+			TeamName:  testTeamNameBackend,
+			UserIDs:   []string{testUserID1},
+			Rebalance: true,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{testUserID1}, response.ActivatedUserIDs)
+		require.Len(t, response.Reassignments, 1)
+		assert.Equal(t, testUserID2, response.Reassignments[0].OldReviewerID)
+		assert.Equal(t, testUserID1, response.Reassignments[0].NewReviewerID)
+		require.NotNil(t, response.Reassignments[0].Meta)
+		assert.Equal(t, domain.AssignmentStrategyRebalance, response.Reassignments[0].Meta.Strategy)
+		assert.Nil(t, response.Reassignments[0].Meta.Seed)
+	})
+
+	t.Run("activate without rebalance", func(t *testing.T) {
+		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), testTeamNameBackend).Return(team, nil)
+		mockTeamRepo.EXPECT().
+			ActivateTeamMembers(gomock.Any(), testTeamNameBackend, []string{testUserID1}, gomock.Len(0)).
+			Return([]string{testUserID1}, nil)
+
+		response, err := service.ActivateTeamMembers(ctx, domain.ActivateTeamMembersRequest{
+			TeamName: testTeamNameBackend,
+			UserIDs:  []string{testUserID1},
+		})
+
+		require.NoError(t, err)
+		assert.Empty(t, response.Reassignments)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), "NonExistent").Return(nil, teamStorage.ErrTeamNotExists)
+
+		_, err := service.ActivateTeamMembers(ctx, domain.ActivateTeamMembersRequest{
+			TeamName: "NonExistent",
+			UserIDs:  []string{testUserID1},
+		})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
+	})
+
+	t.Run("user not member of team", func(t *testing.T) {
+		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), testTeamNameBackend).Return(team, nil)
+
+		_, err := service.ActivateTeamMembers(ctx, domain.ActivateTeamMembersRequest{
+			TeamName: testTeamNameBackend,
+			UserIDs:  []string{"stranger"},
+		})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.InvalidRequest, domainErr.Code)
+	})
+
+	t.Run("stale rebalance is recomputed", func(t *testing.T) {
+		staleReviews := []domain.OpenReview{
+			{PrID: "pr-1", AuthorID: "author", ReviewerID: testUserID2},
+			{PrID: "pr-2", AuthorID: "author", ReviewerID: testUserID2},
+		}
+		freshReviews := []domain.OpenReview{
+			{PrID: "pr-2", AuthorID: "author", ReviewerID: testUserID2},
+			{PrID: "pr-3", AuthorID: "author", ReviewerID: testUserID3},
+			{PrID: "pr-4", AuthorID: "author", ReviewerID: testUserID3},
+		}
+
+		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), testTeamNameBackend).Return(team, nil)
+		gomock.InOrder(
+			mockPrReviewersRepo.EXPECT().GetOpenReviewsByReviewers(gomock.Any(), gomock.Any()).Return(staleReviews, nil),
+			mockTeamRepo.EXPECT().
+				ActivateTeamMembers(gomock.Any(), testTeamNameBackend, []string{testUserID1}, gomock.Any()).
+				Return(nil, fmt.Errorf("%w: PR pr-1 is no longer open", teamStorage.ErrPlanStale)),
+			mockPrReviewersRepo.EXPECT().GetOpenReviewsByReviewers(gomock.Any(), gomock.Any()).Return(freshReviews, nil),
+			mockTeamRepo.EXPECT().
+				ActivateTeamMembers(gomock.Any(), testTeamNameBackend, []string{testUserID1}, gomock.Len(1)).
+				Return([]string{testUserID1}, nil),
+		)
+
+		response, err := service.ActivateTeamMembers(ctx, domain.ActivateTeamMembersRequest{
+			TeamName:  testTeamNameBackend,
+			UserIDs:   []string{testUserID1},
+			Rebalance: true,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, response.Reassignments, 1)
+		assert.Equal(t, testUserID3, response.Reassignments[0].OldReviewerID)
+	})
+
+	t.Run("rebalance stays stale - PLAN_STALE", func(t *testing.T) {
+		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), testTeamNameBackend).Return(team, nil)
+		mockPrReviewersRepo.EXPECT().
+			GetOpenReviewsByReviewers(gomock.Any(), gomock.Any()).
+			Return([]domain.OpenReview{{PrID: "pr-1", AuthorID: "author", ReviewerID: testUserID2}}, nil).
+			Times(maxActivateTeamMembersAttempts)
+		mockTeamRepo.EXPECT().
+			ActivateTeamMembers(gomock.Any(), testTeamNameBackend, []string{testUserID1}, gomock.Any()).
+			Return(nil, fmt.Errorf("%w: PR pr-1 is no longer open", teamStorage.ErrPlanStale)).
+			Times(maxActivateTeamMembersAttempts)
+
+		_, err := service.ActivateTeamMembers(ctx, domain.ActivateTeamMembersRequest{
+			TeamName:  testTeamNameBackend,
+			UserIDs:   []string{testUserID1},
+			Rebalance: true,
+		})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.PlanStale, domainErr.Code)
+	})
+
+	t.Run("error activating users", func(t *testing.T) {
+		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), testTeamNameBackend).Return(team, nil)
+		mockTeamRepo.EXPECT().
+			ActivateTeamMembers(gomock.Any(), testTeamNameBackend, gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("database error"))
+
+		_, err := service.ActivateTeamMembers(ctx, domain.ActivateTeamMembersRequest{
+			TeamName: testTeamNameBackend,
+			UserIDs:  []string{testUserID1},
+		})
+
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,73 @@
+package userService
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// ApplyDeactivationPlan применяет ранее показанный план ровно в том виде, в котором он был сохранён.
+// Если с момента превью изменились ревьюверы затронутых PR или состав команды, план отклоняется как устаревший
+func (s *UserServiceImpl) ApplyDeactivationPlan(
+	ctx context.Context,
+	req domain.ApplyDeactivationPlanRequest,
+) (*domain.DeactivateTeamMembersResponse, error) {
+	planID, err := uuid.Parse(req.PlanID)
+	if err != nil {
+		return nil, domain.NewError(domain.InvalidRequest, "invalid plan_id")
+	}
+
+	plan, err := s.teamRepo.GetDeactivationPlan(ctx, planID)
+	if err != nil {
+		if errors.Is(err, teamStorage.ErrDeactivationPlanNotExists) {
+			return nil, domain.NewError(domain.NotFound, "deactivation plan not found")
+		}
+		return nil, err
+	}
+
+	if err = checkPlanIsCurrent(plan); err != nil {
+		return nil, err
+	}
+
+	deactivatedUserIDs, err := s.teamRepo.ApplyDeactivationPlan(ctx, plan)
+	if err != nil {
+		if errors.Is(err, teamStorage.ErrDeactivationPlanAlreadyApplied) {
+			return nil, domain.NewError(domain.PlanAlreadyApplied, "deactivation plan is already applied")
+		}
+		if errors.Is(err, teamStorage.ErrPlanStale) {
+			return nil, domain.NewError(domain.PlanStale, err.Error()+", preview again")
+		}
+		return nil, err
+	}
+
+	logger.Logger.Infow("deactivation plan applied",
+		"plan_id", plan.PlanID,
+		"team_name", plan.TeamName,
+		"deactivated_count", len(deactivatedUserIDs),
+		"reassignments_count", len(plan.Reassignments),
+	)
+
+	return &domain.DeactivateTeamMembersResponse{
+		DeactivatedUserIDs: deactivatedUserIDs,
+		Reassignments:      plan.Reassignments,
+	}, nil
+}
+
+// checkPlanIsCurrent проверяет, что план не применён и не истёк. Актуальность состава команды
+// и ревьюверов проверяется в транзакции применения (см. TeamStorage.ApplyDeactivationPlan)
+func checkPlanIsCurrent(plan *domain.DeactivationPlan) error {
+	if plan.AppliedAt != nil {
+		return domain.NewError(domain.PlanAlreadyApplied, "deactivation plan is already applied")
+	}
+
+	if time.Now().After(plan.ExpiresAt) {
+		return domain.NewError(domain.PlanStale, "deactivation plan has expired, preview it again")
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package userService
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserService_ApplyDeactivationPlan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockPrReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+	service := NewUserService(mockUserRepo, mockPrReviewersRepo, mockTeamRepo, nil)
+	ctx := context.Background()
+
+	newPlan := func(planID uuid.UUID) *domain.DeactivationPlan {
+		return &domain.DeactivationPlan{
+			PlanID:             planID.String(),
+			TeamName:           testTeamNameBackend,
+			DeactivatedUserIDs: []string{testUserID2},
+			Reassignments: []domain.ReviewerReassignment{{
+				PrID:          testPRID123,
+				OldReviewerID: testUserID2,
+				NewReviewerID: testUserID3,
+			}},
+			ExpiresAt:         time.Now().Add(time.Hour),
+			ReviewersSnapshot: map[string][]string{testPRID123: {testUserID2}},
+		}
+	}
+
+	t.Run("applies plan exactly as previewed", func(t *testing.T) {
+		planID := uuid.New()
+		plan := newPlan(planID)
+
+		mockTeamRepo.EXPECT().GetDeactivationPlan(gomock.Any(), planID).Return(plan, nil)
+		mockTeamRepo.EXPECT().
+			ApplyDeactivationPlan(gomock.Any(), plan).
+			Return([]string{testUserID2}, nil)
+
+		response, err := service.ApplyDeactivationPlan(ctx, domain.ApplyDeactivationPlanRequest{PlanID: planID.String()})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{testUserID2}, response.DeactivatedUserIDs)
+		assert.Equal(t, plan.Reassignments, response.Reassignments)
+	})
+
+	t.Run("reviewers changed since preview", func(t *testing.T) {
+		planID := uuid.New()
+
+		plan := newPlan(planID)
+
+		mockTeamRepo.EXPECT().GetDeactivationPlan(gomock.Any(), planID).Return(plan, nil)
+		mockTeamRepo.EXPECT().
+			ApplyDeactivationPlan(gomock.Any(), plan).
+			Return(nil, fmt.Errorf("%w: reviewers of PR %s have changed", teamStorage.ErrPlanStale, testPRID123))
+
+		_, err := service.ApplyDeactivationPlan(ctx, domain.ApplyDeactivationPlanRequest{PlanID: planID.String()})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.PlanStale, domainErr.Code)
+	})
+
+	t.Run("expired plan", func(t *testing.T) {
+		planID := uuid.New()
+		plan := newPlan(planID)
+		plan.ExpiresAt = time.Now().Add(-time.Minute)
+
+		mockTeamRepo.EXPECT().GetDeactivationPlan(gomock.Any(), planID).Return(plan, nil)
+
+		_, err := service.ApplyDeactivationPlan(ctx, domain.ApplyDeactivationPlanRequest{PlanID: planID.String()})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.PlanStale, domainErr.Code)
+	})
+
+	t.Run("plan already applied", func(t *testing.T) {
+		planID := uuid.New()
+		plan := newPlan(planID)
+		appliedAt := time.Now()
+		plan.AppliedAt = &appliedAt
+
+		mockTeamRepo.EXPECT().GetDeactivationPlan(gomock.Any(), planID).Return(plan, nil)
+
+		_, err := service.ApplyDeactivationPlan(ctx, domain.ApplyDeactivationPlanRequest{PlanID: planID.String()})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.PlanAlreadyApplied, domainErr.Code)
+	})
+
+	t.Run("concurrently applied plan", func(t *testing.T) {
+		planID := uuid.New()
+		plan := newPlan(planID)
+
+		mockTeamRepo.EXPECT().GetDeactivationPlan(gomock.Any(), planID).Return(plan, nil)
+		mockTeamRepo.EXPECT().
+			ApplyDeactivationPlan(gomock.Any(), plan).
+			Return(nil, teamStorage.ErrDeactivationPlanAlreadyApplied)
+
+		_, err := service.ApplyDeactivationPlan(ctx, domain.ApplyDeactivationPlanRequest{PlanID: planID.String()})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.PlanAlreadyApplied, domainErr.Code)
+	})
+
+	t.Run("plan not found", func(t *testing.T) {
+		planID := uuid.New()
+
+		mockTeamRepo.EXPECT().
+			GetDeactivationPlan(gomock.Any(), planID).
+			Return(nil, teamStorage.ErrDeactivationPlanNotExists)
+
+		_, err := service.ApplyDeactivationPlan(ctx, domain.ApplyDeactivationPlanRequest{PlanID: planID.String()})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
+	})
+
+	t.Run("invalid plan id", func(t *testing.T) {
+		_, err := service.ApplyDeactivationPlan(ctx, domain.ApplyDeactivationPlanRequest{PlanID: "not-a-uuid"})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.InvalidRequest, domainErr.Code)
+	})
+}
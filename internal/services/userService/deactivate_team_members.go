@@ -3,11 +3,11 @@ package userService
 import (
 	"context"
 	"errors"
-	"net/http"
+	"sort"
 
-	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
 	"github.com/nedokyrill/avito-pr-api/pkg/utils"
 	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
 )
@@ -15,42 +15,51 @@ import (
 // DeactivateTeamMembers выполняет массовую деактивацию пользователей команды.
 // При деактивации ревьюверов автоматически переназначает их на других активных участников команды
 // для всех открытых PR, где деактивируемые пользователи были назначены ревьюверами.
-func (s *UserServiceImpl) DeactivateTeamMembers(c *gin.Context) {
-	ctx := c.Request.Context()
-
-	var req domain.DeactivateTeamMembersRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
-			domain.InvalidRequest,
-			"invalid request body",
-		))
-		return
+// Ошибки валидации возвращаются как *domain.Error, остальные - ошибки хранилища
+func (s *UserServiceImpl) DeactivateTeamMembers(
+	ctx context.Context,
+	req domain.DeactivateTeamMembersRequest,
+) (*domain.DeactivateTeamMembersResponse, error) {
+	plan, err := s.planDeactivation(ctx, req.TeamName, req.UserIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	team, err := s.teamRepo.GetTeamByName(ctx, req.TeamName)
+	// Выполнение деактивации и переназначений
+	deactivatedUserIDs, err := s.teamRepo.DeactivateTeamMembers(ctx, req.TeamName, req.UserIDs, plan.Reassignments)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
-				domain.NotFound,
-				"team not found",
-			))
-			return
+		return nil, err
+	}
+
+	logger.Logger.Infow("team members deactivated",
+		"team_name", req.TeamName,
+		"deactivated_count", len(deactivatedUserIDs),
+		"reassignments_count", len(plan.Reassignments),
+	)
+
+	return &domain.DeactivateTeamMembersResponse{
+		DeactivatedUserIDs: deactivatedUserIDs,
+		Reassignments:      plan.Reassignments,
+	}, nil
+}
+
+// planDeactivation проверяет запрос и строит план деактивации, ничего не записывая в БД
+func (s *UserServiceImpl) planDeactivation(
+	ctx context.Context,
+	teamName string,
+	userIDs []string,
+) (*domain.DeactivationPlan, error) {
+	team, err := s.teamRepo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, teamStorage.ErrTeamNotExists) {
+			return nil, domain.NewError(domain.NotFound, "team not found")
 		}
-		logger.Logger.Error("error getting team: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrDeactivatingUsersMsg,
-		))
-		return
+		return nil, err
 	}
 
 	// Нельзя деактивировать всех участников команды, некому будет ревьюить
-	if len(req.UserIDs) == 0 || len(req.UserIDs) == len(team.Members) {
-		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
-			domain.InvalidRequest,
-			"cannot deactivate all team members",
-		))
-		return
+	if len(userIDs) == 0 || len(userIDs) == len(team.Members) {
+		return nil, domain.NewError(domain.InvalidRequest, "cannot deactivate all team members")
 	}
 
 	// Проверка, что все указанные пользователи действительно являются членами команды
@@ -59,53 +68,64 @@ func (s *UserServiceImpl) DeactivateTeamMembers(c *gin.Context) {
 		teamMemberIDs[member.UserId] = struct{}{}
 	}
 
-	for _, userID := range req.UserIDs {
+	for _, userID := range userIDs {
 		_, ok := teamMemberIDs[userID]
 		if !ok {
-			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			return nil, domain.NewError(
 				domain.InvalidRequest,
-				"user "+userID+" is not a member of team "+req.TeamName,
-			))
-			return
+				"user "+userID+" is not a member of team "+teamName,
+			)
 		}
 	}
 
 	// Поиск всех открытых PR, где деактивируемые пользователи являются ревьюверами
-	prMap := s.getOpenPRsForUsers(ctx, req.UserIDs)
+	prMap := s.getOpenPRsForUsers(ctx, userIDs)
 
 	// Построение плана переназначения ревьюверов
 	// Для каждого открытого PR определяем, кого нужно заменить и на кого
 	// Алгоритм выбирает случайных активных участников команды, исключая автора
 	// Также проверяет, что после переназначения ни один PR не останется без ревьюверов
-	reassignments, errResp := s.buildReassignmentsPlan(ctx, prMap, req.UserIDs, team)
-	if errResp != nil {
-		c.JSON(http.StatusBadRequest, errResp)
-		return
-	}
-
-	// Выполнение деактивации и переназначений
-	deactivatedUserIDs, err := s.teamRepo.DeactivateTeamMembers(ctx, req.TeamName, req.UserIDs, reassignments)
+	snapshot := make(map[string][]string, len(prMap))
+	reassignments, err := s.buildReassignmentsPlan(ctx, prMap, userIDs, team, snapshot)
 	if err != nil {
-		logger.Logger.Error("error deactivating users atomically: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrDeactivatingUsersMsg,
-		))
-		return
+		return nil, err
+	}
+	if reassignments == nil {
+		reassignments = make([]domain.ReviewerReassignment, 0)
 	}
 
-	logger.Logger.Infow("team members deactivated",
-		"team_name", req.TeamName,
-		"deactivated_count", len(deactivatedUserIDs),
-		"reassignments_count", len(reassignments),
-	)
-
-	response := domain.DeactivateTeamMembersResponse{
-		DeactivatedUserIDs: deactivatedUserIDs,
+	return &domain.DeactivationPlan{
+		TeamName:           teamName,
+		DeactivatedUserIDs: userIDs,
 		Reassignments:      reassignments,
+		PrsLosingReviewers: prsLosingReviewers(reassignments, snapshot),
+		ReviewersSnapshot:  snapshot,
+	}, nil
+}
+
+// prsLosingReviewers собирает PR, у которых ревьюверы снимаются без замены
+func prsLosingReviewers(
+	reassignments []domain.ReviewerReassignment,
+	snapshot map[string][]string,
+) []domain.PrLosingReviewers {
+	lost := make(map[string][]string)
+	for _, reassignment := range reassignments {
+		if reassignment.NewReviewerID == "" {
+			lost[reassignment.PrID] = append(lost[reassignment.PrID], reassignment.OldReviewerID)
+		}
+	}
+
+	result := make([]domain.PrLosingReviewers, 0, len(lost))
+	for prID, lostReviewerIDs := range lost {
+		result = append(result, domain.PrLosingReviewers{
+			PrID:               prID,
+			LostReviewerIDs:    lostReviewerIDs,
+			RemainingReviewers: len(snapshot[prID]) - len(lostReviewerIDs),
+		})
 	}
+	sort.Slice(result, func(i, j int) bool { return result[i].PrID < result[j].PrID })
 
-	c.JSON(http.StatusOK, response)
+	return result
 }
 
 func (s *UserServiceImpl) getOpenPRsForUsers(ctx context.Context, userIDs []string) map[string]domain.PullRequestShort {
@@ -137,7 +157,8 @@ func (s *UserServiceImpl) buildReassignmentsPlan(
 	prMap map[string]domain.PullRequestShort,
 	usersToDeactivate []string,
 	team *domain.Team,
-) ([]domain.ReviewerReassignment, *domain.ErrorResponse) {
+	snapshot map[string][]string,
+) ([]domain.ReviewerReassignment, error) {
 	var reassignments []domain.ReviewerReassignment
 
 	// Множество деактивируемых пользователей
@@ -164,6 +185,8 @@ func (s *UserServiceImpl) buildReassignmentsPlan(
 				"error", err)
 			continue
 		}
+		// Запоминаем ревьюверов на момент построения плана - по ним проверяется актуальность превью
+		snapshot[pr.PullRequestId] = currentReviewers
 
 		// Собираем ревьюверов, которых нужно заменить
 		reviewersToReplace := make([]string, 0)
@@ -184,7 +207,8 @@ func (s *UserServiceImpl) buildReassignmentsPlan(
 		}
 
 		// здесь используем не заданное число ревьюеров (2), а столько, сколько их уже было
-		candidates := utils.RandSelectReviewers(availableMembers, pr.AuthorId, len(reviewersToReplace))
+		seed := utils.NewSeed()
+		candidates := utils.RandSelectReviewersWithSeed(availableMembers, pr.AuthorId, len(reviewersToReplace), seed)
 
 		// Фильтруем кандидатов: исключаем уже назначенных ревьюверов
 		availableCandidates := make([]string, 0)
@@ -195,6 +219,15 @@ func (s *UserServiceImpl) buildReassignmentsPlan(
 			}
 		}
 
+		// Для объяснения назначения: кто из команды не мог стать заменой и почему
+		exclude := utils.ExcludeWithReason(usersToDeactivate, domain.ExclusionReasonInactive)
+		for _, r := range currentReviewers {
+			if _, ok := exclude[r]; !ok {
+				exclude[r] = domain.ExclusionReasonAlreadyAssigned
+			}
+		}
+		candidatePool, exclusions := utils.EvaluateReviewerCandidates([]domain.Team{*team}, pr.AuthorId, exclude)
+
 		// Распределяем кандидатов по ревьюверам последовательно
 		candidateIndex := 0
 		addedCount := 0 // Считаем, сколько ревьюверов реально будет назначено
@@ -211,22 +244,31 @@ func (s *UserServiceImpl) buildReassignmentsPlan(
 			// Если кандидатов нет, newReviewerID останется пустым
 			// Это означает, что ревьювер будет удален без замены
 
-			reassignments = append(reassignments, domain.ReviewerReassignment{
+			reassignment := domain.ReviewerReassignment{
 				PrID:          pr.PullRequestId,
 				OldReviewerID: reviewerID,
 				NewReviewerID: newReviewerID,
-			})
+			}
+			if newReviewerID != "" {
+				reassignment.Meta = &domain.AssignmentMeta{
+					Strategy:           domain.AssignmentStrategyDeactivation,
+					CandidateCount:     len(candidatePool),
+					Excluded:           exclusions,
+					Seed:               &seed,
+					ReplacedReviewerID: reviewerID,
+				}
+			}
+			reassignments = append(reassignments, reassignment)
 		}
 
 		// Проверяем, что после переназначения пр не останется без ревьюверов
 		finalReviewerCount := len(currentReviewers) - len(reviewersToReplace) + addedCount
 
 		if finalReviewerCount == 0 {
-			errRes := domain.NewErrorResponse(
+			return nil, domain.NewError(
 				domain.NoCandidate,
 				"cannot deactivate reviewers: PR "+pr.PullRequestId+" would be left without reviewers",
 			)
-			return nil, &errRes
 		}
 	}
 
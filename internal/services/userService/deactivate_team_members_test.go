@@ -1,14 +1,10 @@
 package userService
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"net/http"
-	"net/http/httptest"
-	"strings"
 	"testing"
 
-	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/jackc/pgx/v5"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
@@ -32,7 +28,8 @@ func TestUserService_DeactivateTeamMembers(t *testing.T) {
 	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
 	mockPrReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
 	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
-	service := NewUserService(mockUserRepo, mockPrReviewersRepo, mockTeamRepo)
+	service := NewUserService(mockUserRepo, mockPrReviewersRepo, mockTeamRepo, nil)
+	ctx := context.Background()
 
 	t.Run("successfully deactivate team members with reassignments", func(t *testing.T) {
 		teamName := testTeamNameBackend
@@ -42,11 +39,6 @@ func TestUserService_DeactivateTeamMembers(t *testing.T) {
 		authorID := testUserID3
 		userID4 := "user-4" // Дополнительный активный участник для замены ревьювера
 
-		requestBody := `{
-			"team_name": "` + teamName + `",
-			"user_ids": ["` + userID1 + `", "` + userID2 + `"]
-		}`
-
 		team := &domain.Team{
 			TeamName: teamName,
 			Members: []domain.TeamMember{
@@ -66,11 +58,6 @@ func TestUserService_DeactivateTeamMembers(t *testing.T) {
 			},
 		}
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/users/deactivate", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
-
 		mockTeamRepo.EXPECT().
 			GetTeamByName(gomock.Any(), teamName).
 			Return(team, nil)
@@ -91,52 +78,32 @@ func TestUserService_DeactivateTeamMembers(t *testing.T) {
 			DeactivateTeamMembers(gomock.Any(), teamName, []string{userID1, userID2}, gomock.Any()).
 			Return([]string{userID1, userID2}, nil)
 
-		service.DeactivateTeamMembers(c)
+		response, err := service.DeactivateTeamMembers(ctx, domain.DeactivateTeamMembersRequest{
+			TeamName: teamName,
+			UserIDs:  []string{userID1, userID2},
+		})
 
-		require.Equal(t, http.StatusOK, w.Code)
-		var response domain.DeactivateTeamMembersResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 		assert.Len(t, response.DeactivatedUserIDs, 2)
 		assert.Contains(t, response.DeactivatedUserIDs, userID1)
 		assert.Contains(t, response.DeactivatedUserIDs, userID2)
 	})
 
-	t.Run("invalid request body", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/users/deactivate", strings.NewReader("invalid json"))
-		c.Request.Header.Set("Content-Type", "application/json")
-
-		service.DeactivateTeamMembers(c)
-
-		require.Equal(t, http.StatusBadRequest, w.Code)
-		var response domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		require.NoError(t, err)
-		assert.Equal(t, domain.InvalidRequest, response.Error.Code)
-	})
-
 	t.Run("team not found", func(t *testing.T) {
 		teamName := "NonExistent"
 
-		requestBody := `{
-			"team_name": "` + teamName + `",
-			"user_ids": ["` + testUserID1 + `"]
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/users/deactivate", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
-
 		mockTeamRepo.EXPECT().
 			GetTeamByName(gomock.Any(), teamName).
 			Return(nil, pgx.ErrNoRows)
 
-		service.DeactivateTeamMembers(c)
+		_, err := service.DeactivateTeamMembers(ctx, domain.DeactivateTeamMembersRequest{
+			TeamName: teamName,
+			UserIDs:  []string{testUserID1},
+		})
 
-		require.Equal(t, http.StatusNotFound, w.Code)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
 	})
 
 	t.Run("cannot deactivate all team members", func(t *testing.T) {
@@ -144,11 +111,6 @@ func TestUserService_DeactivateTeamMembers(t *testing.T) {
 		userID1 := testUserID1
 		userID2 := testUserID2
 
-		requestBody := `{
-			"team_name": "` + teamName + `",
-			"user_ids": ["` + userID1 + `", "` + userID2 + `"]
-		}`
-
 		team := &domain.Team{
 			TeamName: teamName,
 			Members: []domain.TeamMember{
@@ -157,22 +119,18 @@ func TestUserService_DeactivateTeamMembers(t *testing.T) {
 			},
 		}
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/users/deactivate", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
-
 		mockTeamRepo.EXPECT().
 			GetTeamByName(gomock.Any(), teamName).
 			Return(team, nil)
 
-		service.DeactivateTeamMembers(c)
+		_, err := service.DeactivateTeamMembers(ctx, domain.DeactivateTeamMembersRequest{
+			TeamName: teamName,
+			UserIDs:  []string{userID1, userID2},
+		})
 
-		require.Equal(t, http.StatusBadRequest, w.Code)
-		var response domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		require.NoError(t, err)
-		assert.Equal(t, domain.InvalidRequest, response.Error.Code)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.InvalidRequest, domainErr.Code)
 	})
 
 	t.Run("user not member of team", func(t *testing.T) {
@@ -180,11 +138,6 @@ func TestUserService_DeactivateTeamMembers(t *testing.T) {
 		userID1 := testUserID1
 		userID2 := "user-from-other-team"
 
-		requestBody := `{
-			"team_name": "` + teamName + `",
-			"user_ids": ["` + userID1 + `", "` + userID2 + `"]
-		}`
-
 		team := &domain.Team{
 			TeamName: teamName,
 			Members: []domain.TeamMember{
@@ -193,56 +146,42 @@ func TestUserService_DeactivateTeamMembers(t *testing.T) {
 			},
 		}
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/users/deactivate", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
-
 		mockTeamRepo.EXPECT().
 			GetTeamByName(gomock.Any(), teamName).
 			Return(team, nil)
 
-		service.DeactivateTeamMembers(c)
+		_, err := service.DeactivateTeamMembers(ctx, domain.DeactivateTeamMembersRequest{
+			TeamName: teamName,
+			UserIDs:  []string{userID1, userID2},
+		})
 
-		require.Equal(t, http.StatusBadRequest, w.Code)
-		var response domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		require.NoError(t, err)
-		assert.Equal(t, domain.InvalidRequest, response.Error.Code)
-		assert.Contains(t, response.Error.Message, "cannot deactivate all team members")
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.InvalidRequest, domainErr.Code)
+		assert.Contains(t, domainErr.Message, "cannot deactivate all team members")
 	})
 
 	t.Run("error getting team", func(t *testing.T) {
 		teamName := testTeamNameBackend
 
-		requestBody := `{
-			"team_name": "` + teamName + `",
-			"user_ids": ["` + testUserID1 + `"]
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/users/deactivate", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
-
 		mockTeamRepo.EXPECT().
 			GetTeamByName(gomock.Any(), teamName).
 			Return(nil, errors.New("database error"))
 
-		service.DeactivateTeamMembers(c)
+		_, err := service.DeactivateTeamMembers(ctx, domain.DeactivateTeamMembersRequest{
+			TeamName: teamName,
+			UserIDs:  []string{testUserID1},
+		})
 
-		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Error(t, err)
+		var domainErr *domain.Error
+		assert.False(t, errors.As(err, &domainErr))
 	})
 
 	t.Run("error deactivating users", func(t *testing.T) {
 		teamName := testTeamNameBackend
 		userID1 := testUserID1
 
-		requestBody := `{
-			"team_name": "` + teamName + `",
-			"user_ids": ["` + userID1 + `"]
-		}`
-
 		team := &domain.Team{
 			TeamName: teamName,
 			Members: []domain.TeamMember{
@@ -251,11 +190,6 @@ func TestUserService_DeactivateTeamMembers(t *testing.T) {
 			},
 		}
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPost, "/users/deactivate", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
-
 		mockTeamRepo.EXPECT().
 			GetTeamByName(gomock.Any(), teamName).
 			Return(team, nil)
@@ -268,9 +202,11 @@ func TestUserService_DeactivateTeamMembers(t *testing.T) {
 			DeactivateTeamMembers(gomock.Any(), teamName, gomock.Any(), gomock.Any()).
 			Return(nil, errors.New("database error"))
 
-		service.DeactivateTeamMembers(c)
+		_, err := service.DeactivateTeamMembers(ctx, domain.DeactivateTeamMembersRequest{
+			TeamName: teamName,
+			UserIDs:  []string{userID1},
+		})
 
-		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Error(t, err)
 	})
 }
-
@@ -0,0 +1,123 @@
+package userService
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// maxDeactivateUserAttempts - сколько раз план деактивации пересчитывается, если ревью пользователя
+// успели измениться между расчётом и применением
+const maxDeactivateUserAttempts = 3
+
+// DeactivateUser деактивирует пользователя независимо от его команд (деактивация из IdP всегда проходит).
+// Открытые ревью переназначаются на активных участников его команд, где кандидат есть; без кандидата
+// ревьювер снимается, а PR помечается как нуждающийся в ревьюверах
+func (s *UserServiceImpl) DeactivateUser(ctx context.Context, userID string) (*domain.DeactivateTeamMembersResponse, error) {
+	for attempt := 1; ; attempt++ {
+		plan, err := s.planUserDeactivation(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		deactivatedUserIDs, err := s.teamRepo.DeactivateUser(ctx, plan)
+		if err != nil {
+			if errors.Is(err, teamStorage.ErrPlanStale) && attempt < maxDeactivateUserAttempts {
+				continue
+			}
+			return nil, err
+		}
+
+		logger.Logger.Infow("user deactivated",
+			"user_id", userID,
+			"reassignments_count", len(plan.Reassignments),
+		)
+
+		return &domain.DeactivateTeamMembersResponse{
+			DeactivatedUserIDs: deactivatedUserIDs,
+			Reassignments:      plan.Reassignments,
+		}, nil
+	}
+}
+
+// planUserDeactivation строит план деактивации одного пользователя: без проверок состава команды
+// и без отказа, если для какого-то PR не нашлось замены
+func (s *UserServiceImpl) planUserDeactivation(ctx context.Context, userID string) (*domain.DeactivationPlan, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewError(domain.NotFound, "user not found")
+		}
+		return nil, err
+	}
+
+	chains := make([][]domain.Team, 0, len(user.TeamNames))
+	for _, teamName := range user.TeamNames {
+		team, err := s.teamRepo.GetTeamByName(ctx, teamName)
+		if err != nil {
+			if errors.Is(err, teamStorage.ErrTeamNotExists) {
+				continue
+			}
+			return nil, err
+		}
+		chains = append(chains, []domain.Team{*team})
+	}
+	teams := utils.MergeTeamLevels(chains)
+
+	reviews, err := s.prReviewersRepo.GetOpenReviewsByReviewers(ctx, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make(map[string][]string, len(reviews))
+	reassignments := make([]domain.ReviewerReassignment, 0, len(reviews))
+	for _, review := range reviews {
+		currentReviewers, err := s.prReviewersRepo.GetAssignedReviewers(ctx, review.PrID)
+		if err != nil {
+			return nil, err
+		}
+		snapshot[review.PrID] = currentReviewers
+
+		exclude := utils.ExcludeWithReason(currentReviewers, domain.ExclusionReasonAlreadyAssigned)
+		exclude[userID] = domain.ExclusionReasonInactive
+		candidatePool, exclusions := utils.EvaluateReviewerCandidates(teams, review.AuthorID, exclude)
+
+		reassignment := domain.ReviewerReassignment{
+			PrID:          review.PrID,
+			OldReviewerID: userID,
+		}
+
+		seed := utils.NewSeed()
+		replacements := utils.RandSelectReviewersWithFallbackSeed(
+			teams,
+			review.AuthorID,
+			append([]string{userID}, currentReviewers...),
+			1,
+			seed,
+		)
+		if len(replacements) > 0 {
+			reassignment.NewReviewerID = replacements[0]
+			reassignment.Meta = &domain.AssignmentMeta{
+				Strategy:           domain.AssignmentStrategyDeactivation,
+				CandidateCount:     len(candidatePool),
+				Excluded:           exclusions,
+				Seed:               &seed,
+				ReplacedReviewerID: userID,
+			}
+		}
+		reassignments = append(reassignments, reassignment)
+	}
+
+	return &domain.DeactivationPlan{
+		TeamName:           user.TeamName,
+		DeactivatedUserIDs: []string{userID},
+		Reassignments:      reassignments,
+		PrsLosingReviewers: prsLosingReviewers(reassignments, snapshot),
+		ReviewersSnapshot:  snapshot,
+	}, nil
+}
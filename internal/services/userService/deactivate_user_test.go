@@ -0,0 +1,145 @@
+package userService
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserService_DeactivateUser(t *testing.T) {
+	ctx := context.Background()
+
+	newService := func(t *testing.T) (
+		*UserServiceImpl,
+		*mocks.MockUserRepositoryInterface,
+		*mocks.MockPrReviewersRepositoryInterface,
+		*mocks.MockTeamRepositoryInterface,
+	) {
+		ctrl := gomock.NewController(t)
+		userRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		prReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
+		teamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+		return NewUserService(userRepo, prReviewersRepo, teamRepo, nil), userRepo, prReviewersRepo, teamRepo
+	}
+
+	user := &domain.User{
+		UserId:    testUserID1,
+		Username:  "Bob",
+		TeamName:  testTeamNameBackend,
+		TeamNames: []string{testTeamNameBackend},
+		IsActive:  true,
+	}
+
+	t.Run("reviews reassigned to active team member", func(t *testing.T) {
+		service, userRepo, prReviewersRepo, teamRepo := newService(t)
+
+		userRepo.EXPECT().GetUserByID(gomock.Any(), testUserID1).Return(user, nil)
+		teamRepo.EXPECT().GetTeamByName(gomock.Any(), testTeamNameBackend).Return(&domain.Team{
+			TeamName: testTeamNameBackend,
+			Members: []domain.TeamMember{
+				{UserId: testUserID1, IsActive: true},
+				{UserId: testUserID2, IsActive: true},
+				{UserId: testUserID3, IsActive: true},
+			},
+		}, nil)
+		prReviewersRepo.EXPECT().
+			GetOpenReviewsByReviewers(gomock.Any(), []string{testUserID1}).
+			Return([]domain.OpenReview{{PrID: testPRID123, AuthorID: testUserID3, ReviewerID: testUserID1}}, nil)
+		prReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), testPRID123).Return([]string{testUserID1}, nil)
+		teamRepo.EXPECT().DeactivateUser(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, plan *domain.DeactivationPlan) ([]string, error) {
+				require.Len(t, plan.Reassignments, 1)
+				assert.Equal(t, testUserID2, plan.Reassignments[0].NewReviewerID)
+				assert.Equal(t, map[string][]string{testPRID123: {testUserID1}}, plan.ReviewersSnapshot)
+				return []string{testUserID1}, nil
+			})
+
+		result, err := service.DeactivateUser(ctx, testUserID1)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{testUserID1}, result.DeactivatedUserIDs)
+	})
+
+	t.Run("sole member is deactivated and reviewer removed without candidate", func(t *testing.T) {
+		service, userRepo, prReviewersRepo, teamRepo := newService(t)
+
+		userRepo.EXPECT().GetUserByID(gomock.Any(), testUserID1).Return(user, nil)
+		teamRepo.EXPECT().GetTeamByName(gomock.Any(), testTeamNameBackend).Return(&domain.Team{
+			TeamName: testTeamNameBackend,
+			Members:  []domain.TeamMember{{UserId: testUserID1, IsActive: true}},
+		}, nil)
+		prReviewersRepo.EXPECT().
+			GetOpenReviewsByReviewers(gomock.Any(), []string{testUserID1}).
+			Return([]domain.OpenReview{{PrID: testPRID123, AuthorID: testUserID3, ReviewerID: testUserID1}}, nil)
+		prReviewersRepo.EXPECT().GetAssignedReviewers(gomock.Any(), testPRID123).Return([]string{testUserID1}, nil)
+		teamRepo.EXPECT().DeactivateUser(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, plan *domain.DeactivationPlan) ([]string, error) {
+				require.Len(t, plan.Reassignments, 1)
+				assert.Empty(t, plan.Reassignments[0].NewReviewerID)
+				require.Len(t, plan.PrsLosingReviewers, 1)
+				assert.Equal(t, testPRID123, plan.PrsLosingReviewers[0].PrID)
+				return []string{testUserID1}, nil
+			})
+
+		result, err := service.DeactivateUser(ctx, testUserID1)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{testUserID1}, result.DeactivatedUserIDs)
+	})
+
+	t.Run("user without team is deactivated", func(t *testing.T) {
+		service, userRepo, prReviewersRepo, teamRepo := newService(t)
+
+		userRepo.EXPECT().GetUserByID(gomock.Any(), testUserID1).
+			Return(&domain.User{UserId: testUserID1, TeamNames: []string{}, IsActive: true}, nil)
+		prReviewersRepo.EXPECT().
+			GetOpenReviewsByReviewers(gomock.Any(), []string{testUserID1}).
+			Return([]domain.OpenReview{}, nil)
+		teamRepo.EXPECT().DeactivateUser(gomock.Any(), gomock.Any()).Return([]string{testUserID1}, nil)
+
+		result, err := service.DeactivateUser(ctx, testUserID1)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{testUserID1}, result.DeactivatedUserIDs)
+	})
+
+	t.Run("stale plan is recalculated", func(t *testing.T) {
+		service, userRepo, prReviewersRepo, teamRepo := newService(t)
+
+		userRepo.EXPECT().GetUserByID(gomock.Any(), testUserID1).Return(user, nil).Times(2)
+		teamRepo.EXPECT().GetTeamByName(gomock.Any(), testTeamNameBackend).
+			Return(&domain.Team{TeamName: testTeamNameBackend}, nil).Times(2)
+		prReviewersRepo.EXPECT().
+			GetOpenReviewsByReviewers(gomock.Any(), []string{testUserID1}).
+			Return([]domain.OpenReview{}, nil).Times(2)
+		gomock.InOrder(
+			teamRepo.EXPECT().DeactivateUser(gomock.Any(), gomock.Any()).
+				Return(nil, fmt.Errorf("%w: open reviews of deactivated users have changed", teamStorage.ErrPlanStale)),
+			teamRepo.EXPECT().DeactivateUser(gomock.Any(), gomock.Any()).Return([]string{testUserID1}, nil),
+		)
+
+		_, err := service.DeactivateUser(ctx, testUserID1)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		service, userRepo, _, _ := newService(t)
+
+		userRepo.EXPECT().GetUserByID(gomock.Any(), testUserID1).Return(nil, pgx.ErrNoRows)
+
+		_, err := service.DeactivateUser(ctx, testUserID1)
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
+	})
+}
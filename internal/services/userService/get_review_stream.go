@@ -0,0 +1,160 @@
+package userService
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// ReviewStreamConfig - параметры потока очереди ревью. Поток читает закоммиченные события из outbox
+// (см. OutboxStorage.GetUserReviewEvents), поэтому работает на любом экземпляре сервиса. Heartbeat - как
+// часто слать комментарий-пинг, чтобы прокси не закрывали простаивающее соединение
+type ReviewStreamConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Heartbeat    time.Duration
+}
+
+func DefaultReviewStreamConfig() ReviewStreamConfig {
+	return ReviewStreamConfig{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		Heartbeat:    15 * time.Second,
+	}
+}
+
+// StreamReviews отдаёт в sink изменения очереди ревью пользователя: назначение (assigned), снятие
+// (unassigned) и смену статуса PR, где он ревьювер (status_changed). ID события - позиция в потоке outbox;
+// с lastEventID поток продолжается после неё, без него - с текущего момента. Поток живёт до отмены ctx
+// или CloseStreams; ошибка возвращается только до sink.Open, после - при ошибке БД поток закрывается,
+// и клиент переподключается с последним ID
+func (s *UserServiceImpl) StreamReviews(
+	ctx context.Context,
+	userID string,
+	lastEventID *int64,
+	sink domain.ReviewStreamSink,
+) error {
+	_, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.NewError(domain.NotFound, "user not found")
+		}
+		return err
+	}
+
+	var cursor int64
+	if lastEventID != nil {
+		cursor = *lastEventID
+	} else {
+		cursor, err = s.outboxRepo.GetLatestStreamPosition(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	sink.Open()
+	logger.Logger.Infow("review stream opened", "user_id", userID, "cursor", cursor)
+
+	poll := time.NewTicker(s.streamConfig.PollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(s.streamConfig.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		events, err := s.outboxRepo.GetUserReviewEvents(ctx, userID, cursor, s.streamConfig.BatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Logger.Error("error reading review stream events: ", err)
+			}
+			return nil
+		}
+
+		for _, event := range events {
+			cursor = event.StreamPosition
+			streamEvent, ok := reviewStreamEvent(userID, event)
+			if !ok {
+				continue
+			}
+
+			if err = sink.Send(streamEvent); err != nil {
+				logger.Logger.Debugw("review stream closed by client", "user_id", userID, "error", err)
+				return nil
+			}
+		}
+
+		// Полная пачка - вероятно, есть ещё события, забираем без паузы
+		if len(events) == s.streamConfig.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Logger.Infow("review stream closed", "user_id", userID, "cursor", cursor)
+			return nil
+		case <-s.streamsDone:
+			logger.Logger.Infow("review stream closed on shutdown", "user_id", userID, "cursor", cursor)
+			return nil
+		case <-heartbeat.C:
+			if err = sink.Ping(); err != nil {
+				return nil
+			}
+		case <-poll.C:
+		}
+	}
+}
+
+// CloseStreams завершает открытые потоки очереди ревью. Вызывается перед остановкой HTTP-сервера:
+// Shutdown ждёт завершения всех запросов, а поток сам по себе не заканчивается
+func (s *UserServiceImpl) CloseStreams() {
+	s.closeStreams.Do(func() { close(s.streamsDone) })
+}
+
+// reviewStreamEvent переводит событие outbox в изменение очереди ревью userID. Событие, которое
+// пользователя не касается или не разбирается, пропускается
+func reviewStreamEvent(userID string, event domain.OutboxEvent) (domain.ReviewStreamEvent, bool) {
+	streamEvent := domain.ReviewStreamEvent{
+		ID:         event.StreamPosition,
+		EventID:    event.Event.ID,
+		OccurredAt: event.Event.OccurredAt,
+	}
+
+	switch event.Event.Type {
+	case domain.EventReviewerAssigned, domain.EventReviewerReassigned:
+		var data domain.ReviewerEventData
+		if err := json.Unmarshal(event.Event.Data, &data); err != nil {
+			logger.Logger.Warnw("skipping malformed event in review stream", "event_id", event.Event.ID, "error", err)
+			return domain.ReviewStreamEvent{}, false
+		}
+		streamEvent.PullRequestID = data.PullRequestID
+		switch userID {
+		case data.ReviewerID:
+			streamEvent.Type = domain.ReviewStreamAssigned
+		case data.OldReviewerID:
+			streamEvent.Type = domain.ReviewStreamUnassigned
+		default:
+			return domain.ReviewStreamEvent{}, false
+		}
+
+	case domain.EventPRMerged, domain.EventPRClosed, domain.EventPRReopened:
+		var data domain.PullRequestEventData
+		if err := json.Unmarshal(event.Event.Data, &data); err != nil {
+			logger.Logger.Warnw("skipping malformed event in review stream", "event_id", event.Event.ID, "error", err)
+			return domain.ReviewStreamEvent{}, false
+		}
+		streamEvent.Type = domain.ReviewStreamStatusChanged
+		streamEvent.PullRequestID = data.PullRequestID
+		streamEvent.PullRequestName = data.PullRequestName
+		streamEvent.AuthorID = data.AuthorID
+		streamEvent.Status = data.Status
+
+	default:
+		return domain.ReviewStreamEvent{}, false
+	}
+
+	return streamEvent, true
+}
@@ -0,0 +1,213 @@
+package userService
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink - ReviewStreamSink для тестов, запоминает отправленные события
+type recordingSink struct {
+	opened bool
+	events []domain.ReviewStreamEvent
+	pings  int
+}
+
+func (s *recordingSink) Open() {
+	s.opened = true
+}
+
+func (s *recordingSink) Send(event domain.ReviewStreamEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingSink) Ping() error {
+	s.pings++
+	return nil
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestUserService_StreamReviews(t *testing.T) {
+	occurredAt := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	newStreamService := func(ctrl *gomock.Controller) (
+		*UserServiceImpl,
+		*mocks.MockUserRepositoryInterface,
+		*mocks.MockOutboxRepositoryInterface,
+	) {
+		mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockOutboxRepo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+		service := NewUserService(mockUserRepo, nil, nil, mockOutboxRepo)
+		service.streamConfig.PollInterval = time.Millisecond
+		service.streamConfig.BatchSize = 2
+		return service, mockUserRepo, mockOutboxRepo
+	}
+
+	t.Run("resumes after Last-Event-ID and streams queue changes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, mockUserRepo, mockOutboxRepo := newStreamService(ctrl)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sink := &recordingSink{}
+
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&domain.User{UserId: "u2"}, nil)
+		gomock.InOrder(
+			// полная пачка - следующая забирается сразу
+			mockOutboxRepo.EXPECT().GetUserReviewEvents(gomock.Any(), "u2", int64(10), 2).
+				Return([]domain.OutboxEvent{
+					{ID: 4, StreamPosition: 11, Event: domain.Event{ID: "event-1", Type: domain.EventReviewerAssigned, OccurredAt: occurredAt,
+						Data: []byte(`{"pull_request_id":"pr-1","reviewer_id":"u2"}`)}},
+					{ID: 3, StreamPosition: 12, Event: domain.Event{ID: "event-2", Type: domain.EventReviewerReassigned, OccurredAt: occurredAt,
+						Data: []byte(`{"pull_request_id":"pr-2","reviewer_id":"u3","old_reviewer_id":"u2"}`)}},
+				}, nil),
+			mockOutboxRepo.EXPECT().GetUserReviewEvents(gomock.Any(), "u2", int64(12), 2).
+				Return([]domain.OutboxEvent{
+					{ID: 7, StreamPosition: 15, Event: domain.Event{ID: "event-3", Type: domain.EventPRClosed, OccurredAt: occurredAt,
+						Data: []byte(`{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1","status":"CLOSED"}`)}},
+				}, nil),
+			mockOutboxRepo.EXPECT().GetUserReviewEvents(gomock.Any(), "u2", int64(15), 2).
+				DoAndReturn(func(ctx context.Context, _ string, _ int64, _ int) ([]domain.OutboxEvent, error) {
+					cancel()
+					return nil, ctx.Err()
+				}),
+		)
+
+		err := service.StreamReviews(ctx, "u2", int64Ptr(10), sink)
+
+		require.NoError(t, err)
+		assert.True(t, sink.opened)
+		assert.Equal(t, []domain.ReviewStreamEvent{
+			{ID: 11, Type: domain.ReviewStreamAssigned, EventID: "event-1", PullRequestID: "pr-1", OccurredAt: occurredAt},
+			{ID: 12, Type: domain.ReviewStreamUnassigned, EventID: "event-2", PullRequestID: "pr-2", OccurredAt: occurredAt},
+			{
+				ID:              15,
+				Type:            domain.ReviewStreamStatusChanged,
+				EventID:         "event-3",
+				PullRequestID:   "pr-1",
+				PullRequestName: "Add search",
+				AuthorID:        "u1",
+				Status:          domain.PullRequestStatusCLOSED,
+				OccurredAt:      occurredAt,
+			},
+		}, sink.events)
+	})
+
+	t.Run("starts from the latest stream position without Last-Event-ID", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, mockUserRepo, mockOutboxRepo := newStreamService(ctrl)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sink := &recordingSink{}
+
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&domain.User{UserId: "u2"}, nil)
+		mockOutboxRepo.EXPECT().GetLatestStreamPosition(gomock.Any()).Return(int64(42), nil)
+		mockOutboxRepo.EXPECT().GetUserReviewEvents(gomock.Any(), "u2", int64(42), 2).
+			DoAndReturn(func(ctx context.Context, _ string, _ int64, _ int) ([]domain.OutboxEvent, error) {
+				cancel()
+				return nil, ctx.Err()
+			})
+
+		err := service.StreamReviews(ctx, "u2", nil, sink)
+
+		require.NoError(t, err)
+		assert.True(t, sink.opened)
+		assert.Empty(t, sink.events)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, mockUserRepo, _ := newStreamService(ctrl)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sink := &recordingSink{}
+
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(nil, pgx.ErrNoRows)
+
+		err := service.StreamReviews(ctx, "u2", nil, sink)
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
+		assert.False(t, sink.opened)
+	})
+
+	t.Run("storage error before streaming", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, mockUserRepo, mockOutboxRepo := newStreamService(ctrl)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sink := &recordingSink{}
+
+		mockUserRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&domain.User{UserId: "u2"}, nil)
+		mockOutboxRepo.EXPECT().GetLatestStreamPosition(gomock.Any()).Return(int64(0), errors.New("db error"))
+
+		err := service.StreamReviews(ctx, "u2", nil, sink)
+
+		require.Error(t, err)
+		assert.False(t, sink.opened)
+	})
+}
+
+func TestReviewStreamEvent(t *testing.T) {
+	t.Run("reassignment to someone else's PR is skipped", func(t *testing.T) {
+		_, ok := reviewStreamEvent("u2", domain.OutboxEvent{ID: 1, Event: domain.Event{
+			Type: domain.EventReviewerReassigned,
+			Data: []byte(`{"pull_request_id":"pr-1","reviewer_id":"u3","old_reviewer_id":"u4"}`),
+		}})
+		assert.False(t, ok)
+	})
+
+	t.Run("malformed event is skipped", func(t *testing.T) {
+		_, ok := reviewStreamEvent("u2", domain.OutboxEvent{ID: 1, Event: domain.Event{
+			Type: domain.EventPRMerged,
+			Data: []byte(`"oops"`),
+		}})
+		assert.False(t, ok)
+	})
+}
+
+func TestUserService_CloseStreams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+	service := NewUserService(mockUserRepo, nil, nil, mockOutboxRepo)
+	service.streamConfig.PollInterval = time.Hour
+
+	sink := &recordingSink{}
+
+	mockUserRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&domain.User{UserId: "u2"}, nil)
+	mockOutboxRepo.EXPECT().GetLatestStreamPosition(gomock.Any()).Return(int64(0), nil)
+	mockOutboxRepo.EXPECT().GetUserReviewEvents(gomock.Any(), "u2", int64(0), gomock.Any()).
+		DoAndReturn(func(context.Context, string, int64, int) ([]domain.OutboxEvent, error) {
+			service.CloseStreams()
+			return nil, nil
+		})
+
+	err := service.StreamReviews(context.Background(), "u2", nil, sink)
+	service.CloseStreams()
+
+	require.NoError(t, err)
+	assert.True(t, sink.opened)
+}
@@ -1,51 +1,29 @@
 package userService
 
 import (
+	"context"
 	"errors"
-	"net/http"
 
-	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
 	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
 )
 
-func (s *UserServiceImpl) GetUserReviews(c *gin.Context) {
-	ctx := c.Request.Context()
-
-	userID := c.Query("user_id")
-	if userID == "" {
-		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
-			domain.InvalidRequest,
-			"user_id query parameter is required",
-		))
-		return
-	}
-
+// GetUserReviews возвращает PR, где пользователь назначен ревьювером
+func (s *UserServiceImpl) GetUserReviews(ctx context.Context, userID string) ([]domain.PullRequestShort, error) {
 	_, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
-				domain.NotFound,
-				"user not found",
-			))
-			return
+			return nil, domain.NewError(domain.NotFound, "user not found")
 		}
+		return nil, err
 	}
 
 	prs, err := s.prReviewersRepo.GetPRsByReviewer(ctx, userID)
 	if err != nil {
-		logger.Logger.Error("error getting user reviews: ", err)
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
-			domain.InternalError,
-			domain.ErrGetUserReviewsMsg,
-		))
-		return
+		return nil, err
 	}
 
 	logger.Logger.Infow("user reviews retrieved successfully", "user_id", userID)
-	c.JSON(http.StatusOK, gin.H{
-		"user_id":       userID,
-		"pull_requests": prs,
-	})
+	return prs, nil
 }
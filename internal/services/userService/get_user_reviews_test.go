@@ -1,13 +1,10 @@
 package userService
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"net/http"
-	"net/http/httptest"
 	"testing"
 
-	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/jackc/pgx/v5"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
@@ -22,16 +19,16 @@ func TestUserService_GetUserReviews(t *testing.T) {
 
 	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
 	mockPrReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
-	service := NewUserService(mockUserRepo, mockPrReviewersRepo, nil)
+	service := NewUserService(mockUserRepo, mockPrReviewersRepo, nil, nil)
+	ctx := context.Background()
 
-	t.Run("successfully get user reviews", func(t *testing.T) {
-		userID := testUserIDStr
-		user := &domain.User{
-			UserId:   userID,
-			Username: "Alice",
-			IsActive: true,
-		}
+	user := &domain.User{
+		UserId:   testUserIDStr,
+		Username: "Alice",
+		IsActive: true,
+	}
 
+	t.Run("successfully get user reviews", func(t *testing.T) {
 		prs := []domain.PullRequestShort{
 			{
 				PullRequestId:   testUserIDStr,
@@ -41,81 +38,41 @@ func TestUserService_GetUserReviews(t *testing.T) {
 			},
 		}
 
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/users/reviews?user_id="+userID, nil)
-
 		mockUserRepo.EXPECT().
-			GetUserByID(gomock.Any(), userID).
+			GetUserByID(gomock.Any(), testUserIDStr).
 			Return(user, nil)
-
 		mockPrReviewersRepo.EXPECT().
-			GetPRsByReviewer(gomock.Any(), userID).
+			GetPRsByReviewer(gomock.Any(), testUserIDStr).
 			Return(prs, nil)
 
-		service.GetUserReviews(c)
+		response, err := service.GetUserReviews(ctx, testUserIDStr)
 
-		require.Equal(t, http.StatusOK, w.Code)
-		var response map[string]interface{}
-		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Equal(t, userID, response["user_id"])
-		assert.Contains(t, response, "pull_requests")
-	})
-
-	t.Run("missing user_id parameter", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/users/reviews", nil)
-
-		service.GetUserReviews(c)
-
-		require.Equal(t, http.StatusBadRequest, w.Code)
-		var response domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		require.NoError(t, err)
-		assert.Equal(t, domain.InvalidRequest, response.Error.Code)
+		assert.Equal(t, prs, response)
 	})
 
 	t.Run("user not found", func(t *testing.T) {
-		userID := testUserIDStr
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/users/reviews?user_id="+userID, nil)
-
 		mockUserRepo.EXPECT().
-			GetUserByID(gomock.Any(), userID).
+			GetUserByID(gomock.Any(), testUserIDStr).
 			Return(nil, pgx.ErrNoRows)
 
-		service.GetUserReviews(c)
+		_, err := service.GetUserReviews(ctx, testUserIDStr)
 
-		require.Equal(t, http.StatusNotFound, w.Code)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
 	})
 
 	t.Run("error getting reviews", func(t *testing.T) {
-		userID := testUserIDStr
-		user := &domain.User{
-			UserId:   userID,
-			Username: "Alice",
-			IsActive: true,
-		}
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodGet, "/users/reviews?user_id="+userID, nil)
-
 		mockUserRepo.EXPECT().
-			GetUserByID(gomock.Any(), userID).
+			GetUserByID(gomock.Any(), testUserIDStr).
 			Return(user, nil)
-
 		mockPrReviewersRepo.EXPECT().
-			GetPRsByReviewer(gomock.Any(), userID).
+			GetPRsByReviewer(gomock.Any(), testUserIDStr).
 			Return(nil, errors.New("database error"))
 
-		service.GetUserReviews(c)
+		_, err := service.GetUserReviews(ctx, testUserIDStr)
 
-		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Error(t, err)
 	})
 }
-
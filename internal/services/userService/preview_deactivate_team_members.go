@@ -0,0 +1,34 @@
+package userService
+
+import (
+	"context"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// PreviewDeactivateTeamMembers строит план деактивации так же, как DeactivateTeamMembers, но не применяет его:
+// план сохраняется и возвращается с plan_id, по которому его можно применить без пересчёта
+func (s *UserServiceImpl) PreviewDeactivateTeamMembers(
+	ctx context.Context,
+	req domain.DeactivateTeamMembersRequest,
+) (*domain.DeactivationPlan, error) {
+	plan, err := s.planDeactivation(ctx, req.TeamName, req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.ExpiresAt = time.Now().Add(domain.DeactivationPlanTTL)
+	if err = s.teamRepo.SaveDeactivationPlan(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Infow("deactivation plan previewed",
+		"plan_id", plan.PlanID,
+		"team_name", plan.TeamName,
+		"reassignments_count", len(plan.Reassignments),
+	)
+
+	return plan, nil
+}
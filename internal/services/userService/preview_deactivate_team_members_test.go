@@ -0,0 +1,131 @@
+package userService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserService_PreviewDeactivateTeamMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockPrReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+	service := NewUserService(mockUserRepo, mockPrReviewersRepo, mockTeamRepo, nil)
+	ctx := context.Background()
+
+	team := &domain.Team{
+		TeamName: testTeamNameBackend,
+		Members: []domain.TeamMember{
+			{UserId: testUserID1, Username: "Alice", IsActive: true},
+			{UserId: testUserID2, Username: "Bob", IsActive: true},
+			{UserId: testUserID3, Username: "Charlie", IsActive: true},
+		},
+	}
+
+	t.Run("returns saved plan without deactivating anyone", func(t *testing.T) {
+		planID := uuid.New()
+
+		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), testTeamNameBackend).Return(team, nil)
+		mockPrReviewersRepo.EXPECT().
+			GetPRsByReviewer(gomock.Any(), testUserID2).
+			Return([]domain.PullRequestShort{{
+				PullRequestId: testPRID123,
+				AuthorId:      testUserID1,
+				Status:        domain.PullRequestStatusOPEN,
+			}}, nil)
+		mockPrReviewersRepo.EXPECT().
+			GetAssignedReviewers(gomock.Any(), testPRID123).
+			Return([]string{testUserID2}, nil)
+		mockTeamRepo.EXPECT().
+			SaveDeactivationPlan(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ interface{}, plan *domain.DeactivationPlan) error {
+				assert.Equal(t, map[string][]string{testPRID123: {testUserID2}}, plan.ReviewersSnapshot)
+				assert.False(t, plan.ExpiresAt.IsZero())
+				plan.PlanID = planID.String()
+				return nil
+			})
+
+		response, err := service.PreviewDeactivateTeamMembers(ctx, domain.DeactivateTeamMembersRequest{
+			TeamName: testTeamNameBackend,
+			UserIDs:  []string{testUserID2},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, planID.String(), response.PlanID)
+		assert.Equal(t, []string{testUserID2}, response.DeactivatedUserIDs)
+		require.Len(t, response.Reassignments, 1)
+		assert.Equal(t, testUserID3, response.Reassignments[0].NewReviewerID)
+		require.NotNil(t, response.Reassignments[0].Meta)
+		assert.Equal(t, domain.AssignmentStrategyDeactivation, response.Reassignments[0].Meta.Strategy)
+		assert.Equal(t, 1, response.Reassignments[0].Meta.CandidateCount)
+		assert.Equal(t, testUserID2, response.Reassignments[0].Meta.ReplacedReviewerID)
+		assert.Empty(t, response.PrsLosingReviewers)
+	})
+
+	t.Run("reports PRs losing reviewers without replacement", func(t *testing.T) {
+		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), testTeamNameBackend).Return(team, nil)
+		mockPrReviewersRepo.EXPECT().
+			GetPRsByReviewer(gomock.Any(), testUserID2).
+			Return([]domain.PullRequestShort{{
+				PullRequestId: testPRID123,
+				AuthorId:      testUserID1,
+				Status:        domain.PullRequestStatusOPEN,
+			}}, nil)
+		// Единственный кандидат уже ревьюит PR, поэтому user-2 снимается без замены
+		mockPrReviewersRepo.EXPECT().
+			GetAssignedReviewers(gomock.Any(), testPRID123).
+			Return([]string{testUserID2, testUserID3}, nil)
+		mockTeamRepo.EXPECT().SaveDeactivationPlan(gomock.Any(), gomock.Any()).Return(nil)
+
+		response, err := service.PreviewDeactivateTeamMembers(ctx, domain.DeactivateTeamMembersRequest{
+			TeamName: testTeamNameBackend,
+			UserIDs:  []string{testUserID2},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, response.PrsLosingReviewers, 1)
+		assert.Equal(t, testPRID123, response.PrsLosingReviewers[0].PrID)
+		assert.Equal(t, []string{testUserID2}, response.PrsLosingReviewers[0].LostReviewerIDs)
+		assert.Equal(t, 1, response.PrsLosingReviewers[0].RemainingReviewers)
+	})
+
+	t.Run("user not in team", func(t *testing.T) {
+		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), testTeamNameBackend).Return(team, nil)
+
+		_, err := service.PreviewDeactivateTeamMembers(ctx, domain.DeactivateTeamMembersRequest{
+			TeamName: testTeamNameBackend,
+			UserIDs:  []string{"user-404"},
+		})
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.InvalidRequest, domainErr.Code)
+	})
+
+	t.Run("save error", func(t *testing.T) {
+		mockTeamRepo.EXPECT().GetTeamByName(gomock.Any(), testTeamNameBackend).Return(team, nil)
+		mockPrReviewersRepo.EXPECT().
+			GetPRsByReviewer(gomock.Any(), testUserID2).
+			Return([]domain.PullRequestShort{}, nil)
+		mockTeamRepo.EXPECT().
+			SaveDeactivationPlan(gomock.Any(), gomock.Any()).
+			Return(errors.New("db error"))
+
+		_, err := service.PreviewDeactivateTeamMembers(ctx, domain.DeactivateTeamMembersRequest{
+			TeamName: testTeamNameBackend,
+			UserIDs:  []string{testUserID2},
+		})
+
+		require.Error(t, err)
+	})
+}
@@ -1,14 +1,10 @@
 package userService
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"net/http"
-	"net/http/httptest"
-	"strings"
 	"testing"
 
-	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/jackc/pgx/v5"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
@@ -22,7 +18,6 @@ import (
 const testUserIDStr = "test-str-id"
 
 func init() {
-	gin.SetMode(gin.TestMode)
 	logger.Logger = zap.NewNop().Sugar()
 }
 
@@ -32,102 +27,50 @@ func TestUserService_SetIsActive(t *testing.T) {
 
 	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
 	mockPrReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
-	service := NewUserService(mockUserRepo, mockPrReviewersRepo, nil)
+	service := NewUserService(mockUserRepo, mockPrReviewersRepo, nil, nil)
+	ctx := context.Background()
 
-	t.Run("successfully set user active", func(t *testing.T) {
-		userID := testUserIDStr
-		user := &domain.User{
-			UserId:   userID,
-			Username: "Alice",
-			IsActive: true,
-		}
-
-		requestBody := `{
-			"user_id": "` + userID + `",
-			"is_active": true
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPut, "/users/set-active", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
+	req := domain.SetIsActiveRequest{UserID: testUserIDStr, IsActive: true}
 
+	t.Run("successfully set user active", func(t *testing.T) {
 		mockUserRepo.EXPECT().
-			SetUserIsActive(gomock.Any(), userID, true).
+			SetUserIsActive(gomock.Any(), testUserIDStr, true).
 			Return(nil)
-
 		mockUserRepo.EXPECT().
-			GetUserByID(gomock.Any(), userID).
-			Return(user, nil)
-
-		service.SetIsActive(c)
-
-		require.Equal(t, http.StatusOK, w.Code)
-		var response map[string]interface{}
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		require.NoError(t, err)
-		assert.Contains(t, response, "user")
-	})
+			GetUserByID(gomock.Any(), testUserIDStr).
+			Return(&domain.User{UserId: testUserIDStr, Username: "Alice", IsActive: true}, nil)
 
-	t.Run("invalid request body", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPut, "/users/set-active", strings.NewReader("invalid json"))
-		c.Request.Header.Set("Content-Type", "application/json")
+		user, err := service.SetIsActive(ctx, req)
 
-		service.SetIsActive(c)
-
-		require.Equal(t, http.StatusBadRequest, w.Code)
-		var response domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Equal(t, domain.InvalidRequest, response.Error.Code)
+		assert.Equal(t, testUserIDStr, user.UserId)
+		assert.True(t, user.IsActive)
 	})
 
 	t.Run("user not found when setting status", func(t *testing.T) {
-		userID := testUserIDStr
-		requestBody := `{
-			"user_id": "` + userID + `",
-			"is_active": true
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPut, "/users/set-active", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
-
 		mockUserRepo.EXPECT().
-			SetUserIsActive(gomock.Any(), userID, true).
+			SetUserIsActive(gomock.Any(), testUserIDStr, true).
 			Return(pgx.ErrNoRows)
 
-		service.SetIsActive(c)
+		_, err := service.SetIsActive(ctx, req)
 
-		require.Equal(t, http.StatusNotFound, w.Code)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
 	})
 
 	t.Run("error getting user after update", func(t *testing.T) {
-		userID := testUserIDStr
-		requestBody := `{
-			"user_id": "` + userID + `",
-			"is_active": true
-		}`
-
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = httptest.NewRequest(http.MethodPut, "/users/set-active", strings.NewReader(requestBody))
-		c.Request.Header.Set("Content-Type", "application/json")
-
 		mockUserRepo.EXPECT().
-			SetUserIsActive(gomock.Any(), userID, true).
+			SetUserIsActive(gomock.Any(), testUserIDStr, true).
 			Return(nil)
-
 		mockUserRepo.EXPECT().
-			GetUserByID(gomock.Any(), userID).
+			GetUserByID(gomock.Any(), testUserIDStr).
 			Return(nil, errors.New("database error"))
 
-		service.SetIsActive(c)
+		_, err := service.SetIsActive(ctx, req)
 
-		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Error(t, err)
+		var domainErr *domain.Error
+		assert.False(t, errors.As(err, &domainErr))
 	})
 }
-
@@ -0,0 +1,34 @@
+package userService
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// SetPrimaryTeam выбирает основную команду пользователя. Основная команда используется
+// как команда автора при назначении ревьюеров на его PR
+func (s *UserServiceImpl) SetPrimaryTeam(ctx context.Context, req domain.SetPrimaryTeamRequest) (*domain.User, error) {
+	err := s.teamRepo.SetPrimaryTeam(ctx, req.UserID, req.TeamName)
+	if err != nil {
+		if errors.Is(err, teamStorage.ErrNotTeamMember) {
+			return nil, domain.NewError(domain.NotFound, "user "+req.UserID+" is not a member of team "+req.TeamName)
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewError(domain.NotFound, "user not found")
+		}
+		return nil, err
+	}
+
+	logger.Logger.Infow("user primary team updated", "user_id", req.UserID, "team_name", req.TeamName)
+	return user, nil
+}
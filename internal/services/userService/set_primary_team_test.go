@@ -0,0 +1,68 @@
+package userService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/teamStorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserService_SetPrimaryTeam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockPrReviewersRepo := mocks.NewMockPrReviewersRepositoryInterface(ctrl)
+	mockTeamRepo := mocks.NewMockTeamRepositoryInterface(ctrl)
+	service := NewUserService(mockUserRepo, mockPrReviewersRepo, mockTeamRepo, nil)
+	ctx := context.Background()
+
+	req := domain.SetPrimaryTeamRequest{UserID: testUserIDStr, TeamName: "Payments"}
+
+	t.Run("successfully set primary team", func(t *testing.T) {
+		mockTeamRepo.EXPECT().SetPrimaryTeam(gomock.Any(), testUserIDStr, "Payments").Return(nil)
+		mockUserRepo.EXPECT().
+			GetUserByID(gomock.Any(), testUserIDStr).
+			Return(&domain.User{
+				UserId:    testUserIDStr,
+				Username:  "Alice",
+				TeamName:  "Payments",
+				TeamNames: []string{"Backend", "Payments"},
+				IsActive:  true,
+			}, nil)
+
+		user, err := service.SetPrimaryTeam(ctx, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Payments", user.TeamName)
+		assert.Equal(t, []string{"Backend", "Payments"}, user.TeamNames)
+	})
+
+	t.Run("user is not a member of team", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			SetPrimaryTeam(gomock.Any(), testUserIDStr, "Payments").
+			Return(teamStorage.ErrNotTeamMember)
+
+		_, err := service.SetPrimaryTeam(ctx, req)
+
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.NotFound, domainErr.Code)
+	})
+
+	t.Run("error setting primary team", func(t *testing.T) {
+		mockTeamRepo.EXPECT().
+			SetPrimaryTeam(gomock.Any(), testUserIDStr, "Payments").
+			Return(errors.New("db error"))
+
+		_, err := service.SetPrimaryTeam(ctx, req)
+
+		require.Error(t, err)
+	})
+}
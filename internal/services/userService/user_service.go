@@ -1,6 +1,8 @@
 package userService
 
 import (
+	"sync"
+
 	"github.com/nedokyrill/avito-pr-api/internal/storage"
 )
 
@@ -8,16 +10,24 @@ type UserServiceImpl struct {
 	userRepo        storage.UserRepositoryInterface
 	prReviewersRepo storage.PrReviewersRepositoryInterface
 	teamRepo        storage.TeamRepositoryInterface
+	outboxRepo      storage.OutboxRepositoryInterface
+	streamConfig    ReviewStreamConfig
+	streamsDone     chan struct{}
+	closeStreams    sync.Once
 }
 
 func NewUserService(
 	userRepo storage.UserRepositoryInterface,
 	prReviewersRepo storage.PrReviewersRepositoryInterface,
 	teamRepo storage.TeamRepositoryInterface,
+	outboxRepo storage.OutboxRepositoryInterface,
 ) *UserServiceImpl {
 	return &UserServiceImpl{
 		userRepo:        userRepo,
 		prReviewersRepo: prReviewersRepo,
 		teamRepo:        teamRepo,
+		outboxRepo:      outboxRepo,
+		streamConfig:    DefaultReviewStreamConfig(),
+		streamsDone:     make(chan struct{}),
 	}
 }
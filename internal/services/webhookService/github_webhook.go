@@ -0,0 +1,147 @@
+package webhookService
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+const githubSignaturePrefix = "sha256="
+
+// githubPullRequestEvent - нужная часть payload события pull_request
+type githubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title  string `json:"title"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// HandleGitHubWebhook принимает события GitHub. Подлинность проверяется по X-Hub-Signature-256
+// (HMAC-SHA256 тела с секретом вебхука), повторные доставки отсеиваются по X-GitHub-Delivery
+func (s *WebhookServiceImpl) HandleGitHubWebhook(c *gin.Context) {
+	body, err := readWebhookBody(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid request body",
+		))
+		return
+	}
+
+	if !verifyGitHubSignature(s.secrets.GitHub, body, c.GetHeader("X-Hub-Signature-256")) {
+		logger.Logger.Warnw("github webhook signature mismatch", "delivery_id", c.GetHeader("X-GitHub-Delivery"))
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid signature",
+		))
+		return
+	}
+
+	deliveryID := c.GetHeader("X-GitHub-Delivery")
+	if deliveryID == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"X-GitHub-Delivery header is required",
+		))
+		return
+	}
+
+	event, err := parseGitHubEvent(c.GetHeader("X-GitHub-Event"), deliveryID, body)
+	if err != nil {
+		respondWebhook(c, nil, err)
+		return
+	}
+
+	result, err := s.processEvent(c.Request.Context(), event)
+	respondWebhook(c, result, err)
+}
+
+// verifyGitHubSignature сравнивает подпись за постоянное время. Без настроенного секрета
+// все доставки отклоняются, чтобы эндпоинт не оказался открытым
+func verifyGitHubSignature(secret string, body []byte, signature string) bool {
+	if secret == "" || !strings.HasPrefix(signature, githubSignaturePrefix) {
+		return false
+	}
+
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, githubSignaturePrefix))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// parseGitHubEvent переводит событие GitHub в общий вид. Черновики не создаются до ready_for_review,
+// перевод обратно в черновик и закрытие без мерджа закрывают PR
+func parseGitHubEvent(eventName, deliveryID string, body []byte) (domain.VCSPullRequestEvent, error) {
+	event := domain.VCSPullRequestEvent{
+		Provider:   domain.VCSProviderGitHub,
+		DeliveryID: deliveryID,
+		Event:      eventName,
+		Action:     domain.VCSActionIgnore,
+	}
+
+	if eventName != "pull_request" {
+		event.IgnoreReason = "unsupported event " + eventName
+		return event, nil
+	}
+
+	var payload githubPullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return event, domain.NewError(domain.InvalidRequest, "invalid pull_request payload")
+	}
+	if payload.Repository.FullName == "" || payload.Number == 0 {
+		return event, domain.NewError(domain.InvalidRequest, "pull_request payload without repository or number")
+	}
+
+	event.PullRequestID = payload.Repository.FullName + "#" + strconv.Itoa(payload.Number)
+	event.Title = payload.PullRequest.Title
+	event.AuthorLogin = payload.PullRequest.User.Login
+
+	switch payload.Action {
+	case "opened", "reopened":
+		if payload.PullRequest.Draft {
+			event.IgnoreReason = "draft PR"
+			return event, nil
+		}
+		event.Action = domain.VCSActionOpen
+	case "ready_for_review":
+		event.Action = domain.VCSActionOpen
+	case "converted_to_draft":
+		event.Action = domain.VCSActionClose
+	case "closed":
+		if payload.PullRequest.Merged {
+			event.Action = domain.VCSActionMerge
+		} else {
+			event.Action = domain.VCSActionClose
+		}
+	default:
+		event.IgnoreReason = "unsupported action " + payload.Action
+	}
+
+	return event, nil
+}
+
+func readWebhookBody(c *gin.Context) ([]byte, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodyBytes)
+	return c.GetRawData()
+}
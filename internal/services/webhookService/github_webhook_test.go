@@ -0,0 +1,369 @@
+package webhookService
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	testGitHubSecret = "s3cr3t"
+	testDeliveryID   = "72d3162e-cc78-11e3-81ab-4c9367dc0958"
+	testPRID         = "avito/pr-api#42"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	logger.Logger = zap.NewNop().Sugar()
+}
+
+// fakePullRequestOps запоминает вызовы и возвращает заданные ошибки
+type fakePullRequestOps struct {
+	createErr error
+	mergeErr  error
+	closeErr  error
+	reopenErr error
+	created   []domain.CreatePullRequestRequest
+	merged    []string
+	closed    []string
+	reopened  []string
+}
+
+func (f *fakePullRequestOps) CreatePullRequest(_ context.Context, req domain.CreatePullRequestRequest) (*domain.PullRequestResponse, error) {
+	f.created = append(f.created, req)
+	if f.createErr != nil {
+		return nil, f.createErr
+	}
+	return &domain.PullRequestResponse{}, nil
+}
+
+func (f *fakePullRequestOps) MergePullRequest(_ context.Context, prID string) (*domain.PullRequestResponse, error) {
+	f.merged = append(f.merged, prID)
+	if f.mergeErr != nil {
+		return nil, f.mergeErr
+	}
+	return &domain.PullRequestResponse{}, nil
+}
+
+func (f *fakePullRequestOps) ClosePullRequest(_ context.Context, prID string) (*domain.PullRequestResponse, error) {
+	f.closed = append(f.closed, prID)
+	if f.closeErr != nil {
+		return nil, f.closeErr
+	}
+	return &domain.PullRequestResponse{}, nil
+}
+
+func (f *fakePullRequestOps) ReopenPullRequest(_ context.Context, prID string) (*domain.PullRequestResponse, error) {
+	f.reopened = append(f.reopened, prID)
+	if f.reopenErr != nil {
+		return nil, f.reopenErr
+	}
+	return &domain.PullRequestResponse{}, nil
+}
+
+func loadFixture(t *testing.T, provider, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", provider, name))
+	require.NoError(t, err)
+	return body
+}
+
+func signGitHub(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return githubSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newGitHubContext(event string, body []byte, signature string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("X-GitHub-Event", event)
+	c.Request.Header.Set("X-GitHub-Delivery", testDeliveryID)
+	c.Request.Header.Set("X-Hub-Signature-256", signature)
+	return c, w
+}
+
+func decodeResult(t *testing.T, w *httptest.ResponseRecorder) domain.WebhookResult {
+	t.Helper()
+	var result domain.WebhookResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	return result
+}
+
+func TestWebhookService_HandleGitHubWebhookSignature(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	body := loadFixture(t, "github", "pull_request_opened.json")
+
+	t.Run("invalid signature", func(t *testing.T) {
+		service := NewWebhookService(mocks.NewMockWebhookRepositoryInterface(ctrl), &fakePullRequestOps{},
+			Secrets{GitHub: testGitHubSecret})
+		c, w := newGitHubContext("pull_request", body, signGitHub("other", body))
+
+		service.HandleGitHubWebhook(c)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		service := NewWebhookService(mocks.NewMockWebhookRepositoryInterface(ctrl), &fakePullRequestOps{},
+			Secrets{GitHub: testGitHubSecret})
+		c, w := newGitHubContext("pull_request", body, "")
+
+		service.HandleGitHubWebhook(c)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("secret not configured", func(t *testing.T) {
+		service := NewWebhookService(mocks.NewMockWebhookRepositoryInterface(ctrl), &fakePullRequestOps{}, Secrets{})
+		c, w := newGitHubContext("pull_request", body, signGitHub("", body))
+
+		service.HandleGitHubWebhook(c)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("missing delivery id", func(t *testing.T) {
+		service := NewWebhookService(mocks.NewMockWebhookRepositoryInterface(ctrl), &fakePullRequestOps{},
+			Secrets{GitHub: testGitHubSecret})
+		c, w := newGitHubContext("pull_request", body, signGitHub(testGitHubSecret, body))
+		c.Request.Header.Del("X-GitHub-Delivery")
+
+		service.HandleGitHubWebhook(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestWebhookService_HandleGitHubWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWebhookRepo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+
+	send := func(prOps PullRequestOperations, event, fixture string) *httptest.ResponseRecorder {
+		service := NewWebhookService(mockWebhookRepo, prOps, Secrets{GitHub: testGitHubSecret})
+		body := loadFixture(t, "github", fixture)
+		c, w := newGitHubContext(event, body, signGitHub(testGitHubSecret, body))
+		service.HandleGitHubWebhook(c)
+		return w
+	}
+
+	// expectRecorded - доставка забрана, обработана с результатом status и записана
+	expectRecorded := func(action domain.VCSEventAction, status domain.WebhookResultStatus) {
+		mockWebhookRepo.EXPECT().ProcessDelivery(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				ctx context.Context,
+				delivery domain.WebhookDelivery,
+				process func(context.Context) (domain.WebhookResultStatus, error),
+			) (bool, error) {
+				assert.Equal(t, domain.VCSProviderGitHub, delivery.Provider)
+				assert.Equal(t, testDeliveryID, delivery.DeliveryID)
+				assert.Equal(t, action, delivery.Action)
+				result, err := process(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, status, result)
+				return true, nil
+			})
+	}
+
+	// expectNotRecorded - доставка забрана, но обработка упала: заявка откатывается
+	expectNotRecorded := func() {
+		mockWebhookRepo.EXPECT().ProcessDelivery(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				ctx context.Context,
+				_ domain.WebhookDelivery,
+				process func(context.Context) (domain.WebhookResultStatus, error),
+			) (bool, error) {
+				_, err := process(ctx)
+				require.Error(t, err)
+				return false, err
+			})
+	}
+
+	// expectDuplicate - доставку уже забрала другая обработка
+	expectDuplicate := func() {
+		mockWebhookRepo.EXPECT().ProcessDelivery(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+	}
+
+	t.Run("opened creates PR for mapped author", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		mockWebhookRepo.EXPECT().GetUserIDByLogin(gomock.Any(), domain.VCSProviderGitHub, "octocat").Return("u1", nil)
+		expectRecorded(domain.VCSActionOpen, domain.WebhookProcessed)
+
+		w := send(prOps, "pull_request", "pull_request_opened.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, domain.WebhookProcessed, decodeResult(t, w).Status)
+		require.Len(t, prOps.created, 1)
+		assert.Equal(t, domain.CreatePullRequestRequest{
+			PullRequestID:   testPRID,
+			PullRequestName: "Add webhook receiver",
+			AuthorID:        "u1",
+		}, prOps.created[0])
+	})
+
+	t.Run("ready_for_review creates PR", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		mockWebhookRepo.EXPECT().GetUserIDByLogin(gomock.Any(), domain.VCSProviderGitHub, "octocat").Return("u1", nil)
+		expectRecorded(domain.VCSActionOpen, domain.WebhookProcessed)
+
+		w := send(prOps, "pull_request", "pull_request_ready_for_review.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, prOps.created, 1)
+	})
+
+	t.Run("existing PR is reopened", func(t *testing.T) {
+		prOps := &fakePullRequestOps{createErr: domain.NewError(domain.PrExists, "PR id already exists")}
+
+		mockWebhookRepo.EXPECT().GetUserIDByLogin(gomock.Any(), domain.VCSProviderGitHub, "octocat").Return("u1", nil)
+		expectRecorded(domain.VCSActionOpen, domain.WebhookProcessed)
+
+		w := send(prOps, "pull_request", "pull_request_opened.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "PR already exists", decodeResult(t, w).Reason)
+		assert.Equal(t, []string{testPRID}, prOps.reopened)
+	})
+
+	t.Run("reopen of merged PR is ignored", func(t *testing.T) {
+		prOps := &fakePullRequestOps{
+			createErr: domain.NewError(domain.PrExists, "PR id already exists"),
+			reopenErr: domain.NewError(domain.PrMerged, "cannot reopen merged PR"),
+		}
+
+		mockWebhookRepo.EXPECT().GetUserIDByLogin(gomock.Any(), domain.VCSProviderGitHub, "octocat").Return("u1", nil)
+		expectRecorded(domain.VCSActionOpen, domain.WebhookIgnored)
+
+		w := send(prOps, "pull_request", "pull_request_opened.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "PR already merged", decodeResult(t, w).Reason)
+	})
+
+	t.Run("unknown author login is not recorded", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		expectNotRecorded()
+		mockWebhookRepo.EXPECT().GetUserIDByLogin(gomock.Any(), domain.VCSProviderGitHub, "octocat").Return("", pgx.ErrNoRows)
+
+		w := send(prOps, "pull_request", "pull_request_opened.json")
+
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Empty(t, prOps.created)
+	})
+
+	t.Run("draft is ignored", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		expectRecorded(domain.VCSActionIgnore, domain.WebhookIgnored)
+
+		w := send(prOps, "pull_request", "pull_request_opened_draft.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "draft PR", decodeResult(t, w).Reason)
+		assert.Empty(t, prOps.created)
+	})
+
+	t.Run("merged PR is merged", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		expectRecorded(domain.VCSActionMerge, domain.WebhookProcessed)
+
+		w := send(prOps, "pull_request", "pull_request_closed_merged.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{testPRID}, prOps.merged)
+	})
+
+	t.Run("merge of unknown PR is ignored", func(t *testing.T) {
+		prOps := &fakePullRequestOps{mergeErr: domain.NewError(domain.NotFound, "PR not found")}
+
+		expectRecorded(domain.VCSActionMerge, domain.WebhookIgnored)
+
+		w := send(prOps, "pull_request", "pull_request_closed_merged.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, domain.WebhookIgnored, decodeResult(t, w).Status)
+	})
+
+	t.Run("closed without merge closes PR", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		expectRecorded(domain.VCSActionClose, domain.WebhookProcessed)
+
+		w := send(prOps, "pull_request", "pull_request_closed_unmerged.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, prOps.merged)
+		assert.Equal(t, []string{testPRID}, prOps.closed)
+	})
+
+	t.Run("converted to draft closes PR", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		expectRecorded(domain.VCSActionClose, domain.WebhookProcessed)
+
+		w := send(prOps, "pull_request", "pull_request_converted_to_draft.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{testPRID}, prOps.closed)
+	})
+
+	t.Run("ping is ignored", func(t *testing.T) {
+		expectRecorded(domain.VCSActionIgnore, domain.WebhookIgnored)
+
+		w := send(&fakePullRequestOps{}, "ping", "ping.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("duplicate delivery is skipped", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		expectDuplicate()
+
+		w := send(prOps, "pull_request", "pull_request_opened.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, domain.WebhookDuplicate, decodeResult(t, w).Status)
+		assert.Empty(t, prOps.created)
+	})
+
+	t.Run("internal error is not recorded", func(t *testing.T) {
+		prOps := &fakePullRequestOps{createErr: errors.New("db error")}
+
+		expectNotRecorded()
+		mockWebhookRepo.EXPECT().GetUserIDByLogin(gomock.Any(), domain.VCSProviderGitHub, "octocat").Return("u1", nil)
+
+		w := send(prOps, "pull_request", "pull_request_opened.json")
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
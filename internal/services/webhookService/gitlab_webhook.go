@@ -0,0 +1,143 @@
+package webhookService
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+const gitlabMergeRequestHook = "Merge Request Hook"
+
+// gitlabMergeRequestEvent - нужная часть payload события Merge Request Hook
+type gitlabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID    int    `json:"iid"`
+		Title  string `json:"title"`
+		Action string `json:"action"`
+		Draft  bool   `json:"draft"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
+
+// HandleGitLabWebhook принимает события GitLab. Подлинность проверяется по X-Gitlab-Token (секретный токен
+// вебхука), повторные доставки отсеиваются по X-Gitlab-Event-UUID
+func (s *WebhookServiceImpl) HandleGitLabWebhook(c *gin.Context) {
+	body, err := readWebhookBody(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid request body",
+		))
+		return
+	}
+
+	if !verifyGitLabToken(s.secrets.GitLab, c.GetHeader("X-Gitlab-Token")) {
+		logger.Logger.Warnw("gitlab webhook token mismatch", "delivery_id", c.GetHeader("X-Gitlab-Event-UUID"))
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid token",
+		))
+		return
+	}
+
+	deliveryID := c.GetHeader("X-Gitlab-Event-UUID")
+	if deliveryID == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"X-Gitlab-Event-UUID header is required",
+		))
+		return
+	}
+
+	event, err := parseGitLabEvent(c.GetHeader("X-Gitlab-Event"), deliveryID, body)
+	if err != nil {
+		respondWebhook(c, nil, err)
+		return
+	}
+
+	result, err := s.processEvent(c.Request.Context(), event)
+	respondWebhook(c, result, err)
+}
+
+// verifyGitLabToken сравнивает токен за постоянное время; без настроенного секрета доставки отклоняются
+func verifyGitLabToken(secret, token string) bool {
+	if secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1
+}
+
+// parseGitLabEvent переводит Merge Request Hook в общий вид. GitLab не присылает логин автора MR,
+// поэтому автором считается пользователь, вызвавший событие (для open это всегда автор).
+// Черновики не создаются; снятие пометки draft открывает PR, установка - закрывает
+func parseGitLabEvent(eventName, deliveryID string, body []byte) (domain.VCSPullRequestEvent, error) {
+	event := domain.VCSPullRequestEvent{
+		Provider:   domain.VCSProviderGitLab,
+		DeliveryID: deliveryID,
+		Event:      eventName,
+		Action:     domain.VCSActionIgnore,
+	}
+
+	if eventName != gitlabMergeRequestHook {
+		event.IgnoreReason = "unsupported event " + eventName
+		return event, nil
+	}
+
+	var payload gitlabMergeRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return event, domain.NewError(domain.InvalidRequest, "invalid merge request payload")
+	}
+	if payload.Project.PathWithNamespace == "" || payload.ObjectAttributes.IID == 0 {
+		return event, domain.NewError(domain.InvalidRequest, "merge request payload without project or iid")
+	}
+
+	attributes := payload.ObjectAttributes
+	event.PullRequestID = payload.Project.PathWithNamespace + "!" + strconv.Itoa(attributes.IID)
+	event.Title = attributes.Title
+	event.AuthorLogin = payload.User.Username
+
+	switch attributes.Action {
+	case "open", "reopen":
+		if attributes.Draft {
+			event.IgnoreReason = "draft MR"
+			return event, nil
+		}
+		event.Action = domain.VCSActionOpen
+	case "update":
+		draft := payload.Changes.Draft
+		if draft == nil || draft.Previous == draft.Current {
+			event.IgnoreReason = "update without draft change"
+			return event, nil
+		}
+		if draft.Current {
+			event.Action = domain.VCSActionClose
+		} else {
+			event.Action = domain.VCSActionOpen
+		}
+	case "merge":
+		event.Action = domain.VCSActionMerge
+	case "close":
+		event.Action = domain.VCSActionClose
+	default:
+		event.IgnoreReason = "unsupported action " + attributes.Action
+	}
+
+	return event, nil
+}
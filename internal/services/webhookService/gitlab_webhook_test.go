@@ -0,0 +1,262 @@
+package webhookService
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testGitLabToken      = "gl-t0ken"
+	testGitLabDeliveryID = "0b5f3a7e-6d39-4a1c-9a8f-1c2d3e4f5a6b"
+	testMRID             = "avito/pr-api!7"
+)
+
+func newGitLabContext(event string, body []byte, token string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("X-Gitlab-Event", event)
+	c.Request.Header.Set("X-Gitlab-Event-UUID", testGitLabDeliveryID)
+	c.Request.Header.Set("X-Gitlab-Token", token)
+	return c, w
+}
+
+func TestWebhookService_HandleGitLabWebhookToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	body := loadFixture(t, "gitlab", "merge_request_open.json")
+
+	t.Run("invalid token", func(t *testing.T) {
+		service := NewWebhookService(mocks.NewMockWebhookRepositoryInterface(ctrl), &fakePullRequestOps{},
+			Secrets{GitLab: testGitLabToken})
+		c, w := newGitLabContext(gitlabMergeRequestHook, body, "wrong")
+
+		service.HandleGitLabWebhook(c)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("secret not configured", func(t *testing.T) {
+		service := NewWebhookService(mocks.NewMockWebhookRepositoryInterface(ctrl), &fakePullRequestOps{}, Secrets{})
+		c, w := newGitLabContext(gitlabMergeRequestHook, body, "")
+
+		service.HandleGitLabWebhook(c)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("missing event uuid", func(t *testing.T) {
+		service := NewWebhookService(mocks.NewMockWebhookRepositoryInterface(ctrl), &fakePullRequestOps{},
+			Secrets{GitLab: testGitLabToken})
+		c, w := newGitLabContext(gitlabMergeRequestHook, body, testGitLabToken)
+		c.Request.Header.Del("X-Gitlab-Event-UUID")
+
+		service.HandleGitLabWebhook(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestWebhookService_HandleGitLabWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWebhookRepo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+
+	send := func(prOps PullRequestOperations, event, fixture string) *httptest.ResponseRecorder {
+		service := NewWebhookService(mockWebhookRepo, prOps, Secrets{GitLab: testGitLabToken})
+		c, w := newGitLabContext(event, loadFixture(t, "gitlab", fixture), testGitLabToken)
+		service.HandleGitLabWebhook(c)
+		return w
+	}
+
+	// expectRecorded - доставка забрана, обработана с результатом status и записана
+	expectRecorded := func(action domain.VCSEventAction, status domain.WebhookResultStatus) {
+		mockWebhookRepo.EXPECT().ProcessDelivery(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				ctx context.Context,
+				delivery domain.WebhookDelivery,
+				process func(context.Context) (domain.WebhookResultStatus, error),
+			) (bool, error) {
+				assert.Equal(t, domain.VCSProviderGitLab, delivery.Provider)
+				assert.Equal(t, testGitLabDeliveryID, delivery.DeliveryID)
+				assert.Equal(t, action, delivery.Action)
+				result, err := process(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, status, result)
+				return true, nil
+			})
+	}
+
+	// expectNotRecorded - доставка забрана, но обработка упала: заявка откатывается
+	expectNotRecorded := func() {
+		mockWebhookRepo.EXPECT().ProcessDelivery(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				ctx context.Context,
+				_ domain.WebhookDelivery,
+				process func(context.Context) (domain.WebhookResultStatus, error),
+			) (bool, error) {
+				_, err := process(ctx)
+				require.Error(t, err)
+				return false, err
+			})
+	}
+
+	// expectDuplicate - доставку уже забрала другая обработка
+	expectDuplicate := func() {
+		mockWebhookRepo.EXPECT().ProcessDelivery(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+	}
+
+	t.Run("open creates PR for mapped user", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		mockWebhookRepo.EXPECT().GetUserIDByLogin(gomock.Any(), domain.VCSProviderGitLab, "root").Return("u1", nil)
+		expectRecorded(domain.VCSActionOpen, domain.WebhookProcessed)
+
+		w := send(prOps, gitlabMergeRequestHook, "merge_request_open.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, prOps.created, 1)
+		assert.Equal(t, domain.CreatePullRequestRequest{
+			PullRequestID:   testMRID,
+			PullRequestName: "Add GitLab webhook receiver",
+			AuthorID:        "u1",
+		}, prOps.created[0])
+	})
+
+	t.Run("draft MR is ignored", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		expectRecorded(domain.VCSActionIgnore, domain.WebhookIgnored)
+
+		w := send(prOps, gitlabMergeRequestHook, "merge_request_open_draft.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "draft MR", decodeResult(t, w).Reason)
+		assert.Empty(t, prOps.created)
+	})
+
+	t.Run("marked ready creates PR", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		mockWebhookRepo.EXPECT().GetUserIDByLogin(gomock.Any(), domain.VCSProviderGitLab, "root").Return("u1", nil)
+		expectRecorded(domain.VCSActionOpen, domain.WebhookProcessed)
+
+		w := send(prOps, gitlabMergeRequestHook, "merge_request_update_ready.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, prOps.created, 1)
+	})
+
+	t.Run("marked ready reopens PR closed as draft", func(t *testing.T) {
+		prOps := &fakePullRequestOps{createErr: domain.NewError(domain.PrExists, "PR id already exists")}
+
+		mockWebhookRepo.EXPECT().GetUserIDByLogin(gomock.Any(), domain.VCSProviderGitLab, "root").Return("u1", nil)
+		expectRecorded(domain.VCSActionOpen, domain.WebhookProcessed)
+
+		w := send(prOps, gitlabMergeRequestHook, "merge_request_update_ready.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{testMRID}, prOps.reopened)
+	})
+
+	t.Run("marked draft closes PR", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		expectRecorded(domain.VCSActionClose, domain.WebhookProcessed)
+
+		w := send(prOps, gitlabMergeRequestHook, "merge_request_update_draft.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{testMRID}, prOps.closed)
+	})
+
+	t.Run("update without draft change is ignored", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		expectRecorded(domain.VCSActionIgnore, domain.WebhookIgnored)
+
+		w := send(prOps, gitlabMergeRequestHook, "merge_request_update_title.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, prOps.created)
+		assert.Empty(t, prOps.closed)
+	})
+
+	t.Run("merge merges PR", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		expectRecorded(domain.VCSActionMerge, domain.WebhookProcessed)
+
+		w := send(prOps, gitlabMergeRequestHook, "merge_request_merge.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{testMRID}, prOps.merged)
+	})
+
+	t.Run("close closes PR", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		expectRecorded(domain.VCSActionClose, domain.WebhookProcessed)
+
+		w := send(prOps, gitlabMergeRequestHook, "merge_request_close.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{testMRID}, prOps.closed)
+	})
+
+	t.Run("close of merged PR is ignored", func(t *testing.T) {
+		prOps := &fakePullRequestOps{closeErr: domain.NewError(domain.PrMerged, "cannot close merged PR")}
+
+		expectRecorded(domain.VCSActionClose, domain.WebhookIgnored)
+
+		w := send(prOps, gitlabMergeRequestHook, "merge_request_close.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "PR already merged", decodeResult(t, w).Reason)
+	})
+
+	t.Run("unknown user is not recorded", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		expectNotRecorded()
+		mockWebhookRepo.EXPECT().GetUserIDByLogin(gomock.Any(), domain.VCSProviderGitLab, "root").Return("", pgx.ErrNoRows)
+
+		w := send(prOps, gitlabMergeRequestHook, "merge_request_open.json")
+
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("other events are ignored", func(t *testing.T) {
+		expectRecorded(domain.VCSActionIgnore, domain.WebhookIgnored)
+
+		w := send(&fakePullRequestOps{}, "Push Hook", "merge_request_open.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("duplicate delivery is skipped", func(t *testing.T) {
+		prOps := &fakePullRequestOps{}
+
+		expectDuplicate()
+
+		w := send(prOps, gitlabMergeRequestHook, "merge_request_open.json")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, domain.WebhookDuplicate, decodeResult(t, w).Status)
+		assert.Empty(t, prOps.created)
+	})
+}
@@ -0,0 +1,165 @@
+package webhookService
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// processEvent - общая для всех провайдеров обработка события: доставка сначала забирается (повторная
+// или параллельная с тем же id получает duplicate), затем логин переводится в пользователя и вызывается
+// операция над PR. Заявка фиксируется только после успешной обработки, поэтому после ошибки (например,
+// логин ещё не сопоставлен) доставку можно повторить.
+// Ошибки, которые не исправятся повторной доставкой без изменений на нашей стороне, - *domain.Error
+func (s *WebhookServiceImpl) processEvent(ctx context.Context, event domain.VCSPullRequestEvent) (*domain.WebhookResult, error) {
+	delivery := domain.WebhookDelivery{
+		Provider:   event.Provider,
+		DeliveryID: event.DeliveryID,
+		Event:      event.Event,
+		Action:     event.Action,
+	}
+	if event.PullRequestID != "" {
+		delivery.PullRequestID = &event.PullRequestID
+	}
+
+	var result *domain.WebhookResult
+	claimed, err := s.webhookRepo.ProcessDelivery(ctx, delivery,
+		func(ctx context.Context) (domain.WebhookResultStatus, error) {
+			var err error
+			if result, err = s.applyEvent(ctx, event); err != nil {
+				return "", err
+			}
+			return result.Status, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return &domain.WebhookResult{
+			Status:        domain.WebhookDuplicate,
+			Action:        event.Action,
+			PullRequestID: event.PullRequestID,
+		}, nil
+	}
+
+	logger.Logger.Infow("webhook delivery processed",
+		"provider", event.Provider,
+		"delivery_id", event.DeliveryID,
+		"action", event.Action,
+		"pr_id", event.PullRequestID,
+		"result", result.Status,
+		"reason", result.Reason,
+	)
+
+	return result, nil
+}
+
+func (s *WebhookServiceImpl) applyEvent(ctx context.Context, event domain.VCSPullRequestEvent) (*domain.WebhookResult, error) {
+	result := &domain.WebhookResult{
+		Status:        domain.WebhookProcessed,
+		Action:        event.Action,
+		PullRequestID: event.PullRequestID,
+	}
+
+	switch event.Action {
+	case domain.VCSActionOpen:
+		authorID, err := s.webhookRepo.GetUserIDByLogin(ctx, event.Provider, event.AuthorLogin)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, domain.NewError(domain.NotFound,
+					"no user mapping for "+string(event.Provider)+" login "+event.AuthorLogin)
+			}
+			return nil, err
+		}
+
+		_, err = s.prOps.CreatePullRequest(ctx, domain.CreatePullRequestRequest{
+			PullRequestID:   event.PullRequestID,
+			PullRequestName: event.Title,
+			AuthorID:        authorID,
+		})
+		if hasErrorCode(err, domain.PrExists) {
+			// reopen и ready_for_review приходят для уже созданных PR: закрытый открывается снова
+			// с прежними ревьюверами, открытый остаётся как есть
+			result.Reason = "PR already exists"
+			_, err = s.prOps.ReopenPullRequest(ctx, event.PullRequestID)
+		}
+		if hasErrorCode(err, domain.PrMerged) {
+			return ignore(result, "PR already merged"), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+	case domain.VCSActionMerge:
+		_, err := s.prOps.MergePullRequest(ctx, event.PullRequestID)
+		// PR, открытые до подключения вебхука, сервису неизвестны
+		if hasErrorCode(err, domain.NotFound) {
+			return ignore(result, "PR not found"), nil
+		}
+		if hasErrorCode(err, domain.PrClosed) {
+			return ignore(result, "PR is closed"), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+	case domain.VCSActionClose:
+		_, err := s.prOps.ClosePullRequest(ctx, event.PullRequestID)
+		if hasErrorCode(err, domain.NotFound) {
+			return ignore(result, "PR not found"), nil
+		}
+		if hasErrorCode(err, domain.PrMerged) {
+			return ignore(result, "PR already merged"), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return ignore(result, event.IgnoreReason), nil
+	}
+
+	return result, nil
+}
+
+func ignore(result *domain.WebhookResult, reason string) *domain.WebhookResult {
+	result.Status = domain.WebhookIgnored
+	result.Reason = reason
+	return result
+}
+
+func hasErrorCode(err error, code domain.ErrorResponseErrorCode) bool {
+	var domainErr *domain.Error
+	return errors.As(err, &domainErr) && domainErr.Code == code
+}
+
+// respondWebhook отвечает на результат processEvent. *domain.Error - 422 (или 400 для некорректного
+// запроса): VCS покажет доставку как неуспешную, и её можно повторить после исправления
+func respondWebhook(c *gin.Context, result *domain.WebhookResult, err error) {
+	if err == nil {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		status := http.StatusUnprocessableEntity
+		if domainErr.Code == domain.InvalidRequest {
+			status = http.StatusBadRequest
+		}
+		logger.Logger.Warnw("webhook delivery rejected", "reason", domainErr.Message)
+		c.JSON(status, domainErr.Response())
+		return
+	}
+
+	logger.Logger.Error("error processing webhook: ", err)
+	c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+		domain.InternalError,
+		domain.ErrProcessWebhookMsg,
+	))
+}
@@ -0,0 +1,86 @@
+package webhookService
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/webhookStorage"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// SetUserMapping привязывает логин в VCS к пользователю сервиса (или перепривязывает существующий)
+func (s *WebhookServiceImpl) SetUserMapping(c *gin.Context) {
+	var mapping domain.VCSUserMapping
+	if err := c.ShouldBindJSON(&mapping); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"invalid request body",
+		))
+		return
+	}
+
+	if !isKnownProvider(mapping.Provider) {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.InvalidRequest,
+			"unknown provider "+string(mapping.Provider),
+		))
+		return
+	}
+
+	if err := s.webhookRepo.UpsertUserMapping(c.Request.Context(), mapping); err != nil {
+		if errors.Is(err, webhookStorage.ErrUserNotExists) {
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(
+				domain.NotFound,
+				"user not found",
+			))
+			return
+		}
+		logger.Logger.Error("error saving user mapping: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrUserMappingMsg,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, mapping)
+}
+
+// ListUserMappings возвращает соответствия логинов, опционально только для ?provider=
+func (s *WebhookServiceImpl) ListUserMappings(c *gin.Context) {
+	var provider *domain.VCSProvider
+	if raw := c.Query("provider"); raw != "" {
+		p := domain.VCSProvider(raw)
+		if !isKnownProvider(p) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+				domain.InvalidRequest,
+				"unknown provider "+raw,
+			))
+			return
+		}
+		provider = &p
+	}
+
+	mappings, err := s.webhookRepo.ListUserMappings(c.Request.Context(), provider)
+	if err != nil {
+		logger.Logger.Error("error listing user mappings: ", err)
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(
+			domain.InternalError,
+			domain.ErrUserMappingMsg,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"mappings": mappings})
+}
+
+func isKnownProvider(provider domain.VCSProvider) bool {
+	switch provider {
+	case domain.VCSProviderGitHub, domain.VCSProviderGitLab:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,99 @@
+package webhookService
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/mocks"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/webhookStorage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookService_SetUserMapping(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWebhookRepo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+	service := NewWebhookService(mockWebhookRepo, &fakePullRequestOps{}, Secrets{})
+
+	newContext := func(body string) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/webhooks/userMappings", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		return c, w
+	}
+
+	mapping := domain.VCSUserMapping{Provider: domain.VCSProviderGitHub, Login: "octocat", UserID: "u1"}
+
+	t.Run("success", func(t *testing.T) {
+		mockWebhookRepo.EXPECT().UpsertUserMapping(gomock.Any(), mapping).Return(nil)
+
+		c, w := newContext(`{"provider":"github","login":"octocat","user_id":"u1"}`)
+		service.SetUserMapping(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		c, w := newContext(`{"provider":"svn","login":"octocat","user_id":"u1"}`)
+		service.SetUserMapping(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockWebhookRepo.EXPECT().UpsertUserMapping(gomock.Any(), mapping).Return(webhookStorage.ErrUserNotExists)
+
+		c, w := newContext(`{"provider":"github","login":"octocat","user_id":"u1"}`)
+		service.SetUserMapping(c)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		mockWebhookRepo.EXPECT().UpsertUserMapping(gomock.Any(), mapping).Return(errors.New("db error"))
+
+		c, w := newContext(`{"provider":"github","login":"octocat","user_id":"u1"}`)
+		service.SetUserMapping(c)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestWebhookService_ListUserMappings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWebhookRepo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+	service := NewWebhookService(mockWebhookRepo, &fakePullRequestOps{}, Secrets{})
+
+	t.Run("filter by provider", func(t *testing.T) {
+		provider := domain.VCSProviderGitHub
+		mockWebhookRepo.EXPECT().ListUserMappings(gomock.Any(), &provider).
+			Return([]domain.VCSUserMapping{{Provider: provider, Login: "octocat", UserID: "u1"}}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/webhooks/userMappings?provider=github", nil)
+		service.ListUserMappings(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), "octocat")
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/webhooks/userMappings?provider=svn", nil)
+		service.ListUserMappings(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
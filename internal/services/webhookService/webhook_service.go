@@ -0,0 +1,43 @@
+package webhookService
+
+import (
+	"context"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage"
+)
+
+// maxWebhookBodyBytes - предел тела вебхука (GitHub обрезает payload на 25 МБ)
+const maxWebhookBodyBytes = 25 << 20
+
+// PullRequestOperations - операции над PR, в которые переводятся события VCS (реализованы в pullRequestService)
+type PullRequestOperations interface {
+	CreatePullRequest(ctx context.Context, req domain.CreatePullRequestRequest) (*domain.PullRequestResponse, error)
+	MergePullRequest(ctx context.Context, prID string) (*domain.PullRequestResponse, error)
+	ClosePullRequest(ctx context.Context, prID string) (*domain.PullRequestResponse, error)
+	ReopenPullRequest(ctx context.Context, prID string) (*domain.PullRequestResponse, error)
+}
+
+// Secrets - секреты для проверки подлинности вебхуков; пустой секрет отключает приём от провайдера
+type Secrets struct {
+	GitHub string
+	GitLab string
+}
+
+type WebhookServiceImpl struct {
+	webhookRepo storage.WebhookRepositoryInterface
+	prOps       PullRequestOperations
+	secrets     Secrets
+}
+
+func NewWebhookService(
+	webhookRepo storage.WebhookRepositoryInterface,
+	prOps PullRequestOperations,
+	secrets Secrets,
+) *WebhookServiceImpl {
+	return &WebhookServiceImpl{
+		webhookRepo: webhookRepo,
+		prOps:       prOps,
+		secrets:     secrets,
+	}
+}
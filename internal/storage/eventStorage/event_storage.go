@@ -0,0 +1,56 @@
+package eventStorage
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/db"
+)
+
+const lockStreamPositionQuery = `SELECT pg_advisory_xact_lock(hashtext('outbox_stream_position'))`
+
+const insertOutboxEventQuery = `
+	INSERT INTO outbox_events (event_id, event_type, occurred_at, data, stream_position)
+	VALUES ($1, $2, $3, $4, nextval('outbox_stream_position_seq'))`
+
+const insertAssignmentEventQuery = `
+	INSERT INTO assignment_events (pull_request_id, event_type, strategy, old_reviewer_id, new_reviewer_id)
+	VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''))`
+
+// WriteEvents записывает события в outbox внутри транзакции изменения: событие появится только
+// вместе с закоммиченным изменением и не потеряется, если процесс упадёт сразу после коммита.
+// Здесь же событиям выдаются позиции в потоке очереди ревью: advisory-блокировка держится до коммита,
+// поэтому позиции коммитятся по возрастанию независимо от публикации. Блокировка сериализует
+// записывающие транзакции, поэтому WriteEvents вызывается последним перед Commit
+func WriteEvents(ctx context.Context, tx pgx.Tx, events []domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, lockStreamPositionQuery); err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		_, err := tx.Exec(ctx, insertOutboxEventQuery, event.ID, string(event.Type), event.OccurredAt, []byte(event.Data))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteAssignmentEvent записывает событие в assignment_events. Замены пишут его в своей транзакции
+// (q - pgx.Tx), чтобы история совпадала с pr_reviewers
+func WriteAssignmentEvent(ctx context.Context, q db.Querier, event domain.AssignmentEvent) error {
+	_, err := q.Exec(ctx, insertAssignmentEventQuery,
+		event.PrID,
+		string(event.Type),
+		string(event.Strategy),
+		event.OldReviewerID,
+		event.NewReviewerID,
+	)
+	return err
+}
@@ -0,0 +1,113 @@
+package eventStorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteEvents(t *testing.T) {
+	ctx := context.Background()
+	occurredAt := time.Date(2025, 11, 29, 10, 0, 0, 0, time.UTC)
+
+	events := []domain.Event{
+		{ID: "event-1", Type: domain.EventPRCreated, OccurredAt: occurredAt, Data: []byte(`{"pull_request_id":"pr-1"}`)},
+		{ID: "event-2", Type: domain.EventReviewerAssigned, OccurredAt: occurredAt, Data: []byte(`{"reviewer_id":"u2"}`)},
+	}
+
+	t.Run("writes every event inside transaction", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("pg_advisory_xact_lock").
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectExec("INSERT INTO outbox_events").
+			WithArgs("event-1", "pr.created", occurredAt, []byte(`{"pull_request_id":"pr-1"}`)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO outbox_events").
+			WithArgs("event-2", "reviewer.assigned", occurredAt, []byte(`{"reviewer_id":"u2"}`)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		tx, err := mock.Begin(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, WriteEvents(ctx, tx, events))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("insert error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("pg_advisory_xact_lock").
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectExec("INSERT INTO outbox_events").
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(errors.New("db error"))
+
+		tx, err := mock.Begin(ctx)
+		require.NoError(t, err)
+
+		require.Error(t, WriteEvents(ctx, tx, events))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no events - no stream lock", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectBegin()
+
+		tx, err := mock.Begin(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, WriteEvents(ctx, tx, nil))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWriteAssignmentEvent(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("writes event with optional fields", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec("INSERT INTO assignment_events").
+			WithArgs("pr-1", string(domain.AssignmentEventRemoved), "", "u1", "").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		err = WriteAssignmentEvent(ctx, mock, domain.AssignmentEvent{
+			PrID:          "pr-1",
+			Type:          domain.AssignmentEventRemoved,
+			OldReviewerID: "u1",
+		})
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("insert error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec("INSERT INTO assignment_events").
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(errors.New("db error"))
+
+		require.Error(t, WriteAssignmentEvent(ctx, mock, domain.AssignmentEvent{PrID: "pr-1"}))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
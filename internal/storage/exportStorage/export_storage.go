@@ -0,0 +1,122 @@
+package exportStorage
+
+import (
+	"context"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/db"
+)
+
+type ExportStorage struct {
+	db db.Querier
+}
+
+func NewExportStorage(db db.Querier) *ExportStorage {
+	return &ExportStorage{
+		db: db,
+	}
+}
+
+// StreamPullRequests читает PR, созданные в [filter.From, filter.To), и отдаёт их в fn по одной строке,
+// не собирая выгрузку в память. Ошибка fn прерывает чтение и возвращается как есть
+func (s *ExportStorage) StreamPullRequests(
+	ctx context.Context,
+	filter domain.ExportFilter,
+	fn func(row domain.PullRequestExportRow) error,
+) error {
+	query := `
+		SELECT pr.id, pr.name, pr.author_id, au.name, t.name, pr.status::text, pr.need_more_reviewers,
+			coalesce((
+				SELECT array_agg(prr.reviewer_id ORDER BY prr.reviewer_id)
+				FROM pr_reviewers prr
+				WHERE prr.pull_request_id = pr.id
+			), '{}'),
+			pr.created_at, pr.merged_at
+		FROM pull_requests pr
+		LEFT JOIN users au ON au.id = pr.author_id
+		LEFT JOIN teams t ON t.id = au.team_id
+		WHERE ($1::timestamp IS NULL OR pr.created_at >= $1)
+		  AND ($2::timestamp IS NULL OR pr.created_at < $2)
+		ORDER BY pr.created_at, pr.id`
+
+	rows, err := s.db.Query(ctx, query, filter.From, filter.To)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row domain.PullRequestExportRow
+		if err = rows.Scan(
+			&row.PullRequestID,
+			&row.PullRequestName,
+			&row.AuthorID,
+			&row.AuthorName,
+			&row.TeamName,
+			&row.Status,
+			&row.NeedMoreReviewers,
+			&row.ReviewerIDs,
+			&row.CreatedAt,
+			&row.MergedAt,
+		); err != nil {
+			return err
+		}
+
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// StreamReviews читает текущие назначения ревьюверов, сделанные в [filter.From, filter.To),
+// и отдаёт их в fn по одной строке. Заменённые ревьюверы удаляются из pr_reviewers и в выгрузку не попадают
+func (s *ExportStorage) StreamReviews(
+	ctx context.Context,
+	filter domain.ExportFilter,
+	fn func(row domain.ReviewExportRow) error,
+) error {
+	query := `
+		SELECT prr.pull_request_id, pr.name, pr.author_id, t.name, prr.reviewer_id, ru.name, pr.status::text,
+			prr.assignment_meta->>'strategy', prr.assigned_at, pr.created_at, pr.merged_at
+		FROM pr_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pull_request_id
+		JOIN users ru ON ru.id = prr.reviewer_id
+		LEFT JOIN users au ON au.id = pr.author_id
+		LEFT JOIN teams t ON t.id = au.team_id
+		WHERE ($1::timestamp IS NULL OR prr.assigned_at >= $1)
+		  AND ($2::timestamp IS NULL OR prr.assigned_at < $2)
+		ORDER BY prr.assigned_at, prr.pull_request_id, prr.reviewer_id`
+
+	rows, err := s.db.Query(ctx, query, filter.From, filter.To)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row domain.ReviewExportRow
+		if err = rows.Scan(
+			&row.PullRequestID,
+			&row.PullRequestName,
+			&row.AuthorID,
+			&row.TeamName,
+			&row.ReviewerID,
+			&row.ReviewerName,
+			&row.Status,
+			&row.AssignmentStrategy,
+			&row.AssignedAt,
+			&row.CreatedAt,
+			&row.MergedAt,
+		); err != nil {
+			return err
+		}
+
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
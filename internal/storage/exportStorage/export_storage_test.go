@@ -0,0 +1,135 @@
+package exportStorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportStorage_StreamPullRequests(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	filter := domain.ExportFilter{From: &from}
+	columns := []string{"id", "name", "author_id", "author_name", "team_name", "status", "need_more",
+		"reviewers", "created_at", "merged_at"}
+
+	t.Run("streams rows in order", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewExportStorage(mock)
+		authorID, authorName, teamName := "user-alice", "Alice", "Backend"
+		createdAt := from.Add(time.Hour)
+		mergedAt := from.Add(5 * time.Hour)
+
+		mock.ExpectQuery("FROM pull_requests pr").
+			WithArgs(filter.From, filter.To).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow("pr-1", "Add feature", &authorID, &authorName, &teamName, "MERGED", false,
+					[]string{"user-bob"}, &createdAt, &mergedAt).
+				AddRow("pr-2", "Fix bug", &authorID, &authorName, (*string)(nil), "OPEN", true,
+					[]string{}, &createdAt, (*time.Time)(nil)))
+
+		var rows []domain.PullRequestExportRow
+		err = storage.StreamPullRequests(ctx, filter, func(row domain.PullRequestExportRow) error {
+			rows = append(rows, row)
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, "pr-1", rows[0].PullRequestID)
+		assert.Equal(t, []string{"user-bob"}, rows[0].ReviewerIDs)
+		assert.Equal(t, &mergedAt, rows[0].MergedAt)
+		assert.Nil(t, rows[1].TeamName)
+		assert.True(t, rows[1].NeedMoreReviewers)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("callback error stops streaming", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewExportStorage(mock)
+		createdAt := from
+
+		mock.ExpectQuery("FROM pull_requests pr").
+			WithArgs(filter.From, filter.To).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow("pr-1", "A", (*string)(nil), (*string)(nil), (*string)(nil), "OPEN", false,
+					[]string{}, &createdAt, (*time.Time)(nil)).
+				AddRow("pr-2", "B", (*string)(nil), (*string)(nil), (*string)(nil), "OPEN", false,
+					[]string{}, &createdAt, (*time.Time)(nil)))
+
+		writeErr := errors.New("client gone")
+		calls := 0
+		err = storage.StreamPullRequests(ctx, filter, func(row domain.PullRequestExportRow) error {
+			calls++
+			return writeErr
+		})
+
+		assert.ErrorIs(t, err, writeErr)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewExportStorage(mock)
+
+		mock.ExpectQuery("FROM pull_requests pr").
+			WithArgs(filter.From, filter.To).
+			WillReturnError(errors.New("database error"))
+
+		err = storage.StreamPullRequests(ctx, filter, func(row domain.PullRequestExportRow) error { return nil })
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestExportStorage_StreamReviews(t *testing.T) {
+	ctx := context.Background()
+	to := time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC)
+	filter := domain.ExportFilter{To: &to}
+
+	t.Run("streams review assignments", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewExportStorage(mock)
+		authorID, teamName, strategy := "user-alice", "Backend", string(domain.AssignmentStrategyRandom)
+		assignedAt := to.Add(-time.Hour)
+
+		mock.ExpectQuery("FROM pr_reviewers prr").
+			WithArgs(filter.From, filter.To).
+			WillReturnRows(pgxmock.NewRows([]string{"pr_id", "pr_name", "author_id", "team_name", "reviewer_id",
+				"reviewer_name", "status", "strategy", "assigned_at", "created_at", "merged_at"}).
+				AddRow("pr-1", "Add feature", &authorID, &teamName, "user-bob", "Bob", "OPEN",
+					&strategy, &assignedAt, &assignedAt, (*time.Time)(nil)))
+
+		var rows []domain.ReviewExportRow
+		err = storage.StreamReviews(ctx, filter, func(row domain.ReviewExportRow) error {
+			rows = append(rows, row)
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, "user-bob", rows[0].ReviewerID)
+		assert.Equal(t, &strategy, rows[0].AssignmentStrategy)
+		assert.Nil(t, rows[0].MergedAt)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,251 @@
+package metricsStorage
+
+import (
+	"context"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/db"
+)
+
+type MetricsStorage struct {
+	db db.Querier
+}
+
+func NewMetricsStorage(db db.Querier) *MetricsStorage {
+	return &MetricsStorage{
+		db: db,
+	}
+}
+
+// prTeamJoin - команда PR по основной команде автора
+const prTeamJoin = `
+	JOIN users u ON u.id = pr.author_id
+	LEFT JOIN teams t ON t.id = u.team_id`
+
+// histogramQuery группирует длительности (в часах) по команде и номеру бакета width_bucket.
+// width_bucket относит значение, равное границе, к следующему бакету - для часовых длительностей это не важно
+const histogramQuery = `
+	SELECT team_name, width_bucket(hours, $1::float8[]), count(*), sum(hours)
+	FROM durations
+	GROUP BY 1, 2`
+
+// GetPRLifecycleSnapshot считает агрегаты для метрик жизненного цикла PR по всем данным в БД
+func (s *MetricsStorage) GetPRLifecycleSnapshot(ctx context.Context, buckets []float64) (*domain.PRLifecycleSnapshot, error) {
+	timeToMergeQuery := `
+		WITH durations AS (
+			SELECT coalesce(t.name, $2) AS team_name,
+				extract(epoch FROM pr.merged_at - pr.created_at) / 3600 AS hours
+			FROM pull_requests pr` + prTeamJoin + `
+			WHERE pr.status = $3 AND pr.merged_at IS NOT NULL
+		)` + histogramQuery
+
+	timeToMerge, err := s.queryHistograms(ctx, timeToMergeQuery, buckets,
+		domain.MetricsTeamNone, string(domain.PullRequestStatusMERGED))
+	if err != nil {
+		return nil, err
+	}
+
+	// Первое назначение - самое раннее из текущих назначений и замен: строки заменённых ревьюверов
+	// удаляются из pr_reviewers, но момент замены остаётся в assignment_events
+	timeToFirstReviewQuery := `
+		WITH first_assignments AS (
+			SELECT pr.id, pr.created_at, coalesce(t.name, $2) AS team_name,
+				least(
+					(SELECT min(prr.assigned_at) FROM pr_reviewers prr WHERE prr.pull_request_id = pr.id),
+					(SELECT min(ev.created_at) FROM assignment_events ev
+						WHERE ev.pull_request_id = pr.id AND ev.old_reviewer_id IS NOT NULL)
+				) AS first_assigned_at
+			FROM pull_requests pr` + prTeamJoin + `
+		),
+		durations AS (
+			SELECT team_name, greatest(extract(epoch FROM first_assigned_at - created_at), 0) / 3600 AS hours
+			FROM first_assignments
+			WHERE first_assigned_at IS NOT NULL
+		)` + histogramQuery
+
+	timeToFirstReview, err := s.queryHistograms(ctx, timeToFirstReviewQuery, buckets, domain.MetricsTeamNone)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.queryEventCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PRLifecycleSnapshot{
+		TimeToMerge:       timeToMerge,
+		TimeToFirstReview: timeToFirstReview,
+		Events:            events,
+	}, nil
+}
+
+func (s *MetricsStorage) queryHistograms(
+	ctx context.Context,
+	query string,
+	buckets []float64,
+	args ...any,
+) ([]domain.TeamHistogram, error) {
+	rows, err := s.db.Query(ctx, query, append([]any{buckets}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byTeam := make(map[string]*domain.TeamHistogram)
+	order := make([]string, 0)
+	for rows.Next() {
+		var teamName string
+		var bucket int
+		var count int64
+		var sum float64
+		if err = rows.Scan(&teamName, &bucket, &count, &sum); err != nil {
+			return nil, err
+		}
+
+		histogram, ok := byTeam[teamName]
+		if !ok {
+			histogram = &domain.TeamHistogram{
+				TeamName:         teamName,
+				CumulativeCounts: make(map[float64]uint64, len(buckets)),
+			}
+			for _, upperBound := range buckets {
+				histogram.CumulativeCounts[upperBound] = 0
+			}
+			byTeam[teamName] = histogram
+			order = append(order, teamName)
+		}
+
+		histogram.Count += uint64(count)
+		histogram.Sum += sum
+		// Значения из бакета с номером bucket не больше всех границ, начиная с buckets[bucket]
+		for i := bucket; i < len(buckets); i++ {
+			histogram.CumulativeCounts[buckets[i]] += uint64(count)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	histograms := make([]domain.TeamHistogram, 0, len(order))
+	for _, teamName := range order {
+		histograms = append(histograms, *byTeam[teamName])
+	}
+
+	return histograms, nil
+}
+
+func (s *MetricsStorage) queryEventCounts(ctx context.Context) ([]domain.TeamEventCount, error) {
+	query := `
+		SELECT coalesce(t.name, $1), ev.event_type, coalesce(ev.strategy, ''), count(*)
+		FROM assignment_events ev
+		JOIN pull_requests pr ON pr.id = ev.pull_request_id` + prTeamJoin + `
+		GROUP BY 1, 2, 3`
+
+	rows, err := s.db.Query(ctx, query, domain.MetricsTeamNone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]domain.TeamEventCount, 0)
+	for rows.Next() {
+		var teamName, eventType, strategy string
+		var count int64
+		if err = rows.Scan(&teamName, &eventType, &strategy, &count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, domain.TeamEventCount{
+			TeamName: teamName,
+			Type:     domain.AssignmentEventType(eventType),
+			Strategy: domain.AssignmentStrategy(strategy),
+			Count:    uint64(count),
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// GetReviewLoadSnapshot считает открытые PR по командам и число открытых ревью у каждого
+// активного участника каждой команды (включая участников без ревью)
+func (s *MetricsStorage) GetReviewLoadSnapshot(ctx context.Context) (*domain.ReviewLoadSnapshot, error) {
+	openPRsQuery := `
+		SELECT coalesce(t.name, $1), count(*), count(*) FILTER (WHERE pr.need_more_reviewers)
+		FROM pull_requests pr` + prTeamJoin + `
+		WHERE pr.status = $2
+		GROUP BY 1`
+
+	rows, err := s.db.Query(ctx, openPRsQuery, domain.MetricsTeamNone, string(domain.PullRequestStatusOPEN))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	openPRs := make([]domain.TeamOpenPRCounts, 0)
+	for rows.Next() {
+		var teamName string
+		var open, needMore int64
+		if err = rows.Scan(&teamName, &open, &needMore); err != nil {
+			return nil, err
+		}
+		openPRs = append(openPRs, domain.TeamOpenPRCounts{
+			TeamName:          teamName,
+			Open:              uint64(open),
+			NeedMoreReviewers: uint64(needMore),
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	memberLoads, err := s.queryMemberLoads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ReviewLoadSnapshot{
+		OpenPRs:     openPRs,
+		MemberLoads: memberLoads,
+	}, nil
+}
+
+func (s *MetricsStorage) queryMemberLoads(ctx context.Context) ([]domain.TeamMemberLoad, error) {
+	query := `
+		SELECT t.name, u.id, count(pr.id)
+		FROM team_members tm
+		JOIN teams t ON t.id = tm.team_id
+		JOIN users u ON u.id = tm.user_id AND u.is_active
+		LEFT JOIN pr_reviewers prr ON prr.reviewer_id = u.id
+		LEFT JOIN pull_requests pr ON pr.id = prr.pull_request_id AND pr.status = $1
+		GROUP BY t.name, u.id
+		ORDER BY t.name, u.id`
+
+	rows, err := s.db.Query(ctx, query, string(domain.PullRequestStatusOPEN))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	loads := make([]domain.TeamMemberLoad, 0)
+	for rows.Next() {
+		var load domain.TeamMemberLoad
+		var openReviews int64
+		if err = rows.Scan(&load.TeamName, &load.UserID, &openReviews); err != nil {
+			return nil, err
+		}
+		load.OpenReviews = uint64(openReviews)
+		loads = append(loads, load)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return loads, nil
+}
@@ -0,0 +1,141 @@
+package metricsStorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsStorage_GetPRLifecycleSnapshot(t *testing.T) {
+	ctx := context.Background()
+	buckets := []float64{1, 24}
+
+	t.Run("successfully build snapshot", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewMetricsStorage(mock)
+
+		// Backend: 1 PR до часа, 2 PR от 1 до 24 часов, 1 PR дольше суток
+		mock.ExpectQuery("extract\\(epoch FROM pr.merged_at - pr.created_at\\)").
+			WithArgs(buckets, domain.MetricsTeamNone, string(domain.PullRequestStatusMERGED)).
+			WillReturnRows(pgxmock.NewRows([]string{"team_name", "bucket", "count", "sum"}).
+				AddRow("Backend", 0, int64(1), 0.5).
+				AddRow("Backend", 1, int64(2), 10.0).
+				AddRow("Backend", 2, int64(1), 30.0).
+				AddRow(domain.MetricsTeamNone, 2, int64(1), 48.0))
+
+		mock.ExpectQuery("first_assigned_at").
+			WithArgs(buckets, domain.MetricsTeamNone).
+			WillReturnRows(pgxmock.NewRows([]string{"team_name", "bucket", "count", "sum"}).
+				AddRow("Backend", 0, int64(5), 0.0))
+
+		mock.ExpectQuery("FROM assignment_events ev").
+			WithArgs(domain.MetricsTeamNone).
+			WillReturnRows(pgxmock.NewRows([]string{"team_name", "event_type", "strategy", "count"}).
+				AddRow("Backend", string(domain.AssignmentEventReassigned), string(domain.AssignmentStrategyReassign), int64(3)).
+				AddRow("Backend", string(domain.AssignmentEventNoCandidate), string(domain.AssignmentStrategyReassign), int64(1)))
+
+		snapshot, err := storage.GetPRLifecycleSnapshot(ctx, buckets)
+
+		require.NoError(t, err)
+		require.Len(t, snapshot.TimeToMerge, 2)
+		assert.Equal(t, domain.TeamHistogram{
+			TeamName:         "Backend",
+			Count:            4,
+			Sum:              40.5,
+			CumulativeCounts: map[float64]uint64{1: 1, 24: 3},
+		}, snapshot.TimeToMerge[0])
+		assert.Equal(t, map[float64]uint64{1: 0, 24: 0}, snapshot.TimeToMerge[1].CumulativeCounts)
+		assert.Equal(t, uint64(1), snapshot.TimeToMerge[1].Count)
+
+		require.Len(t, snapshot.TimeToFirstReview, 1)
+		assert.Equal(t, map[float64]uint64{1: 5, 24: 5}, snapshot.TimeToFirstReview[0].CumulativeCounts)
+
+		require.Len(t, snapshot.Events, 2)
+		assert.Equal(t, uint64(3), snapshot.Events[0].Count)
+		assert.Equal(t, domain.AssignmentEventNoCandidate, snapshot.Events[1].Type)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewMetricsStorage(mock)
+
+		mock.ExpectQuery("extract\\(epoch FROM pr.merged_at - pr.created_at\\)").
+			WithArgs(buckets, domain.MetricsTeamNone, string(domain.PullRequestStatusMERGED)).
+			WillReturnError(errors.New("database error"))
+
+		_, err = storage.GetPRLifecycleSnapshot(ctx, buckets)
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMetricsStorage_GetReviewLoadSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully build snapshot", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewMetricsStorage(mock)
+
+		mock.ExpectQuery("FILTER \\(WHERE pr.need_more_reviewers\\)").
+			WithArgs(domain.MetricsTeamNone, string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"team_name", "open", "need_more"}).
+				AddRow("Backend", int64(4), int64(1)).
+				AddRow(domain.MetricsTeamNone, int64(1), int64(1)))
+
+		mock.ExpectQuery("FROM team_members tm").
+			WithArgs(string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"team_name", "user_id", "open_reviews"}).
+				AddRow("Backend", "u1", int64(3)).
+				AddRow("Backend", "u2", int64(0)))
+
+		snapshot, err := storage.GetReviewLoadSnapshot(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []domain.TeamOpenPRCounts{
+			{TeamName: "Backend", Open: 4, NeedMoreReviewers: 1},
+			{TeamName: domain.MetricsTeamNone, Open: 1, NeedMoreReviewers: 1},
+		}, snapshot.OpenPRs)
+		assert.Equal(t, []domain.TeamMemberLoad{
+			{TeamName: "Backend", UserID: "u1", OpenReviews: 3},
+			{TeamName: "Backend", UserID: "u2", OpenReviews: 0},
+		}, snapshot.MemberLoads)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("member loads query error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewMetricsStorage(mock)
+
+		mock.ExpectQuery("FILTER \\(WHERE pr.need_more_reviewers\\)").
+			WithArgs(domain.MetricsTeamNone, string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"team_name", "open", "need_more"}))
+
+		mock.ExpectQuery("FROM team_members tm").
+			WithArgs(string(domain.PullRequestStatusOPEN)).
+			WillReturnError(errors.New("database error"))
+
+		_, err = storage.GetReviewLoadSnapshot(ctx)
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -36,19 +37,77 @@ func (m *MockTeamRepositoryInterface) EXPECT() *MockTeamRepositoryInterfaceMockR
 	return m.recorder
 }
 
+// ActivateTeamMembers mocks base method.
+func (m *MockTeamRepositoryInterface) ActivateTeamMembers(ctx context.Context, teamName string, userIDs []string, reassignments []domain.ReviewerReassignment) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateTeamMembers", ctx, teamName, userIDs, reassignments)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActivateTeamMembers indicates an expected call of ActivateTeamMembers.
+func (mr *MockTeamRepositoryInterfaceMockRecorder) ActivateTeamMembers(ctx, teamName, userIDs, reassignments interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateTeamMembers", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).ActivateTeamMembers), ctx, teamName, userIDs, reassignments)
+}
+
+// AddTeamMember mocks base method.
+func (m *MockTeamRepositoryInterface) AddTeamMember(ctx context.Context, teamName, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTeamMember", ctx, teamName, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTeamMember indicates an expected call of AddTeamMember.
+func (mr *MockTeamRepositoryInterfaceMockRecorder) AddTeamMember(ctx, teamName, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTeamMember", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).AddTeamMember), ctx, teamName, userID)
+}
+
+// ApplyDeactivationPlan mocks base method.
+func (m *MockTeamRepositoryInterface) ApplyDeactivationPlan(ctx context.Context, plan *domain.DeactivationPlan) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyDeactivationPlan", ctx, plan)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyDeactivationPlan indicates an expected call of ApplyDeactivationPlan.
+func (mr *MockTeamRepositoryInterfaceMockRecorder) ApplyDeactivationPlan(ctx, plan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyDeactivationPlan", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).ApplyDeactivationPlan), ctx, plan)
+}
+
+// ApplyRosterImportPlan mocks base method.
+func (m *MockTeamRepositoryInterface) ApplyRosterImportPlan(ctx context.Context, plan *domain.RosterImportPlan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyRosterImportPlan", ctx, plan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyRosterImportPlan indicates an expected call of ApplyRosterImportPlan.
+func (mr *MockTeamRepositoryInterfaceMockRecorder) ApplyRosterImportPlan(ctx, plan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyRosterImportPlan", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).ApplyRosterImportPlan), ctx, plan)
+}
+
 // CreateTeamWithMembers mocks base method.
-func (m *MockTeamRepositoryInterface) CreateTeamWithMembers(ctx context.Context, teamName string, members []domain.TeamMember) (uuid.UUID, error) {
+func (m *MockTeamRepositoryInterface) CreateTeamWithMembers(ctx context.Context, teamName string, parentTeamName *string, members []domain.TeamMember) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTeamWithMembers", ctx, teamName, members)
+	ret := m.ctrl.Call(m, "CreateTeamWithMembers", ctx, teamName, parentTeamName, members)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateTeamWithMembers indicates an expected call of CreateTeamWithMembers.
-func (mr *MockTeamRepositoryInterfaceMockRecorder) CreateTeamWithMembers(ctx, teamName, members interface{}) *gomock.Call {
+func (mr *MockTeamRepositoryInterfaceMockRecorder) CreateTeamWithMembers(ctx, teamName, parentTeamName, members interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTeamWithMembers", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).CreateTeamWithMembers), ctx, teamName, members)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTeamWithMembers", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).CreateTeamWithMembers), ctx, teamName, parentTeamName, members)
 }
 
 // DeactivateTeamMembers mocks base method.
@@ -66,6 +125,51 @@ func (mr *MockTeamRepositoryInterfaceMockRecorder) DeactivateTeamMembers(ctx, te
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateTeamMembers", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).DeactivateTeamMembers), ctx, teamName, userIDs, reassignments)
 }
 
+// DeactivateUser mocks base method.
+func (m *MockTeamRepositoryInterface) DeactivateUser(ctx context.Context, plan *domain.DeactivationPlan) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateUser", ctx, plan)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeactivateUser indicates an expected call of DeactivateUser.
+func (mr *MockTeamRepositoryInterfaceMockRecorder) DeactivateUser(ctx, plan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateUser", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).DeactivateUser), ctx, plan)
+}
+
+// GetDeactivationPlan mocks base method.
+func (m *MockTeamRepositoryInterface) GetDeactivationPlan(ctx context.Context, planID uuid.UUID) (*domain.DeactivationPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeactivationPlan", ctx, planID)
+	ret0, _ := ret[0].(*domain.DeactivationPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeactivationPlan indicates an expected call of GetDeactivationPlan.
+func (mr *MockTeamRepositoryInterfaceMockRecorder) GetDeactivationPlan(ctx, planID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeactivationPlan", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).GetDeactivationPlan), ctx, planID)
+}
+
+// GetRoster mocks base method.
+func (m *MockTeamRepositoryInterface) GetRoster(ctx context.Context) ([]domain.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoster", ctx)
+	ret0, _ := ret[0].([]domain.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoster indicates an expected call of GetRoster.
+func (mr *MockTeamRepositoryInterfaceMockRecorder) GetRoster(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoster", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).GetRoster), ctx)
+}
+
 // GetTeamByName mocks base method.
 func (m *MockTeamRepositoryInterface) GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +185,106 @@ func (mr *MockTeamRepositoryInterfaceMockRecorder) GetTeamByName(ctx, teamName i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamByName", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).GetTeamByName), ctx, teamName)
 }
 
+// GetTeamWithAncestors mocks base method.
+func (m *MockTeamRepositoryInterface) GetTeamWithAncestors(ctx context.Context, teamName string) ([]domain.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamWithAncestors", ctx, teamName)
+	ret0, _ := ret[0].([]domain.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamWithAncestors indicates an expected call of GetTeamWithAncestors.
+func (mr *MockTeamRepositoryInterfaceMockRecorder) GetTeamWithAncestors(ctx, teamName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamWithAncestors", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).GetTeamWithAncestors), ctx, teamName)
+}
+
+// GetTeamsHierarchy mocks base method.
+func (m *MockTeamRepositoryInterface) GetTeamsHierarchy(ctx context.Context) ([]domain.TeamTreeNode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamsHierarchy", ctx)
+	ret0, _ := ret[0].([]domain.TeamTreeNode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamsHierarchy indicates an expected call of GetTeamsHierarchy.
+func (mr *MockTeamRepositoryInterfaceMockRecorder) GetTeamsHierarchy(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamsHierarchy", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).GetTeamsHierarchy), ctx)
+}
+
+// RemoveTeamMember mocks base method.
+func (m *MockTeamRepositoryInterface) RemoveTeamMember(ctx context.Context, teamName, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTeamMember", ctx, teamName, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTeamMember indicates an expected call of RemoveTeamMember.
+func (mr *MockTeamRepositoryInterfaceMockRecorder) RemoveTeamMember(ctx, teamName, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTeamMember", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).RemoveTeamMember), ctx, teamName, userID)
+}
+
+// SaveDeactivationPlan mocks base method.
+func (m *MockTeamRepositoryInterface) SaveDeactivationPlan(ctx context.Context, plan *domain.DeactivationPlan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeactivationPlan", ctx, plan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDeactivationPlan indicates an expected call of SaveDeactivationPlan.
+func (mr *MockTeamRepositoryInterfaceMockRecorder) SaveDeactivationPlan(ctx, plan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeactivationPlan", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).SaveDeactivationPlan), ctx, plan)
+}
+
+// SetPrimaryTeam mocks base method.
+func (m *MockTeamRepositoryInterface) SetPrimaryTeam(ctx context.Context, userID, teamName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPrimaryTeam", ctx, userID, teamName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPrimaryTeam indicates an expected call of SetPrimaryTeam.
+func (mr *MockTeamRepositoryInterfaceMockRecorder) SetPrimaryTeam(ctx, userID, teamName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrimaryTeam", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).SetPrimaryTeam), ctx, userID, teamName)
+}
+
+// SetReviewSLA mocks base method.
+func (m *MockTeamRepositoryInterface) SetReviewSLA(ctx context.Context, teamName string, hours *int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReviewSLA", ctx, teamName, hours)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReviewSLA indicates an expected call of SetReviewSLA.
+func (mr *MockTeamRepositoryInterfaceMockRecorder) SetReviewSLA(ctx, teamName, hours interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReviewSLA", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).SetReviewSLA), ctx, teamName, hours)
+}
+
+// SetTeamParent mocks base method.
+func (m *MockTeamRepositoryInterface) SetTeamParent(ctx context.Context, teamName string, parentTeamName *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTeamParent", ctx, teamName, parentTeamName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTeamParent indicates an expected call of SetTeamParent.
+func (mr *MockTeamRepositoryInterfaceMockRecorder) SetTeamParent(ctx, teamName, parentTeamName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTeamParent", reflect.TypeOf((*MockTeamRepositoryInterface)(nil).SetTeamParent), ctx, teamName, parentTeamName)
+}
+
 // MockUserRepositoryInterface is a mock of UserRepositoryInterface interface.
 type MockUserRepositoryInterface struct {
 	ctrl     *gomock.Controller
@@ -104,6 +308,20 @@ func (m *MockUserRepositoryInterface) EXPECT() *MockUserRepositoryInterfaceMockR
 	return m.recorder
 }
 
+// CreateUser mocks base method.
+func (m *MockUserRepositoryInterface) CreateUser(ctx context.Context, userID, username string, isActive bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, userID, username, isActive)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserRepositoryInterfaceMockRecorder) CreateUser(ctx, userID, username, isActive interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepositoryInterface)(nil).CreateUser), ctx, userID, username, isActive)
+}
+
 // GetUserByID mocks base method.
 func (m *MockUserRepositoryInterface) GetUserByID(ctx context.Context, userID string) (*domain.User, error) {
 	m.ctrl.T.Helper()
@@ -119,6 +337,22 @@ func (mr *MockUserRepositoryInterfaceMockRecorder) GetUserByID(ctx, userID inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetUserByID), ctx, userID)
 }
 
+// ListUsers mocks base method.
+func (m *MockUserRepositoryInterface) ListUsers(ctx context.Context, filter domain.UserFilter, offset, limit int) ([]domain.User, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, filter, offset, limit)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockUserRepositoryInterfaceMockRecorder) ListUsers(ctx, filter, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockUserRepositoryInterface)(nil).ListUsers), ctx, filter, offset, limit)
+}
+
 // SetUserIsActive mocks base method.
 func (m *MockUserRepositoryInterface) SetUserIsActive(ctx context.Context, userID string, isActive bool) error {
 	m.ctrl.T.Helper()
@@ -133,6 +367,20 @@ func (mr *MockUserRepositoryInterfaceMockRecorder) SetUserIsActive(ctx, userID,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserIsActive", reflect.TypeOf((*MockUserRepositoryInterface)(nil).SetUserIsActive), ctx, userID, isActive)
 }
 
+// SetUserName mocks base method.
+func (m *MockUserRepositoryInterface) SetUserName(ctx context.Context, userID, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserName", ctx, userID, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserName indicates an expected call of SetUserName.
+func (mr *MockUserRepositoryInterfaceMockRecorder) SetUserName(ctx, userID, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserName", reflect.TypeOf((*MockUserRepositoryInterface)(nil).SetUserName), ctx, userID, username)
+}
+
 // MockPullRequestRepositoryInterface is a mock of PullRequestRepositoryInterface interface.
 type MockPullRequestRepositoryInterface struct {
 	ctrl     *gomock.Controller
@@ -156,18 +404,32 @@ func (m *MockPullRequestRepositoryInterface) EXPECT() *MockPullRequestRepository
 	return m.recorder
 }
 
+// ClosePullRequest mocks base method.
+func (m *MockPullRequestRepositoryInterface) ClosePullRequest(ctx context.Context, prID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePullRequest", ctx, prID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClosePullRequest indicates an expected call of ClosePullRequest.
+func (mr *MockPullRequestRepositoryInterfaceMockRecorder) ClosePullRequest(ctx, prID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePullRequest", reflect.TypeOf((*MockPullRequestRepositoryInterface)(nil).ClosePullRequest), ctx, prID)
+}
+
 // CreatePullRequestWithReviewers mocks base method.
-func (m *MockPullRequestRepositoryInterface) CreatePullRequestWithReviewers(ctx context.Context, pr *domain.PullRequest, reviewerIDs []string, needMoreReviewers bool) error {
+func (m *MockPullRequestRepositoryInterface) CreatePullRequestWithReviewers(ctx context.Context, pr *domain.PullRequest, reviewerIDs []string, needMoreReviewers bool, meta *domain.AssignmentMeta) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePullRequestWithReviewers", ctx, pr, reviewerIDs, needMoreReviewers)
+	ret := m.ctrl.Call(m, "CreatePullRequestWithReviewers", ctx, pr, reviewerIDs, needMoreReviewers, meta)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreatePullRequestWithReviewers indicates an expected call of CreatePullRequestWithReviewers.
-func (mr *MockPullRequestRepositoryInterfaceMockRecorder) CreatePullRequestWithReviewers(ctx, pr, reviewerIDs, needMoreReviewers interface{}) *gomock.Call {
+func (mr *MockPullRequestRepositoryInterfaceMockRecorder) CreatePullRequestWithReviewers(ctx, pr, reviewerIDs, needMoreReviewers, meta interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePullRequestWithReviewers", reflect.TypeOf((*MockPullRequestRepositoryInterface)(nil).CreatePullRequestWithReviewers), ctx, pr, reviewerIDs, needMoreReviewers)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePullRequestWithReviewers", reflect.TypeOf((*MockPullRequestRepositoryInterface)(nil).CreatePullRequestWithReviewers), ctx, pr, reviewerIDs, needMoreReviewers, meta)
 }
 
 // GetPullRequestByID mocks base method.
@@ -199,6 +461,20 @@ func (mr *MockPullRequestRepositoryInterfaceMockRecorder) MergePullRequest(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergePullRequest", reflect.TypeOf((*MockPullRequestRepositoryInterface)(nil).MergePullRequest), ctx, prID)
 }
 
+// ReopenPullRequest mocks base method.
+func (m *MockPullRequestRepositoryInterface) ReopenPullRequest(ctx context.Context, prID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReopenPullRequest", ctx, prID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReopenPullRequest indicates an expected call of ReopenPullRequest.
+func (mr *MockPullRequestRepositoryInterfaceMockRecorder) ReopenPullRequest(ctx, prID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReopenPullRequest", reflect.TypeOf((*MockPullRequestRepositoryInterface)(nil).ReopenPullRequest), ctx, prID)
+}
+
 // SetNeedMoreReviewers mocks base method.
 func (m *MockPullRequestRepositoryInterface) SetNeedMoreReviewers(ctx context.Context, prID string, needMore bool) error {
 	m.ctrl.T.Helper()
@@ -251,6 +527,21 @@ func (mr *MockPrReviewersRepositoryInterfaceMockRecorder) GetAssignedReviewers(c
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignedReviewers", reflect.TypeOf((*MockPrReviewersRepositoryInterface)(nil).GetAssignedReviewers), ctx, prID)
 }
 
+// GetOpenReviewsByReviewers mocks base method.
+func (m *MockPrReviewersRepositoryInterface) GetOpenReviewsByReviewers(ctx context.Context, reviewerIDs []string) ([]domain.OpenReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenReviewsByReviewers", ctx, reviewerIDs)
+	ret0, _ := ret[0].([]domain.OpenReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenReviewsByReviewers indicates an expected call of GetOpenReviewsByReviewers.
+func (mr *MockPrReviewersRepositoryInterfaceMockRecorder) GetOpenReviewsByReviewers(ctx, reviewerIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenReviewsByReviewers", reflect.TypeOf((*MockPrReviewersRepositoryInterface)(nil).GetOpenReviewsByReviewers), ctx, reviewerIDs)
+}
+
 // GetPRsByReviewer mocks base method.
 func (m *MockPrReviewersRepositoryInterface) GetPRsByReviewer(ctx context.Context, userID string) ([]domain.PullRequestShort, error) {
 	m.ctrl.T.Helper()
@@ -266,16 +557,736 @@ func (mr *MockPrReviewersRepositoryInterfaceMockRecorder) GetPRsByReviewer(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPRsByReviewer", reflect.TypeOf((*MockPrReviewersRepositoryInterface)(nil).GetPRsByReviewer), ctx, userID)
 }
 
+// GetPendingReviews mocks base method.
+func (m *MockPrReviewersRepositoryInterface) GetPendingReviews(ctx context.Context) ([]domain.PendingReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingReviews", ctx)
+	ret0, _ := ret[0].([]domain.PendingReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingReviews indicates an expected call of GetPendingReviews.
+func (mr *MockPrReviewersRepositoryInterfaceMockRecorder) GetPendingReviews(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingReviews", reflect.TypeOf((*MockPrReviewersRepositoryInterface)(nil).GetPendingReviews), ctx)
+}
+
+// GetReviewerAssignments mocks base method.
+func (m *MockPrReviewersRepositoryInterface) GetReviewerAssignments(ctx context.Context, prID string) ([]domain.ReviewerAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewerAssignments", ctx, prID)
+	ret0, _ := ret[0].([]domain.ReviewerAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewerAssignments indicates an expected call of GetReviewerAssignments.
+func (mr *MockPrReviewersRepositoryInterfaceMockRecorder) GetReviewerAssignments(ctx, prID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewerAssignments", reflect.TypeOf((*MockPrReviewersRepositoryInterface)(nil).GetReviewerAssignments), ctx, prID)
+}
+
 // ReassignReviewerAtomic mocks base method.
-func (m *MockPrReviewersRepositoryInterface) ReassignReviewerAtomic(ctx context.Context, prID, oldReviewerID, newReviewerID string) error {
+func (m *MockPrReviewersRepositoryInterface) ReassignReviewerAtomic(ctx context.Context, prID, oldReviewerID, newReviewerID string, meta *domain.AssignmentMeta) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReassignReviewerAtomic", ctx, prID, oldReviewerID, newReviewerID)
+	ret := m.ctrl.Call(m, "ReassignReviewerAtomic", ctx, prID, oldReviewerID, newReviewerID, meta)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ReassignReviewerAtomic indicates an expected call of ReassignReviewerAtomic.
-func (mr *MockPrReviewersRepositoryInterfaceMockRecorder) ReassignReviewerAtomic(ctx, prID, oldReviewerID, newReviewerID interface{}) *gomock.Call {
+func (mr *MockPrReviewersRepositoryInterfaceMockRecorder) ReassignReviewerAtomic(ctx, prID, oldReviewerID, newReviewerID, meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignReviewerAtomic", reflect.TypeOf((*MockPrReviewersRepositoryInterface)(nil).ReassignReviewerAtomic), ctx, prID, oldReviewerID, newReviewerID, meta)
+}
+
+// RecordAssignmentEvent mocks base method.
+func (m *MockPrReviewersRepositoryInterface) RecordAssignmentEvent(ctx context.Context, event domain.AssignmentEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAssignmentEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAssignmentEvent indicates an expected call of RecordAssignmentEvent.
+func (mr *MockPrReviewersRepositoryInterfaceMockRecorder) RecordAssignmentEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAssignmentEvent", reflect.TypeOf((*MockPrReviewersRepositoryInterface)(nil).RecordAssignmentEvent), ctx, event)
+}
+
+// MockStatsRepositoryInterface is a mock of StatsRepositoryInterface interface.
+type MockStatsRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsRepositoryInterfaceMockRecorder
+}
+
+// MockStatsRepositoryInterfaceMockRecorder is the mock recorder for MockStatsRepositoryInterface.
+type MockStatsRepositoryInterfaceMockRecorder struct {
+	mock *MockStatsRepositoryInterface
+}
+
+// NewMockStatsRepositoryInterface creates a new mock instance.
+func NewMockStatsRepositoryInterface(ctrl *gomock.Controller) *MockStatsRepositoryInterface {
+	mock := &MockStatsRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockStatsRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsRepositoryInterface) EXPECT() *MockStatsRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetTeamDigest mocks base method.
+func (m *MockStatsRepositoryInterface) GetTeamDigest(ctx context.Context, teamName string, from, to time.Time) (*domain.TeamDigest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamDigest", ctx, teamName, from, to)
+	ret0, _ := ret[0].(*domain.TeamDigest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamDigest indicates an expected call of GetTeamDigest.
+func (mr *MockStatsRepositoryInterfaceMockRecorder) GetTeamDigest(ctx, teamName, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamDigest", reflect.TypeOf((*MockStatsRepositoryInterface)(nil).GetTeamDigest), ctx, teamName, from, to)
+}
+
+// GetTeamReviewStats mocks base method.
+func (m *MockStatsRepositoryInterface) GetTeamReviewStats(ctx context.Context, filter domain.StatsFilter) ([]domain.TeamReviewStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamReviewStats", ctx, filter)
+	ret0, _ := ret[0].([]domain.TeamReviewStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamReviewStats indicates an expected call of GetTeamReviewStats.
+func (mr *MockStatsRepositoryInterfaceMockRecorder) GetTeamReviewStats(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamReviewStats", reflect.TypeOf((*MockStatsRepositoryInterface)(nil).GetTeamReviewStats), ctx, filter)
+}
+
+// GetUserReviewStats mocks base method.
+func (m *MockStatsRepositoryInterface) GetUserReviewStats(ctx context.Context, filter domain.StatsFilter) ([]domain.UserReviewStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserReviewStats", ctx, filter)
+	ret0, _ := ret[0].([]domain.UserReviewStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserReviewStats indicates an expected call of GetUserReviewStats.
+func (mr *MockStatsRepositoryInterfaceMockRecorder) GetUserReviewStats(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserReviewStats", reflect.TypeOf((*MockStatsRepositoryInterface)(nil).GetUserReviewStats), ctx, filter)
+}
+
+// MockExportRepositoryInterface is a mock of ExportRepositoryInterface interface.
+type MockExportRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportRepositoryInterfaceMockRecorder
+}
+
+// MockExportRepositoryInterfaceMockRecorder is the mock recorder for MockExportRepositoryInterface.
+type MockExportRepositoryInterfaceMockRecorder struct {
+	mock *MockExportRepositoryInterface
+}
+
+// NewMockExportRepositoryInterface creates a new mock instance.
+func NewMockExportRepositoryInterface(ctrl *gomock.Controller) *MockExportRepositoryInterface {
+	mock := &MockExportRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockExportRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportRepositoryInterface) EXPECT() *MockExportRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// StreamPullRequests mocks base method.
+func (m *MockExportRepositoryInterface) StreamPullRequests(ctx context.Context, filter domain.ExportFilter, fn func(domain.PullRequestExportRow) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamPullRequests", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamPullRequests indicates an expected call of StreamPullRequests.
+func (mr *MockExportRepositoryInterfaceMockRecorder) StreamPullRequests(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamPullRequests", reflect.TypeOf((*MockExportRepositoryInterface)(nil).StreamPullRequests), ctx, filter, fn)
+}
+
+// StreamReviews mocks base method.
+func (m *MockExportRepositoryInterface) StreamReviews(ctx context.Context, filter domain.ExportFilter, fn func(domain.ReviewExportRow) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamReviews", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamReviews indicates an expected call of StreamReviews.
+func (mr *MockExportRepositoryInterfaceMockRecorder) StreamReviews(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamReviews", reflect.TypeOf((*MockExportRepositoryInterface)(nil).StreamReviews), ctx, filter, fn)
+}
+
+// MockWebhookRepositoryInterface is a mock of WebhookRepositoryInterface interface.
+type MockWebhookRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryInterfaceMockRecorder
+}
+
+// MockWebhookRepositoryInterfaceMockRecorder is the mock recorder for MockWebhookRepositoryInterface.
+type MockWebhookRepositoryInterfaceMockRecorder struct {
+	mock *MockWebhookRepositoryInterface
+}
+
+// NewMockWebhookRepositoryInterface creates a new mock instance.
+func NewMockWebhookRepositoryInterface(ctrl *gomock.Controller) *MockWebhookRepositoryInterface {
+	mock := &MockWebhookRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepositoryInterface) EXPECT() *MockWebhookRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetUserIDByLogin mocks base method.
+func (m *MockWebhookRepositoryInterface) GetUserIDByLogin(ctx context.Context, provider domain.VCSProvider, login string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIDByLogin", ctx, provider, login)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIDByLogin indicates an expected call of GetUserIDByLogin.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) GetUserIDByLogin(ctx, provider, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIDByLogin", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).GetUserIDByLogin), ctx, provider, login)
+}
+
+// ListUserMappings mocks base method.
+func (m *MockWebhookRepositoryInterface) ListUserMappings(ctx context.Context, provider *domain.VCSProvider) ([]domain.VCSUserMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserMappings", ctx, provider)
+	ret0, _ := ret[0].([]domain.VCSUserMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserMappings indicates an expected call of ListUserMappings.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) ListUserMappings(ctx, provider interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserMappings", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).ListUserMappings), ctx, provider)
+}
+
+// ProcessDelivery mocks base method.
+func (m *MockWebhookRepositoryInterface) ProcessDelivery(ctx context.Context, delivery domain.WebhookDelivery, process func(context.Context) (domain.WebhookResultStatus, error)) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessDelivery", ctx, delivery, process)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessDelivery indicates an expected call of ProcessDelivery.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) ProcessDelivery(ctx, delivery, process interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessDelivery", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).ProcessDelivery), ctx, delivery, process)
+}
+
+// UpsertUserMapping mocks base method.
+func (m *MockWebhookRepositoryInterface) UpsertUserMapping(ctx context.Context, mapping domain.VCSUserMapping) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserMapping", ctx, mapping)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertUserMapping indicates an expected call of UpsertUserMapping.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) UpsertUserMapping(ctx, mapping interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserMapping", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).UpsertUserMapping), ctx, mapping)
+}
+
+// MockSubscriptionRepositoryInterface is a mock of SubscriptionRepositoryInterface interface.
+type MockSubscriptionRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionRepositoryInterfaceMockRecorder
+}
+
+// MockSubscriptionRepositoryInterfaceMockRecorder is the mock recorder for MockSubscriptionRepositoryInterface.
+type MockSubscriptionRepositoryInterfaceMockRecorder struct {
+	mock *MockSubscriptionRepositoryInterface
+}
+
+// NewMockSubscriptionRepositoryInterface creates a new mock instance.
+func NewMockSubscriptionRepositoryInterface(ctrl *gomock.Controller) *MockSubscriptionRepositoryInterface {
+	mock := &MockSubscriptionRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriptionRepositoryInterface) EXPECT() *MockSubscriptionRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ClaimDueDeliveries mocks base method.
+func (m *MockSubscriptionRepositoryInterface) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.DueDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueDeliveries", ctx, limit, lease)
+	ret0, _ := ret[0].([]domain.DueDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueDeliveries indicates an expected call of ClaimDueDeliveries.
+func (mr *MockSubscriptionRepositoryInterfaceMockRecorder) ClaimDueDeliveries(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueDeliveries", reflect.TypeOf((*MockSubscriptionRepositoryInterface)(nil).ClaimDueDeliveries), ctx, limit, lease)
+}
+
+// CreateSubscription mocks base method.
+func (m *MockSubscriptionRepositoryInterface) CreateSubscription(ctx context.Context, subscription *domain.Subscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscription", ctx, subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSubscription indicates an expected call of CreateSubscription.
+func (mr *MockSubscriptionRepositoryInterfaceMockRecorder) CreateSubscription(ctx, subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockSubscriptionRepositoryInterface)(nil).CreateSubscription), ctx, subscription)
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockSubscriptionRepositoryInterface) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscription", ctx, subscriptionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockSubscriptionRepositoryInterfaceMockRecorder) DeleteSubscription(ctx, subscriptionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockSubscriptionRepositoryInterface)(nil).DeleteSubscription), ctx, subscriptionID)
+}
+
+// EnqueueEvent mocks base method.
+func (m *MockSubscriptionRepositoryInterface) EnqueueEvent(ctx context.Context, event domain.Event) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueEvent", ctx, event)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueEvent indicates an expected call of EnqueueEvent.
+func (mr *MockSubscriptionRepositoryInterfaceMockRecorder) EnqueueEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueEvent", reflect.TypeOf((*MockSubscriptionRepositoryInterface)(nil).EnqueueEvent), ctx, event)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockSubscriptionRepositoryInterface) ListDeliveries(ctx context.Context, filter domain.DeliveryLogFilter) ([]domain.SubscriptionDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, filter)
+	ret0, _ := ret[0].([]domain.SubscriptionDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockSubscriptionRepositoryInterfaceMockRecorder) ListDeliveries(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockSubscriptionRepositoryInterface)(nil).ListDeliveries), ctx, filter)
+}
+
+// ListSubscriptions mocks base method.
+func (m *MockSubscriptionRepositoryInterface) ListSubscriptions(ctx context.Context) ([]domain.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptions", ctx)
+	ret0, _ := ret[0].([]domain.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptions indicates an expected call of ListSubscriptions.
+func (mr *MockSubscriptionRepositoryInterfaceMockRecorder) ListSubscriptions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptions", reflect.TypeOf((*MockSubscriptionRepositoryInterface)(nil).ListSubscriptions), ctx)
+}
+
+// RecordAttempt mocks base method.
+func (m *MockSubscriptionRepositoryInterface) RecordAttempt(ctx context.Context, attempt domain.DeliveryAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAttempt", ctx, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAttempt indicates an expected call of RecordAttempt.
+func (mr *MockSubscriptionRepositoryInterfaceMockRecorder) RecordAttempt(ctx, attempt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAttempt", reflect.TypeOf((*MockSubscriptionRepositoryInterface)(nil).RecordAttempt), ctx, attempt)
+}
+
+// RequeueDeadDelivery mocks base method.
+func (m *MockSubscriptionRepositoryInterface) RequeueDeadDelivery(ctx context.Context, deliveryID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueDeadDelivery", ctx, deliveryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueDeadDelivery indicates an expected call of RequeueDeadDelivery.
+func (mr *MockSubscriptionRepositoryInterfaceMockRecorder) RequeueDeadDelivery(ctx, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueDeadDelivery", reflect.TypeOf((*MockSubscriptionRepositoryInterface)(nil).RequeueDeadDelivery), ctx, deliveryID)
+}
+
+// MockOutboxRepositoryInterface is a mock of OutboxRepositoryInterface interface.
+type MockOutboxRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryInterfaceMockRecorder
+}
+
+// MockOutboxRepositoryInterfaceMockRecorder is the mock recorder for MockOutboxRepositoryInterface.
+type MockOutboxRepositoryInterfaceMockRecorder struct {
+	mock *MockOutboxRepositoryInterface
+}
+
+// NewMockOutboxRepositoryInterface creates a new mock instance.
+func NewMockOutboxRepositoryInterface(ctrl *gomock.Controller) *MockOutboxRepositoryInterface {
+	mock := &MockOutboxRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepositoryInterface) EXPECT() *MockOutboxRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ClaimPendingEvents mocks base method.
+func (m *MockOutboxRepositoryInterface) ClaimPendingEvents(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPendingEvents", ctx, limit, lease)
+	ret0, _ := ret[0].([]domain.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPendingEvents indicates an expected call of ClaimPendingEvents.
+func (mr *MockOutboxRepositoryInterfaceMockRecorder) ClaimPendingEvents(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPendingEvents", reflect.TypeOf((*MockOutboxRepositoryInterface)(nil).ClaimPendingEvents), ctx, limit, lease)
+}
+
+// DeletePublishedEvents mocks base method.
+func (m *MockOutboxRepositoryInterface) DeletePublishedEvents(ctx context.Context, before time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePublishedEvents", ctx, before, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePublishedEvents indicates an expected call of DeletePublishedEvents.
+func (mr *MockOutboxRepositoryInterfaceMockRecorder) DeletePublishedEvents(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePublishedEvents", reflect.TypeOf((*MockOutboxRepositoryInterface)(nil).DeletePublishedEvents), ctx, before, limit)
+}
+
+// GetLatestStreamPosition mocks base method.
+func (m *MockOutboxRepositoryInterface) GetLatestStreamPosition(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestStreamPosition", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestStreamPosition indicates an expected call of GetLatestStreamPosition.
+func (mr *MockOutboxRepositoryInterfaceMockRecorder) GetLatestStreamPosition(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestStreamPosition", reflect.TypeOf((*MockOutboxRepositoryInterface)(nil).GetLatestStreamPosition), ctx)
+}
+
+// GetUserReviewEvents mocks base method.
+func (m *MockOutboxRepositoryInterface) GetUserReviewEvents(ctx context.Context, userID string, afterPosition int64, limit int) ([]domain.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserReviewEvents", ctx, userID, afterPosition, limit)
+	ret0, _ := ret[0].([]domain.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserReviewEvents indicates an expected call of GetUserReviewEvents.
+func (mr *MockOutboxRepositoryInterfaceMockRecorder) GetUserReviewEvents(ctx, userID, afterPosition, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserReviewEvents", reflect.TypeOf((*MockOutboxRepositoryInterface)(nil).GetUserReviewEvents), ctx, userID, afterPosition, limit)
+}
+
+// MarkEventsPublished mocks base method.
+func (m *MockOutboxRepositoryInterface) MarkEventsPublished(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEventsPublished", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEventsPublished indicates an expected call of MarkEventsPublished.
+func (mr *MockOutboxRepositoryInterfaceMockRecorder) MarkEventsPublished(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEventsPublished", reflect.TypeOf((*MockOutboxRepositoryInterface)(nil).MarkEventsPublished), ctx, ids)
+}
+
+// RecordPublishFailure mocks base method.
+func (m *MockOutboxRepositoryInterface) RecordPublishFailure(ctx context.Context, id int64, errMsg string, nextAttemptAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPublishFailure", ctx, id, errMsg, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordPublishFailure indicates an expected call of RecordPublishFailure.
+func (mr *MockOutboxRepositoryInterfaceMockRecorder) RecordPublishFailure(ctx, id, errMsg, nextAttemptAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPublishFailure", reflect.TypeOf((*MockOutboxRepositoryInterface)(nil).RecordPublishFailure), ctx, id, errMsg, nextAttemptAt)
+}
+
+// MockNotificationRepositoryInterface is a mock of NotificationRepositoryInterface interface.
+type MockNotificationRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationRepositoryInterfaceMockRecorder
+}
+
+// MockNotificationRepositoryInterfaceMockRecorder is the mock recorder for MockNotificationRepositoryInterface.
+type MockNotificationRepositoryInterfaceMockRecorder struct {
+	mock *MockNotificationRepositoryInterface
+}
+
+// NewMockNotificationRepositoryInterface creates a new mock instance.
+func NewMockNotificationRepositoryInterface(ctrl *gomock.Controller) *MockNotificationRepositoryInterface {
+	mock := &MockNotificationRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockNotificationRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationRepositoryInterface) EXPECT() *MockNotificationRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ClaimDueEmails mocks base method.
+func (m *MockNotificationRepositoryInterface) ClaimDueEmails(ctx context.Context, limit int, lease time.Duration) ([]domain.DueEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueEmails", ctx, limit, lease)
+	ret0, _ := ret[0].([]domain.DueEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueEmails indicates an expected call of ClaimDueEmails.
+func (mr *MockNotificationRepositoryInterfaceMockRecorder) ClaimDueEmails(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueEmails", reflect.TypeOf((*MockNotificationRepositoryInterface)(nil).ClaimDueEmails), ctx, limit, lease)
+}
+
+// ClaimDueNotifications mocks base method.
+func (m *MockNotificationRepositoryInterface) ClaimDueNotifications(ctx context.Context, limit int, lease time.Duration) ([]domain.DueNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueNotifications", ctx, limit, lease)
+	ret0, _ := ret[0].([]domain.DueNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueNotifications indicates an expected call of ClaimDueNotifications.
+func (mr *MockNotificationRepositoryInterfaceMockRecorder) ClaimDueNotifications(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueNotifications", reflect.TypeOf((*MockNotificationRepositoryInterface)(nil).ClaimDueNotifications), ctx, limit, lease)
+}
+
+// DeleteTeamChannel mocks base method.
+func (m *MockNotificationRepositoryInterface) DeleteTeamChannel(ctx context.Context, teamName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTeamChannel", ctx, teamName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTeamChannel indicates an expected call of DeleteTeamChannel.
+func (mr *MockNotificationRepositoryInterfaceMockRecorder) DeleteTeamChannel(ctx, teamName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTeamChannel", reflect.TypeOf((*MockNotificationRepositoryInterface)(nil).DeleteTeamChannel), ctx, teamName)
+}
+
+// EnqueueEmails mocks base method.
+func (m *MockNotificationRepositoryInterface) EnqueueEmails(ctx context.Context, emails []domain.EmailNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueEmails", ctx, emails)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueEmails indicates an expected call of EnqueueEmails.
+func (mr *MockNotificationRepositoryInterfaceMockRecorder) EnqueueEmails(ctx, emails interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueEmails", reflect.TypeOf((*MockNotificationRepositoryInterface)(nil).EnqueueEmails), ctx, emails)
+}
+
+// EnqueueNotifications mocks base method.
+func (m *MockNotificationRepositoryInterface) EnqueueNotifications(ctx context.Context, notifications []domain.ChatNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueNotifications", ctx, notifications)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueNotifications indicates an expected call of EnqueueNotifications.
+func (mr *MockNotificationRepositoryInterfaceMockRecorder) EnqueueNotifications(ctx, notifications interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueNotifications", reflect.TypeOf((*MockNotificationRepositoryInterface)(nil).EnqueueNotifications), ctx, notifications)
+}
+
+// GetNotificationPreferences mocks base method.
+func (m *MockNotificationRepositoryInterface) GetNotificationPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationPreferences", ctx, userID)
+	ret0, _ := ret[0].(*domain.NotificationPreferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationPreferences indicates an expected call of GetNotificationPreferences.
+func (mr *MockNotificationRepositoryInterfaceMockRecorder) GetNotificationPreferences(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationPreferences", reflect.TypeOf((*MockNotificationRepositoryInterface)(nil).GetNotificationPreferences), ctx, userID)
+}
+
+// GetNotificationRecipients mocks base method.
+func (m *MockNotificationRepositoryInterface) GetNotificationRecipients(ctx context.Context, userIDs []string) ([]domain.NotificationRecipient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationRecipients", ctx, userIDs)
+	ret0, _ := ret[0].([]domain.NotificationRecipient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationRecipients indicates an expected call of GetNotificationRecipients.
+func (mr *MockNotificationRepositoryInterfaceMockRecorder) GetNotificationRecipients(ctx, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationRecipients", reflect.TypeOf((*MockNotificationRepositoryInterface)(nil).GetNotificationRecipients), ctx, userIDs)
+}
+
+// ListTeamChannels mocks base method.
+func (m *MockNotificationRepositoryInterface) ListTeamChannels(ctx context.Context) ([]domain.TeamChatChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTeamChannels", ctx)
+	ret0, _ := ret[0].([]domain.TeamChatChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTeamChannels indicates an expected call of ListTeamChannels.
+func (mr *MockNotificationRepositoryInterfaceMockRecorder) ListTeamChannels(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTeamChannels", reflect.TypeOf((*MockNotificationRepositoryInterface)(nil).ListTeamChannels), ctx)
+}
+
+// ListUserMentions mocks base method.
+func (m *MockNotificationRepositoryInterface) ListUserMentions(ctx context.Context) ([]domain.UserChatMention, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserMentions", ctx)
+	ret0, _ := ret[0].([]domain.UserChatMention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserMentions indicates an expected call of ListUserMentions.
+func (mr *MockNotificationRepositoryInterfaceMockRecorder) ListUserMentions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserMentions", reflect.TypeOf((*MockNotificationRepositoryInterface)(nil).ListUserMentions), ctx)
+}
+
+// RecordEmailAttempt mocks base method.
+func (m *MockNotificationRepositoryInterface) RecordEmailAttempt(ctx context.Context, attempt domain.EmailAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordEmailAttempt", ctx, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordEmailAttempt indicates an expected call of RecordEmailAttempt.
+func (mr *MockNotificationRepositoryInterfaceMockRecorder) RecordEmailAttempt(ctx, attempt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordEmailAttempt", reflect.TypeOf((*MockNotificationRepositoryInterface)(nil).RecordEmailAttempt), ctx, attempt)
+}
+
+// RecordNotificationAttempt mocks base method.
+func (m *MockNotificationRepositoryInterface) RecordNotificationAttempt(ctx context.Context, attempt domain.NotificationAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordNotificationAttempt", ctx, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordNotificationAttempt indicates an expected call of RecordNotificationAttempt.
+func (mr *MockNotificationRepositoryInterfaceMockRecorder) RecordNotificationAttempt(ctx, attempt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordNotificationAttempt", reflect.TypeOf((*MockNotificationRepositoryInterface)(nil).RecordNotificationAttempt), ctx, attempt)
+}
+
+// UpsertNotificationPreferences mocks base method.
+func (m *MockNotificationRepositoryInterface) UpsertNotificationPreferences(ctx context.Context, prefs domain.NotificationPreferences) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationPreferences", ctx, prefs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertNotificationPreferences indicates an expected call of UpsertNotificationPreferences.
+func (mr *MockNotificationRepositoryInterfaceMockRecorder) UpsertNotificationPreferences(ctx, prefs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationPreferences", reflect.TypeOf((*MockNotificationRepositoryInterface)(nil).UpsertNotificationPreferences), ctx, prefs)
+}
+
+// UpsertTeamChannel mocks base method.
+func (m *MockNotificationRepositoryInterface) UpsertTeamChannel(ctx context.Context, channel domain.TeamChatChannel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTeamChannel", ctx, channel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertTeamChannel indicates an expected call of UpsertTeamChannel.
+func (mr *MockNotificationRepositoryInterfaceMockRecorder) UpsertTeamChannel(ctx, channel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTeamChannel", reflect.TypeOf((*MockNotificationRepositoryInterface)(nil).UpsertTeamChannel), ctx, channel)
+}
+
+// UpsertUserMention mocks base method.
+func (m *MockNotificationRepositoryInterface) UpsertUserMention(ctx context.Context, mention domain.UserChatMention) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserMention", ctx, mention)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertUserMention indicates an expected call of UpsertUserMention.
+func (mr *MockNotificationRepositoryInterfaceMockRecorder) UpsertUserMention(ctx, mention interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignReviewerAtomic", reflect.TypeOf((*MockPrReviewersRepositoryInterface)(nil).ReassignReviewerAtomic), ctx, prID, oldReviewerID, newReviewerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserMention", reflect.TypeOf((*MockNotificationRepositoryInterface)(nil).UpsertUserMention), ctx, mention)
 }
@@ -0,0 +1,532 @@
+package notificationStorage
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/db"
+)
+
+var (
+	ErrTeamNotExists = errors.New(domain.TeamNotExistsErr)
+	ErrUserNotExists = errors.New("user does not exist")
+)
+
+type NotificationStorage struct {
+	db db.Querier
+}
+
+func NewNotificationStorage(db db.Querier) *NotificationStorage {
+	return &NotificationStorage{
+		db: db,
+	}
+}
+
+// UpsertTeamChannel задаёт или меняет канал команды; если команды нет - ErrTeamNotExists
+func (s *NotificationStorage) UpsertTeamChannel(ctx context.Context, channel domain.TeamChatChannel) error {
+	query := `
+		INSERT INTO team_chat_channels (team_id, url)
+		SELECT id, $2 FROM teams WHERE name = $1
+		ON CONFLICT (team_id) DO UPDATE SET url = EXCLUDED.url`
+
+	tag, err := s.db.Exec(ctx, query, channel.TeamName, channel.URL)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTeamNotExists
+	}
+
+	return nil
+}
+
+func (s *NotificationStorage) ListTeamChannels(ctx context.Context) ([]domain.TeamChatChannel, error) {
+	query := `
+		SELECT t.name, c.url
+		FROM team_chat_channels c
+		JOIN teams t ON t.id = c.team_id
+		ORDER BY t.name`
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := make([]domain.TeamChatChannel, 0)
+	for rows.Next() {
+		var channel domain.TeamChatChannel
+		if err = rows.Scan(&channel.TeamName, &channel.URL); err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+
+	return channels, rows.Err()
+}
+
+// DeleteTeamChannel отключает уведомления команды; если канала нет - pgx.ErrNoRows
+func (s *NotificationStorage) DeleteTeamChannel(ctx context.Context, teamName string) error {
+	query := `
+		DELETE FROM team_chat_channels c
+		USING teams t
+		WHERE t.id = c.team_id AND t.name = $1`
+
+	tag, err := s.db.Exec(ctx, query, teamName)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpsertUserMention задаёт или меняет упоминание пользователя; если пользователя нет - ErrUserNotExists
+func (s *NotificationStorage) UpsertUserMention(ctx context.Context, mention domain.UserChatMention) error {
+	query := `
+		INSERT INTO user_chat_mentions (user_id, mention)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET mention = EXCLUDED.mention`
+
+	_, err := s.db.Exec(ctx, query, mention.UserID, mention.Mention)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrUserNotExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *NotificationStorage) ListUserMentions(ctx context.Context) ([]domain.UserChatMention, error) {
+	query := `
+		SELECT user_id, mention
+		FROM user_chat_mentions
+		ORDER BY user_id`
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mentions := make([]domain.UserChatMention, 0)
+	for rows.Next() {
+		var mention domain.UserChatMention
+		if err = rows.Scan(&mention.UserID, &mention.Mention); err != nil {
+			return nil, err
+		}
+		mentions = append(mentions, mention)
+	}
+
+	return mentions, rows.Err()
+}
+
+// GetNotificationRecipients возвращает найденных пользователей с каналом их основной команды, упоминанием
+// и настройками уведомлений
+func (s *NotificationStorage) GetNotificationRecipients(ctx context.Context, userIDs []string) ([]domain.NotificationRecipient, error) {
+	query := `
+		SELECT u.id, u.name, u.is_active, c.url, m.mention, ` + preferencesColumns + `
+		FROM users u
+		LEFT JOIN team_chat_channels c ON c.team_id = u.team_id
+		LEFT JOIN user_chat_mentions m ON m.user_id = u.id
+		LEFT JOIN user_notification_preferences p ON p.user_id = u.id
+		WHERE u.id = ANY($1)`
+
+	rows, err := s.db.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipients := make([]domain.NotificationRecipient, 0, len(userIDs))
+	for rows.Next() {
+		var recipient domain.NotificationRecipient
+		var prefs preferencesRow
+		dest := append([]any{
+			&recipient.UserID,
+			&recipient.Username,
+			&recipient.IsActive,
+			&recipient.ChannelURL,
+			&recipient.Mention,
+		}, prefs.dest()...)
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		recipient.Preferences = prefs.toDomain(recipient.UserID)
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, rows.Err()
+}
+
+// EnqueueNotifications ставит сообщения в очередь отправки. Повторная постановка того же уведомления
+// (событие, получатель, вид) ничего не добавляет, поэтому событие можно обработать повторно
+func (s *NotificationStorage) EnqueueNotifications(ctx context.Context, notifications []domain.ChatNotification) error {
+	query := `
+		INSERT INTO chat_notifications (event_id, kind, user_id, url, text, status, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, now()))
+		ON CONFLICT (event_id, user_id, kind) DO NOTHING`
+
+	for _, notification := range notifications {
+		_, err := s.db.Exec(ctx, query,
+			notification.EventID,
+			string(notification.Kind),
+			notification.UserID,
+			notification.URL,
+			notification.Text,
+			string(domain.NotificationPending),
+			notification.NotBefore,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ClaimDueNotifications забирает до limit уведомлений, время которых подошло, и сдвигает их
+// next_attempt_at на lease, чтобы другие экземпляры сервиса их не взяли
+func (s *NotificationStorage) ClaimDueNotifications(ctx context.Context, limit int, lease time.Duration) ([]domain.DueNotification, error) {
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM chat_notifications
+			WHERE status = $1 AND next_attempt_at <= now()
+			ORDER BY next_attempt_at, id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE chat_notifications n
+		SET next_attempt_at = now() + make_interval(secs => $3)
+		FROM due
+		WHERE n.id = due.id
+		RETURNING n.id, n.url, n.text, n.attempts`
+
+	rows, err := s.db.Query(ctx, query, string(domain.NotificationPending), limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]domain.DueNotification, 0)
+	for rows.Next() {
+		var notification domain.DueNotification
+		err = rows.Scan(&notification.ID, &notification.URL, &notification.Text, &notification.Attempts)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, rows.Err()
+}
+
+// RecordNotificationAttempt сохраняет итог попытки: успех - SENT, неуспех - PENDING с новым временем
+// попытки или FAILED, если повторять больше не нужно
+func (s *NotificationStorage) RecordNotificationAttempt(ctx context.Context, attempt domain.NotificationAttempt) error {
+	status := domain.NotificationPending
+	switch {
+	case attempt.Sent:
+		status = domain.NotificationSent
+	case attempt.NextAttemptAt == nil:
+		status = domain.NotificationFailed
+	}
+
+	var lastError *string
+	if attempt.Error != "" {
+		lastError = &attempt.Error
+	}
+
+	var nextAttemptAt, sentAt *time.Time
+	if attempt.Sent {
+		now := time.Now()
+		sentAt = &now
+	} else {
+		nextAttemptAt = attempt.NextAttemptAt
+	}
+
+	query := `
+		UPDATE chat_notifications
+		SET status = $1,
+			attempts = attempts + 1,
+			next_attempt_at = $2,
+			last_error = $3,
+			sent_at = $4
+		WHERE id = $5`
+
+	_, err := s.db.Exec(ctx, query,
+		string(status),
+		nextAttemptAt,
+		lastError,
+		sentAt,
+		attempt.NotificationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// preferencesColumns - колонки настроек для LEFT JOIN user_notification_preferences p
+const preferencesColumns = `p.user_id IS NOT NULL, p.email, p.channels, p.delivery, p.digest_hour,
+		p.quiet_hours_start, p.quiet_hours_end, p.timezone`
+
+// preferencesRow - настройки из LEFT JOIN, где все колонки могут быть NULL
+type preferencesRow struct {
+	exists          bool
+	email           *string
+	channels        []string
+	delivery        *string
+	digestHour      *int
+	quietHoursStart *int
+	quietHoursEnd   *int
+	timezone        *string
+}
+
+func (r *preferencesRow) dest() []any {
+	return []any{
+		&r.exists,
+		&r.email,
+		&r.channels,
+		&r.delivery,
+		&r.digestHour,
+		&r.quietHoursStart,
+		&r.quietHoursEnd,
+		&r.timezone,
+	}
+}
+
+// toDomain возвращает nil, если пользователь не сохранял настройки
+func (r *preferencesRow) toDomain(userID string) *domain.NotificationPreferences {
+	if !r.exists {
+		return nil
+	}
+
+	prefs := domain.NotificationPreferences{
+		UserID:          userID,
+		Email:           r.email,
+		Channels:        make([]domain.NotificationChannel, 0, len(r.channels)),
+		QuietHoursStart: r.quietHoursStart,
+		QuietHoursEnd:   r.quietHoursEnd,
+	}
+	for _, channel := range r.channels {
+		prefs.Channels = append(prefs.Channels, domain.NotificationChannel(channel))
+	}
+	if r.delivery != nil {
+		prefs.Delivery = domain.NotificationDelivery(*r.delivery)
+	}
+	if r.digestHour != nil {
+		prefs.DigestHour = *r.digestHour
+	}
+	if r.timezone != nil {
+		prefs.Timezone = *r.timezone
+	}
+
+	return &prefs
+}
+
+// GetNotificationPreferences возвращает настройки пользователя (по умолчанию, если он их не менял);
+// если пользователя нет - pgx.ErrNoRows
+func (s *NotificationStorage) GetNotificationPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	query := `
+		SELECT ` + preferencesColumns + `
+		FROM users u
+		LEFT JOIN user_notification_preferences p ON p.user_id = u.id
+		WHERE u.id = $1`
+
+	var row preferencesRow
+	if err := s.db.QueryRow(ctx, query, userID).Scan(row.dest()...); err != nil {
+		return nil, err
+	}
+
+	if prefs := row.toDomain(userID); prefs != nil {
+		return prefs, nil
+	}
+	prefs := domain.DefaultNotificationPreferences(userID)
+	return &prefs, nil
+}
+
+// UpsertNotificationPreferences сохраняет настройки целиком; если пользователя нет - ErrUserNotExists
+func (s *NotificationStorage) UpsertNotificationPreferences(ctx context.Context, prefs domain.NotificationPreferences) error {
+	query := `
+		INSERT INTO user_notification_preferences
+			(user_id, email, channels, delivery, digest_hour, quiet_hours_start, quiet_hours_end, timezone, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, now())
+		ON CONFLICT (user_id) DO UPDATE SET
+			email = EXCLUDED.email,
+			channels = EXCLUDED.channels,
+			delivery = EXCLUDED.delivery,
+			digest_hour = EXCLUDED.digest_hour,
+			quiet_hours_start = EXCLUDED.quiet_hours_start,
+			quiet_hours_end = EXCLUDED.quiet_hours_end,
+			timezone = EXCLUDED.timezone,
+			updated_at = EXCLUDED.updated_at`
+
+	channels := make([]string, 0, len(prefs.Channels))
+	for _, channel := range prefs.Channels {
+		channels = append(channels, string(channel))
+	}
+
+	_, err := s.db.Exec(ctx, query,
+		prefs.UserID,
+		prefs.Email,
+		channels,
+		string(prefs.Delivery),
+		prefs.DigestHour,
+		prefs.QuietHoursStart,
+		prefs.QuietHoursEnd,
+		prefs.Timezone,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrUserNotExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// EnqueueEmails ставит письма в очередь отправки; как и для чата, повтор того же письма ничего не добавляет
+func (s *NotificationStorage) EnqueueEmails(ctx context.Context, emails []domain.EmailNotification) error {
+	query := `
+		INSERT INTO email_notifications
+			(event_id, kind, user_id, email, subject, text_body, html_body, digest, status, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10, now()))
+		ON CONFLICT (event_id, user_id, kind) DO NOTHING`
+
+	for _, email := range emails {
+		_, err := s.db.Exec(ctx, query,
+			email.EventID,
+			string(email.Kind),
+			email.UserID,
+			email.Email,
+			email.Subject,
+			email.TextBody,
+			email.HTMLBody,
+			email.Digest,
+			string(domain.NotificationPending),
+			email.NotBefore,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ClaimDueEmails забирает до limit писем, время которых подошло, и сдвигает их next_attempt_at на lease.
+// Письма отсортированы по получателю, чтобы дайджест собирался из соседних записей
+func (s *NotificationStorage) ClaimDueEmails(ctx context.Context, limit int, lease time.Duration) ([]domain.DueEmail, error) {
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM email_notifications
+			WHERE status = $1 AND next_attempt_at <= now()
+			ORDER BY next_attempt_at, id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE email_notifications n
+		SET next_attempt_at = now() + make_interval(secs => $3)
+		FROM due
+		WHERE n.id = due.id
+		RETURNING n.id, n.user_id, n.email, n.subject, n.text_body, n.html_body, n.digest, n.attempts`
+
+	rows, err := s.db.Query(ctx, query, string(domain.NotificationPending), limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	emails := make([]domain.DueEmail, 0)
+	for rows.Next() {
+		var email domain.DueEmail
+		err = rows.Scan(
+			&email.ID,
+			&email.UserID,
+			&email.Email,
+			&email.Subject,
+			&email.TextBody,
+			&email.HTMLBody,
+			&email.Digest,
+			&email.Attempts,
+		)
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(emails, func(i, j int) bool {
+		if emails[i].UserID != emails[j].UserID {
+			return emails[i].UserID < emails[j].UserID
+		}
+		return emails[i].ID < emails[j].ID
+	})
+
+	return emails, nil
+}
+
+// RecordEmailAttempt сохраняет итог попытки для всех писем одного сообщения, статусы как у чата
+func (s *NotificationStorage) RecordEmailAttempt(ctx context.Context, attempt domain.EmailAttempt) error {
+	status := domain.NotificationPending
+	switch {
+	case attempt.Sent:
+		status = domain.NotificationSent
+	case attempt.NextAttemptAt == nil:
+		status = domain.NotificationFailed
+	}
+
+	var lastError *string
+	if attempt.Error != "" {
+		lastError = &attempt.Error
+	}
+
+	var nextAttemptAt, sentAt *time.Time
+	if attempt.Sent {
+		now := time.Now()
+		sentAt = &now
+	} else {
+		nextAttemptAt = attempt.NextAttemptAt
+	}
+
+	query := `
+		UPDATE email_notifications
+		SET status = $1,
+			attempts = attempts + 1,
+			next_attempt_at = $2,
+			last_error = $3,
+			sent_at = $4
+		WHERE id = ANY($5)`
+
+	_, err := s.db.Exec(ctx, query,
+		string(status),
+		nextAttemptAt,
+		lastError,
+		sentAt,
+		attempt.EmailIDs,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,499 @@
+package notificationStorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChannelURL = "https://hooks.slack.com/services/T000/B000/XXX"
+
+func TestNotificationStorage_UpsertTeamChannel(t *testing.T) {
+	ctx := context.Background()
+	channel := domain.TeamChatChannel{TeamName: "Backend", URL: testChannelURL}
+
+	t.Run("successfully upsert", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewNotificationStorage(mock)
+
+		mock.ExpectExec("INSERT INTO team_chat_channels").
+			WithArgs("Backend", testChannelURL).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		require.NoError(t, storage.UpsertTeamChannel(ctx, channel))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown team", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewNotificationStorage(mock)
+
+		mock.ExpectExec("INSERT INTO team_chat_channels").
+			WithArgs("Backend", testChannelURL).
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+
+		assert.ErrorIs(t, storage.UpsertTeamChannel(ctx, channel), ErrTeamNotExists)
+	})
+}
+
+func TestNotificationStorage_ListTeamChannels(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewNotificationStorage(mock)
+
+	mock.ExpectQuery("FROM team_chat_channels").
+		WillReturnRows(pgxmock.NewRows([]string{"name", "url"}).AddRow("Backend", testChannelURL))
+
+	channels, err := storage.ListTeamChannels(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, []domain.TeamChatChannel{{TeamName: "Backend", URL: testChannelURL}}, channels)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationStorage_DeleteTeamChannel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully delete", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewNotificationStorage(mock)
+
+		mock.ExpectExec("DELETE FROM team_chat_channels").
+			WithArgs("Backend").
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+		require.NoError(t, storage.DeleteTeamChannel(ctx, "Backend"))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("channel not found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewNotificationStorage(mock)
+
+		mock.ExpectExec("DELETE FROM team_chat_channels").
+			WithArgs("Backend").
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+		assert.ErrorIs(t, storage.DeleteTeamChannel(ctx, "Backend"), pgx.ErrNoRows)
+	})
+}
+
+func TestNotificationStorage_UpsertUserMention(t *testing.T) {
+	ctx := context.Background()
+	mention := domain.UserChatMention{UserID: "user-alice", Mention: "<@U024BE7LH>"}
+
+	t.Run("successfully upsert", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewNotificationStorage(mock)
+
+		mock.ExpectExec("INSERT INTO user_chat_mentions").
+			WithArgs("user-alice", "<@U024BE7LH>").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		require.NoError(t, storage.UpsertUserMention(ctx, mention))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewNotificationStorage(mock)
+
+		mock.ExpectExec("INSERT INTO user_chat_mentions").
+			WithArgs("user-alice", "<@U024BE7LH>").
+			WillReturnError(&pgconn.PgError{Code: "23503"})
+
+		assert.ErrorIs(t, storage.UpsertUserMention(ctx, mention), ErrUserNotExists)
+	})
+}
+
+func TestNotificationStorage_ListUserMentions(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewNotificationStorage(mock)
+
+	mock.ExpectQuery("FROM user_chat_mentions").
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "mention"}).AddRow("user-alice", "@alice"))
+
+	mentions, err := storage.ListUserMentions(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, []domain.UserChatMention{{UserID: "user-alice", Mention: "@alice"}}, mentions)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationStorage_GetNotificationRecipients(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewNotificationStorage(mock)
+	url := testChannelURL
+	mention := "@alice"
+	email := "alice@example.com"
+	delivery := "DIGEST"
+	digestHour, quietStart, quietEnd := 9, 22, 7
+	timezone := "Europe/Moscow"
+
+	mock.ExpectQuery("FROM users u").
+		WithArgs([]string{"user-alice", "user-bob"}).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "name", "is_active", "url", "mention", "has_preferences", "email", "channels", "delivery",
+			"digest_hour", "quiet_hours_start", "quiet_hours_end", "timezone",
+		}).
+			AddRow("user-alice", "Alice", true, &url, &mention, true, &email, []string{"CHAT", "EMAIL"}, &delivery,
+				&digestHour, &quietStart, &quietEnd, &timezone).
+			AddRow("user-bob", "Bob", false, nil, nil, false, nil, nil, nil, nil, nil, nil, nil))
+
+	recipients, err := storage.GetNotificationRecipients(ctx, []string{"user-alice", "user-bob"})
+
+	require.NoError(t, err)
+	require.Len(t, recipients, 2)
+	assert.Equal(t, testChannelURL, *recipients[0].ChannelURL)
+	assert.Equal(t, "@alice", *recipients[0].Mention)
+	assert.False(t, recipients[1].IsActive)
+	assert.Nil(t, recipients[1].ChannelURL)
+	assert.Equal(t, &domain.NotificationPreferences{
+		UserID:          "user-alice",
+		Email:           &email,
+		Channels:        []domain.NotificationChannel{domain.NotificationChannelChat, domain.NotificationChannelEmail},
+		Delivery:        domain.DeliveryDigest,
+		DigestHour:      9,
+		QuietHoursStart: &quietStart,
+		QuietHoursEnd:   &quietEnd,
+		Timezone:        "Europe/Moscow",
+	}, recipients[0].Preferences)
+	assert.Nil(t, recipients[1].Preferences)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationStorage_EnqueueNotifications(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewNotificationStorage(mock)
+	quietEnd := time.Date(2025, 12, 1, 7, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("INSERT INTO chat_notifications").
+		WithArgs("event-1", "ASSIGNED", "user-alice", testChannelURL, "@alice, review pr-1", "PENDING", (*time.Time)(nil)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	// повтор того же уведомления не создаёт дубль
+	mock.ExpectExec("INSERT INTO chat_notifications").
+		WithArgs("event-1", "UNASSIGNED", "user-bob", testChannelURL, "Bob, no longer pr-1", "PENDING", &quietEnd).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+
+	err = storage.EnqueueNotifications(ctx, []domain.ChatNotification{
+		{EventID: "event-1", Kind: domain.NotificationAssigned, UserID: "user-alice", URL: testChannelURL, Text: "@alice, review pr-1"},
+		{EventID: "event-1", Kind: domain.NotificationUnassigned, UserID: "user-bob", URL: testChannelURL, Text: "Bob, no longer pr-1",
+			NotBefore: &quietEnd},
+	})
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationStorage_ClaimDueNotifications(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewNotificationStorage(mock)
+
+	mock.ExpectQuery("FOR UPDATE SKIP LOCKED").
+		WithArgs("PENDING", 10, float64(10)).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "url", "text", "attempts"}).
+			AddRow(int64(4), testChannelURL, "@alice, review pr-1", 1))
+
+	notifications, err := storage.ClaimDueNotifications(ctx, 10, 10*time.Second)
+
+	require.NoError(t, err)
+	assert.Equal(t, []domain.DueNotification{
+		{ID: 4, URL: testChannelURL, Text: "@alice, review pr-1", Attempts: 1},
+	}, notifications)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationStorage_RecordNotificationAttempt(t *testing.T) {
+	ctx := context.Background()
+	next := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name    string
+		attempt domain.NotificationAttempt
+		status  string
+	}{
+		{
+			name:    "sent",
+			attempt: domain.NotificationAttempt{NotificationID: 4, Sent: true},
+			status:  "SENT",
+		},
+		{
+			name:    "retry scheduled",
+			attempt: domain.NotificationAttempt{NotificationID: 4, Error: "HTTP 500", NextAttemptAt: &next},
+			status:  "PENDING",
+		},
+		{
+			name:    "attempts exhausted",
+			attempt: domain.NotificationAttempt{NotificationID: 4, Error: "timeout"},
+			status:  "FAILED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			storage := NewNotificationStorage(mock)
+
+			mock.ExpectExec("UPDATE chat_notifications").
+				WithArgs(tt.status, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), int64(4)).
+				WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+			require.NoError(t, storage.RecordNotificationAttempt(ctx, tt.attempt))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func preferencesColumnNames() []string {
+	return []string{
+		"has_preferences", "email", "channels", "delivery", "digest_hour",
+		"quiet_hours_start", "quiet_hours_end", "timezone",
+	}
+}
+
+func TestNotificationStorage_GetNotificationPreferences(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("saved preferences", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewNotificationStorage(mock)
+		email := "alice@example.com"
+		delivery := "IMMEDIATE"
+		digestHour := 10
+		timezone := "UTC"
+
+		mock.ExpectQuery("FROM users u").
+			WithArgs("user-alice").
+			WillReturnRows(pgxmock.NewRows(preferencesColumnNames()).
+				AddRow(true, &email, []string{"EMAIL"}, &delivery, &digestHour, nil, nil, &timezone))
+
+		prefs, err := storage.GetNotificationPreferences(ctx, "user-alice")
+
+		require.NoError(t, err)
+		assert.Equal(t, &domain.NotificationPreferences{
+			UserID:     "user-alice",
+			Email:      &email,
+			Channels:   []domain.NotificationChannel{domain.NotificationChannelEmail},
+			Delivery:   domain.DeliveryImmediate,
+			DigestHour: 10,
+			Timezone:   "UTC",
+		}, prefs)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("defaults when nothing saved", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewNotificationStorage(mock)
+
+		mock.ExpectQuery("FROM users u").
+			WithArgs("user-alice").
+			WillReturnRows(pgxmock.NewRows(preferencesColumnNames()).
+				AddRow(false, nil, nil, nil, nil, nil, nil, nil))
+
+		prefs, err := storage.GetNotificationPreferences(ctx, "user-alice")
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.DefaultNotificationPreferences("user-alice"), *prefs)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewNotificationStorage(mock)
+
+		mock.ExpectQuery("FROM users u").
+			WithArgs("user-alice").
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err = storage.GetNotificationPreferences(ctx, "user-alice")
+
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+	})
+}
+
+func TestNotificationStorage_UpsertNotificationPreferences(t *testing.T) {
+	ctx := context.Background()
+	email := "alice@example.com"
+	quietStart, quietEnd := 22, 7
+	prefs := domain.NotificationPreferences{
+		UserID:          "user-alice",
+		Email:           &email,
+		Channels:        []domain.NotificationChannel{domain.NotificationChannelChat, domain.NotificationChannelEmail},
+		Delivery:        domain.DeliveryDigest,
+		DigestHour:      9,
+		QuietHoursStart: &quietStart,
+		QuietHoursEnd:   &quietEnd,
+		Timezone:        "Europe/Moscow",
+	}
+
+	t.Run("successfully upsert", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewNotificationStorage(mock)
+
+		mock.ExpectExec("INSERT INTO user_notification_preferences").
+			WithArgs("user-alice", &email, []string{"CHAT", "EMAIL"}, "DIGEST", 9, &quietStart, &quietEnd, "Europe/Moscow").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		require.NoError(t, storage.UpsertNotificationPreferences(ctx, prefs))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewNotificationStorage(mock)
+
+		mock.ExpectExec("INSERT INTO user_notification_preferences").
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+				pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(&pgconn.PgError{Code: "23503"})
+
+		assert.ErrorIs(t, storage.UpsertNotificationPreferences(ctx, prefs), ErrUserNotExists)
+	})
+}
+
+func TestNotificationStorage_EnqueueEmails(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewNotificationStorage(mock)
+	digestAt := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("INSERT INTO email_notifications").
+		WithArgs("event-1", "ASSIGNED", "user-alice", "alice@example.com", "Review pr-1", "text", "<p>html</p>",
+			true, "PENDING", &digestAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	err = storage.EnqueueEmails(ctx, []domain.EmailNotification{{
+		EventID:   "event-1",
+		Kind:      domain.NotificationAssigned,
+		UserID:    "user-alice",
+		Email:     "alice@example.com",
+		Subject:   "Review pr-1",
+		TextBody:  "text",
+		HTMLBody:  "<p>html</p>",
+		Digest:    true,
+		NotBefore: &digestAt,
+	}})
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationStorage_ClaimDueEmails(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewNotificationStorage(mock)
+
+	mock.ExpectQuery("FOR UPDATE SKIP LOCKED").
+		WithArgs("PENDING", 10, float64(10)).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "user_id", "email", "subject", "text_body", "html_body", "digest", "attempts",
+		}).
+			AddRow(int64(7), "user-bob", "bob@example.com", "s", "t", "h", false, 0).
+			AddRow(int64(5), "user-alice", "alice@example.com", "s2", "t2", "h2", true, 0).
+			AddRow(int64(3), "user-alice", "alice@example.com", "s1", "t1", "h1", true, 1))
+
+	emails, err := storage.ClaimDueEmails(ctx, 10, 10*time.Second)
+
+	require.NoError(t, err)
+	require.Len(t, emails, 3)
+	// письма одного получателя идут подряд, чтобы собрать дайджест
+	assert.Equal(t, []int64{3, 5, 7}, []int64{emails[0].ID, emails[1].ID, emails[2].ID})
+	assert.Equal(t, domain.DueEmail{
+		ID: 3, UserID: "user-alice", Email: "alice@example.com", Subject: "s1", TextBody: "t1", HTMLBody: "h1",
+		Digest: true, Attempts: 1,
+	}, emails[0])
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationStorage_RecordEmailAttempt(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewNotificationStorage(mock)
+
+	mock.ExpectExec("UPDATE email_notifications").
+		WithArgs("FAILED", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), []int64{3, 5}).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+
+	err = storage.RecordEmailAttempt(ctx, domain.EmailAttempt{EmailIDs: []int64{3, 5}, Error: "550 mailbox unavailable"})
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,193 @@
+package outboxStorage
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/db"
+)
+
+type OutboxStorage struct {
+	db db.Querier
+}
+
+func NewOutboxStorage(db db.Querier) *OutboxStorage {
+	return &OutboxStorage{
+		db: db,
+	}
+}
+
+// ClaimPendingEvents забирает до limit неопубликованных событий, время которых подошло, и сдвигает их
+// available_at на lease, чтобы другие экземпляры ретранслятора их не взяли. Если процесс упадёт до
+// отметки о публикации, события вернутся в очередь после lease. События возвращаются в порядке записи
+func (s *OutboxStorage) ClaimPendingEvents(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxEvent, error) {
+	query := `
+		WITH pending AS (
+			SELECT id
+			FROM outbox_events
+			WHERE published_at IS NULL AND available_at <= now()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE outbox_events o
+		SET available_at = now() + make_interval(secs => $2)
+		FROM pending
+		WHERE o.id = pending.id
+		RETURNING o.id, o.event_id, o.event_type, o.occurred_at, o.data, o.attempts, o.stream_position`
+
+	rows, err := s.db.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := scanOutboxEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+
+	return events, nil
+}
+
+// MarkEventsPublished отмечает события опубликованными; повторно ретранслятор их не возьмёт
+func (s *OutboxStorage) MarkEventsPublished(ctx context.Context, ids []int64) error {
+	query := `
+		UPDATE outbox_events
+		SET published_at = now(), attempts = attempts + 1, last_error = NULL
+		WHERE id = ANY($1)`
+
+	_, err := s.db.Exec(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RecordPublishFailure сохраняет ошибку публикации; событие снова станет доступным в nextAttemptAt
+func (s *OutboxStorage) RecordPublishFailure(ctx context.Context, id int64, errMsg string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = $1, available_at = $2
+		WHERE id = $3`
+
+	_, err := s.db.Exec(ctx, query, errMsg, nextAttemptAt, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeletePublishedEvents удаляет до limit событий, опубликованных раньше before, и возвращает, сколько
+// удалено. Неопубликованные события не удаляются, сколько бы они ни ждали публикации
+func (s *OutboxStorage) DeletePublishedEvents(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `
+		DELETE FROM outbox_events
+		WHERE id IN (
+			SELECT id
+			FROM outbox_events
+			WHERE published_at < $1
+			ORDER BY id
+			LIMIT $2
+		)`
+
+	tag, err := s.db.Exec(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// reviewQueueEventTypes - события, меняющие очередь ревью пользователя
+var reviewQueueEventTypes = []string{
+	string(domain.EventReviewerAssigned),
+	string(domain.EventReviewerReassigned),
+	string(domain.EventPRMerged),
+	string(domain.EventPRClosed),
+	string(domain.EventPRReopened),
+}
+
+// GetLatestStreamPosition возвращает последнюю выданную позицию потока (0, если её нет) - с неё
+// начинается поток для клиента, который подключается без Last-Event-ID
+func (s *OutboxStorage) GetLatestStreamPosition(ctx context.Context) (int64, error) {
+	query := `
+		SELECT COALESCE(max(stream_position), 0)
+		FROM outbox_events`
+
+	var position int64
+	if err := s.db.QueryRow(ctx, query).Scan(&position); err != nil {
+		return 0, err
+	}
+
+	return position, nil
+}
+
+// GetUserReviewEvents возвращает до limit событий после позиции afterPosition, касающихся очереди ревью
+// пользователя: назначение и снятие его ревьювером, смена статуса PR, где он ревьювер. События видны
+// сразу после коммита, не дожидаясь публикации, и упорядочены по позиции в потоке (см.
+// eventStorage.WriteEvents), а не по id: id выдаётся при вставке, и транзакции с меньшим id могут
+// закоммититься позже
+func (s *OutboxStorage) GetUserReviewEvents(
+	ctx context.Context,
+	userID string,
+	afterPosition int64,
+	limit int,
+) ([]domain.OutboxEvent, error) {
+	query := `
+		SELECT id, event_id, event_type, occurred_at, data, attempts, stream_position
+		FROM outbox_events
+		WHERE stream_position > $1
+			AND event_type = ANY($2)
+			AND (data->>'reviewer_id' = $3
+				OR data->>'old_reviewer_id' = $3
+				OR data->'assigned_reviewers' ? $3)
+		ORDER BY stream_position
+		LIMIT $4`
+
+	rows, err := s.db.Query(ctx, query, afterPosition, reviewQueueEventTypes, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanOutboxEvents(rows)
+}
+
+func scanOutboxEvents(rows pgx.Rows) ([]domain.OutboxEvent, error) {
+	defer rows.Close()
+
+	events := make([]domain.OutboxEvent, 0)
+	for rows.Next() {
+		var event domain.OutboxEvent
+		var eventType string
+		var data []byte
+		err := rows.Scan(
+			&event.ID,
+			&event.Event.ID,
+			&eventType,
+			&event.Event.OccurredAt,
+			&data,
+			&event.Attempts,
+			&event.StreamPosition,
+		)
+		if err != nil {
+			return nil, err
+		}
+		event.Event.Type = domain.EventType(eventType)
+		event.Event.OccurredAt = event.Event.OccurredAt.UTC()
+		event.Event.Data = data
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,182 @@
+package outboxStorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxStorage_ClaimPendingEvents(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewOutboxStorage(mock)
+	occurredAt := time.Date(2025, 11, 29, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("FOR UPDATE SKIP LOCKED").
+		WithArgs(100, float64(30)).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "event_id", "event_type", "occurred_at", "data", "attempts", "stream_position"}).
+			AddRow(int64(8), "event-2", "pr.merged", occurredAt, []byte(`{"pull_request_id":"pr-1"}`), 0, int64(6)).
+			AddRow(int64(3), "event-1", "pr.created", occurredAt, []byte(`{"pull_request_id":"pr-1"}`), 2, int64(2)))
+
+	events, err := storage.ClaimPendingEvents(ctx, 100, 30*time.Second)
+
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(3), events[0].ID)
+	assert.Equal(t, "event-1", events[0].Event.ID)
+	assert.Equal(t, domain.EventPRCreated, events[0].Event.Type)
+	assert.Equal(t, 2, events[0].Attempts)
+	assert.Equal(t, int64(8), events[1].ID)
+	assert.JSONEq(t, `{"pull_request_id":"pr-1"}`, string(events[1].Event.Data))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxStorage_MarkEventsPublished(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewOutboxStorage(mock)
+
+	mock.ExpectExec("SET published_at = now()").
+		WithArgs([]int64{3, 8}).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+
+	require.NoError(t, storage.MarkEventsPublished(ctx, []int64{3, 8}))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxStorage_DeletePublishedEvents(t *testing.T) {
+	ctx := context.Background()
+	before := time.Date(2025, 11, 29, 10, 0, 0, 0, time.UTC)
+
+	t.Run("deletes published events older than cutoff", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewOutboxStorage(mock)
+
+		mock.ExpectExec("DELETE FROM outbox_events").
+			WithArgs(before, 500).
+			WillReturnResult(pgxmock.NewResult("DELETE", 42))
+
+		deleted, err := storage.DeletePublishedEvents(ctx, before, 500)
+
+		require.NoError(t, err)
+		assert.Equal(t, 42, deleted)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewOutboxStorage(mock)
+
+		mock.ExpectExec("DELETE FROM outbox_events").
+			WithArgs(before, 500).
+			WillReturnError(errors.New("db error"))
+
+		_, err = storage.DeletePublishedEvents(ctx, before, 500)
+
+		require.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOutboxStorage_RecordPublishFailure(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewOutboxStorage(mock)
+	next := time.Date(2025, 11, 29, 10, 1, 0, 0, time.UTC)
+
+	mock.ExpectExec("UPDATE outbox_events").
+		WithArgs("queue unavailable", next, int64(3)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	require.NoError(t, storage.RecordPublishFailure(ctx, 3, "queue unavailable", next))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxStorage_GetLatestStreamPosition(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewOutboxStorage(mock)
+
+	mock.ExpectQuery("SELECT COALESCE\\(max\\(stream_position\\), 0\\)").
+		WillReturnRows(pgxmock.NewRows([]string{"max"}).AddRow(int64(42)))
+
+	position, err := storage.GetLatestStreamPosition(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), position)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxStorage_GetUserReviewEvents(t *testing.T) {
+	ctx := context.Background()
+	occurredAt := time.Date(2025, 11, 29, 10, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewOutboxStorage(mock)
+
+		mock.ExpectQuery("FROM outbox_events").
+			WithArgs(int64(10), reviewQueueEventTypes, "u2", 100).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "event_id", "event_type", "occurred_at", "data", "attempts", "stream_position"}).
+				AddRow(int64(14), "event-1", "reviewer.assigned", occurredAt, []byte(`{"reviewer_id":"u2"}`), 1, int64(11)).
+				AddRow(int64(9), "event-2", "pr.merged", occurredAt, []byte(`{"assigned_reviewers":["u2"]}`), 1, int64(15)))
+
+		events, err := storage.GetUserReviewEvents(ctx, "u2", 10, 100)
+
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, int64(11), events[0].StreamPosition)
+		assert.Equal(t, domain.EventReviewerAssigned, events[0].Event.Type)
+		assert.Equal(t, int64(15), events[1].StreamPosition)
+		assert.Equal(t, domain.EventPRMerged, events[1].Event.Type)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewOutboxStorage(mock)
+
+		mock.ExpectQuery("FROM outbox_events").
+			WithArgs(int64(10), reviewQueueEventTypes, "u2", 100).
+			WillReturnError(errors.New("db error"))
+
+		_, err = storage.GetUserReviewEvents(ctx, "u2", 10, 100)
+
+		require.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -2,8 +2,10 @@ package prReviewersStorage
 
 import (
 	"context"
+	"time"
 
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/eventStorage"
 	"github.com/nedokyrill/avito-pr-api/pkg/utils/db"
 )
 
@@ -91,6 +93,7 @@ func (s *PrReviewersStorage) ReassignReviewerAtomic(
 	prID,
 	oldReviewerID,
 	newReviewerID string,
+	meta *domain.AssignmentMeta,
 ) error {
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
@@ -105,15 +108,143 @@ func (s *PrReviewersStorage) ReassignReviewerAtomic(
 	}
 
 	insertQuery := `
-		INSERT INTO pr_reviewers (pull_request_id, reviewer_id) VALUES ($1, $2)`
-	_, err = tx.Exec(ctx, insertQuery, prID, newReviewerID)
+		INSERT INTO pr_reviewers (pull_request_id, reviewer_id, assignment_meta) VALUES ($1, $2, $3)`
+	_, err = tx.Exec(ctx, insertQuery, prID, newReviewerID, meta)
 	if err != nil {
 		return err
 	}
 
+	reassignment := domain.ReviewerReassignment{
+		PrID:          prID,
+		OldReviewerID: oldReviewerID,
+		NewReviewerID: newReviewerID,
+		Meta:          meta,
+	}
+	if err = eventStorage.WriteAssignmentEvent(ctx, tx, domain.NewReassignmentEvent(reassignment)); err != nil {
+		return err
+	}
+
+	outboxEvent, err := domain.NewReviewerReassignedEvent(reassignment, time.Now())
+	if err != nil {
+		return err
+	}
+	if err = eventStorage.WriteEvents(ctx, tx, []domain.Event{outboxEvent}); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// RecordAssignmentEvent сохраняет событие вне транзакции замены (например, NO_CANDIDATE)
+func (s *PrReviewersStorage) RecordAssignmentEvent(ctx context.Context, event domain.AssignmentEvent) error {
+	return eventStorage.WriteAssignmentEvent(ctx, s.db, event)
+}
+
+// GetReviewerAssignments возвращает назначения ревьюверов PR вместе с метаданными назначения
+func (s *PrReviewersStorage) GetReviewerAssignments(ctx context.Context, prID string) ([]domain.ReviewerAssignment, error) {
+	query := `
+		SELECT reviewer_id, assigned_at, assignment_meta
+		FROM pr_reviewers
+		WHERE pull_request_id = $1
+		ORDER BY assigned_at`
+
+	rows, err := s.db.Query(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments := make([]domain.ReviewerAssignment, 0)
+	for rows.Next() {
+		var assignment domain.ReviewerAssignment
+		if err = rows.Scan(&assignment.ReviewerID, &assignment.AssignedAt, &assignment.Meta); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+// GetPendingReviews возвращает открытые PR в очередях всех активных ревьюверов: по ревьюверу,
+// от самого старого PR к новому, с SLA команды автора
+func (s *PrReviewersStorage) GetPendingReviews(ctx context.Context) ([]domain.PendingReview, error) {
+	query := `
+		SELECT prr.reviewer_id, pr.id, pr.name, pr.author_id, pr.created_at, t.review_sla_hours
+		FROM pr_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pull_request_id
+		JOIN users u ON u.id = prr.reviewer_id
+		LEFT JOIN users a ON a.id = pr.author_id
+		LEFT JOIN teams t ON t.id = a.team_id
+		WHERE pr.status = $1 AND u.is_active
+		ORDER BY prr.reviewer_id, pr.created_at, pr.id`
+
+	rows, err := s.db.Query(ctx, query, string(domain.PullRequestStatusOPEN))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []domain.PendingReview
+	for rows.Next() {
+		var review domain.PendingReview
+		err = rows.Scan(
+			&review.ReviewerID,
+			&review.PullRequestID,
+			&review.PullRequestName,
+			&review.AuthorID,
+			&review.CreatedAt,
+			&review.ReviewSLAHours,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// GetOpenReviewsByReviewers возвращает назначения указанных ревьюверов на открытые PR
+func (s *PrReviewersStorage) GetOpenReviewsByReviewers(ctx context.Context, reviewerIDs []string) ([]domain.OpenReview, error) {
+	query := `
+		SELECT prr.pull_request_id, pr.author_id, prr.reviewer_id
+		FROM pr_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pull_request_id
+		WHERE prr.reviewer_id = ANY($1)
+		  AND pr.status = $2
+		ORDER BY prr.pull_request_id, prr.reviewer_id`
+
+	rows, err := s.db.Query(ctx, query, reviewerIDs, string(domain.PullRequestStatusOPEN))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []domain.OpenReview
+	for rows.Next() {
+		var review domain.OpenReview
+		if err = rows.Scan(&review.PrID, &review.AuthorID, &review.ReviewerID); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
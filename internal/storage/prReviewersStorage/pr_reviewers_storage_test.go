@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
 	"github.com/pashagolub/pgxmock/v2"
@@ -128,12 +129,19 @@ func TestPrReviewersStorage_ReassignReviewerAtomic(t *testing.T) {
 			WillReturnResult(pgxmock.NewResult("DELETE", 1))
 
 		mock.ExpectExec("INSERT INTO pr_reviewers").
-			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO assignment_events").
+			WithArgs(pgxmock.AnyArg(), string(domain.AssignmentEventReassigned), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectExec("INSERT INTO outbox_events").
+			WithArgs(pgxmock.AnyArg(), string(domain.EventReviewerReassigned), pgxmock.AnyArg(), pgxmock.AnyArg()).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		mock.ExpectCommit()
 
-		err = storage.ReassignReviewerAtomic(ctx, prID, oldReviewerID, newReviewerID)
+		err = storage.ReassignReviewerAtomic(ctx, prID, oldReviewerID, newReviewerID, nil)
 
 		require.NoError(t, err)
 		require.NoError(t, mock.ExpectationsWereMet())
@@ -156,7 +164,7 @@ func TestPrReviewersStorage_ReassignReviewerAtomic(t *testing.T) {
 
 		mock.ExpectRollback()
 
-		err = storage.ReassignReviewerAtomic(ctx, prID, oldReviewerID, newReviewerID)
+		err = storage.ReassignReviewerAtomic(ctx, prID, oldReviewerID, newReviewerID, nil)
 
 		assert.Error(t, err)
 		require.NoError(t, mock.ExpectationsWereMet())
@@ -178,12 +186,12 @@ func TestPrReviewersStorage_ReassignReviewerAtomic(t *testing.T) {
 			WillReturnResult(pgxmock.NewResult("DELETE", 1))
 
 		mock.ExpectExec("INSERT INTO pr_reviewers").
-			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 			WillReturnError(errors.New("insert error"))
 
 		mock.ExpectRollback()
 
-		err = storage.ReassignReviewerAtomic(ctx, prID, oldReviewerID, newReviewerID)
+		err = storage.ReassignReviewerAtomic(ctx, prID, oldReviewerID, newReviewerID, nil)
 
 		assert.Error(t, err)
 		require.NoError(t, mock.ExpectationsWereMet())
@@ -205,13 +213,209 @@ func TestPrReviewersStorage_ReassignReviewerAtomic(t *testing.T) {
 			WillReturnResult(pgxmock.NewResult("DELETE", 1))
 
 		mock.ExpectExec("INSERT INTO pr_reviewers").
-			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO assignment_events").
+			WithArgs(pgxmock.AnyArg(), string(domain.AssignmentEventReassigned), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectExec("INSERT INTO outbox_events").
+			WithArgs(pgxmock.AnyArg(), string(domain.EventReviewerReassigned), pgxmock.AnyArg(), pgxmock.AnyArg()).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		mock.ExpectCommit().WillReturnError(errors.New("commit error"))
 		mock.ExpectRollback()
 
-		err = storage.ReassignReviewerAtomic(ctx, prID, oldReviewerID, newReviewerID)
+		err = storage.ReassignReviewerAtomic(ctx, prID, oldReviewerID, newReviewerID, nil)
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPrReviewersStorage_GetPendingReviews(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully get pending reviews", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPrReviewersStorage(mock)
+		createdAt := time.Date(2025, 11, 28, 10, 0, 0, 0, time.UTC)
+		sla := 24
+
+		mock.ExpectQuery("SELECT prr.reviewer_id, pr.id, pr.name").
+			WithArgs(string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"reviewer_id", "id", "name", "author_id", "created_at", "review_sla_hours"}).
+				AddRow("user-1", testStrID, "Add search", "author", createdAt, &sla).
+				AddRow("user-2", testStrID, "Add search", "author", createdAt, nil))
+
+		reviews, err := storage.GetPendingReviews(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []domain.PendingReview{
+			{ReviewerID: "user-1", PullRequestID: testStrID, PullRequestName: "Add search", AuthorID: "author",
+				CreatedAt: createdAt, ReviewSLAHours: &sla},
+			{ReviewerID: "user-2", PullRequestID: testStrID, PullRequestName: "Add search", AuthorID: "author",
+				CreatedAt: createdAt},
+		}, reviews)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPrReviewersStorage(mock)
+
+		mock.ExpectQuery("SELECT prr.reviewer_id").
+			WithArgs(string(domain.PullRequestStatusOPEN)).
+			WillReturnError(errors.New("database error"))
+
+		_, err = storage.GetPendingReviews(ctx)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestPrReviewersStorage_GetOpenReviewsByReviewers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully get open reviews", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPrReviewersStorage(mock)
+		reviewerIDs := []string{"user-1", "user-2"}
+
+		mock.ExpectQuery("SELECT prr.pull_request_id, pr.author_id, prr.reviewer_id").
+			WithArgs(reviewerIDs, string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"pull_request_id", "author_id", "reviewer_id"}).
+				AddRow(testStrID, "author", "user-1").
+				AddRow(testStrID, "author", "user-2"))
+
+		reviews, err := storage.GetOpenReviewsByReviewers(ctx, reviewerIDs)
+
+		require.NoError(t, err)
+		assert.Equal(t, []domain.OpenReview{
+			{PrID: testStrID, AuthorID: "author", ReviewerID: "user-1"},
+			{PrID: testStrID, AuthorID: "author", ReviewerID: "user-2"},
+		}, reviews)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPrReviewersStorage(mock)
+
+		mock.ExpectQuery("SELECT prr.pull_request_id").
+			WithArgs([]string{"user-1"}, string(domain.PullRequestStatusOPEN)).
+			WillReturnError(errors.New("database error"))
+
+		_, err = storage.GetOpenReviewsByReviewers(ctx, []string{"user-1"})
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPrReviewersStorage_GetReviewerAssignments(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully get assignments with meta", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPrReviewersStorage(mock)
+		assignedAt := time.Now()
+		seed := int64(42)
+		meta := &domain.AssignmentMeta{
+			Strategy:       domain.AssignmentStrategyRandom,
+			CandidateCount: 3,
+			Seed:           &seed,
+		}
+
+		mock.ExpectQuery("SELECT reviewer_id, assigned_at, assignment_meta").
+			WithArgs(testID).
+			WillReturnRows(pgxmock.NewRows([]string{"reviewer_id", "assigned_at", "assignment_meta"}).
+				AddRow("user-1", &assignedAt, meta).
+				AddRow("user-2", &assignedAt, (*domain.AssignmentMeta)(nil)))
+
+		assignments, err := storage.GetReviewerAssignments(ctx, testID)
+
+		require.NoError(t, err)
+		require.Len(t, assignments, 2)
+		assert.Equal(t, "user-1", assignments[0].ReviewerID)
+		assert.Equal(t, meta, assignments[0].Meta)
+		assert.Nil(t, assignments[1].Meta)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPrReviewersStorage(mock)
+
+		mock.ExpectQuery("SELECT reviewer_id, assigned_at, assignment_meta").
+			WithArgs(testID).
+			WillReturnError(errors.New("database error"))
+
+		_, err = storage.GetReviewerAssignments(ctx, testID)
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPrReviewersStorage_RecordAssignmentEvent(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully record no candidate event", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPrReviewersStorage(mock)
+
+		mock.ExpectExec("INSERT INTO assignment_events").
+			WithArgs(testID, string(domain.AssignmentEventNoCandidate), string(domain.AssignmentStrategyReassign), "user-1", "").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		err = storage.RecordAssignmentEvent(ctx, domain.AssignmentEvent{
+			PrID:          testID,
+			Type:          domain.AssignmentEventNoCandidate,
+			Strategy:      domain.AssignmentStrategyReassign,
+			OldReviewerID: "user-1",
+		})
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("insert error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPrReviewersStorage(mock)
+
+		mock.ExpectExec("INSERT INTO assignment_events").
+			WithArgs(testID, string(domain.AssignmentEventNoCandidate), "", "", "").
+			WillReturnError(errors.New("database error"))
+
+		err = storage.RecordAssignmentEvent(ctx, domain.AssignmentEvent{
+			PrID: testID,
+			Type: domain.AssignmentEventNoCandidate,
+		})
 
 		assert.Error(t, err)
 		require.NoError(t, mock.ExpectationsWereMet())
@@ -2,12 +2,18 @@ package pullRequestStorage
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/eventStorage"
 	"github.com/nedokyrill/avito-pr-api/pkg/utils/db"
 )
 
+// ErrPullRequestNotOpen - PR уже не в статусе OPEN (смерджен или закрыт параллельным запросом)
+var ErrPullRequestNotOpen = errors.New(domain.PullRequestNotOpenErr)
+
 type PullRequestStorage struct {
 	db db.Querier
 }
@@ -48,21 +54,122 @@ func (s *PullRequestStorage) GetPullRequestByID(ctx context.Context, prID string
 	return pr, nil
 }
 
+// MergePullRequest мерджит открытый PR и в той же транзакции пишет pr.merged в outbox. Если PR
+// уже не в статусе OPEN, ничего не меняется и возвращается ErrPullRequestNotOpen
 func (s *PullRequestStorage) MergePullRequest(ctx context.Context, prID string) error {
 	mergedAt := time.Now()
 
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
 	query := `
 		UPDATE pull_requests
 		SET status = $1, merged_at = $2
-		WHERE id = $3 AND status != $1`
+		WHERE id = $3 AND status = $4
+		RETURNING name, author_id,
+			ARRAY(SELECT reviewer_id FROM pr_reviewers WHERE pull_request_id = $3 ORDER BY assigned_at)`
 
-	_, err := s.db.Exec(ctx, query, string(domain.PullRequestStatusMERGED), mergedAt, prID)
+	pr := &domain.PullRequest{
+		PullRequestId: prID,
+		Status:        domain.PullRequestStatusMERGED,
+		MergedAt:      &mergedAt,
+	}
+	err = tx.QueryRow(ctx, query, string(domain.PullRequestStatusMERGED), mergedAt, prID,
+		string(domain.PullRequestStatusOPEN)).
+		Scan(&pr.PullRequestName, &pr.AuthorId, &pr.AssignedReviewers)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPullRequestNotOpen
+		}
+		return err
+	}
+
+	event, err := domain.NewPullRequestMergedEvent(pr, mergedAt)
+	if err != nil {
+		return err
+	}
+	if err = eventStorage.WriteEvents(ctx, tx, []domain.Event{event}); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// ClosePullRequest закрывает открытый PR без мерджа и в той же транзакции пишет pr.closed в outbox.
+// Для PR не в статусе OPEN ничего не меняется и событие не пишется
+func (s *PullRequestStorage) ClosePullRequest(ctx context.Context, prID string) error {
+	return s.changeStatus(ctx, prID, domain.PullRequestStatusOPEN, domain.PullRequestStatusCLOSED,
+		domain.NewPullRequestClosedEvent)
+}
+
+// ReopenPullRequest снова открывает закрытый PR и в той же транзакции пишет pr.reopened в outbox
+func (s *PullRequestStorage) ReopenPullRequest(ctx context.Context, prID string) error {
+	return s.changeStatus(ctx, prID, domain.PullRequestStatusCLOSED, domain.PullRequestStatusOPEN,
+		domain.NewPullRequestReopenedEvent)
+}
+
+func (s *PullRequestStorage) changeStatus(
+	ctx context.Context,
+	prID string,
+	from, to domain.PullRequestStatus,
+	newEvent func(pr *domain.PullRequest, occurredAt time.Time) (domain.Event, error),
+) error {
+	changedAt := time.Now()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// closed_at хранит время последнего закрытия, при повторном открытии сбрасывается
+	var closedAt *time.Time
+	if to == domain.PullRequestStatusCLOSED {
+		closedAt = &changedAt
+	}
+
+	query := `
+		UPDATE pull_requests
+		SET status = $1, closed_at = $4
+		WHERE id = $2 AND status = $3
+		RETURNING name, author_id,
+			ARRAY(SELECT reviewer_id FROM pr_reviewers WHERE pull_request_id = $2 ORDER BY assigned_at)`
+
+	pr := &domain.PullRequest{
+		PullRequestId: prID,
+		Status:        to,
+	}
+	err = tx.QueryRow(ctx, query, string(to), prID, string(from), closedAt).
+		Scan(&pr.PullRequestName, &pr.AuthorId, &pr.AssignedReviewers)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	event, err := newEvent(pr, changedAt)
+	if err != nil {
+		return err
+	}
+	if err = eventStorage.WriteEvents(ctx, tx, []domain.Event{event}); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *PullRequestStorage) SetNeedMoreReviewers(ctx context.Context, prID string, needMore bool) error {
 	query := `UPDATE pull_requests SET need_more_reviewers = $1 WHERE id = $2`
 
@@ -79,6 +186,7 @@ func (s *PullRequestStorage) CreatePullRequestWithReviewers(
 	pr *domain.PullRequest,
 	reviewerIDs []string,
 	needMoreReviewers bool,
+	meta *domain.AssignmentMeta,
 ) error {
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
@@ -93,12 +201,15 @@ func (s *PullRequestStorage) CreatePullRequestWithReviewers(
 	}
 
 	reviewerQuery := `
-		INSERT INTO pr_reviewers (pull_request_id, reviewer_id, assigned_at)
-		VALUES ($1, $2, $3)`
+		INSERT INTO pr_reviewers (pull_request_id, reviewer_id, assigned_at, assignment_meta)
+		VALUES ($1, $2, $3, $4)`
 
 	now := time.Now()
+	if pr.CreatedAt != nil {
+		now = *pr.CreatedAt
+	}
 	for _, reviewerID := range reviewerIDs {
-		_, err = tx.Exec(ctx, reviewerQuery, pr.PullRequestId, reviewerID, now)
+		_, err = tx.Exec(ctx, reviewerQuery, pr.PullRequestId, reviewerID, now, meta)
 		if err != nil {
 			return err
 		}
@@ -112,6 +223,21 @@ func (s *PullRequestStorage) CreatePullRequestWithReviewers(
 		}
 	}
 
+	// pr.created и reviewer.assigned пишутся в outbox вместе с PR
+	created := *pr
+	created.AssignedReviewers = reviewerIDs
+	var strategy domain.AssignmentStrategy
+	if meta != nil {
+		strategy = meta.Strategy
+	}
+	events, err := domain.NewPullRequestCreatedEvents(&created, strategy, now)
+	if err != nil {
+		return err
+	}
+	if err = eventStorage.WriteEvents(ctx, tx, events); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		return err
 	}
@@ -77,15 +77,170 @@ func TestPullRequestStorage_MergePullRequest(t *testing.T) {
 		storage := NewPullRequestStorage(mock)
 		prID := testID
 
-		mock.ExpectExec("UPDATE pull_requests").
-			WithArgs(string(domain.PullRequestStatusMERGED), pgxmock.AnyArg(), prID).
-			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE pull_requests").
+			WithArgs(string(domain.PullRequestStatusMERGED), pgxmock.AnyArg(), prID,
+				string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"name", "author_id", "reviewers"}).
+				AddRow("Add feature", "u1", []string{"u2", "u3"}))
+		expectOutboxLock(mock)
+		expectOutboxInsert(mock, domain.EventPRMerged)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
 
 		err = storage.MergePullRequest(ctx, prID)
 
 		require.NoError(t, err)
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("PR no longer open - no event", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPullRequestStorage(mock)
+		prID := testID
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE pull_requests").
+			WithArgs(string(domain.PullRequestStatusMERGED), pgxmock.AnyArg(), prID,
+				string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"name", "author_id", "reviewers"}))
+		mock.ExpectRollback()
+
+		err = storage.MergePullRequest(ctx, prID)
+
+		require.ErrorIs(t, err, ErrPullRequestNotOpen)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error writing outbox - rollback", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPullRequestStorage(mock)
+		prID := testID
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE pull_requests").
+			WithArgs(string(domain.PullRequestStatusMERGED), pgxmock.AnyArg(), prID,
+				string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"name", "author_id", "reviewers"}).
+				AddRow("Add feature", "u1", []string{"u2"}))
+		expectOutboxLock(mock)
+		mock.ExpectExec("INSERT INTO outbox_events").
+			WithArgs(pgxmock.AnyArg(), string(domain.EventPRMerged), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(errors.New("outbox error"))
+		mock.ExpectRollback()
+
+		err = storage.MergePullRequest(ctx, prID)
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPullRequestStorage_ClosePullRequest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully close PR", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPullRequestStorage(mock)
+		prID := testID
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE pull_requests").
+			WithArgs(string(domain.PullRequestStatusCLOSED), prID, string(domain.PullRequestStatusOPEN), pgxmock.AnyArg()).
+			WillReturnRows(pgxmock.NewRows([]string{"name", "author_id", "reviewers"}).
+				AddRow("Add feature", "u1", []string{"u2", "u3"}))
+		expectOutboxLock(mock)
+		expectOutboxInsert(mock, domain.EventPRClosed)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err = storage.ClosePullRequest(ctx, prID)
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("PR not open - no event", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPullRequestStorage(mock)
+		prID := testID
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE pull_requests").
+			WithArgs(string(domain.PullRequestStatusCLOSED), prID, string(domain.PullRequestStatusOPEN), pgxmock.AnyArg()).
+			WillReturnRows(pgxmock.NewRows([]string{"name", "author_id", "reviewers"}))
+		mock.ExpectRollback()
+
+		err = storage.ClosePullRequest(ctx, prID)
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPullRequestStorage_ReopenPullRequest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully reopen PR", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPullRequestStorage(mock)
+		prID := testID
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE pull_requests").
+			WithArgs(string(domain.PullRequestStatusOPEN), prID, string(domain.PullRequestStatusCLOSED), (*time.Time)(nil)).
+			WillReturnRows(pgxmock.NewRows([]string{"name", "author_id", "reviewers"}).
+				AddRow("Add feature", "u1", []string{"u2"}))
+		expectOutboxLock(mock)
+		expectOutboxInsert(mock, domain.EventPRReopened)
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err = storage.ReopenPullRequest(ctx, prID)
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error writing outbox - rollback", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPullRequestStorage(mock)
+		prID := testID
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE pull_requests").
+			WithArgs(string(domain.PullRequestStatusOPEN), prID, string(domain.PullRequestStatusCLOSED), (*time.Time)(nil)).
+			WillReturnRows(pgxmock.NewRows([]string{"name", "author_id", "reviewers"}).
+				AddRow("Add feature", "u1", []string{"u2"}))
+		expectOutboxLock(mock)
+		mock.ExpectExec("INSERT INTO outbox_events").
+			WithArgs(pgxmock.AnyArg(), string(domain.EventPRReopened), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(errors.New("outbox error"))
+		mock.ExpectRollback()
+
+		err = storage.ReopenPullRequest(ctx, prID)
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestPullRequestStorage_SetNeedMoreReviewers(t *testing.T) {
@@ -139,17 +294,22 @@ func TestPullRequestStorage_CreatePullRequestWithReviewers(t *testing.T) {
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		mock.ExpectExec("INSERT INTO pr_reviewers").
-			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		mock.ExpectExec("INSERT INTO pr_reviewers").
-			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
+		expectOutboxLock(mock)
+		expectOutboxInsert(mock, domain.EventPRCreated)
+		expectOutboxInsert(mock, domain.EventReviewerAssigned)
+		expectOutboxInsert(mock, domain.EventReviewerAssigned)
+
 		mock.ExpectCommit()
 		mock.ExpectRollback()
 
-		err = storage.CreatePullRequestWithReviewers(ctx, pr, reviewerIDs, false)
+		err = storage.CreatePullRequestWithReviewers(ctx, pr, reviewerIDs, false, nil)
 
 		require.NoError(t, err)
 		require.NoError(t, mock.ExpectationsWereMet())
@@ -180,17 +340,21 @@ func TestPullRequestStorage_CreatePullRequestWithReviewers(t *testing.T) {
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		mock.ExpectExec("INSERT INTO pr_reviewers").
-			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		mock.ExpectExec("UPDATE pull_requests SET need_more_reviewers").
 			WithArgs(true, pgxmock.AnyArg()).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 
+		expectOutboxLock(mock)
+		expectOutboxInsert(mock, domain.EventPRCreated)
+		expectOutboxInsert(mock, domain.EventReviewerAssigned)
+
 		mock.ExpectCommit()
 		mock.ExpectRollback()
 
-		err = storage.CreatePullRequestWithReviewers(ctx, pr, reviewerIDs, true)
+		err = storage.CreatePullRequestWithReviewers(ctx, pr, reviewerIDs, true, nil)
 
 		require.NoError(t, err)
 		require.NoError(t, mock.ExpectationsWereMet())
@@ -219,7 +383,7 @@ func TestPullRequestStorage_CreatePullRequestWithReviewers(t *testing.T) {
 
 		mock.ExpectRollback()
 
-		err = storage.CreatePullRequestWithReviewers(ctx, pr, []string{}, false)
+		err = storage.CreatePullRequestWithReviewers(ctx, pr, []string{}, false, nil)
 
 		assert.Error(t, err)
 		require.NoError(t, mock.ExpectationsWereMet())
@@ -250,14 +414,58 @@ func TestPullRequestStorage_CreatePullRequestWithReviewers(t *testing.T) {
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		mock.ExpectExec("INSERT INTO pr_reviewers").
-			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
 			WillReturnError(errors.New("reviewer insert error"))
 
 		mock.ExpectRollback()
 
-		err = storage.CreatePullRequestWithReviewers(ctx, pr, reviewerIDs, false)
+		err = storage.CreatePullRequestWithReviewers(ctx, pr, reviewerIDs, false, nil)
 
 		assert.Error(t, err)
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("error writing outbox - rollback", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewPullRequestStorage(mock)
+
+		pr := &domain.PullRequest{
+			PullRequestId:   testStrID,
+			PullRequestName: "Add feature",
+			AuthorId:        testStrID,
+			Status:          domain.PullRequestStatusOPEN,
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO pull_requests").
+			WithArgs(pgxmock.AnyArg(), "Add feature", pgxmock.AnyArg(), string(domain.PullRequestStatusOPEN)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		expectOutboxLock(mock)
+		mock.ExpectExec("INSERT INTO outbox_events").
+			WithArgs(pgxmock.AnyArg(), string(domain.EventPRCreated), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(errors.New("outbox error"))
+
+		mock.ExpectRollback()
+
+		err = storage.CreatePullRequestWithReviewers(ctx, pr, []string{}, false, nil)
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// expectOutboxLock ожидает блокировку позиций потока, которую берёт запись событий в outbox
+func expectOutboxLock(mock pgxmock.PgxPoolIface) {
+	mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+}
+
+// expectOutboxInsert ожидает запись события в outbox внутри транзакции
+func expectOutboxInsert(mock pgxmock.PgxPoolIface, eventType domain.EventType) {
+	mock.ExpectExec("INSERT INTO outbox_events").
+		WithArgs(pgxmock.AnyArg(), string(eventType), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 }
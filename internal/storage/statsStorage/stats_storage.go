@@ -0,0 +1,298 @@
+package statsStorage
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/db"
+)
+
+type StatsStorage struct {
+	db db.Querier
+}
+
+func NewStatsStorage(db db.Querier) *StatsStorage {
+	return &StatsStorage{
+		db: db,
+	}
+}
+
+// reviewsCTE - назначения за период и замены (по assignment_meta), сгруппированные по заменённому ревьюверу.
+// Параметры: $1 - from, $2 - to
+const reviewsCTE = `
+	WITH assigned AS (
+		SELECT prr.reviewer_id, pr.status, prr.assigned_at, pr.merged_at
+		FROM pr_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pull_request_id
+		WHERE prr.assigned_at >= $1 AND prr.assigned_at < $2
+	),
+	reassigned AS (
+		SELECT prr.assignment_meta->>'replaced_reviewer_id' AS reviewer_id, count(*) AS cnt
+		FROM pr_reviewers prr
+		WHERE prr.assigned_at >= $1 AND prr.assigned_at < $2
+		  AND prr.assignment_meta ? 'replaced_reviewer_id'
+		GROUP BY 1
+	)`
+
+// GetUserReviewStats считает статистику ревью по каждому пользователю (или участникам команды) одним запросом
+func (s *StatsStorage) GetUserReviewStats(ctx context.Context, filter domain.StatsFilter) ([]domain.UserReviewStats, error) {
+	query := reviewsCTE + `
+	SELECT u.id, u.name,
+		count(a.reviewer_id) FILTER (WHERE a.status = $4),
+		count(a.reviewer_id) FILTER (WHERE a.status = $5),
+		count(a.reviewer_id) FILTER (WHERE a.status = $6),
+		coalesce(r.cnt, 0),
+		percentile_cont(0.5) WITHIN GROUP (ORDER BY extract(epoch FROM a.merged_at - a.assigned_at) / 3600)
+			FILTER (WHERE a.status = $5)
+	FROM users u
+	LEFT JOIN assigned a ON a.reviewer_id = u.id
+	LEFT JOIN reassigned r ON r.reviewer_id = u.id
+	WHERE $3::text IS NULL OR EXISTS (
+		SELECT 1 FROM team_members tm JOIN teams t ON t.id = tm.team_id
+		WHERE tm.user_id = u.id AND t.name = $3
+	)
+	GROUP BY u.id, u.name, r.cnt
+	ORDER BY u.id`
+
+	rows, err := s.db.Query(ctx, query,
+		filter.From,
+		filter.To,
+		filter.TeamName,
+		string(domain.PullRequestStatusOPEN),
+		string(domain.PullRequestStatusMERGED),
+		string(domain.PullRequestStatusCLOSED),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]domain.UserReviewStats, 0)
+	for rows.Next() {
+		var userStats domain.UserReviewStats
+		if err = scanReviewStats(rows, &userStats.ReviewStats, &userStats.UserID, &userStats.Username); err != nil {
+			return nil, err
+		}
+		stats = append(stats, userStats)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// GetTeamReviewStats считает статистику ревью по командам через членство участников
+func (s *StatsStorage) GetTeamReviewStats(ctx context.Context, filter domain.StatsFilter) ([]domain.TeamReviewStats, error) {
+	query := reviewsCTE + `
+	SELECT t.name,
+		count(a.reviewer_id) FILTER (WHERE a.status = $4),
+		count(a.reviewer_id) FILTER (WHERE a.status = $5),
+		count(a.reviewer_id) FILTER (WHERE a.status = $6),
+		coalesce((
+			SELECT sum(r.cnt)::bigint
+			FROM reassigned r
+			JOIN team_members m ON m.user_id = r.reviewer_id
+			WHERE m.team_id = t.id
+		), 0),
+		percentile_cont(0.5) WITHIN GROUP (ORDER BY extract(epoch FROM a.merged_at - a.assigned_at) / 3600)
+			FILTER (WHERE a.status = $5)
+	FROM teams t
+	LEFT JOIN team_members tm ON tm.team_id = t.id
+	LEFT JOIN assigned a ON a.reviewer_id = tm.user_id
+	WHERE $3::text IS NULL OR t.name = $3
+	GROUP BY t.id, t.name
+	ORDER BY t.name`
+
+	rows, err := s.db.Query(ctx, query,
+		filter.From,
+		filter.To,
+		filter.TeamName,
+		string(domain.PullRequestStatusOPEN),
+		string(domain.PullRequestStatusMERGED),
+		string(domain.PullRequestStatusCLOSED),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]domain.TeamReviewStats, 0)
+	for rows.Next() {
+		var teamStats domain.TeamReviewStats
+		if err = scanReviewStats(rows, &teamStats.ReviewStats, &teamStats.TeamName); err != nil {
+			return nil, err
+		}
+		stats = append(stats, teamStats)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// scanReviewStats читает ключевые колонки (keys) и следующие за ними счётчики
+func scanReviewStats(rows pgx.Rows, stats *domain.ReviewStats, keys ...any) error {
+	var reassigned int64
+	dest := append(keys, &stats.Open, &stats.Completed, &stats.Closed, &reassigned, &stats.MedianHoursToMerge)
+	if err := rows.Scan(dest...); err != nil {
+		return err
+	}
+
+	stats.ReassignedAway = int(reassigned)
+	stats.Assigned = stats.Open + stats.Completed + stats.Closed + stats.ReassignedAway
+	return nil
+}
+
+// teamPRsJoin - PR команды по основной команде автора, $1 - имя команды
+const teamPRsJoin = `
+	FROM pull_requests pr
+	JOIN users au ON au.id = pr.author_id
+	JOIN teams t ON t.id = au.team_id AND t.name = $1`
+
+// GetTeamDigest собирает дайджест команды за [from, to). Если команды нет - pgx.ErrNoRows
+func (s *StatsStorage) GetTeamDigest(ctx context.Context, teamName string, from, to time.Time) (*domain.TeamDigest, error) {
+	digest := &domain.TeamDigest{TeamName: teamName, From: from, To: to}
+
+	summaryQuery := `
+		SELECT
+			count(pr.id) FILTER (WHERE pr.created_at >= $2 AND pr.created_at < $3),
+			count(pr.id) FILTER (WHERE pr.status = $4 AND pr.merged_at >= $2 AND pr.merged_at < $3),
+			avg(extract(epoch FROM pr.merged_at - pr.created_at) / 3600)
+				FILTER (WHERE pr.status = $4 AND pr.merged_at >= $2 AND pr.merged_at < $3),
+			count(pr.id) FILTER (WHERE pr.status = $5 AND pr.closed_at >= $2 AND pr.closed_at < $3)
+		FROM teams t
+		LEFT JOIN users au ON au.team_id = t.id
+		LEFT JOIN pull_requests pr ON pr.author_id = au.id
+		WHERE t.name = $1
+		GROUP BY t.id`
+
+	err := s.db.QueryRow(ctx, summaryQuery, teamName, from, to,
+		string(domain.PullRequestStatusMERGED), string(domain.PullRequestStatusCLOSED)).
+		Scan(&digest.Opened, &digest.Merged, &digest.AvgHoursToMerge, &digest.Closed)
+	if err != nil {
+		return nil, err
+	}
+
+	if digest.TopReviewers, err = s.queryDigestTopReviewers(ctx, teamName, from, to); err != nil {
+		return nil, err
+	}
+	if digest.Waiting, err = s.queryDigestWaiting(ctx, teamName); err != nil {
+		return nil, err
+	}
+	if digest.Reassignments, err = s.queryDigestReassignments(ctx, teamName, from, to); err != nil {
+		return nil, err
+	}
+
+	return digest, nil
+}
+
+// queryDigestTopReviewers - ревьюверы PR команды, смердженных за период. Заменённые ревьюверы
+// удаляются из pr_reviewers, поэтому учитываются только те, кто остался на PR до мерджа
+func (s *StatsStorage) queryDigestTopReviewers(ctx context.Context, teamName string, from, to time.Time) ([]domain.DigestReviewer, error) {
+	query := `
+		SELECT prr.reviewer_id, ru.name, count(*)` + teamPRsJoin + `
+		JOIN pr_reviewers prr ON prr.pull_request_id = pr.id
+		JOIN users ru ON ru.id = prr.reviewer_id
+		WHERE pr.status = $4 AND pr.merged_at >= $2 AND pr.merged_at < $3
+		GROUP BY prr.reviewer_id, ru.name
+		ORDER BY count(*) DESC, prr.reviewer_id
+		LIMIT $5`
+
+	rows, err := s.db.Query(ctx, query, teamName, from, to,
+		string(domain.PullRequestStatusMERGED), domain.DigestTopReviewersLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviewers := make([]domain.DigestReviewer, 0)
+	for rows.Next() {
+		var reviewer domain.DigestReviewer
+		var reviews int64
+		if err = rows.Scan(&reviewer.UserID, &reviewer.Username, &reviews); err != nil {
+			return nil, err
+		}
+		reviewer.Reviews = int(reviews)
+		reviewers = append(reviewers, reviewer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviewers, nil
+}
+
+// queryDigestWaiting - все открытые PR команды, самые старые первыми
+func (s *StatsStorage) queryDigestWaiting(ctx context.Context, teamName string) ([]domain.DigestWaitingPR, error) {
+	query := `
+		SELECT pr.id, pr.name, pr.author_id, pr.created_at, pr.need_more_reviewers,
+			coalesce(array_agg(prr.reviewer_id ORDER BY prr.reviewer_id)
+				FILTER (WHERE prr.reviewer_id IS NOT NULL), '{}')` + teamPRsJoin + `
+		LEFT JOIN pr_reviewers prr ON prr.pull_request_id = pr.id
+		WHERE pr.status = $2
+		GROUP BY pr.id
+		ORDER BY pr.created_at, pr.id`
+
+	rows, err := s.db.Query(ctx, query, teamName, string(domain.PullRequestStatusOPEN))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	waiting := make([]domain.DigestWaitingPR, 0)
+	for rows.Next() {
+		var pr domain.DigestWaitingPR
+		if err = rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.CreatedAt,
+			&pr.NeedMoreReviewers, &pr.ReviewerIDs); err != nil {
+			return nil, err
+		}
+		waiting = append(waiting, pr)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return waiting, nil
+}
+
+func (s *StatsStorage) queryDigestReassignments(ctx context.Context, teamName string, from, to time.Time) ([]domain.DigestReassignment, error) {
+	query := `
+		SELECT ev.pull_request_id, ev.event_type, coalesce(ev.strategy, ''),
+			ev.old_reviewer_id, ev.new_reviewer_id, ev.created_at` + teamPRsJoin + `
+		JOIN assignment_events ev ON ev.pull_request_id = pr.id
+		WHERE ev.created_at >= $2 AND ev.created_at < $3
+		ORDER BY ev.created_at, ev.id`
+
+	rows, err := s.db.Query(ctx, query, teamName, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reassignments := make([]domain.DigestReassignment, 0)
+	for rows.Next() {
+		var reassignment domain.DigestReassignment
+		var eventType, strategy string
+		if err = rows.Scan(&reassignment.PullRequestID, &eventType, &strategy,
+			&reassignment.OldReviewerID, &reassignment.NewReviewerID, &reassignment.CreatedAt); err != nil {
+			return nil, err
+		}
+		reassignment.Type = domain.AssignmentEventType(eventType)
+		reassignment.Strategy = domain.AssignmentStrategy(strategy)
+		reassignments = append(reassignments, reassignment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reassignments, nil
+}
@@ -0,0 +1,208 @@
+package statsStorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFilter() domain.StatsFilter {
+	to := time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC)
+	return domain.StatsFilter{From: to.Add(-domain.DefaultStatsPeriod), To: to}
+}
+
+func TestStatsStorage_GetUserReviewStats(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully get user stats", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewStatsStorage(mock)
+		filter := testFilter()
+		median := 5.5
+
+		mock.ExpectQuery("SELECT u.id, u.name").
+			WithArgs(filter.From, filter.To, filter.TeamName,
+				string(domain.PullRequestStatusOPEN), string(domain.PullRequestStatusMERGED),
+				string(domain.PullRequestStatusCLOSED)).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "name", "open", "completed", "closed", "reassigned", "median"}).
+				AddRow("user-1", "Alice", 2, 3, 1, int64(1), &median).
+				AddRow("user-2", "Bob", 0, 0, 0, int64(0), (*float64)(nil)))
+
+		stats, err := storage.GetUserReviewStats(ctx, filter)
+
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+		assert.Equal(t, domain.UserReviewStats{
+			UserID:   "user-1",
+			Username: "Alice",
+			ReviewStats: domain.ReviewStats{
+				Assigned:           7,
+				Open:               2,
+				Completed:          3,
+				Closed:             1,
+				ReassignedAway:     1,
+				MedianHoursToMerge: &median,
+			},
+		}, stats[0])
+		assert.Nil(t, stats[1].MedianHoursToMerge)
+		assert.Equal(t, 0, stats[1].Assigned)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewStatsStorage(mock)
+		filter := testFilter()
+
+		mock.ExpectQuery("SELECT u.id, u.name").
+			WithArgs(filter.From, filter.To, filter.TeamName,
+				string(domain.PullRequestStatusOPEN), string(domain.PullRequestStatusMERGED),
+				string(domain.PullRequestStatusCLOSED)).
+			WillReturnError(errors.New("database error"))
+
+		_, err = storage.GetUserReviewStats(ctx, filter)
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestStatsStorage_GetTeamReviewStats(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully get team stats", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewStatsStorage(mock)
+		filter := testFilter()
+		teamName := "Backend"
+		filter.TeamName = &teamName
+		median := 12.0
+
+		mock.ExpectQuery("SELECT t.name").
+			WithArgs(filter.From, filter.To, filter.TeamName,
+				string(domain.PullRequestStatusOPEN), string(domain.PullRequestStatusMERGED),
+				string(domain.PullRequestStatusCLOSED)).
+			WillReturnRows(pgxmock.NewRows([]string{"name", "open", "completed", "closed", "reassigned", "median"}).
+				AddRow("Backend", 4, 10, 3, int64(2), &median))
+
+		stats, err := storage.GetTeamReviewStats(ctx, filter)
+
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.Equal(t, "Backend", stats[0].TeamName)
+		assert.Equal(t, 19, stats[0].Assigned)
+		assert.Equal(t, 3, stats[0].Closed)
+		assert.Equal(t, 2, stats[0].ReassignedAway)
+		assert.Equal(t, &median, stats[0].MedianHoursToMerge)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewStatsStorage(mock)
+		filter := testFilter()
+
+		mock.ExpectQuery("SELECT t.name").
+			WithArgs(filter.From, filter.To, filter.TeamName,
+				string(domain.PullRequestStatusOPEN), string(domain.PullRequestStatusMERGED),
+				string(domain.PullRequestStatusCLOSED)).
+			WillReturnError(errors.New("database error"))
+
+		_, err = storage.GetTeamReviewStats(ctx, filter)
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestStatsStorage_GetTeamDigest(t *testing.T) {
+	ctx := context.Background()
+	to := time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC)
+	from := to.Add(-domain.DefaultDigestPeriod)
+
+	t.Run("successfully build digest", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewStatsStorage(mock)
+		avg := 10.5
+		createdAt := to.Add(-48 * time.Hour)
+		oldReviewer := "user-bob"
+
+		mock.ExpectQuery("FROM teams t").
+			WithArgs("Backend", from, to,
+				string(domain.PullRequestStatusMERGED), string(domain.PullRequestStatusCLOSED)).
+			WillReturnRows(pgxmock.NewRows([]string{"opened", "merged", "avg", "closed"}).
+				AddRow(3, 2, &avg, 1))
+
+		mock.ExpectQuery("SELECT prr.reviewer_id, ru.name, count").
+			WithArgs("Backend", from, to, string(domain.PullRequestStatusMERGED), domain.DigestTopReviewersLimit).
+			WillReturnRows(pgxmock.NewRows([]string{"reviewer_id", "name", "count"}).
+				AddRow("user-alice", "Alice", int64(2)))
+
+		mock.ExpectQuery("array_agg\\(prr.reviewer_id").
+			WithArgs("Backend", string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "name", "author_id", "created_at", "need_more", "reviewers"}).
+				AddRow("pr-1", "Add feature", "user-carol", createdAt, true, []string{"user-alice"}))
+
+		mock.ExpectQuery("JOIN assignment_events ev").
+			WithArgs("Backend", from, to).
+			WillReturnRows(pgxmock.NewRows([]string{"pr_id", "type", "strategy", "old", "new", "created_at"}).
+				AddRow("pr-1", string(domain.AssignmentEventRemoved), string(domain.AssignmentStrategyDeactivation),
+					&oldReviewer, (*string)(nil), to.Add(-time.Hour)))
+
+		digest, err := storage.GetTeamDigest(ctx, "Backend", from, to)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, digest.Opened)
+		assert.Equal(t, 2, digest.Merged)
+		assert.Equal(t, &avg, digest.AvgHoursToMerge)
+		assert.Equal(t, 1, digest.Closed)
+		assert.Equal(t, []domain.DigestReviewer{{UserID: "user-alice", Username: "Alice", Reviews: 2}}, digest.TopReviewers)
+		require.Len(t, digest.Waiting, 1)
+		assert.True(t, digest.Waiting[0].NeedMoreReviewers)
+		assert.Equal(t, []string{"user-alice"}, digest.Waiting[0].ReviewerIDs)
+		require.Len(t, digest.Reassignments, 1)
+		assert.Equal(t, domain.AssignmentEventRemoved, digest.Reassignments[0].Type)
+		assert.Nil(t, digest.Reassignments[0].NewReviewerID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewStatsStorage(mock)
+
+		mock.ExpectQuery("FROM teams t").
+			WithArgs("Unknown", from, to,
+				string(domain.PullRequestStatusMERGED), string(domain.PullRequestStatusCLOSED)).
+			WillReturnRows(pgxmock.NewRows([]string{"opened", "merged", "avg", "closed"}))
+
+		_, err = storage.GetTeamDigest(ctx, "Unknown", from, to)
+
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
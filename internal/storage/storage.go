@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
@@ -9,24 +10,112 @@ import (
 
 type TeamRepositoryInterface interface {
 	GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
-	CreateTeamWithMembers(ctx context.Context, teamName string, members []domain.TeamMember) (uuid.UUID, error)
+	GetTeamWithAncestors(ctx context.Context, teamName string) ([]domain.Team, error)
+	GetTeamsHierarchy(ctx context.Context) ([]domain.TeamTreeNode, error)
+	SetTeamParent(ctx context.Context, teamName string, parentTeamName *string) error
+	SetReviewSLA(ctx context.Context, teamName string, hours *int) error
+	CreateTeamWithMembers(ctx context.Context, teamName string, parentTeamName *string, members []domain.TeamMember) (uuid.UUID, error)
+	AddTeamMember(ctx context.Context, teamName, userID string) error
+	RemoveTeamMember(ctx context.Context, teamName, userID string) error
+	SetPrimaryTeam(ctx context.Context, userID, teamName string) error
 	DeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string, reassignments []domain.ReviewerReassignment) ([]string, error)
+	SaveDeactivationPlan(ctx context.Context, plan *domain.DeactivationPlan) error
+	GetDeactivationPlan(ctx context.Context, planID uuid.UUID) (*domain.DeactivationPlan, error)
+	ApplyDeactivationPlan(ctx context.Context, plan *domain.DeactivationPlan) ([]string, error)
+	DeactivateUser(ctx context.Context, plan *domain.DeactivationPlan) ([]string, error)
+	ActivateTeamMembers(ctx context.Context, teamName string, userIDs []string, reassignments []domain.ReviewerReassignment) ([]string, error)
+	GetRoster(ctx context.Context) ([]domain.Team, error)
+	ApplyRosterImportPlan(ctx context.Context, plan *domain.RosterImportPlan) error
 }
 
 type UserRepositoryInterface interface {
 	GetUserByID(ctx context.Context, userID string) (*domain.User, error)
 	SetUserIsActive(ctx context.Context, userID string, isActive bool) error
+	ListUsers(ctx context.Context, filter domain.UserFilter, offset, limit int) ([]domain.User, int, error)
+	CreateUser(ctx context.Context, userID, username string, isActive bool) error
+	SetUserName(ctx context.Context, userID, username string) error
 }
 
 type PullRequestRepositoryInterface interface {
 	GetPullRequestByID(ctx context.Context, prID string) (*domain.PullRequest, error)
 	MergePullRequest(ctx context.Context, prID string) error
+	ClosePullRequest(ctx context.Context, prID string) error
+	ReopenPullRequest(ctx context.Context, prID string) error
 	SetNeedMoreReviewers(ctx context.Context, prID string, needMore bool) error
-	CreatePullRequestWithReviewers(ctx context.Context, pr *domain.PullRequest, reviewerIDs []string, needMoreReviewers bool) error
+	CreatePullRequestWithReviewers(
+		ctx context.Context,
+		pr *domain.PullRequest,
+		reviewerIDs []string,
+		needMoreReviewers bool,
+		meta *domain.AssignmentMeta,
+	) error
 }
 
 type PrReviewersRepositoryInterface interface {
 	GetAssignedReviewers(ctx context.Context, prID string) ([]string, error)
 	GetPRsByReviewer(ctx context.Context, userID string) ([]domain.PullRequestShort, error)
-	ReassignReviewerAtomic(ctx context.Context, prID, oldReviewerID, newReviewerID string) error
+	GetReviewerAssignments(ctx context.Context, prID string) ([]domain.ReviewerAssignment, error)
+	ReassignReviewerAtomic(ctx context.Context, prID, oldReviewerID, newReviewerID string, meta *domain.AssignmentMeta) error
+	GetOpenReviewsByReviewers(ctx context.Context, reviewerIDs []string) ([]domain.OpenReview, error)
+	GetPendingReviews(ctx context.Context) ([]domain.PendingReview, error)
+	RecordAssignmentEvent(ctx context.Context, event domain.AssignmentEvent) error
+}
+
+type StatsRepositoryInterface interface {
+	GetUserReviewStats(ctx context.Context, filter domain.StatsFilter) ([]domain.UserReviewStats, error)
+	GetTeamReviewStats(ctx context.Context, filter domain.StatsFilter) ([]domain.TeamReviewStats, error)
+	GetTeamDigest(ctx context.Context, teamName string, from, to time.Time) (*domain.TeamDigest, error)
+}
+
+type ExportRepositoryInterface interface {
+	StreamPullRequests(ctx context.Context, filter domain.ExportFilter, fn func(row domain.PullRequestExportRow) error) error
+	StreamReviews(ctx context.Context, filter domain.ExportFilter, fn func(row domain.ReviewExportRow) error) error
+}
+
+type WebhookRepositoryInterface interface {
+	GetUserIDByLogin(ctx context.Context, provider domain.VCSProvider, login string) (string, error)
+	UpsertUserMapping(ctx context.Context, mapping domain.VCSUserMapping) error
+	ListUserMappings(ctx context.Context, provider *domain.VCSProvider) ([]domain.VCSUserMapping, error)
+	ProcessDelivery(
+		ctx context.Context,
+		delivery domain.WebhookDelivery,
+		process func(ctx context.Context) (domain.WebhookResultStatus, error),
+	) (bool, error)
+}
+
+type SubscriptionRepositoryInterface interface {
+	CreateSubscription(ctx context.Context, subscription *domain.Subscription) error
+	ListSubscriptions(ctx context.Context) ([]domain.Subscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID string) error
+	EnqueueEvent(ctx context.Context, event domain.Event) (int64, error)
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.DueDelivery, error)
+	RecordAttempt(ctx context.Context, attempt domain.DeliveryAttempt) error
+	ListDeliveries(ctx context.Context, filter domain.DeliveryLogFilter) ([]domain.SubscriptionDelivery, error)
+	RequeueDeadDelivery(ctx context.Context, deliveryID int64) error
+}
+
+type OutboxRepositoryInterface interface {
+	ClaimPendingEvents(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxEvent, error)
+	MarkEventsPublished(ctx context.Context, ids []int64) error
+	RecordPublishFailure(ctx context.Context, id int64, errMsg string, nextAttemptAt time.Time) error
+	DeletePublishedEvents(ctx context.Context, before time.Time, limit int) (int, error)
+	GetLatestStreamPosition(ctx context.Context) (int64, error)
+	GetUserReviewEvents(ctx context.Context, userID string, afterPosition int64, limit int) ([]domain.OutboxEvent, error)
+}
+
+type NotificationRepositoryInterface interface {
+	UpsertTeamChannel(ctx context.Context, channel domain.TeamChatChannel) error
+	ListTeamChannels(ctx context.Context) ([]domain.TeamChatChannel, error)
+	DeleteTeamChannel(ctx context.Context, teamName string) error
+	UpsertUserMention(ctx context.Context, mention domain.UserChatMention) error
+	ListUserMentions(ctx context.Context) ([]domain.UserChatMention, error)
+	GetNotificationRecipients(ctx context.Context, userIDs []string) ([]domain.NotificationRecipient, error)
+	EnqueueNotifications(ctx context.Context, notifications []domain.ChatNotification) error
+	ClaimDueNotifications(ctx context.Context, limit int, lease time.Duration) ([]domain.DueNotification, error)
+	RecordNotificationAttempt(ctx context.Context, attempt domain.NotificationAttempt) error
+	GetNotificationPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error)
+	UpsertNotificationPreferences(ctx context.Context, prefs domain.NotificationPreferences) error
+	EnqueueEmails(ctx context.Context, emails []domain.EmailNotification) error
+	ClaimDueEmails(ctx context.Context, limit int, lease time.Duration) ([]domain.DueEmail, error)
+	RecordEmailAttempt(ctx context.Context, attempt domain.EmailAttempt) error
 }
@@ -0,0 +1,295 @@
+package subscriptionStorage
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/db"
+)
+
+type SubscriptionStorage struct {
+	db db.Querier
+}
+
+func NewSubscriptionStorage(db db.Querier) *SubscriptionStorage {
+	return &SubscriptionStorage{
+		db: db,
+	}
+}
+
+func (s *SubscriptionStorage) CreateSubscription(ctx context.Context, subscription *domain.Subscription) error {
+	query := `
+		INSERT INTO subscriptions (id, url, secret, event_types, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at`
+
+	return s.db.QueryRow(ctx, query,
+		subscription.ID,
+		subscription.URL,
+		subscription.Secret,
+		eventTypesToStrings(subscription.EventTypes),
+		subscription.IsActive,
+	).Scan(&subscription.CreatedAt)
+}
+
+func (s *SubscriptionStorage) ListSubscriptions(ctx context.Context) ([]domain.Subscription, error) {
+	query := `
+		SELECT id::text, url, event_types, is_active, created_at
+		FROM subscriptions
+		ORDER BY created_at, id`
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := make([]domain.Subscription, 0)
+	for rows.Next() {
+		var subscription domain.Subscription
+		var eventTypes []string
+		err = rows.Scan(
+			&subscription.ID,
+			&subscription.URL,
+			&eventTypes,
+			&subscription.IsActive,
+			&subscription.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		subscription.EventTypes = stringsToEventTypes(eventTypes)
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// DeleteSubscription удаляет подписку вместе с её журналом доставок; если подписки нет - pgx.ErrNoRows
+func (s *SubscriptionStorage) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	query := `DELETE FROM subscriptions WHERE id::text = $1`
+
+	tag, err := s.db.Exec(ctx, query, subscriptionID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// EnqueueEvent ставит событие в очередь всем активным подписчикам на его тип. Повторная постановка
+// того же события (тот же ID) ничего не добавляет. Возвращает число новых доставок
+func (s *SubscriptionStorage) EnqueueEvent(ctx context.Context, event domain.Event) (int64, error) {
+	payload, err := eventPayload(event)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		INSERT INTO subscription_deliveries (subscription_id, event_id, event_type, payload, status, next_attempt_at)
+		SELECT id, $1, $2, $3, $4, now()
+		FROM subscriptions
+		WHERE is_active AND $2 = ANY(event_types)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`
+
+	tag, err := s.db.Exec(ctx, query, event.ID, string(event.Type), payload, string(domain.DeliveryPending))
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// ClaimDueDeliveries забирает до limit доставок, время которых подошло, и сдвигает их next_attempt_at
+// на lease: пока попытка идёт, другие экземпляры сервиса их не возьмут, а если процесс упадёт -
+// доставка вернётся в очередь после lease
+func (s *SubscriptionStorage) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.DueDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM subscription_deliveries
+			WHERE status = $1 AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE subscription_deliveries d
+		SET next_attempt_at = now() + make_interval(secs => $3)
+		FROM due, subscriptions s
+		WHERE d.id = due.id AND s.id = d.subscription_id
+		RETURNING d.id, d.subscription_id::text, s.url, s.secret, d.event_id, d.event_type, d.payload, d.attempts`
+
+	rows, err := s.db.Query(ctx, query, string(domain.DeliveryPending), limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]domain.DueDelivery, 0)
+	for rows.Next() {
+		var delivery domain.DueDelivery
+		var eventType string
+		var payload []byte
+		err = rows.Scan(
+			&delivery.ID,
+			&delivery.SubscriptionID,
+			&delivery.URL,
+			&delivery.Secret,
+			&delivery.EventID,
+			&eventType,
+			&payload,
+			&delivery.Attempts,
+		)
+		if err != nil {
+			return nil, err
+		}
+		delivery.EventType = domain.EventType(eventType)
+		delivery.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// RecordAttempt сохраняет итог попытки: успех - DELIVERED, неуспех - PENDING с новым временем попытки
+// или DEAD, если повторять больше не нужно
+func (s *SubscriptionStorage) RecordAttempt(ctx context.Context, attempt domain.DeliveryAttempt) error {
+	status := domain.DeliveryPending
+	switch {
+	case attempt.Delivered:
+		status = domain.DeliveryDelivered
+	case attempt.NextAttemptAt == nil:
+		status = domain.DeliveryDead
+	}
+
+	var lastError *string
+	if attempt.Error != "" {
+		lastError = &attempt.Error
+	}
+
+	var nextAttemptAt, deliveredAt *time.Time
+	if attempt.Delivered {
+		now := time.Now()
+		deliveredAt = &now
+	} else {
+		nextAttemptAt = attempt.NextAttemptAt
+	}
+
+	query := `
+		UPDATE subscription_deliveries
+		SET status = $1,
+			attempts = attempts + 1,
+			next_attempt_at = $2,
+			last_status_code = $3,
+			last_error = $4,
+			delivered_at = $5
+		WHERE id = $6`
+
+	_, err := s.db.Exec(ctx, query,
+		string(status),
+		nextAttemptAt,
+		attempt.StatusCode,
+		lastError,
+		deliveredAt,
+		attempt.DeliveryID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListDeliveries возвращает журнал доставок, новые первыми
+func (s *SubscriptionStorage) ListDeliveries(ctx context.Context, filter domain.DeliveryLogFilter) ([]domain.SubscriptionDelivery, error) {
+	query := `
+		SELECT id, subscription_id::text, event_id, event_type, payload, status, attempts,
+			next_attempt_at, last_status_code, last_error, created_at, delivered_at
+		FROM subscription_deliveries
+		WHERE ($1::text IS NULL OR subscription_id::text = $1)
+			AND ($2::text IS NULL OR status = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3`
+
+	rows, err := s.db.Query(ctx, query, filter.SubscriptionID, (*string)(filter.Status), filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]domain.SubscriptionDelivery, 0)
+	for rows.Next() {
+		var delivery domain.SubscriptionDelivery
+		var eventType, status string
+		var payload []byte
+		err = rows.Scan(
+			&delivery.ID,
+			&delivery.SubscriptionID,
+			&delivery.EventID,
+			&eventType,
+			&payload,
+			&status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&delivery.LastStatusCode,
+			&delivery.LastError,
+			&delivery.CreatedAt,
+			&delivery.DeliveredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		delivery.EventType = domain.EventType(eventType)
+		delivery.Status = domain.DeliveryStatus(status)
+		delivery.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// RequeueDeadDelivery возвращает доставку из DEAD в очередь с обнулённым счётчиком попыток;
+// если доставки нет или она не в DEAD - pgx.ErrNoRows
+func (s *SubscriptionStorage) RequeueDeadDelivery(ctx context.Context, deliveryID int64) error {
+	query := `
+		UPDATE subscription_deliveries
+		SET status = $1, attempts = 0, next_attempt_at = now()
+		WHERE id = $2 AND status = $3`
+
+	tag, err := s.db.Exec(ctx, query, string(domain.DeliveryPending), deliveryID, string(domain.DeliveryDead))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// eventPayload - тело доставки: событие целиком, как его получит подписчик
+func eventPayload(event domain.Event) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+func eventTypesToStrings(eventTypes []domain.EventType) []string {
+	result := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		result = append(result, string(eventType))
+	}
+	return result
+}
+
+func stringsToEventTypes(values []string) []domain.EventType {
+	result := make([]domain.EventType, 0, len(values))
+	for _, value := range values {
+		result = append(result, domain.EventType(value))
+	}
+	return result
+}
@@ -0,0 +1,284 @@
+package subscriptionStorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSubscriptionID = "5f0c6a52-6f0e-4c7a-9a55-1f2f3c4d5e6f"
+
+func TestSubscriptionStorage_CreateSubscription(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewSubscriptionStorage(mock)
+	createdAt := time.Date(2025, 11, 28, 10, 0, 0, 0, time.UTC)
+
+	subscription := &domain.Subscription{
+		ID:         testSubscriptionID,
+		URL:        "https://hooks.example.com/pr",
+		Secret:     "s3cr3t",
+		EventTypes: []domain.EventType{domain.EventPRCreated, domain.EventPRMerged},
+		IsActive:   true,
+	}
+
+	mock.ExpectQuery("INSERT INTO subscriptions").
+		WithArgs(testSubscriptionID, "https://hooks.example.com/pr", "s3cr3t",
+			[]string{"pr.created", "pr.merged"}, true).
+		WillReturnRows(pgxmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+
+	err = storage.CreateSubscription(ctx, subscription)
+
+	require.NoError(t, err)
+	assert.Equal(t, createdAt, subscription.CreatedAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubscriptionStorage_ListSubscriptions(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewSubscriptionStorage(mock)
+	createdAt := time.Date(2025, 11, 28, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("FROM subscriptions").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "url", "event_types", "is_active", "created_at"}).
+			AddRow(testSubscriptionID, "https://hooks.example.com/pr", []string{"reviewer.assigned"}, true, createdAt))
+
+	subscriptions, err := storage.ListSubscriptions(ctx)
+
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	assert.Equal(t, []domain.EventType{domain.EventReviewerAssigned}, subscriptions[0].EventTypes)
+	assert.Empty(t, subscriptions[0].Secret)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubscriptionStorage_DeleteSubscription(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully delete", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewSubscriptionStorage(mock)
+
+		mock.ExpectExec("DELETE FROM subscriptions").
+			WithArgs(testSubscriptionID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+		require.NoError(t, storage.DeleteSubscription(ctx, testSubscriptionID))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("subscription not found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewSubscriptionStorage(mock)
+
+		mock.ExpectExec("DELETE FROM subscriptions").
+			WithArgs(testSubscriptionID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+		assert.ErrorIs(t, storage.DeleteSubscription(ctx, testSubscriptionID), pgx.ErrNoRows)
+	})
+}
+
+func TestSubscriptionStorage_EnqueueEvent(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewSubscriptionStorage(mock)
+
+	event, err := domain.NewEvent(domain.EventPRMerged, time.Now(), domain.PullRequestEventData{PullRequestID: "pr-1"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	mock.ExpectExec("INSERT INTO subscription_deliveries").
+		WithArgs(event.ID, "pr.merged", payload, "PENDING").
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	enqueued, err := storage.EnqueueEvent(ctx, event)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), enqueued)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubscriptionStorage_ClaimDueDeliveries(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewSubscriptionStorage(mock)
+
+	mock.ExpectQuery("FOR UPDATE SKIP LOCKED").
+		WithArgs("PENDING", 10, float64(30)).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "subscription_id", "url", "secret", "event_id", "event_type", "payload", "attempts",
+		}).AddRow(int64(7), testSubscriptionID, "https://hooks.example.com/pr", "s3cr3t",
+			"event-1", "pr.created", []byte(`{"id":"event-1"}`), 2))
+
+	deliveries, err := storage.ClaimDueDeliveries(ctx, 10, 30*time.Second)
+
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, int64(7), deliveries[0].ID)
+	assert.Equal(t, domain.EventPRCreated, deliveries[0].EventType)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.JSONEq(t, `{"id":"event-1"}`, string(deliveries[0].Payload))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubscriptionStorage_RecordAttempt(t *testing.T) {
+	ctx := context.Background()
+	statusCode := 500
+	next := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name    string
+		attempt domain.DeliveryAttempt
+		status  string
+	}{
+		{
+			name:    "delivered",
+			attempt: domain.DeliveryAttempt{DeliveryID: 7, Delivered: true},
+			status:  "DELIVERED",
+		},
+		{
+			name:    "retry scheduled",
+			attempt: domain.DeliveryAttempt{DeliveryID: 7, StatusCode: &statusCode, Error: "HTTP 500", NextAttemptAt: &next},
+			status:  "PENDING",
+		},
+		{
+			name:    "attempts exhausted",
+			attempt: domain.DeliveryAttempt{DeliveryID: 7, Error: "timeout"},
+			status:  "DEAD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			storage := NewSubscriptionStorage(mock)
+
+			mock.ExpectExec("UPDATE subscription_deliveries").
+				WithArgs(tt.status, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), int64(7)).
+				WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+			require.NoError(t, storage.RecordAttempt(ctx, tt.attempt))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSubscriptionStorage_ListDeliveries(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewSubscriptionStorage(mock)
+
+	subscriptionID := testSubscriptionID
+	status := domain.DeliveryDead
+	createdAt := time.Date(2025, 11, 28, 10, 0, 0, 0, time.UTC)
+	lastError := "HTTP 503"
+	lastStatus := 503
+
+	mock.ExpectQuery("FROM subscription_deliveries").
+		WithArgs(&subscriptionID, pgxmock.AnyArg(), 50).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "subscription_id", "event_id", "event_type", "payload", "status", "attempts",
+			"next_attempt_at", "last_status_code", "last_error", "created_at", "delivered_at",
+		}).AddRow(int64(7), testSubscriptionID, "event-1", "pr.merged", []byte(`{}`), "DEAD", 8,
+			nil, &lastStatus, &lastError, createdAt, nil))
+
+	deliveries, err := storage.ListDeliveries(ctx, domain.DeliveryLogFilter{
+		SubscriptionID: &subscriptionID,
+		Status:         &status,
+		Limit:          50,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, domain.DeliveryDead, deliveries[0].Status)
+	assert.Equal(t, 8, deliveries[0].Attempts)
+	assert.Equal(t, &lastError, deliveries[0].LastError)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubscriptionStorage_RequeueDeadDelivery(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully requeue", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewSubscriptionStorage(mock)
+
+		mock.ExpectExec("UPDATE subscription_deliveries").
+			WithArgs("PENDING", int64(7), "DEAD").
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		require.NoError(t, storage.RequeueDeadDelivery(ctx, 7))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delivery is not dead", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewSubscriptionStorage(mock)
+
+		mock.ExpectExec("UPDATE subscription_deliveries").
+			WithArgs("PENDING", int64(7), "DEAD").
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		assert.ErrorIs(t, storage.RequeueDeadDelivery(ctx, 7), pgx.ErrNoRows)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewSubscriptionStorage(mock)
+
+		mock.ExpectExec("UPDATE subscription_deliveries").
+			WithArgs("PENDING", int64(7), "DEAD").
+			WillReturnError(errors.New("db error"))
+
+		assert.Error(t, storage.RequeueDeadDelivery(ctx, 7))
+	})
+}
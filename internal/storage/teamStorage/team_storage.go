@@ -3,15 +3,26 @@ package teamStorage
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/internal/storage/eventStorage"
 	"github.com/nedokyrill/avito-pr-api/pkg/utils/db"
 )
 
 var ErrNoUsersInTeam = errors.New(domain.NoUsersInTeamErr)
 var ErrTeamNotExists = errors.New(domain.TeamNotExistsErr)
+var ErrNotTeamMember = errors.New(domain.NotTeamMemberErr)
+var ErrParentTeamNotExists = errors.New(domain.ParentTeamNotExistsErr)
+var ErrTeamHierarchyCycle = errors.New(domain.TeamHierarchyCycleErr)
+var ErrDeactivationPlanNotExists = errors.New(domain.DeactivationPlanNotExistsErr)
+var ErrDeactivationPlanAlreadyApplied = errors.New(domain.DeactivationPlanAlreadyAppliedErr)
+var ErrPlanStale = errors.New(domain.PlanStaleErr)
 
 type TeamStorage struct {
 	db db.Querier
@@ -25,8 +36,13 @@ func NewTeamStorage(db db.Querier) *TeamStorage {
 
 func (s *TeamStorage) GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error) {
 	var teamID uuid.UUID
-	query := `SELECT id FROM teams WHERE name = $1`
-	err := s.db.QueryRow(ctx, query, teamName).Scan(&teamID)
+	var parentTeamName *string
+	query := `
+		SELECT t.id, p.name
+		FROM teams t
+		LEFT JOIN teams p ON t.parent_id = p.id
+		WHERE t.name = $1`
+	err := s.db.QueryRow(ctx, query, teamName).Scan(&teamID, &parentTeamName)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrTeamNotExists
@@ -34,11 +50,310 @@ func (s *TeamStorage) GetTeamByName(ctx context.Context, teamName string) (*doma
 		return nil, err
 	}
 
+	members, err := s.getTeamMembers(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	team := &domain.Team{
+		TeamName:       teamName,
+		ParentTeamName: parentTeamName,
+		Members:        members,
+	}
+
+	return team, nil
+}
+
+// GetTeamWithAncestors возвращает команду и цепочку её предков: первым элементом идёт сама команда,
+// далее родитель, родитель родителя и т.д. (не глубже MaxTeamHierarchyDepth)
+func (s *TeamStorage) GetTeamWithAncestors(ctx context.Context, teamName string) ([]domain.Team, error) {
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT id, name, parent_id, 0 AS depth
+			FROM teams
+			WHERE name = $1
+			UNION ALL
+			SELECT t.id, t.name, t.parent_id, c.depth + 1
+			FROM teams t
+			JOIN chain c ON t.id = c.parent_id
+			WHERE c.depth < $2
+		)
+		SELECT c.id, c.name, p.name
+		FROM chain c
+		LEFT JOIN teams p ON c.parent_id = p.id
+		ORDER BY c.depth`
+
+	rows, err := s.db.Query(ctx, query, teamName, domain.MaxTeamHierarchyDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	var teamIDs []uuid.UUID
+	var teams []domain.Team
+	for rows.Next() {
+		var teamID uuid.UUID
+		var name string
+		var parentTeamName *string
+
+		if err = rows.Scan(&teamID, &name, &parentTeamName); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		teamIDs = append(teamIDs, teamID)
+		teams = append(teams, domain.Team{
+			TeamName:       name,
+			ParentTeamName: parentTeamName,
+		})
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(teams) == 0 {
+		return nil, ErrTeamNotExists
+	}
+
+	for i, teamID := range teamIDs {
+		teams[i].Members, err = s.getTeamMembers(ctx, teamID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return teams, nil
+}
+
+// GetTeamsHierarchy возвращает все команды плоским списком (без участников и дочерних узлов),
+// дерево собирается на сервисном слое
+func (s *TeamStorage) GetTeamsHierarchy(ctx context.Context) ([]domain.TeamTreeNode, error) {
+	query := `
+		SELECT t.name, p.name
+		FROM teams t
+		LEFT JOIN teams p ON t.parent_id = p.id
+		ORDER BY t.name`
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodes []domain.TeamTreeNode
+	for rows.Next() {
+		var name string
+		var parentTeamName *string
+
+		if err = rows.Scan(&name, &parentTeamName); err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, domain.TeamTreeNode{
+			TeamName:       name,
+			ParentTeamName: parentTeamName,
+			Children:       []domain.TeamTreeNode{},
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// SetTeamParent переносит команду под родителя (nil - делает корневой). Проверка на цикл и обновление
+// идут в одной транзакции: перестановки иерархии сериализуются advisory-блокировкой, а строки команды
+// и родителя блокируются, чтобы родитель не исчез между проверкой и обновлением
+func (s *TeamStorage) SetTeamParent(ctx context.Context, teamName string, parentTeamName *string) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('teams_hierarchy'))`)
+	if err != nil {
+		return err
+	}
+
+	var teamID uuid.UUID
+	err = tx.QueryRow(ctx, `SELECT id FROM teams WHERE name = $1 FOR UPDATE`, teamName).Scan(&teamID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTeamNotExists
+		}
+		return err
+	}
+
+	var parentID *uuid.UUID
+	if parentTeamName != nil {
+		var id uuid.UUID
+		err = tx.QueryRow(ctx, `SELECT id FROM teams WHERE name = $1 FOR UPDATE`, *parentTeamName).Scan(&id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrParentTeamNotExists
+			}
+			return err
+		}
+
+		// цепочка предков нового родителя (включая его самого) не должна содержать саму команду
+		cycleQuery := `
+			WITH RECURSIVE chain AS (
+				SELECT id, parent_id
+				FROM teams
+				WHERE id = $1
+				UNION
+				SELECT t.id, t.parent_id
+				FROM teams t
+				JOIN chain c ON t.id = c.parent_id
+			)
+			SELECT EXISTS (SELECT 1 FROM chain WHERE id = $2)`
+		var cycle bool
+		if err = tx.QueryRow(ctx, cycleQuery, id, teamID).Scan(&cycle); err != nil {
+			return err
+		}
+		if cycle {
+			return ErrTeamHierarchyCycle
+		}
+
+		parentID = &id
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE teams SET parent_id = $2 WHERE id = $1`, teamID, parentID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SetReviewSLA задаёт SLA ревью команды в часах (nil - снять SLA)
+func (s *TeamStorage) SetReviewSLA(ctx context.Context, teamName string, hours *int) error {
+	query := `
+		UPDATE teams
+		SET review_sla_hours = $2
+		WHERE name = $1`
+
+	tag, err := s.db.Exec(ctx, query, teamName, hours)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrTeamNotExists
+	}
+
+	return nil
+}
+
+func (s *TeamStorage) AddTeamMember(ctx context.Context, teamName, userID string) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var teamID uuid.UUID
+	err = tx.QueryRow(ctx, `SELECT id FROM teams WHERE name = $1`, teamName).Scan(&teamID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTeamNotExists
+		}
+		return err
+	}
+
+	memberQuery := `
+		INSERT INTO team_members (team_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`
+	_, err = tx.Exec(ctx, memberQuery, teamID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Если основной команды у пользователя нет, ей становится добавленная
+	primaryQuery := `UPDATE users SET team_id = $1 WHERE id = $2 AND team_id IS NULL`
+	_, err = tx.Exec(ctx, primaryQuery, teamID, userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s *TeamStorage) RemoveTeamMember(ctx context.Context, teamName, userID string) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var teamID uuid.UUID
+	deleteQuery := `
+		DELETE FROM team_members tm
+		USING teams t
+		WHERE tm.team_id = t.id
+		  AND t.name = $1
+		  AND tm.user_id = $2
+		RETURNING tm.team_id`
+	err = tx.QueryRow(ctx, deleteQuery, teamName, userID).Scan(&teamID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotTeamMember
+		}
+		return err
+	}
+
+	// Если команда была основной, основной становится самая ранняя из оставшихся (или никакая)
+	primaryQuery := `
+		UPDATE users
+		SET team_id = (
+			SELECT team_id FROM team_members
+			WHERE user_id = $2
+			ORDER BY created_at
+			LIMIT 1
+		)
+		WHERE id = $2 AND team_id = $1`
+	_, err = tx.Exec(ctx, primaryQuery, teamID, userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s *TeamStorage) SetPrimaryTeam(ctx context.Context, userID, teamName string) error {
+	query := `
+		UPDATE users u
+		SET team_id = tm.team_id
+		FROM team_members tm
+		JOIN teams t ON tm.team_id = t.id
+		WHERE u.id = tm.user_id
+		  AND u.id = $1
+		  AND t.name = $2`
+
+	tag, err := s.db.Exec(ctx, query, userID, teamName)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotTeamMember
+	}
+
+	return nil
+}
+
+func (s *TeamStorage) getTeamMembers(ctx context.Context, teamID uuid.UUID) ([]domain.TeamMember, error) {
 	membersQuery := `
-		SELECT id, name, is_active 
-		FROM users 
-		WHERE team_id = $1
-		ORDER BY name`
+		SELECT u.id, u.name, u.is_active
+		FROM team_members tm
+		JOIN users u ON u.id = tm.user_id
+		WHERE tm.team_id = $1
+		ORDER BY u.name`
 
 	rows, err := s.db.Query(ctx, membersQuery, teamID)
 	if err != nil {
@@ -70,17 +385,13 @@ func (s *TeamStorage) GetTeamByName(ctx context.Context, teamName string) (*doma
 		return nil, err
 	}
 
-	team := &domain.Team{
-		TeamName: teamName,
-		Members:  members,
-	}
-
-	return team, nil
+	return members, nil
 }
 
 func (s *TeamStorage) CreateTeamWithMembers(
 	ctx context.Context,
 	teamName string,
+	parentTeamName *string,
 	members []domain.TeamMember,
 ) (uuid.UUID, error) {
 	tx, err := s.db.Begin(ctx)
@@ -90,21 +401,34 @@ func (s *TeamStorage) CreateTeamWithMembers(
 	defer func() { _ = tx.Rollback(ctx) }()
 
 	var teamID uuid.UUID
-	query := `INSERT INTO teams (name) VALUES ($1) RETURNING id`
-	err = tx.QueryRow(ctx, query, teamName).Scan(&teamID)
+	query := `
+		INSERT INTO teams (name, parent_id)
+		VALUES ($1, (SELECT id FROM teams WHERE name = $2))
+		RETURNING id`
+	err = tx.QueryRow(ctx, query, teamName, parentTeamName).Scan(&teamID)
 	if err != nil {
 		return uuid.Nil, err
 	}
 
+	// Создаются только новые пользователи: существующих добавляют в команду через AddTeamMember
 	userQuery := `
 		INSERT INTO users (id, name, team_id, is_active)
 		VALUES ($1, $2, $3, $4)`
 
+	memberQuery := `
+		INSERT INTO team_members (team_id, user_id)
+		VALUES ($1, $2)`
+
 	for _, member := range members {
 		_, err = tx.Exec(ctx, userQuery, member.UserId, member.Username, teamID, member.IsActive)
 		if err != nil {
 			return uuid.Nil, err
 		}
+
+		_, err = tx.Exec(ctx, memberQuery, teamID, member.UserId)
+		if err != nil {
+			return uuid.Nil, err
+		}
 	}
 
 	if err = tx.Commit(ctx); err != nil {
@@ -126,30 +450,57 @@ func (s *TeamStorage) DeactivateTeamMembers(
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
-	// Деактивируем пользователей
-	query := `
-		UPDATE users u
-		SET is_active = false
-		FROM teams t
-		WHERE u.team_id = t.id 
-		  AND t.name = $1`
+	deactivatedIDs, err := deactivateUsers(ctx, tx, teamName, userIDs)
+	if err != nil {
+		return nil, err
+	}
 
-	var args []interface{}
-	args = append(args, teamName)
+	// Переназначаем ревьюверов
+	if err = applyReassignments(ctx, tx, reassignments); err != nil {
+		return nil, err
+	}
 
-	if len(userIDs) > 0 {
-		query += ` AND u.id = ANY($2)`
-		args = append(args, userIDs)
+	if err = writeDeactivationEvents(ctx, tx, teamName, deactivatedIDs, reassignments); err != nil {
+		return nil, err
 	}
 
-	query += ` RETURNING u.id`
+	// 3. Коммитим транзакцию
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 
-	rows, err := tx.Query(ctx, query, args...)
+	return deactivatedIDs, nil
+}
+
+// DeactivateUser деактивирует пользователей плана независимо от их команд (деактивация из IdP) и применяет
+// переназначения их открытых ревью. Открытые ревью и ревьюверы сверяются с ReviewersSnapshot под блокировкой,
+// при расхождении возвращается ErrPlanStale
+func (s *TeamStorage) DeactivateUser(ctx context.Context, plan *domain.DeactivationPlan) ([]string, error) {
+	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer func() { _ = tx.Rollback(ctx) }()
 
-	var deactivatedIDs []string
+	if err = lockOpenReviews(ctx, tx, plan.DeactivatedUserIDs, plan.ReviewersSnapshot); err != nil {
+		return nil, err
+	}
+
+	if err = checkNewReviewersActive(ctx, tx, plan.Reassignments); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE users
+		SET is_active = false
+		WHERE id = ANY($1) AND is_active
+		RETURNING id`
+	rows, err := tx.Query(ctx, query, plan.DeactivatedUserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	deactivatedIDs := make([]string, 0, len(plan.DeactivatedUserIDs))
 	for rows.Next() {
 		var userID string
 		if err = rows.Scan(&userID); err != nil {
@@ -164,26 +515,786 @@ func (s *TeamStorage) DeactivateTeamMembers(
 		return nil, err
 	}
 
-	// Переназначаем ревьюверов
-	for _, reassignment := range reassignments {
-		deleteQuery := `DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND reviewer_id = $2`
-		_, err = tx.Exec(ctx, deleteQuery, reassignment.PrID, reassignment.OldReviewerID)
-		if err != nil {
+	if err = applyReassignments(ctx, tx, plan.Reassignments); err != nil {
+		return nil, err
+	}
+
+	if err = writeDeactivationEvents(ctx, tx, plan.TeamName, deactivatedIDs, plan.Reassignments); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return deactivatedIDs, nil
+}
+
+// ActivateTeamMembers активирует участников команды и применяет переносы ревью ребаланса. PR переносов
+// блокируются и сверяются под блокировкой: если PR уже не открыт, донор с него снят или получатель
+// уже назначен, возвращается ErrPlanStale
+func (s *TeamStorage) ActivateTeamMembers(
+	ctx context.Context,
+	teamName string,
+	userIDs []string,
+	reassignments []domain.ReviewerReassignment,
+) ([]string, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err = lockReassignedReviews(ctx, tx, reassignments); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE users u
+		SET is_active = true
+		FROM team_members tm
+		JOIN teams t ON tm.team_id = t.id
+		WHERE u.id = tm.user_id
+		  AND t.name = $1
+		  AND u.id = ANY($2)
+		RETURNING u.id`
+
+	rows, err := tx.Query(ctx, query, teamName, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	activatedIDs := make([]string, 0, len(userIDs))
+	for rows.Next() {
+		var userID string
+		if err = rows.Scan(&userID); err != nil {
+			rows.Close()
 			return nil, err
 		}
+		activatedIDs = append(activatedIDs, userID)
+	}
+	rows.Close()
 
-		if reassignment.NewReviewerID != "" {
-			insertQuery := `
-				INSERT INTO pr_reviewers (pull_request_id, reviewer_id, assigned_at)
-				VALUES ($1, $2)`
-			_, err = tx.Exec(ctx, insertQuery, reassignment.PrID, reassignment.NewReviewerID)
-			if err != nil {
-				return nil, err
-			}
-		}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err = applyReassignments(ctx, tx, reassignments); err != nil {
+		return nil, err
+	}
+
+	// Ребаланс пишет reviewer.reassigned в outbox в той же транзакции, как и деактивация
+	events, err := domain.NewReviewerReassignedEvents(reassignments, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = eventStorage.WriteEvents(ctx, tx, events); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return activatedIDs, nil
+}
+
+// deactivateUsers деактивирует участников команды внутри транзакции (всех, если userIDs пустой)
+func deactivateUsers(ctx context.Context, tx pgx.Tx, teamName string, userIDs []string) ([]string, error) {
+	query := `
+		UPDATE users u
+		SET is_active = false
+		FROM team_members tm
+		JOIN teams t ON tm.team_id = t.id
+		WHERE u.id = tm.user_id
+		  AND t.name = $1`
+
+	var args []interface{}
+	args = append(args, teamName)
+
+	if len(userIDs) > 0 {
+		query += ` AND u.id = ANY($2)`
+		args = append(args, userIDs)
+	}
+
+	query += ` RETURNING u.id`
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deactivatedIDs []string
+	for rows.Next() {
+		var userID string
+		if err = rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		deactivatedIDs = append(deactivatedIDs, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deactivatedIDs, nil
+}
+
+// applyReassignments заменяет ревьюверов внутри транзакции. Пустой NewReviewerID - удалить без замены,
+// такой PR помечается как нуждающийся в ревьюверах
+func applyReassignments(ctx context.Context, tx pgx.Tx, reassignments []domain.ReviewerReassignment) error {
+	deleteQuery := `DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND reviewer_id = $2`
+	insertQuery := `
+		INSERT INTO pr_reviewers (pull_request_id, reviewer_id, assignment_meta)
+		VALUES ($1, $2, $3)`
+	needMoreQuery := `UPDATE pull_requests SET need_more_reviewers = true WHERE id = $1`
+
+	for _, reassignment := range reassignments {
+		_, err := tx.Exec(ctx, deleteQuery, reassignment.PrID, reassignment.OldReviewerID)
+		if err != nil {
+			return err
+		}
+
+		if reassignment.NewReviewerID != "" {
+			_, err = tx.Exec(ctx, insertQuery, reassignment.PrID, reassignment.NewReviewerID, reassignment.Meta)
+		} else {
+			_, err = tx.Exec(ctx, needMoreQuery, reassignment.PrID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = eventStorage.WriteAssignmentEvent(ctx, tx, domain.NewReassignmentEvent(reassignment)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeDeactivationEvents пишет в outbox user.deactivated и reviewer.reassigned в транзакции деактивации
+func writeDeactivationEvents(
+	ctx context.Context,
+	tx pgx.Tx,
+	teamName string,
+	deactivatedIDs []string,
+	reassignments []domain.ReviewerReassignment,
+) error {
+	events, err := domain.NewUserDeactivatedEvents(teamName, deactivatedIDs, reassignments, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return eventStorage.WriteEvents(ctx, tx, events)
+}
+
+// lockOpenReviews блокирует пользователей и их открытые PR и сверяет ревьюверов этих PR со snapshot плана.
+// Пока транзакция держит блокировки, на этих пользователей не назначаются новые ревью,
+// а у затронутых PR не меняются ревьюверы и статус
+func lockOpenReviews(ctx context.Context, tx pgx.Tx, userIDs []string, snapshot map[string][]string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, `SELECT id FROM users WHERE id = ANY($1) ORDER BY id FOR UPDATE`, userIDs)
+	if err != nil {
+		return err
+	}
+
+	prQuery := `
+		SELECT pr.id
+		FROM pull_requests pr
+		WHERE pr.status = $2
+		  AND EXISTS (
+			SELECT 1 FROM pr_reviewers prr
+			WHERE prr.pull_request_id = pr.id AND prr.reviewer_id = ANY($1)
+		  )
+		ORDER BY pr.id
+		FOR UPDATE`
+	rows, err := tx.Query(ctx, prQuery, userIDs, string(domain.PullRequestStatusOPEN))
+	if err != nil {
+		return err
+	}
+
+	var prIDs []string
+	for rows.Next() {
+		var prID string
+		if err = rows.Scan(&prID); err != nil {
+			rows.Close()
+			return err
+		}
+		if _, ok := snapshot[prID]; !ok {
+			rows.Close()
+			return fmt.Errorf("%w: PR %s has changed", ErrPlanStale, prID)
+		}
+		prIDs = append(prIDs, prID)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	if len(prIDs) != len(snapshot) {
+		return fmt.Errorf("%w: open reviews of deactivated users have changed", ErrPlanStale)
+	}
+
+	reviewersQuery := `
+		SELECT pull_request_id, reviewer_id
+		FROM pr_reviewers
+		WHERE pull_request_id = ANY($1)
+		FOR UPDATE`
+	rows, err = tx.Query(ctx, reviewersQuery, prIDs)
+	if err != nil {
+		return err
+	}
+
+	reviewers := make(map[string][]string, len(prIDs))
+	for rows.Next() {
+		var prID, reviewerID string
+		if err = rows.Scan(&prID, &reviewerID); err != nil {
+			rows.Close()
+			return err
+		}
+		reviewers[prID] = append(reviewers[prID], reviewerID)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, prID := range prIDs {
+		if !sameReviewers(reviewers[prID], snapshot[prID]) {
+			return fmt.Errorf("%w: reviewers of PR %s have changed", ErrPlanStale, prID)
+		}
+	}
+
+	return nil
+}
+
+// lockReassignedReviews блокирует PR переназначений и их ревьюверов и проверяет, что каждый PR открыт,
+// старый ревьювер на нём по-прежнему назначен, а новый - ещё нет
+func lockReassignedReviews(ctx context.Context, tx pgx.Tx, reassignments []domain.ReviewerReassignment) error {
+	if len(reassignments) == 0 {
+		return nil
+	}
+
+	prIDs := make([]string, 0, len(reassignments))
+	for _, reassignment := range reassignments {
+		if !slices.Contains(prIDs, reassignment.PrID) {
+			prIDs = append(prIDs, reassignment.PrID)
+		}
+	}
+
+	prQuery := `
+		SELECT id
+		FROM pull_requests
+		WHERE id = ANY($1) AND status = $2
+		ORDER BY id
+		FOR UPDATE`
+	rows, err := tx.Query(ctx, prQuery, prIDs, string(domain.PullRequestStatusOPEN))
+	if err != nil {
+		return err
+	}
+
+	open := make(map[string]struct{}, len(prIDs))
+	for rows.Next() {
+		var prID string
+		if err = rows.Scan(&prID); err != nil {
+			rows.Close()
+			return err
+		}
+		open[prID] = struct{}{}
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, prID := range prIDs {
+		if _, ok := open[prID]; !ok {
+			return fmt.Errorf("%w: PR %s is no longer open", ErrPlanStale, prID)
+		}
+	}
+
+	reviewersQuery := `
+		SELECT pull_request_id, reviewer_id
+		FROM pr_reviewers
+		WHERE pull_request_id = ANY($1)
+		FOR UPDATE`
+	rows, err = tx.Query(ctx, reviewersQuery, prIDs)
+	if err != nil {
+		return err
+	}
+
+	reviewers := make(map[string][]string, len(prIDs))
+	for rows.Next() {
+		var prID, reviewerID string
+		if err = rows.Scan(&prID, &reviewerID); err != nil {
+			rows.Close()
+			return err
+		}
+		reviewers[prID] = append(reviewers[prID], reviewerID)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	// Переносы применяются по порядку, поэтому сверяем их с состоянием после предыдущих
+	for _, reassignment := range reassignments {
+		current := reviewers[reassignment.PrID]
+		if !slices.Contains(current, reassignment.OldReviewerID) {
+			return fmt.Errorf("%w: reviewer %s is no longer assigned to PR %s",
+				ErrPlanStale, reassignment.OldReviewerID, reassignment.PrID)
+		}
+		if reassignment.NewReviewerID != "" && slices.Contains(current, reassignment.NewReviewerID) {
+			return fmt.Errorf("%w: reviewer %s is already assigned to PR %s",
+				ErrPlanStale, reassignment.NewReviewerID, reassignment.PrID)
+		}
+
+		current = slices.DeleteFunc(slices.Clone(current), func(reviewerID string) bool {
+			return reviewerID == reassignment.OldReviewerID
+		})
+		if reassignment.NewReviewerID != "" {
+			current = append(current, reassignment.NewReviewerID)
+		}
+		reviewers[reassignment.PrID] = current
+	}
+
+	return nil
+}
+
+// checkPlanMembers блокирует членства команды плана и проверяет, что деактивируемые пользователи
+// и новые ревьюверы по-прежнему в команде: до коммита их нельзя вывести из неё
+func checkPlanMembers(ctx context.Context, tx pgx.Tx, plan *domain.DeactivationPlan) error {
+	query := `
+		SELECT tm.user_id
+		FROM team_members tm
+		JOIN teams t ON t.id = tm.team_id
+		WHERE t.name = $1
+		ORDER BY tm.user_id
+		FOR UPDATE OF tm`
+	rows, err := tx.Query(ctx, query, plan.TeamName)
+	if err != nil {
+		return err
+	}
+
+	members := make(map[string]struct{})
+	for rows.Next() {
+		var userID string
+		if err = rows.Scan(&userID); err != nil {
+			rows.Close()
+			return err
+		}
+		members[userID] = struct{}{}
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, userID := range plan.DeactivatedUserIDs {
+		if _, ok := members[userID]; !ok {
+			return fmt.Errorf("%w: user %s is no longer a member of team %s", ErrPlanStale, userID, plan.TeamName)
+		}
+	}
+
+	for _, reassignment := range plan.Reassignments {
+		if reassignment.NewReviewerID == "" {
+			continue
+		}
+		if _, ok := members[reassignment.NewReviewerID]; !ok {
+			return fmt.Errorf("%w: reviewer %s is no longer available", ErrPlanStale, reassignment.NewReviewerID)
+		}
+	}
+
+	return nil
+}
+
+// checkNewReviewersActive блокирует новых ревьюверов переназначений на чтение и проверяет, что они активны
+func checkNewReviewersActive(ctx context.Context, tx pgx.Tx, reassignments []domain.ReviewerReassignment) error {
+	var reviewerIDs []string
+	for _, reassignment := range reassignments {
+		if reassignment.NewReviewerID != "" && !slices.Contains(reviewerIDs, reassignment.NewReviewerID) {
+			reviewerIDs = append(reviewerIDs, reassignment.NewReviewerID)
+		}
+	}
+	if len(reviewerIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		SELECT id
+		FROM users
+		WHERE id = ANY($1) AND is_active
+		ORDER BY id
+		FOR SHARE`
+	rows, err := tx.Query(ctx, query, reviewerIDs)
+	if err != nil {
+		return err
+	}
+
+	active := make(map[string]struct{}, len(reviewerIDs))
+	for rows.Next() {
+		var userID string
+		if err = rows.Scan(&userID); err != nil {
+			rows.Close()
+			return err
+		}
+		active[userID] = struct{}{}
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, reviewerID := range reviewerIDs {
+		if _, ok := active[reviewerID]; !ok {
+			return fmt.Errorf("%w: reviewer %s is no longer available", ErrPlanStale, reviewerID)
+		}
+	}
+
+	return nil
+}
+
+func sameReviewers(a, b []string) bool {
+	a = slices.Clone(a)
+	b = slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// GetRoster возвращает все команды с родителями и участниками (для экспорта и расчёта плана импорта)
+func (s *TeamStorage) GetRoster(ctx context.Context) ([]domain.Team, error) {
+	return getRoster(ctx, s.db)
+}
+
+func getRoster(ctx context.Context, q db.Querier) ([]domain.Team, error) {
+	query := `
+		SELECT t.name, p.name, u.id, u.name, u.is_active
+		FROM teams t
+		LEFT JOIN teams p ON t.parent_id = p.id
+		LEFT JOIN team_members tm ON tm.team_id = t.id
+		LEFT JOIN users u ON u.id = tm.user_id
+		ORDER BY t.name, u.name, u.id`
+
+	rows, err := q.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []domain.Team
+	for rows.Next() {
+		var teamName string
+		var parentTeamName, userID, username *string
+		var isActive *bool
+
+		if err = rows.Scan(&teamName, &parentTeamName, &userID, &username, &isActive); err != nil {
+			return nil, err
+		}
+
+		if len(teams) == 0 || teams[len(teams)-1].TeamName != teamName {
+			teams = append(teams, domain.Team{
+				TeamName:       teamName,
+				ParentTeamName: parentTeamName,
+				Members:        []domain.TeamMember{},
+			})
+		}
+
+		// У команды без участников LEFT JOIN даёт одну строку с пустым пользователем
+		if userID == nil {
+			continue
+		}
+
+		last := &teams[len(teams)-1]
+		last.Members = append(last.Members, domain.TeamMember{
+			UserId:   *userID,
+			Username: *username,
+			IsActive: *isActive,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+// ApplyRosterImportPlan применяет план импорта в одной транзакции. Перед применением состав команд
+// и ревьюверы затронутых PR блокируются и сверяются со snapshot плана: если с момента построения плана
+// они изменились, возвращается ErrPlanStale
+func (s *TeamStorage) ApplyRosterImportPlan(ctx context.Context, plan *domain.RosterImportPlan) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Та же блокировка, что и при переносе команды, иначе параллельный SetTeamParent мог бы создать цикл
+	_, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('teams_hierarchy'))`)
+	if err != nil {
+		return err
+	}
+
+	// Состав не должен меняться до конца импорта: чтение разрешено, запись в эти таблицы ждёт коммита
+	_, err = tx.Exec(ctx, `LOCK TABLE teams, team_members, users IN SHARE ROW EXCLUSIVE MODE`)
+	if err != nil {
+		return err
+	}
+
+	current, err := getRoster(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(current, plan.RosterSnapshot) {
+		return fmt.Errorf("%w: roster has changed, import again", ErrPlanStale)
+	}
+
+	if err = lockOpenReviews(ctx, tx, plan.DeactivatedUsers, plan.ReviewersSnapshot); err != nil {
+		return err
+	}
+
+	// Сначала создаём все команды, родители проставляются после - родитель может идти в документе позже
+	for _, team := range plan.CreatedTeams {
+		_, err = tx.Exec(ctx, `INSERT INTO teams (name) VALUES ($1)`, team.TeamName)
+		if err != nil {
+			return err
+		}
+	}
+
+	parentQuery := `
+		UPDATE teams
+		SET parent_id = (SELECT id FROM teams WHERE name = $2)
+		WHERE name = $1`
+	for _, team := range plan.CreatedTeams {
+		if team.ParentTeamName == nil {
+			continue
+		}
+		_, err = tx.Exec(ctx, parentQuery, team.TeamName, team.ParentTeamName)
+		if err != nil {
+			return err
+		}
+	}
+	// У обновлённых команд пустой родитель означает перенос в корень
+	for _, team := range plan.UpdatedTeams {
+		_, err = tx.Exec(ctx, parentQuery, team.TeamName, team.ParentTeamName)
+		if err != nil {
+			return err
+		}
+	}
+
+	userQuery := `
+		INSERT INTO users (id, name, is_active)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE
+		SET name = EXCLUDED.name,
+		    is_active = EXCLUDED.is_active`
+	for _, user := range append(append([]domain.TeamMember{}, plan.CreatedUsers...), plan.UpdatedUsers...) {
+		_, err = tx.Exec(ctx, userQuery, user.UserId, user.Username, user.IsActive)
+		if err != nil {
+			return err
+		}
+	}
+
+	addMemberQuery := `
+		INSERT INTO team_members (team_id, user_id)
+		SELECT id, $2 FROM teams WHERE name = $1
+		ON CONFLICT DO NOTHING`
+	for _, membership := range plan.AddedMemberships {
+		_, err = tx.Exec(ctx, addMemberQuery, membership.TeamName, membership.UserID)
+		if err != nil {
+			return err
+		}
+	}
+
+	removeMemberQuery := `
+		DELETE FROM team_members tm
+		USING teams t
+		WHERE tm.team_id = t.id
+		  AND t.name = $1
+		  AND tm.user_id = $2`
+	for _, membership := range plan.RemovedMemberships {
+		_, err = tx.Exec(ctx, removeMemberQuery, membership.TeamName, membership.UserID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Основная команда: если её нет или пользователя из неё убрали - берём самую раннюю из оставшихся
+	var touchedUserIDs []string
+	for _, membership := range plan.AddedMemberships {
+		touchedUserIDs = append(touchedUserIDs, membership.UserID)
+	}
+	for _, membership := range plan.RemovedMemberships {
+		touchedUserIDs = append(touchedUserIDs, membership.UserID)
+	}
+	if len(touchedUserIDs) > 0 {
+		primaryQuery := `
+			UPDATE users u
+			SET team_id = (
+				SELECT team_id FROM team_members
+				WHERE user_id = u.id
+				ORDER BY created_at
+				LIMIT 1
+			)
+			WHERE u.id = ANY($1)
+			  AND (u.team_id IS NULL OR NOT EXISTS (
+				SELECT 1 FROM team_members
+				WHERE user_id = u.id AND team_id = u.team_id
+			  ))`
+		_, err = tx.Exec(ctx, primaryQuery, touchedUserIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(plan.DeactivatedUsers) > 0 {
+		_, err = tx.Exec(ctx, `UPDATE users SET is_active = false WHERE id = ANY($1)`, plan.DeactivatedUsers)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Открытые ревью деактивированных переназначаются так же, как при DeactivateTeamMembers
+	if err = applyReassignments(ctx, tx, plan.Reassignments); err != nil {
+		return err
+	}
+
+	if err = writeRosterDeactivationEvents(ctx, tx, plan); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// writeRosterDeactivationEvents пишет в outbox user.deactivated (с командой, из которой пользователь выбыл)
+// и reviewer.reassigned в транзакции импорта
+func writeRosterDeactivationEvents(ctx context.Context, tx pgx.Tx, plan *domain.RosterImportPlan) error {
+	now := time.Now()
+	events := make([]domain.Event, 0, len(plan.DeactivatedUsers)+len(plan.Reassignments))
+
+	for _, userID := range plan.DeactivatedUsers {
+		userEvents, err := domain.NewUserDeactivatedEvents(plan.DeactivationTeams[userID], []string{userID}, nil, now)
+		if err != nil {
+			return err
+		}
+		events = append(events, userEvents...)
+	}
+
+	reassignmentEvents, err := domain.NewReviewerReassignedEvents(plan.Reassignments, now)
+	if err != nil {
+		return err
+	}
+
+	return eventStorage.WriteEvents(ctx, tx, append(events, reassignmentEvents...))
+}
+
+// SaveDeactivationPlan сохраняет превью деактивации, заполняя PlanID и CreatedAt
+func (s *TeamStorage) SaveDeactivationPlan(ctx context.Context, plan *domain.DeactivationPlan) error {
+	query := `
+		INSERT INTO deactivation_plans (team_name, user_ids, reassignments, reviewers_snapshot, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	var planID uuid.UUID
+	err := s.db.QueryRow(ctx, query,
+		plan.TeamName,
+		plan.DeactivatedUserIDs,
+		plan.Reassignments,
+		plan.ReviewersSnapshot,
+		plan.ExpiresAt,
+	).Scan(&planID, &plan.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	plan.PlanID = planID.String()
+	return nil
+}
+
+func (s *TeamStorage) GetDeactivationPlan(ctx context.Context, planID uuid.UUID) (*domain.DeactivationPlan, error) {
+	query := `
+		SELECT team_name, user_ids, reassignments, reviewers_snapshot, created_at, expires_at, applied_at
+		FROM deactivation_plans
+		WHERE id = $1`
+
+	plan := &domain.DeactivationPlan{PlanID: planID.String()}
+	err := s.db.QueryRow(ctx, query, planID).Scan(
+		&plan.TeamName,
+		&plan.DeactivatedUserIDs,
+		&plan.Reassignments,
+		&plan.ReviewersSnapshot,
+		&plan.CreatedAt,
+		&plan.ExpiresAt,
+		&plan.AppliedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeactivationPlanNotExists
+		}
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// ApplyDeactivationPlan применяет сохранённый план в одной транзакции с отметкой о применении,
+// поэтому один и тот же план нельзя применить дважды
+func (s *TeamStorage) ApplyDeactivationPlan(ctx context.Context, plan *domain.DeactivationPlan) ([]string, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	claimQuery := `
+		UPDATE deactivation_plans
+		SET applied_at = now()
+		WHERE id = $1 AND applied_at IS NULL`
+	tag, err := tx.Exec(ctx, claimQuery, plan.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrDeactivationPlanAlreadyApplied
+	}
+
+	if err = checkPlanMembers(ctx, tx, plan); err != nil {
+		return nil, err
+	}
+
+	if err = lockOpenReviews(ctx, tx, plan.DeactivatedUserIDs, plan.ReviewersSnapshot); err != nil {
+		return nil, err
+	}
+
+	if err = checkNewReviewersActive(ctx, tx, plan.Reassignments); err != nil {
+		return nil, err
+	}
+
+	deactivatedIDs, err := deactivateUsers(ctx, tx, plan.TeamName, plan.DeactivatedUserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = applyReassignments(ctx, tx, plan.Reassignments); err != nil {
+		return nil, err
+	}
+
+	if err = writeDeactivationEvents(ctx, tx, plan.TeamName, deactivatedIDs, plan.Reassignments); err != nil {
+		return nil, err
 	}
 
-	// 3. Коммитим транзакцию
 	if err = tx.Commit(ctx); err != nil {
 		return nil, err
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -32,11 +33,11 @@ func TestTeamStorage_GetTeamByName(t *testing.T) {
 		user1ID := "test-id"
 		user2ID := "test-id"
 
-		mock.ExpectQuery("SELECT id FROM teams WHERE name").
+		mock.ExpectQuery("SELECT t.id, p.name").
 			WithArgs(teamName).
-			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(teamID))
+			WillReturnRows(pgxmock.NewRows([]string{"id", "name"}).AddRow(teamID, nil))
 
-		mock.ExpectQuery("SELECT id, name, is_active").
+		mock.ExpectQuery("SELECT u.id, u.name, u.is_active").
 			WithArgs(teamID).
 			WillReturnRows(pgxmock.NewRows([]string{"id", "name", "is_active"}).
 				AddRow(user1ID, "Alice", true).
@@ -58,7 +59,7 @@ func TestTeamStorage_GetTeamByName(t *testing.T) {
 
 		storage := NewTeamStorage(mock)
 
-		mock.ExpectQuery("SELECT id FROM teams WHERE name").
+		mock.ExpectQuery("SELECT t.id, p.name").
 			WithArgs("NonExistent Team").
 			WillReturnError(pgx.ErrNoRows)
 
@@ -92,21 +93,29 @@ func TestTeamStorage_CreateTeamWithMembers(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectQuery("INSERT INTO teams").
-			WithArgs(teamName).
+			WithArgs(teamName, pgxmock.AnyArg()).
 			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(teamID))
 
 		mock.ExpectExec("INSERT INTO users").
 			WithArgs(pgxmock.AnyArg(), "Alice", teamID, true).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
+		mock.ExpectExec("INSERT INTO team_members").
+			WithArgs(teamID, user1ID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
 		mock.ExpectExec("INSERT INTO users").
 			WithArgs(pgxmock.AnyArg(), "Bob", teamID, false).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
+		mock.ExpectExec("INSERT INTO team_members").
+			WithArgs(teamID, user2ID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
 		mock.ExpectCommit()
 		mock.ExpectRollback()
 
-		resultID, err := storage.CreateTeamWithMembers(ctx, teamName, members)
+		resultID, err := storage.CreateTeamWithMembers(ctx, teamName, nil, members)
 
 		require.NoError(t, err)
 		assert.Equal(t, teamID, resultID)
@@ -126,11 +135,11 @@ func TestTeamStorage_CreateTeamWithMembers(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectQuery("INSERT INTO teams").
-			WithArgs(teamName).
+			WithArgs(teamName, pgxmock.AnyArg()).
 			WillReturnError(errors.New("database error"))
 		mock.ExpectRollback()
 
-		resultID, err := storage.CreateTeamWithMembers(ctx, teamName, members)
+		resultID, err := storage.CreateTeamWithMembers(ctx, teamName, nil, members)
 
 		assert.Error(t, err)
 		assert.Equal(t, uuid.Nil, resultID)
@@ -153,7 +162,7 @@ func TestTeamStorage_CreateTeamWithMembers(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectQuery("INSERT INTO teams").
-			WithArgs(teamName).
+			WithArgs(teamName, pgxmock.AnyArg()).
 			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(teamID))
 
 		mock.ExpectExec("INSERT INTO users").
@@ -162,7 +171,7 @@ func TestTeamStorage_CreateTeamWithMembers(t *testing.T) {
 
 		mock.ExpectRollback()
 
-		resultID, err := storage.CreateTeamWithMembers(ctx, teamName, members)
+		resultID, err := storage.CreateTeamWithMembers(ctx, teamName, nil, members)
 
 		assert.Error(t, err)
 		assert.Equal(t, uuid.Nil, resultID)
@@ -198,9 +207,20 @@ func TestTeamStorage_DeactivateTeamMembers(t *testing.T) {
 
 		// INSERT new reviewer
 		mock.ExpectExec(`INSERT INTO pr_reviewers`).
-			WithArgs(prID, newReviewerID).
+			WithArgs(prID, newReviewerID, pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		// событие замены для истории и метрик
+		mock.ExpectExec(`INSERT INTO assignment_events`).
+			WithArgs(prID, string(domain.AssignmentEventReassigned), pgxmock.AnyArg(), userID1, newReviewerID).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
+		// доменные события пишутся в outbox в той же транзакции
+		expectOutboxLock(mock)
+		expectOutboxInsert(mock, domain.EventUserDeactivated)
+		expectOutboxInsert(mock, domain.EventUserDeactivated)
+		expectOutboxInsert(mock, domain.EventReviewerReassigned)
+
 		mock.ExpectCommit()
 
 		reassignments := []domain.ReviewerReassignment{
@@ -220,3 +240,999 @@ func TestTeamStorage_DeactivateTeamMembers(t *testing.T) {
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestTeamStorage_DeactivateUser(t *testing.T) {
+	ctx := context.Background()
+	userID := "user-1"
+	prID := "pr-123"
+
+	t.Run("sole reviewer without candidate is removed and PR needs reviewers", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`SELECT id FROM users WHERE id = ANY`).
+			WithArgs([]string{userID}).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectQuery(`SELECT pr.id`).
+			WithArgs([]string{userID}, string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(prID))
+		mock.ExpectQuery(`SELECT pull_request_id, reviewer_id`).
+			WithArgs([]string{prID}).
+			WillReturnRows(pgxmock.NewRows([]string{"pull_request_id", "reviewer_id"}).AddRow(prID, userID))
+		mock.ExpectQuery(`UPDATE users`).
+			WithArgs([]string{userID}).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(userID))
+		mock.ExpectExec(`DELETE FROM pr_reviewers`).
+			WithArgs(prID, userID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectExec(`UPDATE pull_requests SET need_more_reviewers = true`).
+			WithArgs(prID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec(`INSERT INTO assignment_events`).
+			WithArgs(prID, string(domain.AssignmentEventRemoved), pgxmock.AnyArg(), userID, "").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		expectOutboxLock(mock)
+		expectOutboxInsert(mock, domain.EventUserDeactivated)
+		expectOutboxInsert(mock, domain.EventReviewerReassigned)
+		mock.ExpectCommit()
+
+		plan := &domain.DeactivationPlan{
+			DeactivatedUserIDs: []string{userID},
+			Reassignments:      []domain.ReviewerReassignment{{PrID: prID, OldReviewerID: userID}},
+			ReviewersSnapshot:  map[string][]string{prID: {userID}},
+		}
+
+		result, err := storage.DeactivateUser(ctx, plan)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{userID}, result)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stale plan when reviewers have changed", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`SELECT id FROM users WHERE id = ANY`).
+			WithArgs([]string{userID}).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectQuery(`SELECT pr.id`).
+			WithArgs([]string{userID}, string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(prID))
+		mock.ExpectQuery(`SELECT pull_request_id, reviewer_id`).
+			WithArgs([]string{prID}).
+			WillReturnRows(pgxmock.NewRows([]string{"pull_request_id", "reviewer_id"}).
+				AddRow(prID, userID).
+				AddRow(prID, "user-2"))
+		mock.ExpectRollback()
+
+		plan := &domain.DeactivationPlan{
+			DeactivatedUserIDs: []string{userID},
+			Reassignments:      []domain.ReviewerReassignment{{PrID: prID, OldReviewerID: userID}},
+			ReviewersSnapshot:  map[string][]string{prID: {userID}},
+		}
+
+		_, err = storage.DeactivateUser(ctx, plan)
+
+		require.ErrorIs(t, err, ErrPlanStale)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTeamStorage_GetTeamWithAncestors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully get team with ancestors", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+		squadID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440010")
+		guildID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440011")
+		guildName := "Backend Guild"
+
+		mock.ExpectQuery("WITH RECURSIVE chain").
+			WithArgs(testTeam, domain.MaxTeamHierarchyDepth).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "name", "parent_name"}).
+				AddRow(squadID, testTeam, &guildName).
+				AddRow(guildID, guildName, nil))
+
+		mock.ExpectQuery("SELECT u.id, u.name, u.is_active").
+			WithArgs(squadID).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "name", "is_active"}).
+				AddRow("user-1", "Alice", true))
+
+		mock.ExpectQuery("SELECT u.id, u.name, u.is_active").
+			WithArgs(guildID).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "name", "is_active"}).
+				AddRow("user-2", "Bob", true).
+				AddRow("user-3", "Charlie", false))
+
+		teams, err := storage.GetTeamWithAncestors(ctx, testTeam)
+
+		require.NoError(t, err)
+		require.Len(t, teams, 2)
+		assert.Equal(t, testTeam, teams[0].TeamName)
+		require.NotNil(t, teams[0].ParentTeamName)
+		assert.Equal(t, guildName, *teams[0].ParentTeamName)
+		assert.Len(t, teams[0].Members, 1)
+		assert.Equal(t, guildName, teams[1].TeamName)
+		assert.Nil(t, teams[1].ParentTeamName)
+		assert.Len(t, teams[1].Members, 2)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectQuery("WITH RECURSIVE chain").
+			WithArgs("NonExistent Team", domain.MaxTeamHierarchyDepth).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "name", "parent_name"}))
+
+		teams, err := storage.GetTeamWithAncestors(ctx, "NonExistent Team")
+
+		assert.ErrorIs(t, err, ErrTeamNotExists)
+		assert.Nil(t, teams)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectQuery("WITH RECURSIVE chain").
+			WithArgs(testTeam, domain.MaxTeamHierarchyDepth).
+			WillReturnError(errors.New("database error"))
+
+		teams, err := storage.GetTeamWithAncestors(ctx, testTeam)
+
+		assert.Error(t, err)
+		assert.Nil(t, teams)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTeamStorage_GetTeamsHierarchy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully get hierarchy", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+		guildName := "Backend Guild"
+
+		mock.ExpectQuery("SELECT t.name, p.name").
+			WillReturnRows(pgxmock.NewRows([]string{"name", "parent_name"}).
+				AddRow(guildName, nil).
+				AddRow(testTeam, &guildName))
+
+		nodes, err := storage.GetTeamsHierarchy(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, nodes, 2)
+		assert.Nil(t, nodes[0].ParentTeamName)
+		require.NotNil(t, nodes[1].ParentTeamName)
+		assert.Equal(t, guildName, *nodes[1].ParentTeamName)
+		assert.NotNil(t, nodes[1].Children)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectQuery("SELECT t.name, p.name").
+			WillReturnError(errors.New("database error"))
+
+		nodes, err := storage.GetTeamsHierarchy(ctx)
+
+		assert.Error(t, err)
+		assert.Nil(t, nodes)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTeamStorage_SetTeamParent(t *testing.T) {
+	ctx := context.Background()
+	parentName := "Backend Guild"
+	teamID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+	parentID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440001")
+
+	t.Run("successfully set parent", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("pg_advisory_xact_lock").
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectQuery("SELECT id FROM teams WHERE name = \\$1 FOR UPDATE").
+			WithArgs(testTeam).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(teamID))
+		mock.ExpectQuery("SELECT id FROM teams WHERE name = \\$1 FOR UPDATE").
+			WithArgs(parentName).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(parentID))
+		mock.ExpectQuery("WITH RECURSIVE chain").
+			WithArgs(parentID, teamID).
+			WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectExec("UPDATE teams SET parent_id").
+			WithArgs(teamID, &parentID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+
+		err = storage.SetTeamParent(ctx, testTeam, &parentName)
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("successfully detach from parent", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("pg_advisory_xact_lock").
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectQuery("SELECT id FROM teams WHERE name = \\$1 FOR UPDATE").
+			WithArgs(testTeam).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(teamID))
+		mock.ExpectExec("UPDATE teams SET parent_id").
+			WithArgs(teamID, (*uuid.UUID)(nil)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+
+		err = storage.SetTeamParent(ctx, testTeam, nil)
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("pg_advisory_xact_lock").
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectQuery("SELECT id FROM teams WHERE name = \\$1 FOR UPDATE").
+			WithArgs("NonExistent Team").
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		err = storage.SetTeamParent(ctx, "NonExistent Team", &parentName)
+
+		assert.ErrorIs(t, err, ErrTeamNotExists)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("parent team not found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("pg_advisory_xact_lock").
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectQuery("SELECT id FROM teams WHERE name = \\$1 FOR UPDATE").
+			WithArgs(testTeam).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(teamID))
+		mock.ExpectQuery("SELECT id FROM teams WHERE name = \\$1 FOR UPDATE").
+			WithArgs(parentName).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		err = storage.SetTeamParent(ctx, testTeam, &parentName)
+
+		assert.ErrorIs(t, err, ErrParentTeamNotExists)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("cycle in hierarchy", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("pg_advisory_xact_lock").
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectQuery("SELECT id FROM teams WHERE name = \\$1 FOR UPDATE").
+			WithArgs(testTeam).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(teamID))
+		mock.ExpectQuery("SELECT id FROM teams WHERE name = \\$1 FOR UPDATE").
+			WithArgs(parentName).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(parentID))
+		mock.ExpectQuery("WITH RECURSIVE chain").
+			WithArgs(parentID, teamID).
+			WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectRollback()
+
+		err = storage.SetTeamParent(ctx, testTeam, &parentName)
+
+		assert.ErrorIs(t, err, ErrTeamHierarchyCycle)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTeamStorage_SetReviewSLA(t *testing.T) {
+	ctx := context.Background()
+	hours := 24
+
+	t.Run("successfully set SLA", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectExec("SET review_sla_hours").
+			WithArgs(testTeam, &hours).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err = storage.SetReviewSLA(ctx, testTeam, &hours)
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectExec("SET review_sla_hours").
+			WithArgs("NonExistent Team", &hours).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		err = storage.SetReviewSLA(ctx, "NonExistent Team", &hours)
+
+		assert.ErrorIs(t, err, ErrTeamNotExists)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTeamStorage_AddTeamMember(t *testing.T) {
+	ctx := context.Background()
+	teamID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440020")
+
+	t.Run("successfully add member", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM teams WHERE name").
+			WithArgs(testTeam).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(teamID))
+		mock.ExpectExec("INSERT INTO team_members").
+			WithArgs(teamID, testStrID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("UPDATE users SET team_id").
+			WithArgs(teamID, testStrID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err = storage.AddTeamMember(ctx, testTeam, testStrID)
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM teams WHERE name").
+			WithArgs("NonExistent Team").
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		err = storage.AddTeamMember(ctx, "NonExistent Team", testStrID)
+
+		assert.ErrorIs(t, err, ErrTeamNotExists)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTeamStorage_RemoveTeamMember(t *testing.T) {
+	ctx := context.Background()
+	teamID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440021")
+
+	t.Run("successfully remove member", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("DELETE FROM team_members").
+			WithArgs(testTeam, testStrID).
+			WillReturnRows(pgxmock.NewRows([]string{"team_id"}).AddRow(teamID))
+		mock.ExpectExec("UPDATE users").
+			WithArgs(teamID, testStrID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err = storage.RemoveTeamMember(ctx, testTeam, testStrID)
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("user is not a member", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("DELETE FROM team_members").
+			WithArgs(testTeam, testStrID).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		err = storage.RemoveTeamMember(ctx, testTeam, testStrID)
+
+		assert.ErrorIs(t, err, ErrNotTeamMember)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTeamStorage_SetPrimaryTeam(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully set primary team", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectExec("UPDATE users u").
+			WithArgs(testStrID, testTeam).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err = storage.SetPrimaryTeam(ctx, testStrID, testTeam)
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("user is not a member", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectExec("UPDATE users u").
+			WithArgs(testStrID, testTeam).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		err = storage.SetPrimaryTeam(ctx, testStrID, testTeam)
+
+		assert.ErrorIs(t, err, ErrNotTeamMember)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTeamStorage_GetRoster(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully get roster", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+		parent := "Backend Guild"
+		aliceID, aliceName, active := "user-alice", "Alice", true
+
+		mock.ExpectQuery("SELECT t.name, p.name, u.id, u.name, u.is_active").
+			WillReturnRows(pgxmock.NewRows([]string{"name", "parent", "id", "username", "is_active"}).
+				AddRow("Backend Guild", nil, nil, nil, nil).
+				AddRow(testTeam, &parent, &aliceID, &aliceName, &active))
+
+		teams, err := storage.GetRoster(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, teams, 2)
+		assert.Empty(t, teams[0].Members)
+		require.NotNil(t, teams[1].ParentTeamName)
+		assert.Equal(t, parent, *teams[1].ParentTeamName)
+		require.Len(t, teams[1].Members, 1)
+		assert.Equal(t, aliceID, teams[1].Members[0].UserId)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTeamStorage_ApplyRosterImportPlan(t *testing.T) {
+	ctx := context.Background()
+
+	parent := "Backend Guild"
+	snapshot := []domain.Team{
+		{TeamName: testTeam, Members: []domain.TeamMember{{UserId: "user-gone", Username: "Gone", IsActive: true}}},
+	}
+	plan := &domain.RosterImportPlan{
+		CreatedTeams:       []domain.RosterTeamChange{{TeamName: "Payments", ParentTeamName: &parent}},
+		CreatedUsers:       []domain.TeamMember{{UserId: testStrID, Username: "Alice", IsActive: true}},
+		AddedMemberships:   []domain.RosterMembership{{TeamName: testTeam, UserID: testStrID}},
+		RemovedMemberships: []domain.RosterMembership{{TeamName: testTeam, UserID: "user-gone"}},
+		DeactivatedUsers:   []string{"user-gone"},
+		Reassignments:      []domain.ReviewerReassignment{{PrID: "pr-1", OldReviewerID: "user-gone", NewReviewerID: testStrID}},
+		DeactivationTeams:  map[string]string{"user-gone": testTeam},
+		RosterSnapshot:     snapshot,
+		ReviewersSnapshot:  map[string][]string{"pr-1": {"user-gone"}},
+	}
+
+	expectLocksAndSnapshot := func(mock pgxmock.PgxPoolIface, rosterRows *pgxmock.Rows) {
+		mock.ExpectBegin()
+		mock.ExpectExec("pg_advisory_xact_lock").
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectExec("LOCK TABLE teams, team_members, users").
+			WillReturnResult(pgxmock.NewResult("LOCK TABLE", 0))
+		mock.ExpectQuery("SELECT t.name, p.name, u.id, u.name, u.is_active").
+			WillReturnRows(rosterRows)
+	}
+
+	currentRoster := func() *pgxmock.Rows {
+		return pgxmock.NewRows([]string{"name", "parent", "id", "username", "is_active"}).
+			AddRow(testTeam, nil, strPtr("user-gone"), strPtr("Gone"), boolPtr(true))
+	}
+
+	t.Run("successfully apply plan", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		expectLocksAndSnapshot(mock, currentRoster())
+		mock.ExpectExec("SELECT id FROM users WHERE id = ANY").
+			WithArgs([]string{"user-gone"}).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectQuery("FROM pull_requests pr").
+			WithArgs([]string{"user-gone"}, string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("pr-1"))
+		mock.ExpectQuery("SELECT pull_request_id, reviewer_id").
+			WithArgs([]string{"pr-1"}).
+			WillReturnRows(pgxmock.NewRows([]string{"pull_request_id", "reviewer_id"}).AddRow("pr-1", "user-gone"))
+		mock.ExpectExec("INSERT INTO teams").
+			WithArgs("Payments").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("UPDATE teams").
+			WithArgs("Payments", &parent).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec("INSERT INTO users").
+			WithArgs(testStrID, "Alice", true).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO team_members").
+			WithArgs(testTeam, testStrID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("DELETE FROM team_members").
+			WithArgs(testTeam, "user-gone").
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectExec("UPDATE users u").
+			WithArgs([]string{testStrID, "user-gone"}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+		mock.ExpectExec("UPDATE users SET is_active = false").
+			WithArgs([]string{"user-gone"}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec("DELETE FROM pr_reviewers").
+			WithArgs("pr-1", "user-gone").
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectExec("INSERT INTO pr_reviewers").
+			WithArgs("pr-1", testStrID, pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO assignment_events").
+			WithArgs("pr-1", string(domain.AssignmentEventReassigned), pgxmock.AnyArg(), "user-gone", testStrID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		expectOutboxLock(mock)
+		mock.ExpectExec("INSERT INTO outbox_events").
+			WithArgs(pgxmock.AnyArg(), string(domain.EventUserDeactivated), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO outbox_events").
+			WithArgs(pgxmock.AnyArg(), string(domain.EventReviewerReassigned), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		err = storage.ApplyRosterImportPlan(ctx, plan)
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("roster changed since plan", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		expectLocksAndSnapshot(mock, currentRoster().
+			AddRow(testTeam, nil, strPtr("user-new"), strPtr("New"), boolPtr(true)))
+		mock.ExpectRollback()
+
+		err = storage.ApplyRosterImportPlan(ctx, plan)
+
+		assert.ErrorIs(t, err, ErrPlanStale)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reviewers changed since plan", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		expectLocksAndSnapshot(mock, currentRoster())
+		mock.ExpectExec("SELECT id FROM users WHERE id = ANY").
+			WithArgs([]string{"user-gone"}).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectQuery("FROM pull_requests pr").
+			WithArgs([]string{"user-gone"}, string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("pr-1").AddRow("pr-2"))
+		mock.ExpectRollback()
+
+		err = storage.ApplyRosterImportPlan(ctx, plan)
+
+		assert.ErrorIs(t, err, ErrPlanStale)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		expectLocksAndSnapshot(mock, currentRoster())
+		mock.ExpectExec("SELECT id FROM users WHERE id = ANY").
+			WithArgs([]string{"user-gone"}).
+			WillReturnError(errors.New("lock timeout"))
+		mock.ExpectRollback()
+
+		err = storage.ApplyRosterImportPlan(ctx, plan)
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTeamStorage_ActivateTeamMembers(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewTeamStorage(mock)
+
+	t.Run("successfully activate users with rebalance", func(t *testing.T) {
+		teamName := "Backend"
+		userID := "user-1"
+		prID := "pr-123"
+		busyReviewerID := "user-2"
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id\s+FROM pull_requests`).
+			WithArgs([]string{prID}, string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(prID))
+		mock.ExpectQuery(`SELECT pull_request_id, reviewer_id`).
+			WithArgs([]string{prID}).
+			WillReturnRows(pgxmock.NewRows([]string{"pull_request_id", "reviewer_id"}).AddRow(prID, busyReviewerID))
+		mock.ExpectQuery(`UPDATE users u`).
+			WithArgs(teamName, []string{userID}).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(userID))
+		mock.ExpectExec(`DELETE FROM pr_reviewers`).
+			WithArgs(prID, busyReviewerID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectExec(`INSERT INTO pr_reviewers`).
+			WithArgs(prID, userID, pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(`INSERT INTO assignment_events`).
+			WithArgs(prID, string(domain.AssignmentEventReassigned), pgxmock.AnyArg(), busyReviewerID, userID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		expectOutboxLock(mock)
+		expectOutboxInsert(mock, domain.EventReviewerReassigned)
+		mock.ExpectCommit()
+
+		reassignments := []domain.ReviewerReassignment{
+			{PrID: prID, OldReviewerID: busyReviewerID, NewReviewerID: userID},
+		}
+
+		result, err := storage.ActivateTeamMembers(context.Background(), teamName, []string{userID}, reassignments)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{userID}, result)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("PR closed since rebalance - stale", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id\s+FROM pull_requests`).
+			WithArgs([]string{"pr-123"}, string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		reassignments := []domain.ReviewerReassignment{
+			{PrID: "pr-123", OldReviewerID: "user-2", NewReviewerID: "user-1"},
+		}
+
+		_, err := storage.ActivateTeamMembers(context.Background(), "Backend", []string{"user-1"}, reassignments)
+
+		require.ErrorIs(t, err, ErrPlanStale)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("donor unassigned since rebalance - stale", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id\s+FROM pull_requests`).
+			WithArgs([]string{"pr-123"}, string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("pr-123"))
+		mock.ExpectQuery(`SELECT pull_request_id, reviewer_id`).
+			WithArgs([]string{"pr-123"}).
+			WillReturnRows(pgxmock.NewRows([]string{"pull_request_id", "reviewer_id"}).AddRow("pr-123", "user-3"))
+		mock.ExpectRollback()
+
+		reassignments := []domain.ReviewerReassignment{
+			{PrID: "pr-123", OldReviewerID: "user-2", NewReviewerID: "user-1"},
+		}
+
+		_, err := storage.ActivateTeamMembers(context.Background(), "Backend", []string{"user-1"}, reassignments)
+
+		require.ErrorIs(t, err, ErrPlanStale)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTeamStorage_SaveDeactivationPlan(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewTeamStorage(mock)
+	planID := uuid.New()
+	createdAt := time.Now()
+
+	plan := &domain.DeactivationPlan{
+		TeamName:           "Backend",
+		DeactivatedUserIDs: []string{"user-1"},
+		Reassignments:      []domain.ReviewerReassignment{{PrID: "pr-1", OldReviewerID: "user-1", NewReviewerID: "user-2"}},
+		ReviewersSnapshot:  map[string][]string{"pr-1": {"user-1"}},
+		ExpiresAt:          createdAt.Add(domain.DeactivationPlanTTL),
+	}
+
+	mock.ExpectQuery("INSERT INTO deactivation_plans").
+		WithArgs(plan.TeamName, plan.DeactivatedUserIDs, plan.Reassignments, plan.ReviewersSnapshot, plan.ExpiresAt).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(planID, createdAt))
+
+	err = storage.SaveDeactivationPlan(context.Background(), plan)
+
+	require.NoError(t, err)
+	assert.Equal(t, planID.String(), plan.PlanID)
+	assert.Equal(t, createdAt, plan.CreatedAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTeamStorage_GetDeactivationPlan(t *testing.T) {
+	t.Run("plan not found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+		planID := uuid.New()
+
+		mock.ExpectQuery("SELECT team_name, user_ids, reassignments").
+			WithArgs(planID).
+			WillReturnError(pgx.ErrNoRows)
+
+		plan, err := storage.GetDeactivationPlan(context.Background(), planID)
+
+		assert.ErrorIs(t, err, ErrDeactivationPlanNotExists)
+		assert.Nil(t, plan)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTeamStorage_ApplyDeactivationPlan(t *testing.T) {
+	plan := &domain.DeactivationPlan{
+		PlanID:             uuid.New().String(),
+		TeamName:           "Backend",
+		DeactivatedUserIDs: []string{"user-1"},
+		Reassignments:      []domain.ReviewerReassignment{{PrID: "pr-1", OldReviewerID: "user-1", NewReviewerID: "user-2"}},
+		ReviewersSnapshot:  map[string][]string{"pr-1": {"user-1"}},
+	}
+
+	expectClaim := func(mock pgxmock.PgxPoolIface) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE deactivation_plans").
+			WithArgs(plan.PlanID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	}
+
+	expectMembers := func(mock pgxmock.PgxPoolIface, userIDs ...string) {
+		rows := pgxmock.NewRows([]string{"user_id"})
+		for _, userID := range userIDs {
+			rows.AddRow(userID)
+		}
+		mock.ExpectQuery("FOR UPDATE OF tm").
+			WithArgs("Backend").
+			WillReturnRows(rows)
+	}
+
+	t.Run("successfully apply plan", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		expectClaim(mock)
+		expectMembers(mock, "user-1", "user-2", "user-3")
+		mock.ExpectExec(`SELECT id FROM users WHERE id = ANY`).
+			WithArgs([]string{"user-1"}).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectQuery(`SELECT pr.id`).
+			WithArgs([]string{"user-1"}, string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("pr-1"))
+		mock.ExpectQuery(`SELECT pull_request_id, reviewer_id`).
+			WithArgs([]string{"pr-1"}).
+			WillReturnRows(pgxmock.NewRows([]string{"pull_request_id", "reviewer_id"}).AddRow("pr-1", "user-1"))
+		mock.ExpectQuery(`WHERE id = ANY\(\$1\) AND is_active`).
+			WithArgs([]string{"user-2"}).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("user-2"))
+		mock.ExpectQuery("UPDATE users u").
+			WithArgs("Backend", []string{"user-1"}).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("user-1"))
+		mock.ExpectExec("DELETE FROM pr_reviewers").
+			WithArgs("pr-1", "user-1").
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectExec("INSERT INTO pr_reviewers").
+			WithArgs("pr-1", "user-2", pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO assignment_events").
+			WithArgs("pr-1", string(domain.AssignmentEventReassigned), pgxmock.AnyArg(), "user-1", "user-2").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		expectOutboxLock(mock)
+		expectOutboxInsert(mock, domain.EventUserDeactivated)
+		expectOutboxInsert(mock, domain.EventReviewerReassigned)
+		mock.ExpectCommit()
+
+		result, err := storage.ApplyDeactivationPlan(context.Background(), plan)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user-1"}, result)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("plan already applied", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE deactivation_plans").
+			WithArgs(plan.PlanID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+		mock.ExpectRollback()
+
+		_, err = storage.ApplyDeactivationPlan(context.Background(), plan)
+
+		assert.ErrorIs(t, err, ErrDeactivationPlanAlreadyApplied)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stale plan when user left the team", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		expectClaim(mock)
+		expectMembers(mock, "user-2", "user-3")
+		mock.ExpectRollback()
+
+		_, err = storage.ApplyDeactivationPlan(context.Background(), plan)
+
+		assert.ErrorIs(t, err, ErrPlanStale)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stale plan when new reviewer was deactivated", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewTeamStorage(mock)
+
+		expectClaim(mock)
+		expectMembers(mock, "user-1", "user-2", "user-3")
+		mock.ExpectExec(`SELECT id FROM users WHERE id = ANY`).
+			WithArgs([]string{"user-1"}).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectQuery(`SELECT pr.id`).
+			WithArgs([]string{"user-1"}, string(domain.PullRequestStatusOPEN)).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("pr-1"))
+		mock.ExpectQuery(`SELECT pull_request_id, reviewer_id`).
+			WithArgs([]string{"pr-1"}).
+			WillReturnRows(pgxmock.NewRows([]string{"pull_request_id", "reviewer_id"}).AddRow("pr-1", "user-1"))
+		mock.ExpectQuery(`WHERE id = ANY\(\$1\) AND is_active`).
+			WithArgs([]string{"user-2"}).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err = storage.ApplyDeactivationPlan(context.Background(), plan)
+
+		assert.ErrorIs(t, err, ErrPlanStale)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// expectOutboxLock ожидает блокировку позиций потока, которую берёт запись событий в outbox
+func expectOutboxLock(mock pgxmock.PgxPoolIface) {
+	mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+}
+
+// expectOutboxInsert ожидает запись события в outbox внутри транзакции
+func expectOutboxInsert(mock pgxmock.PgxPoolIface, eventType domain.EventType) {
+	mock.ExpectExec("INSERT INTO outbox_events").
+		WithArgs(pgxmock.AnyArg(), string(eventType), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
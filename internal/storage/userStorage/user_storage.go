@@ -19,28 +19,100 @@ func NewUserStorage(db db.Querier) *UserStorage {
 
 func (s *UserStorage) GetUserByID(ctx context.Context, userID string) (*domain.User, error) {
 	var username string
-	var teamName string
+	var primaryTeamName *string
 	var isActive bool
+	var teamNames []string
 
+	// team_names - все команды пользователя по членству, team_name - основная команда
 	query := `
-		SELECT u.name, t.name, u.is_active
+		SELECT u.name, pt.name, u.is_active,
+		       COALESCE(array_agg(t.name ORDER BY t.name) FILTER (WHERE t.name IS NOT NULL), '{}')
 		FROM users u
-		LEFT JOIN teams t ON u.team_id = t.id
-		WHERE u.id = $1`
+		LEFT JOIN teams pt ON u.team_id = pt.id
+		LEFT JOIN team_members tm ON tm.user_id = u.id
+		LEFT JOIN teams t ON tm.team_id = t.id
+		WHERE u.id = $1
+		GROUP BY u.id, u.name, pt.name, u.is_active`
 
-	err := s.db.QueryRow(ctx, query, userID).Scan(&username, &teamName, &isActive)
+	err := s.db.QueryRow(ctx, query, userID).Scan(&username, &primaryTeamName, &isActive, &teamNames)
 	if err != nil {
 		return nil, err
 	}
 
-	user := &domain.User{
-		UserId:   userID,
-		Username: username,
-		TeamName: teamName,
-		IsActive: isActive,
+	return newUser(userID, username, primaryTeamName, isActive, teamNames), nil
+}
+
+// ListUsers возвращает страницу пользователей, подходящих под фильтр, и общее число таких пользователей
+func (s *UserStorage) ListUsers(ctx context.Context, filter domain.UserFilter, offset, limit int) ([]domain.User, int, error) {
+	var total int
+	countQuery := `
+		SELECT count(*)
+		FROM users u
+		WHERE ($1::text IS NULL OR u.id = $1)
+		  AND ($2::text IS NULL OR u.name = $2)`
+	err := s.db.QueryRow(ctx, countQuery, filter.UserID, filter.Username).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT u.id, u.name, pt.name, u.is_active,
+		       COALESCE(array_agg(t.name ORDER BY t.name) FILTER (WHERE t.name IS NOT NULL), '{}')
+		FROM users u
+		LEFT JOIN teams pt ON u.team_id = pt.id
+		LEFT JOIN team_members tm ON tm.user_id = u.id
+		LEFT JOIN teams t ON tm.team_id = t.id
+		WHERE ($1::text IS NULL OR u.id = $1)
+		  AND ($2::text IS NULL OR u.name = $2)
+		GROUP BY u.id, u.name, pt.name, u.is_active
+		ORDER BY u.id
+		OFFSET $3 LIMIT $4`
+
+	rows, err := s.db.Query(ctx, query, filter.UserID, filter.Username, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0)
+	for rows.Next() {
+		var userID, username string
+		var primaryTeamName *string
+		var isActive bool
+		var teamNames []string
+
+		if err = rows.Scan(&userID, &username, &primaryTeamName, &isActive, &teamNames); err != nil {
+			return nil, 0, err
+		}
+
+		users = append(users, *newUser(userID, username, primaryTeamName, isActive, teamNames))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
 	}
 
-	return user, nil
+	return users, total, nil
+}
+
+// CreateUser создаёт пользователя без команды. Если пользователь уже есть, вернётся ошибка уникальности (23505)
+func (s *UserStorage) CreateUser(ctx context.Context, userID, username string, isActive bool) error {
+	query := `
+		INSERT INTO users (id, name, is_active)
+		VALUES ($1, $2, $3)`
+
+	_, err := s.db.Exec(ctx, query, userID, username, isActive)
+	return err
+}
+
+func (s *UserStorage) SetUserName(ctx context.Context, userID, username string) error {
+	query := `
+		UPDATE users
+		SET name = $1
+		WHERE id = $2`
+
+	_, err := s.db.Exec(ctx, query, username, userID)
+	return err
 }
 
 func (s *UserStorage) SetUserIsActive(ctx context.Context, userID string, isActive bool) error {
@@ -56,3 +128,23 @@ func (s *UserStorage) SetUserIsActive(ctx context.Context, userID string, isActi
 
 	return nil
 }
+
+// newUser собирает пользователя. Основная команда необязательна - для обратной совместимости
+// team_name заполняется первой из команд
+func newUser(userID, username string, primaryTeamName *string, isActive bool, teamNames []string) *domain.User {
+	var teamName string
+	switch {
+	case primaryTeamName != nil:
+		teamName = *primaryTeamName
+	case len(teamNames) > 0:
+		teamName = teamNames[0]
+	}
+
+	return &domain.User{
+		UserId:    userID,
+		Username:  username,
+		TeamName:  teamName,
+		TeamNames: teamNames,
+		IsActive:  isActive,
+	}
+}
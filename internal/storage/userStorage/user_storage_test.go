@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
 	"github.com/pashagolub/pgxmock/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -21,10 +22,12 @@ func TestUserStorage_GetUserByID(t *testing.T) {
 		storage := NewUserStorage(mock)
 		userID := "user-123"
 
-		mock.ExpectQuery("SELECT u.name, t.name, u.is_active").
+		primaryTeam := "Backend Team"
+
+		mock.ExpectQuery("SELECT u.name, pt.name, u.is_active").
 			WithArgs(userID).
-			WillReturnRows(pgxmock.NewRows([]string{"name", "name", "is_active"}).
-				AddRow("Alice", "Backend Team", true))
+			WillReturnRows(pgxmock.NewRows([]string{"name", "name", "is_active", "team_names"}).
+				AddRow("Alice", &primaryTeam, true, []string{"Backend Team", "Payments"}))
 
 		user, err := storage.GetUserByID(ctx, userID)
 
@@ -32,6 +35,29 @@ func TestUserStorage_GetUserByID(t *testing.T) {
 		require.NotNil(t, user)
 		assert.Equal(t, userID, user.UserId)
 		assert.Equal(t, "Alice", user.Username)
+		assert.Equal(t, primaryTeam, user.TeamName)
+		assert.Equal(t, []string{"Backend Team", "Payments"}, user.TeamNames)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("user without primary team falls back to first membership", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewUserStorage(mock)
+		userID := "user-124"
+
+		mock.ExpectQuery("SELECT u.name, pt.name, u.is_active").
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows([]string{"name", "name", "is_active", "team_names"}).
+				AddRow("Bob", nil, true, []string{"Mobile", "Payments"}))
+
+		user, err := storage.GetUserByID(ctx, userID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Mobile", user.TeamName)
+		assert.Len(t, user.TeamNames, 2)
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
 
@@ -43,7 +69,7 @@ func TestUserStorage_GetUserByID(t *testing.T) {
 		storage := NewUserStorage(mock)
 		userID := "user-456"
 
-		mock.ExpectQuery("SELECT u.name, t.name, u.is_active").
+		mock.ExpectQuery("SELECT u.name, pt.name, u.is_active").
 			WithArgs(userID).
 			WillReturnError(pgx.ErrNoRows)
 
@@ -76,3 +102,75 @@ func TestUserStorage_SetUserIsActive(t *testing.T) {
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestUserStorage_ListUsers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully list users by id", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewUserStorage(mock)
+		userID := "user-123"
+		filter := domain.UserFilter{UserID: &userID}
+
+		mock.ExpectQuery("SELECT count").
+			WithArgs(&userID, (*string)(nil)).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery("SELECT u.id, u.name, pt.name, u.is_active").
+			WithArgs(&userID, (*string)(nil), 0, 100).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "name", "team", "is_active", "teams"}).
+				AddRow(userID, "Alice", nil, true, []string{"Backend"}))
+
+		users, total, err := storage.ListUsers(ctx, filter, 0, 100)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, users, 1)
+		assert.Equal(t, "Backend", users[0].TeamName)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserStorage_CreateUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully create user", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewUserStorage(mock)
+
+		mock.ExpectExec("INSERT INTO users").
+			WithArgs("user-123", "Alice", true).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		err = storage.CreateUser(ctx, "user-123", "Alice", true)
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserStorage_SetUserName(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully set user name", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewUserStorage(mock)
+
+		mock.ExpectExec("UPDATE users").
+			WithArgs("Alice Smith", "user-123").
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err = storage.SetUserName(ctx, "user-123", "Alice Smith")
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,141 @@
+package webhookStorage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/db"
+)
+
+var ErrUserNotExists = errors.New("user does not exist")
+
+type WebhookStorage struct {
+	db db.Querier
+}
+
+func NewWebhookStorage(db db.Querier) *WebhookStorage {
+	return &WebhookStorage{
+		db: db,
+	}
+}
+
+// GetUserIDByLogin возвращает пользователя по логину в VCS; если соответствия нет - pgx.ErrNoRows
+func (s *WebhookStorage) GetUserIDByLogin(ctx context.Context, provider domain.VCSProvider, login string) (string, error) {
+	query := `
+		SELECT user_id
+		FROM vcs_user_mappings
+		WHERE provider = $1 AND login = $2`
+
+	var userID string
+	err := s.db.QueryRow(ctx, query, string(provider), login).Scan(&userID)
+	if err != nil {
+		return "", err
+	}
+
+	return userID, nil
+}
+
+// UpsertUserMapping создаёт или перепривязывает логин к пользователю
+func (s *WebhookStorage) UpsertUserMapping(ctx context.Context, mapping domain.VCSUserMapping) error {
+	query := `
+		INSERT INTO vcs_user_mappings (provider, login, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (provider, login) DO UPDATE SET user_id = EXCLUDED.user_id`
+
+	_, err := s.db.Exec(ctx, query, string(mapping.Provider), mapping.Login, mapping.UserID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrUserNotExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ListUserMappings возвращает соответствия логинов, при provider != nil - только для этого провайдера
+func (s *WebhookStorage) ListUserMappings(ctx context.Context, provider *domain.VCSProvider) ([]domain.VCSUserMapping, error) {
+	query := `
+		SELECT provider, login, user_id
+		FROM vcs_user_mappings
+		WHERE $1::text IS NULL OR provider = $1
+		ORDER BY provider, login`
+
+	rows, err := s.db.Query(ctx, query, (*string)(provider))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mappings := make([]domain.VCSUserMapping, 0)
+	for rows.Next() {
+		var mapping domain.VCSUserMapping
+		var mappingProvider string
+		if err = rows.Scan(&mappingProvider, &mapping.Login, &mapping.UserID); err != nil {
+			return nil, err
+		}
+		mapping.Provider = domain.VCSProvider(mappingProvider)
+		mappings = append(mappings, mapping)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return mappings, nil
+}
+
+// ProcessDelivery забирает доставку и вызывает process, пока держит заявку. Заявка - строка webhook_deliveries,
+// вставленная в транзакции через ON CONFLICT DO NOTHING: параллельная доставка с тем же id ждёт на первичном
+// ключе и после коммита получает конфликт. Если process вернул ошибку или процесс упал, транзакция
+// откатывается и доставку можно повторить. false - доставка уже обработана, process не вызывался
+func (s *WebhookStorage) ProcessDelivery(
+	ctx context.Context,
+	delivery domain.WebhookDelivery,
+	process func(ctx context.Context) (domain.WebhookResultStatus, error),
+) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	claimQuery := `
+		INSERT INTO webhook_deliveries (provider, delivery_id, event, action, result, pull_request_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (provider, delivery_id) DO NOTHING`
+
+	tag, err := tx.Exec(ctx, claimQuery,
+		string(delivery.Provider),
+		delivery.DeliveryID,
+		delivery.Event,
+		string(delivery.Action),
+		string(domain.WebhookProcessing),
+		delivery.PullRequestID,
+	)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	result, err := process(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	resultQuery := `UPDATE webhook_deliveries SET result = $3 WHERE provider = $1 AND delivery_id = $2`
+	if _, err = tx.Exec(ctx, resultQuery, string(delivery.Provider), delivery.DeliveryID, string(result)); err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,216 @@
+package webhookStorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookStorage_GetUserIDByLogin(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("mapping exists", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewWebhookStorage(mock)
+
+		mock.ExpectQuery("FROM vcs_user_mappings").
+			WithArgs("github", "octocat").
+			WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow("user-alice"))
+
+		userID, err := storage.GetUserIDByLogin(ctx, domain.VCSProviderGitHub, "octocat")
+
+		require.NoError(t, err)
+		assert.Equal(t, "user-alice", userID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("mapping not found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewWebhookStorage(mock)
+
+		mock.ExpectQuery("FROM vcs_user_mappings").
+			WithArgs("github", "stranger").
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err = storage.GetUserIDByLogin(ctx, domain.VCSProviderGitHub, "stranger")
+
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+	})
+}
+
+func TestWebhookStorage_UpsertUserMapping(t *testing.T) {
+	ctx := context.Background()
+	mapping := domain.VCSUserMapping{Provider: domain.VCSProviderGitHub, Login: "octocat", UserID: "user-alice"}
+
+	t.Run("successfully upsert", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewWebhookStorage(mock)
+
+		mock.ExpectExec("INSERT INTO vcs_user_mappings").
+			WithArgs("github", "octocat", "user-alice").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		require.NoError(t, storage.UpsertUserMapping(ctx, mapping))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewWebhookStorage(mock)
+
+		mock.ExpectExec("INSERT INTO vcs_user_mappings").
+			WithArgs("github", "octocat", "user-alice").
+			WillReturnError(&pgconn.PgError{Code: "23503"})
+
+		assert.ErrorIs(t, storage.UpsertUserMapping(ctx, mapping), ErrUserNotExists)
+	})
+}
+
+func TestWebhookStorage_ListUserMappings(t *testing.T) {
+	ctx := context.Background()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := NewWebhookStorage(mock)
+	provider := domain.VCSProviderGitHub
+	providerArg := "github"
+
+	mock.ExpectQuery("FROM vcs_user_mappings").
+		WithArgs(&providerArg).
+		WillReturnRows(pgxmock.NewRows([]string{"provider", "login", "user_id"}).
+			AddRow("github", "octocat", "user-alice"))
+
+	mappings, err := storage.ListUserMappings(ctx, &provider)
+
+	require.NoError(t, err)
+	assert.Equal(t, []domain.VCSUserMapping{{Provider: provider, Login: "octocat", UserID: "user-alice"}}, mappings)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookStorage_ProcessDelivery(t *testing.T) {
+	ctx := context.Background()
+	prID := "octo-org/api#42"
+	delivery := domain.WebhookDelivery{
+		Provider:      domain.VCSProviderGitHub,
+		DeliveryID:    "delivery-1",
+		Event:         "pull_request",
+		Action:        domain.VCSActionOpen,
+		PullRequestID: &prID,
+	}
+
+	t.Run("claimed delivery is processed and recorded", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewWebhookStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO webhook_deliveries").
+			WithArgs("github", "delivery-1", "pull_request", "OPEN", "processing", &prID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("UPDATE webhook_deliveries SET result").
+			WithArgs("github", "delivery-1", "processed").
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		calls := 0
+		claimed, err := storage.ProcessDelivery(ctx, delivery, func(context.Context) (domain.WebhookResultStatus, error) {
+			calls++
+			return domain.WebhookProcessed, nil
+		})
+
+		require.NoError(t, err)
+		assert.True(t, claimed)
+		assert.Equal(t, 1, calls)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delivery claimed by another request is not processed", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewWebhookStorage(mock)
+
+		// Параллельная доставка с тем же id уже вставила заявку: ON CONFLICT DO NOTHING ничего не вставляет
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO webhook_deliveries").
+			WithArgs("github", "delivery-1", "pull_request", "OPEN", "processing", &prID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+		mock.ExpectRollback()
+
+		claimed, err := storage.ProcessDelivery(ctx, delivery, func(context.Context) (domain.WebhookResultStatus, error) {
+			t.Fatal("duplicate delivery must not be processed")
+			return "", nil
+		})
+
+		require.NoError(t, err)
+		assert.False(t, claimed)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed processing releases the claim", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewWebhookStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO webhook_deliveries").
+			WithArgs("github", "delivery-1", "pull_request", "OPEN", "processing", &prID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectRollback()
+
+		_, err = storage.ProcessDelivery(ctx, delivery, func(context.Context) (domain.WebhookResultStatus, error) {
+			return "", errors.New("login is not mapped")
+		})
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("claim error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		storage := NewWebhookStorage(mock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO webhook_deliveries").
+			WithArgs("github", "delivery-1", "pull_request", "OPEN", "processing", &prID).
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		_, err = storage.ProcessDelivery(ctx, delivery, func(context.Context) (domain.WebhookResultStatus, error) {
+			return domain.WebhookProcessed, nil
+		})
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DBPoolStats - снимок pgxpool.Stat. Отдельная структура нужна, чтобы коллектор можно было
+// проверить без настоящего пула (у pgxpool.Stat нет публичного конструктора)
+type DBPoolStats struct {
+	AcquireCount         int64
+	EmptyAcquireCount    int64
+	CanceledAcquireCount int64
+	AcquireDuration      time.Duration
+	EmptyAcquireWaitTime time.Duration
+
+	AcquiredConns     int32
+	IdleConns         int32
+	TotalConns        int32
+	ConstructingConns int32
+	MaxConns          int32
+}
+
+// PgxPoolStats возвращает функцию, снимающую статистику с пула при каждом скрейпе
+func PgxPoolStats(pool *pgxpool.Pool) func() DBPoolStats {
+	return func() DBPoolStats {
+		stat := pool.Stat()
+		return DBPoolStats{
+			AcquireCount:         stat.AcquireCount(),
+			EmptyAcquireCount:    stat.EmptyAcquireCount(),
+			CanceledAcquireCount: stat.CanceledAcquireCount(),
+			AcquireDuration:      stat.AcquireDuration(),
+			EmptyAcquireWaitTime: stat.EmptyAcquireWaitTime(),
+			AcquiredConns:        stat.AcquiredConns(),
+			IdleConns:            stat.IdleConns(),
+			TotalConns:           stat.TotalConns(),
+			ConstructingConns:    stat.ConstructingConns(),
+			MaxConns:             stat.MaxConns(),
+		}
+	}
+}
+
+// DBPoolCollector публикует статистику пула соединений. Счётчики pgxpool монотонные,
+// поэтому отдаются как counter, текущее состояние соединений - как gauge
+type DBPoolCollector struct {
+	stats func() DBPoolStats
+
+	acquires          *prometheus.Desc
+	emptyAcquires     *prometheus.Desc
+	canceledAcquires  *prometheus.Desc
+	acquireDuration   *prometheus.Desc
+	emptyAcquireWait  *prometheus.Desc
+	acquiredConns     *prometheus.Desc
+	idleConns         *prometheus.Desc
+	totalConns        *prometheus.Desc
+	constructingConns *prometheus.Desc
+	maxConns          *prometheus.Desc
+}
+
+func NewDBPoolCollector(stats func() DBPoolStats) *DBPoolCollector {
+	return &DBPoolCollector{
+		stats: stats,
+		acquires: prometheus.NewDesc(
+			"db_pool_acquires_total",
+			"Total number of successful connection acquires from the pool",
+			nil, nil,
+		),
+		emptyAcquires: prometheus.NewDesc(
+			"db_pool_empty_acquires_total",
+			"Total number of acquires that had to wait because the pool had no idle connection",
+			nil, nil,
+		),
+		canceledAcquires: prometheus.NewDesc(
+			"db_pool_canceled_acquires_total",
+			"Total number of acquires canceled by their context",
+			nil, nil,
+		),
+		acquireDuration: prometheus.NewDesc(
+			"db_pool_acquire_duration_seconds_total",
+			"Total time spent on successful acquires in seconds",
+			nil, nil,
+		),
+		emptyAcquireWait: prometheus.NewDesc(
+			"db_pool_empty_acquire_wait_seconds_total",
+			"Total time spent waiting for a connection when the pool was empty in seconds",
+			nil, nil,
+		),
+		acquiredConns: prometheus.NewDesc(
+			"db_pool_acquired_conns",
+			"Number of connections currently acquired",
+			nil, nil,
+		),
+		idleConns: prometheus.NewDesc(
+			"db_pool_idle_conns",
+			"Number of idle connections in the pool",
+			nil, nil,
+		),
+		totalConns: prometheus.NewDesc(
+			"db_pool_total_conns",
+			"Total number of connections in the pool, including constructing ones",
+			nil, nil,
+		),
+		constructingConns: prometheus.NewDesc(
+			"db_pool_constructing_conns",
+			"Number of connections being established",
+			nil, nil,
+		),
+		maxConns: prometheus.NewDesc(
+			"db_pool_max_conns",
+			"Maximum size of the pool",
+			nil, nil,
+		),
+	}
+}
+
+func (c *DBPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquires
+	ch <- c.emptyAcquires
+	ch <- c.canceledAcquires
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquireWait
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.constructingConns
+	ch <- c.maxConns
+}
+
+func (c *DBPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(stats.AcquireCount))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(stats.EmptyAcquireCount))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquires, prometheus.CounterValue, float64(stats.CanceledAcquireCount))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stats.AcquireDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireWait, prometheus.CounterValue, stats.EmptyAcquireWaitTime.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stats.AcquiredConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.constructingConns, prometheus.GaugeValue, float64(stats.ConstructingConns))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stats.MaxConns))
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBPoolCollector(t *testing.T) {
+	collector := NewDBPoolCollector(func() DBPoolStats {
+		return DBPoolStats{
+			AcquireCount:         10,
+			EmptyAcquireCount:    3,
+			CanceledAcquireCount: 1,
+			AcquireDuration:      1500 * time.Millisecond,
+			EmptyAcquireWaitTime: 500 * time.Millisecond,
+			AcquiredConns:        2,
+			IdleConns:            4,
+			TotalConns:           6,
+			ConstructingConns:    0,
+			MaxConns:             8,
+		}
+	})
+
+	expected := `
+# HELP db_pool_acquire_duration_seconds_total Total time spent on successful acquires in seconds
+# TYPE db_pool_acquire_duration_seconds_total counter
+db_pool_acquire_duration_seconds_total 1.5
+# HELP db_pool_acquired_conns Number of connections currently acquired
+# TYPE db_pool_acquired_conns gauge
+db_pool_acquired_conns 2
+# HELP db_pool_acquires_total Total number of successful connection acquires from the pool
+# TYPE db_pool_acquires_total counter
+db_pool_acquires_total 10
+# HELP db_pool_empty_acquire_wait_seconds_total Total time spent waiting for a connection when the pool was empty in seconds
+# TYPE db_pool_empty_acquire_wait_seconds_total counter
+db_pool_empty_acquire_wait_seconds_total 0.5
+# HELP db_pool_empty_acquires_total Total number of acquires that had to wait because the pool had no idle connection
+# TYPE db_pool_empty_acquires_total counter
+db_pool_empty_acquires_total 3
+# HELP db_pool_idle_conns Number of idle connections in the pool
+# TYPE db_pool_idle_conns gauge
+db_pool_idle_conns 4
+# HELP db_pool_max_conns Maximum size of the pool
+# TYPE db_pool_max_conns gauge
+db_pool_max_conns 8
+# HELP db_pool_total_conns Total number of connections in the pool, including constructing ones
+# TYPE db_pool_total_conns gauge
+db_pool_total_conns 6
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"db_pool_acquires_total", "db_pool_empty_acquires_total", "db_pool_acquire_duration_seconds_total",
+		"db_pool_empty_acquire_wait_seconds_total", "db_pool_acquired_conns", "db_pool_idle_conns",
+		"db_pool_total_conns", "db_pool_max_conns",
+	))
+	require.Equal(t, 10, testutil.CollectAndCount(collector))
+}
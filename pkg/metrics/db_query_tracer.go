@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DBQueryNameOther - метка для запросов, вызванных не из storage-пакетов (например, Ping)
+	DBQueryNameOther = "other"
+
+	dbQueryStatusOK    = "ok"
+	dbQueryStatusError = "error"
+
+	storagePackagePrefix = "/internal/storage/"
+)
+
+// DBQueryDurationSeconds - длительность запросов к Postgres по имени storage-метода
+var DBQueryDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "db_query_duration_seconds",
+	Help:    "Postgres query latency in seconds by storage method and result",
+	Buckets: []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+}, []string{"query", "status"})
+
+type dbQueryStartKey struct{}
+
+type dbQueryStart struct {
+	name  string
+	start time.Time
+}
+
+// DBQueryTracer - pgx.QueryTracer, который пишет DBQueryDurationSeconds. Имя запроса -
+// storage-метод, из которого он вызван ("userStorage.GetUserByID"), поэтому SQL размечать не нужно,
+// а число меток ограничено числом методов. Для Query время включает чтение строк до rows.Close
+type DBQueryTracer struct{}
+
+func NewDBQueryTracer() *DBQueryTracer {
+	return &DBQueryTracer{}
+}
+
+func (t *DBQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, dbQueryStartKey{}, dbQueryStart{
+		name:  storageCallerName(),
+		start: time.Now(),
+	})
+}
+
+func (t *DBQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	started, ok := ctx.Value(dbQueryStartKey{}).(dbQueryStart)
+	if !ok {
+		return
+	}
+
+	status := dbQueryStatusOK
+	if data.Err != nil {
+		status = dbQueryStatusError
+	}
+
+	DBQueryDurationSeconds.WithLabelValues(started.name, status).Observe(time.Since(started.start).Seconds())
+}
+
+// storageCallerName ищет в стеке ближайший вызов из internal/storage и возвращает его
+// как "пакет.Метод" без получателя
+func storageCallerName() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		if idx := strings.Index(frame.Function, storagePackagePrefix); idx >= 0 {
+			return queryNameFromFunction(frame.Function[idx+len(storagePackagePrefix):])
+		}
+		if !more {
+			return DBQueryNameOther
+		}
+	}
+}
+
+// queryNameFromFunction превращает "userStorage.(*UserStorage).GetUserByID" в "userStorage.GetUserByID"
+// и "teamStorage.(*TeamStorage).ApplyDeactivationPlan.func1" в "teamStorage.ApplyDeactivationPlan"
+func queryNameFromFunction(function string) string {
+	parts := strings.Split(function, ".")
+	if len(parts) < 2 {
+		return function
+	}
+
+	name := parts[0]
+	for _, part := range parts[1:] {
+		if strings.HasPrefix(part, "(") || strings.HasPrefix(part, "func") {
+			continue
+		}
+		return name + "." + part
+	}
+	return name
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryNameFromFunction(t *testing.T) {
+	tests := []struct {
+		function string
+		expected string
+	}{
+		{"userStorage.(*UserStorage).GetUserByID", "userStorage.GetUserByID"},
+		{"teamStorage.(*TeamStorage).ApplyDeactivationPlan.func1", "teamStorage.ApplyDeactivationPlan"},
+		{"prReviewersStorage.execAssignmentEvent", "prReviewersStorage.execAssignmentEvent"},
+		{"statsStorage", "statsStorage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			assert.Equal(t, tt.expected, queryNameFromFunction(tt.function))
+		})
+	}
+}
+
+func TestDBQueryTracer(t *testing.T) {
+	tracer := NewDBQueryTracer()
+
+	t.Run("query outside storage is labelled other", func(t *testing.T) {
+		ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "select 1"})
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+
+		// Delete возвращает true, только если серия с такими метками была записана
+		assert.True(t, DBQueryDurationSeconds.DeleteLabelValues(DBQueryNameOther, dbQueryStatusError))
+		assert.False(t, DBQueryDurationSeconds.DeleteLabelValues(DBQueryNameOther, dbQueryStatusOK))
+	})
+
+	t.Run("end without start is ignored", func(t *testing.T) {
+		before := testutil.CollectAndCount(DBQueryDurationSeconds)
+
+		tracer.TraceQueryEnd(context.Background(), nil, pgx.TraceQueryEndData{})
+
+		assert.Equal(t, before, testutil.CollectAndCount(DBQueryDurationSeconds))
+	})
+}
@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HTTPRouteUnmatched - метка route для запросов, не попавших ни в один маршрут (ограничивает кардинальность)
+const HTTPRouteUnmatched = "unmatched"
+
+// HTTPRequestsTotal считает запросы по методу, шаблону маршрута и статусу ответа
+var HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "http_requests_total",
+	Help: "Total number of HTTP requests by method, route template and status code",
+}, []string{"method", "route", "status"})
+
+// HTTPRequestDurationSeconds - длительность обработки запроса в секундах
+var HTTPRequestDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "http_request_duration_seconds",
+	Help:    "HTTP request latency in seconds by method, route template and status code",
+	Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+}, []string{"method", "route", "status"})
@@ -0,0 +1,212 @@
+package metrics
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultMaxTeamLabels - сколько команд получают собственную метку, остальные попадают в "other"
+	DefaultMaxTeamLabels = 50
+
+	prLifecycleRefreshInterval = 30 * time.Second
+	prLifecycleQueryTimeout    = 5 * time.Second
+)
+
+// PRLifecycleHourBuckets - бакеты гистограмм жизненного цикла PR в часах
+var PRLifecycleHourBuckets = []float64{1, 6, 12, 24, 48, 72, 168}
+
+// PRLifecycleSource - источник агрегатов жизненного цикла PR (storage)
+type PRLifecycleSource interface {
+	GetPRLifecycleSnapshot(ctx context.Context, buckets []float64) (*domain.PRLifecycleSnapshot, error)
+}
+
+// PRLifecycleCollector публикует метрики жизненного цикла PR по командам, считая их по БД,
+// поэтому значения не теряются при рестарте. Результат запроса кешируется на prLifecycleRefreshInterval
+type PRLifecycleCollector struct {
+	source   PRLifecycleSource
+	maxTeams int
+
+	timeToMerge       *prometheus.Desc
+	timeToFirstReview *prometheus.Desc
+	reassignments     *prometheus.Desc
+	noCandidate       *prometheus.Desc
+
+	mu        sync.Mutex
+	snapshot  *domain.PRLifecycleSnapshot
+	fetchedAt time.Time
+}
+
+func NewPRLifecycleCollector(source PRLifecycleSource, maxTeams int) *PRLifecycleCollector {
+	return &PRLifecycleCollector{
+		source:   source,
+		maxTeams: maxTeams,
+		timeToMerge: prometheus.NewDesc(
+			"pr_time_to_merge_hours",
+			"Time from PR creation to merge in hours, by author's primary team",
+			[]string{"team"}, nil,
+		),
+		timeToFirstReview: prometheus.NewDesc(
+			"pr_time_to_first_review_hours",
+			"Time from PR creation to the first reviewer assignment in hours, by author's primary team",
+			[]string{"team"}, nil,
+		),
+		reassignments: prometheus.NewDesc(
+			"pr_reviewer_reassignments_total",
+			"Reviewer replacements and removals, by author's primary team and assignment strategy",
+			[]string{"team", "strategy"}, nil,
+		),
+		noCandidate: prometheus.NewDesc(
+			"pr_no_candidate_total",
+			"Reassign attempts that failed with NO_CANDIDATE, by author's primary team",
+			[]string{"team"}, nil,
+		),
+	}
+}
+
+func (c *PRLifecycleCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.timeToMerge
+	ch <- c.timeToFirstReview
+	ch <- c.reassignments
+	ch <- c.noCandidate
+}
+
+func (c *PRLifecycleCollector) Collect(ch chan<- prometheus.Metric) {
+	snapshot := c.currentSnapshot()
+	if snapshot == nil {
+		return
+	}
+
+	labels := c.teamLabels(snapshot)
+
+	for _, histogram := range mergeHistograms(snapshot.TimeToMerge, labels) {
+		ch <- prometheus.MustNewConstHistogram(c.timeToMerge,
+			histogram.Count, histogram.Sum, histogram.CumulativeCounts, histogram.TeamName)
+	}
+	for _, histogram := range mergeHistograms(snapshot.TimeToFirstReview, labels) {
+		ch <- prometheus.MustNewConstHistogram(c.timeToFirstReview,
+			histogram.Count, histogram.Sum, histogram.CumulativeCounts, histogram.TeamName)
+	}
+
+	reassignments := make(map[[2]string]uint64)
+	noCandidate := make(map[string]uint64)
+	for _, event := range snapshot.Events {
+		team := labels[event.TeamName]
+		if event.Type == domain.AssignmentEventNoCandidate {
+			noCandidate[team] += event.Count
+			continue
+		}
+		reassignments[[2]string{team, string(event.Strategy)}] += event.Count
+	}
+
+	for key, count := range reassignments {
+		ch <- prometheus.MustNewConstMetric(c.reassignments, prometheus.CounterValue, float64(count), key[0], key[1])
+	}
+	for team, count := range noCandidate {
+		ch <- prometheus.MustNewConstMetric(c.noCandidate, prometheus.CounterValue, float64(count), team)
+	}
+}
+
+// currentSnapshot возвращает закешированные агрегаты, обновляя их не чаще prLifecycleRefreshInterval.
+// При ошибке БД отдаются последние успешно полученные значения
+func (c *PRLifecycleCollector) currentSnapshot() *domain.PRLifecycleSnapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.snapshot != nil && time.Since(c.fetchedAt) < prLifecycleRefreshInterval {
+		return c.snapshot
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), prLifecycleQueryTimeout)
+	defer cancel()
+
+	snapshot, err := c.source.GetPRLifecycleSnapshot(ctx, PRLifecycleHourBuckets)
+	if err != nil {
+		logger.Logger.Error("error collecting PR lifecycle metrics: ", err)
+		return c.snapshot
+	}
+
+	c.snapshot = snapshot
+	c.fetchedAt = time.Now()
+	return snapshot
+}
+
+// teamLabels ограничивает кардинальность: собственную метку получают maxTeams команд с наибольшим
+// числом PR, остальные объединяются в MetricsTeamOther
+func (c *PRLifecycleCollector) teamLabels(snapshot *domain.PRLifecycleSnapshot) map[string]string {
+	volume := make(map[string]uint64)
+	for _, histogram := range snapshot.TimeToFirstReview {
+		volume[histogram.TeamName] += histogram.Count
+	}
+	for _, histogram := range snapshot.TimeToMerge {
+		volume[histogram.TeamName] += histogram.Count
+	}
+	for _, event := range snapshot.Events {
+		volume[event.TeamName] += event.Count
+	}
+
+	return limitLabels(volume, c.maxTeams)
+}
+
+// limitLabels оставляет собственную метку max значениям с наибольшим объёмом, остальные получают MetricsTeamOther
+func limitLabels(volume map[string]uint64, max int) map[string]string {
+	values := make([]string, 0, len(volume))
+	for value := range volume {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if volume[values[i]] != volume[values[j]] {
+			return volume[values[i]] > volume[values[j]]
+		}
+		return values[i] < values[j]
+	})
+
+	labels := make(map[string]string, len(values))
+	for i, value := range values {
+		if i < max {
+			labels[value] = value
+		} else {
+			labels[value] = domain.MetricsTeamOther
+		}
+	}
+
+	return labels
+}
+
+// mergeHistograms переименовывает команды по labels и складывает гистограммы с одинаковой меткой
+func mergeHistograms(histograms []domain.TeamHistogram, labels map[string]string) []domain.TeamHistogram {
+	byLabel := make(map[string]*domain.TeamHistogram)
+	order := make([]string, 0)
+
+	for _, histogram := range histograms {
+		label := labels[histogram.TeamName]
+		merged, ok := byLabel[label]
+		if !ok {
+			merged = &domain.TeamHistogram{
+				TeamName:         label,
+				CumulativeCounts: make(map[float64]uint64, len(histogram.CumulativeCounts)),
+			}
+			byLabel[label] = merged
+			order = append(order, label)
+		}
+
+		merged.Count += histogram.Count
+		merged.Sum += histogram.Sum
+		for upperBound, count := range histogram.CumulativeCounts {
+			merged.CumulativeCounts[upperBound] += count
+		}
+	}
+
+	result := make([]domain.TeamHistogram, 0, len(order))
+	for _, label := range order {
+		result = append(result, *byLabel[label])
+	}
+
+	return result
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger.Logger = zap.NewNop().Sugar()
+}
+
+type fakeLifecycleSource struct {
+	snapshot *domain.PRLifecycleSnapshot
+	err      error
+	calls    int
+}
+
+func (f *fakeLifecycleSource) GetPRLifecycleSnapshot(_ context.Context, _ []float64) (*domain.PRLifecycleSnapshot, error) {
+	f.calls++
+	return f.snapshot, f.err
+}
+
+func testSnapshot() *domain.PRLifecycleSnapshot {
+	counts := func(n uint64) map[float64]uint64 {
+		result := make(map[float64]uint64)
+		for _, upperBound := range PRLifecycleHourBuckets {
+			result[upperBound] = n
+		}
+		return result
+	}
+
+	return &domain.PRLifecycleSnapshot{
+		TimeToMerge: []domain.TeamHistogram{
+			{TeamName: "Backend", Count: 3, Sum: 6, CumulativeCounts: counts(3)},
+			{TeamName: "Frontend", Count: 2, Sum: 4, CumulativeCounts: counts(2)},
+			{TeamName: "Mobile", Count: 1, Sum: 2, CumulativeCounts: counts(1)},
+		},
+		Events: []domain.TeamEventCount{
+			{TeamName: "Backend", Type: domain.AssignmentEventReassigned, Strategy: domain.AssignmentStrategyReassign, Count: 4},
+			{TeamName: "Mobile", Type: domain.AssignmentEventNoCandidate, Strategy: domain.AssignmentStrategyReassign, Count: 2},
+		},
+	}
+}
+
+func TestPRLifecycleCollector(t *testing.T) {
+	t.Run("exports team labelled metrics", func(t *testing.T) {
+		source := &fakeLifecycleSource{snapshot: testSnapshot()}
+		collector := NewPRLifecycleCollector(source, DefaultMaxTeamLabels)
+
+		expected := `
+# HELP pr_reviewer_reassignments_total Reviewer replacements and removals, by author's primary team and assignment strategy
+# TYPE pr_reviewer_reassignments_total counter
+pr_reviewer_reassignments_total{strategy="REASSIGN",team="Backend"} 4
+# HELP pr_no_candidate_total Reassign attempts that failed with NO_CANDIDATE, by author's primary team
+# TYPE pr_no_candidate_total counter
+pr_no_candidate_total{team="Mobile"} 2
+`
+		err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+			"pr_reviewer_reassignments_total", "pr_no_candidate_total")
+		require.NoError(t, err)
+
+		// 3 гистограммы по командам + 2 счётчика
+		assert.Equal(t, 5, testutil.CollectAndCount(collector))
+		// второй сбор берётся из кеша
+		assert.Equal(t, 1, source.calls)
+	})
+
+	t.Run("teams over the limit are merged into other", func(t *testing.T) {
+		source := &fakeLifecycleSource{snapshot: testSnapshot()}
+		collector := NewPRLifecycleCollector(source, 1)
+
+		expected := `
+# HELP pr_no_candidate_total Reassign attempts that failed with NO_CANDIDATE, by author's primary team
+# TYPE pr_no_candidate_total counter
+pr_no_candidate_total{team="other"} 2
+`
+		err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "pr_no_candidate_total")
+		require.NoError(t, err)
+
+		// Backend и other
+		assert.Equal(t, 2, testutil.CollectAndCount(collector, "pr_time_to_merge_hours"))
+	})
+
+	t.Run("source error without cached data exports nothing", func(t *testing.T) {
+		source := &fakeLifecycleSource{err: errors.New("db error")}
+		collector := NewPRLifecycleCollector(source, DefaultMaxTeamLabels)
+
+		assert.Equal(t, 0, testutil.CollectAndCount(collector))
+	})
+}
@@ -0,0 +1,180 @@
+package metrics
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils"
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultMaxUserLabels - сколько самых загруженных ревьюверов получают собственную серию
+	DefaultMaxUserLabels = 100
+
+	reviewLoadRefreshInterval = 30 * time.Second
+	reviewLoadQueryTimeout    = 5 * time.Second
+)
+
+// ReviewLoadSource - источник текущей нагрузки ревью (storage)
+type ReviewLoadSource interface {
+	GetReviewLoadSnapshot(ctx context.Context) (*domain.ReviewLoadSnapshot, error)
+}
+
+// ReviewLoadCollector публикует gauge текущего состояния: открытые PR и PR без нужного числа ревьюверов
+// по командам, открытые ревью по пользователям и индекс справедливости нагрузки внутри команды.
+// Результат запроса кешируется на reviewLoadRefreshInterval
+type ReviewLoadCollector struct {
+	source   ReviewLoadSource
+	maxTeams int
+	maxUsers int
+
+	openPRs           *prometheus.Desc
+	needMoreReviewers *prometheus.Desc
+	userOpenReviews   *prometheus.Desc
+	loadFairness      *prometheus.Desc
+
+	mu        sync.Mutex
+	snapshot  *domain.ReviewLoadSnapshot
+	fetchedAt time.Time
+}
+
+// NewReviewLoadCollector - maxUsers <= 0 снимает ограничение на число пользователей
+func NewReviewLoadCollector(source ReviewLoadSource, maxTeams, maxUsers int) *ReviewLoadCollector {
+	return &ReviewLoadCollector{
+		source:   source,
+		maxTeams: maxTeams,
+		maxUsers: maxUsers,
+		openPRs: prometheus.NewDesc(
+			"pr_open",
+			"Number of open PRs, by author's primary team",
+			[]string{"team"}, nil,
+		),
+		needMoreReviewers: prometheus.NewDesc(
+			"pr_need_more_reviewers",
+			"Number of open PRs flagged need_more_reviewers, by author's primary team",
+			[]string{"team"}, nil,
+		),
+		userOpenReviews: prometheus.NewDesc(
+			"reviewer_open_reviews",
+			"Number of open PRs the user is assigned to review, for the most loaded active users",
+			[]string{"user"}, nil,
+		),
+		loadFairness: prometheus.NewDesc(
+			"team_review_load_fairness",
+			"Jain's fairness index of open review load across the team's active members (1 - perfectly even, 1/n - all on one member)",
+			[]string{"team"}, nil,
+		),
+	}
+}
+
+func (c *ReviewLoadCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.openPRs
+	ch <- c.needMoreReviewers
+	ch <- c.userOpenReviews
+	ch <- c.loadFairness
+}
+
+func (c *ReviewLoadCollector) Collect(ch chan<- prometheus.Metric) {
+	snapshot := c.currentSnapshot()
+	if snapshot == nil {
+		return
+	}
+
+	labels := c.teamLabels(snapshot)
+
+	openPRs := make(map[string]uint64)
+	needMore := make(map[string]uint64)
+	for _, counts := range snapshot.OpenPRs {
+		team := labels[counts.TeamName]
+		openPRs[team] += counts.Open
+		needMore[team] += counts.NeedMoreReviewers
+	}
+	for team, count := range openPRs {
+		ch <- prometheus.MustNewConstMetric(c.openPRs, prometheus.GaugeValue, float64(count), team)
+		ch <- prometheus.MustNewConstMetric(c.needMoreReviewers, prometheus.GaugeValue, float64(needMore[team]), team)
+	}
+
+	// Индекс имеет смысл только внутри одной команды, поэтому для "other" он не публикуется
+	teamLoads := make(map[string][]uint64)
+	for _, load := range snapshot.MemberLoads {
+		if labels[load.TeamName] == domain.MetricsTeamOther {
+			continue
+		}
+		teamLoads[load.TeamName] = append(teamLoads[load.TeamName], load.OpenReviews)
+	}
+	for team, loads := range teamLoads {
+		ch <- prometheus.MustNewConstMetric(c.loadFairness, prometheus.GaugeValue, utils.JainFairnessIndex(loads), team)
+	}
+
+	for _, load := range c.topUserLoads(snapshot) {
+		ch <- prometheus.MustNewConstMetric(c.userOpenReviews, prometheus.GaugeValue, float64(load.OpenReviews), load.UserID)
+	}
+}
+
+// currentSnapshot возвращает закешированную нагрузку, обновляя её не чаще reviewLoadRefreshInterval.
+// При ошибке БД отдаются последние успешно полученные значения
+func (c *ReviewLoadCollector) currentSnapshot() *domain.ReviewLoadSnapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.snapshot != nil && time.Since(c.fetchedAt) < reviewLoadRefreshInterval {
+		return c.snapshot
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), reviewLoadQueryTimeout)
+	defer cancel()
+
+	snapshot, err := c.source.GetReviewLoadSnapshot(ctx)
+	if err != nil {
+		logger.Logger.Error("error collecting review load metrics: ", err)
+		return c.snapshot
+	}
+
+	c.snapshot = snapshot
+	c.fetchedAt = time.Now()
+	return snapshot
+}
+
+// teamLabels ранжирует команды по числу открытых PR и активных участников
+func (c *ReviewLoadCollector) teamLabels(snapshot *domain.ReviewLoadSnapshot) map[string]string {
+	volume := make(map[string]uint64)
+	for _, counts := range snapshot.OpenPRs {
+		volume[counts.TeamName] += counts.Open
+	}
+	for _, load := range snapshot.MemberLoads {
+		volume[load.TeamName]++
+	}
+
+	return limitLabels(volume, c.maxTeams)
+}
+
+// topUserLoads убирает дубли пользователей из нескольких команд и оставляет maxUsers самых загруженных
+func (c *ReviewLoadCollector) topUserLoads(snapshot *domain.ReviewLoadSnapshot) []domain.TeamMemberLoad {
+	seen := make(map[string]struct{}, len(snapshot.MemberLoads))
+	loads := make([]domain.TeamMemberLoad, 0, len(snapshot.MemberLoads))
+	for _, load := range snapshot.MemberLoads {
+		if _, ok := seen[load.UserID]; ok {
+			continue
+		}
+		seen[load.UserID] = struct{}{}
+		loads = append(loads, load)
+	}
+
+	sort.Slice(loads, func(i, j int) bool {
+		if loads[i].OpenReviews != loads[j].OpenReviews {
+			return loads[i].OpenReviews > loads[j].OpenReviews
+		}
+		return loads[i].UserID < loads[j].UserID
+	})
+
+	if c.maxUsers > 0 && len(loads) > c.maxUsers {
+		loads = loads[:c.maxUsers]
+	}
+
+	return loads
+}
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReviewLoadSource struct {
+	snapshot *domain.ReviewLoadSnapshot
+	err      error
+	calls    int
+}
+
+func (f *fakeReviewLoadSource) GetReviewLoadSnapshot(_ context.Context) (*domain.ReviewLoadSnapshot, error) {
+	f.calls++
+	return f.snapshot, f.err
+}
+
+func testReviewLoadSnapshot() *domain.ReviewLoadSnapshot {
+	return &domain.ReviewLoadSnapshot{
+		OpenPRs: []domain.TeamOpenPRCounts{
+			{TeamName: "Backend", Open: 5, NeedMoreReviewers: 2},
+			{TeamName: "Frontend", Open: 1, NeedMoreReviewers: 0},
+		},
+		MemberLoads: []domain.TeamMemberLoad{
+			{TeamName: "Backend", UserID: "u1", OpenReviews: 4},
+			{TeamName: "Backend", UserID: "u2", OpenReviews: 0},
+			{TeamName: "Frontend", UserID: "u2", OpenReviews: 0},
+			{TeamName: "Frontend", UserID: "u3", OpenReviews: 1},
+		},
+	}
+}
+
+func TestReviewLoadCollector(t *testing.T) {
+	t.Run("exports team and user gauges", func(t *testing.T) {
+		source := &fakeReviewLoadSource{snapshot: testReviewLoadSnapshot()}
+		collector := NewReviewLoadCollector(source, DefaultMaxTeamLabels, DefaultMaxUserLabels)
+
+		expected := `
+# HELP pr_need_more_reviewers Number of open PRs flagged need_more_reviewers, by author's primary team
+# TYPE pr_need_more_reviewers gauge
+pr_need_more_reviewers{team="Backend"} 2
+pr_need_more_reviewers{team="Frontend"} 0
+# HELP pr_open Number of open PRs, by author's primary team
+# TYPE pr_open gauge
+pr_open{team="Backend"} 5
+pr_open{team="Frontend"} 1
+# HELP reviewer_open_reviews Number of open PRs the user is assigned to review, for the most loaded active users
+# TYPE reviewer_open_reviews gauge
+reviewer_open_reviews{user="u1"} 4
+reviewer_open_reviews{user="u2"} 0
+reviewer_open_reviews{user="u3"} 1
+# HELP team_review_load_fairness Jain's fairness index of open review load across the team's active members (1 - perfectly even, 1/n - all on one member)
+# TYPE team_review_load_fairness gauge
+team_review_load_fairness{team="Backend"} 0.5
+team_review_load_fairness{team="Frontend"} 0.5
+`
+		require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+	})
+
+	t.Run("limits users and teams", func(t *testing.T) {
+		source := &fakeReviewLoadSource{snapshot: testReviewLoadSnapshot()}
+		collector := NewReviewLoadCollector(source, 1, 1)
+
+		expected := `
+# HELP pr_open Number of open PRs, by author's primary team
+# TYPE pr_open gauge
+pr_open{team="Backend"} 5
+pr_open{team="other"} 1
+# HELP reviewer_open_reviews Number of open PRs the user is assigned to review, for the most loaded active users
+# TYPE reviewer_open_reviews gauge
+reviewer_open_reviews{user="u1"} 4
+# HELP team_review_load_fairness Jain's fairness index of open review load across the team's active members (1 - perfectly even, 1/n - all on one member)
+# TYPE team_review_load_fairness gauge
+team_review_load_fairness{team="Backend"} 0.5
+`
+		require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+			"pr_open", "reviewer_open_reviews", "team_review_load_fairness"))
+	})
+
+	t.Run("caches snapshot and keeps last values on error", func(t *testing.T) {
+		source := &fakeReviewLoadSource{snapshot: testReviewLoadSnapshot()}
+		collector := NewReviewLoadCollector(source, DefaultMaxTeamLabels, 0)
+
+		first := testutil.CollectAndCount(collector)
+		second := testutil.CollectAndCount(collector)
+
+		assert.Equal(t, first, second)
+		assert.Equal(t, 1, source.calls)
+
+		failing := &fakeReviewLoadSource{err: errors.New("db error")}
+		assert.Equal(t, 0, testutil.CollectAndCount(NewReviewLoadCollector(failing, DefaultMaxTeamLabels, 0)))
+	})
+}
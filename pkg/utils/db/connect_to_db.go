@@ -5,11 +5,21 @@ import (
 	"os"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nedokyrill/avito-pr-api/pkg/metrics"
 	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
 )
 
 func Connect(ctx context.Context) (*pgxpool.Pool, error) {
-	conn, err := pgxpool.New(ctx, os.Getenv("DB_URL"))
+	config, err := pgxpool.ParseConfig(os.Getenv("DB_URL"))
+	if err != nil {
+		logger.Logger.Errorf("unable to parse database config: %v\n", err)
+		return nil, err
+	}
+
+	// Трейсер пишет латентность запросов по storage-методам в db_query_duration_seconds
+	config.ConnConfig.Tracer = metrics.NewDBQueryTracer()
+
+	conn, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		logger.Logger.Errorf("unable to connect to database: %v\n", err)
 		return nil, err
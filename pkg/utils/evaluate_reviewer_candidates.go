@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+)
+
+// EvaluateReviewerCandidates раскладывает участников команд (команда автора и её предки, как для
+// RandSelectReviewersWithFallback) на кандидатов и исключённых с причиной.
+// Пользователь, состоящий в нескольких командах иерархии, учитывается один раз - на нижнем уровне.
+// exclude - дополнительно исключаемые пользователи с причиной исключения
+func EvaluateReviewerCandidates(
+	teams []domain.Team,
+	authorID string,
+	exclude map[string]domain.ExclusionReason,
+) ([]domain.ReviewerCandidate, []domain.ExcludedReviewer) {
+	candidates := make([]domain.ReviewerCandidate, 0)
+	excluded := make([]domain.ExcludedReviewer, 0)
+	seen := make(map[string]struct{})
+
+	for level, team := range teams {
+		for _, member := range team.Members {
+			if _, ok := seen[member.UserId]; ok {
+				continue
+			}
+			seen[member.UserId] = struct{}{}
+
+			reason, excludedByCaller := exclude[member.UserId]
+			switch {
+			case member.UserId == authorID:
+				reason = domain.ExclusionReasonAuthor
+			case excludedByCaller:
+			case !member.IsActive:
+				reason = domain.ExclusionReasonInactive
+			}
+
+			if reason != "" {
+				excluded = append(excluded, domain.ExcludedReviewer{
+					UserID:   member.UserId,
+					Username: member.Username,
+					TeamName: team.TeamName,
+					Reason:   reason,
+				})
+				continue
+			}
+
+			candidates = append(candidates, domain.ReviewerCandidate{
+				UserID:   member.UserId,
+				Username: member.Username,
+				TeamName: team.TeamName,
+				Level:    level,
+			})
+		}
+	}
+
+	return candidates, excluded
+}
+
+// ExcludeWithReason строит exclude для EvaluateReviewerCandidates из списка пользователей с одной причиной
+func ExcludeWithReason(userIDs []string, reason domain.ExclusionReason) map[string]domain.ExclusionReason {
+	exclude := make(map[string]domain.ExclusionReason, len(userIDs))
+	for _, userID := range userIDs {
+		exclude[userID] = reason
+	}
+	return exclude
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateReviewerCandidates(t *testing.T) {
+	t.Run("classifies members with exclusion reasons", func(t *testing.T) {
+		teams := []domain.Team{
+			{
+				TeamName: "squad",
+				Members: []domain.TeamMember{
+					{UserId: testUser1, Username: "Alice", IsActive: true},
+					{UserId: testUser2, Username: "Bob", IsActive: false},
+					{UserId: testUser3, Username: "Charlie", IsActive: true},
+					{UserId: testUser4, Username: "David", IsActive: true},
+				},
+			},
+		}
+
+		candidates, excluded := EvaluateReviewerCandidates(teams, testUser1,
+			ExcludeWithReason([]string{testUser4}, domain.ExclusionReasonExcluded))
+
+		require.Len(t, candidates, 1)
+		assert.Equal(t, testUser3, candidates[0].UserID)
+		assert.Equal(t, 0, candidates[0].Level)
+
+		assert.Equal(t, []domain.ExcludedReviewer{
+			{UserID: testUser1, Username: "Alice", TeamName: "squad", Reason: domain.ExclusionReasonAuthor},
+			{UserID: testUser2, Username: "Bob", TeamName: "squad", Reason: domain.ExclusionReasonInactive},
+			{UserID: testUser4, Username: "David", TeamName: "squad", Reason: domain.ExclusionReasonExcluded},
+		}, excluded)
+	})
+
+	t.Run("ancestor levels and members of several teams", func(t *testing.T) {
+		teams := []domain.Team{
+			{
+				TeamName: "squad",
+				Members: []domain.TeamMember{
+					{UserId: testUser1, IsActive: true},
+					{UserId: testUser2, IsActive: true},
+				},
+			},
+			{
+				TeamName: "guild",
+				Members: []domain.TeamMember{
+					{UserId: testUser2, IsActive: true},
+					{UserId: testUser5, IsActive: true},
+				},
+			},
+		}
+
+		candidates, excluded := EvaluateReviewerCandidates(teams, testUser1, nil)
+
+		require.Len(t, candidates, 2)
+		assert.Equal(t, domain.ReviewerCandidate{UserID: testUser2, TeamName: "squad", Level: 0}, candidates[0])
+		assert.Equal(t, domain.ReviewerCandidate{UserID: testUser5, TeamName: "guild", Level: 1}, candidates[1])
+		require.Len(t, excluded, 1)
+		assert.Equal(t, domain.ExclusionReasonAuthor, excluded[0].Reason)
+	})
+}
@@ -0,0 +1,16 @@
+package utils
+
+import "time"
+
+// ExponentialBackoff - задержка перед повтором после неудачной попытки attempt (с единицы):
+// base * 2^(attempt-1), но не больше maxDelay
+func ExponentialBackoff(base, maxDelay time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempt  int
+		expected time.Duration
+	}{
+		{name: "first attempt", attempt: 1, expected: time.Second},
+		{name: "doubles", attempt: 3, expected: 4 * time.Second},
+		{name: "capped by max", attempt: 5, expected: 10 * time.Second},
+		{name: "large attempt does not overflow", attempt: 100, expected: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExponentialBackoff(time.Second, 10*time.Second, tt.attempt))
+		})
+	}
+}
@@ -5,11 +5,15 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/nedokyrill/avito-pr-api/internal/middleware"
 )
 
 func InitRouter() *gin.Engine {
 	router := gin.New()
 
+	// Access log и метрики снаружи Recovery, чтобы запросы с паникой тоже попадали в них со статусом 500
+	router.Use(middleware.AccessLogMiddleware("/metrics"))
+	router.Use(middleware.HTTPMetricsMiddleware())
 	router.Use(gin.Recovery())
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
@@ -0,0 +1,18 @@
+package utils
+
+// JainFairnessIndex - индекс справедливости Джейна (Σx)² / (n·Σx²): 1 при равной нагрузке,
+// 1/n когда вся нагрузка у одного. Пустой набор и нулевая нагрузка считаются справедливыми
+func JainFairnessIndex(loads []uint64) float64 {
+	var sum, sumSquares float64
+	for _, load := range loads {
+		x := float64(load)
+		sum += x
+		sumSquares += x * x
+	}
+
+	if sumSquares == 0 {
+		return 1
+	}
+
+	return sum * sum / (float64(len(loads)) * sumSquares)
+}
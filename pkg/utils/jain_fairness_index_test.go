@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJainFairnessIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		loads    []uint64
+		expected float64
+	}{
+		{name: "empty", loads: nil, expected: 1},
+		{name: "no load", loads: []uint64{0, 0, 0}, expected: 1},
+		{name: "equal load", loads: []uint64{3, 3, 3}, expected: 1},
+		{name: "all load on one member", loads: []uint64{5, 0, 0, 0}, expected: 0.25},
+		{name: "skewed load", loads: []uint64{1, 3}, expected: 0.8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, JainFairnessIndex(tt.loads), 1e-9)
+		})
+	}
+}
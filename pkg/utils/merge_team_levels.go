@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+)
+
+// MergeTeamLevels сводит цепочки команд (команда и её предки, как из GetTeamWithAncestors) в один список
+// уровней для RandSelectReviewersWithFallback: на i-м уровне - участники всех команд i-го уровня цепочек.
+// Команда, уже попавшая на более низкий уровень, повторно не учитывается, участник внутри уровня - тоже.
+// Имя уровня - имена его команд через запятую
+func MergeTeamLevels(chains [][]domain.Team) []domain.Team {
+	levels := make([]domain.Team, 0)
+	seenTeams := make(map[string]struct{})
+
+	for depth := 0; ; depth++ {
+		var names []string
+		var members []domain.TeamMember
+		seenMembers := make(map[string]struct{})
+		found := false
+
+		for _, chain := range chains {
+			if depth >= len(chain) {
+				continue
+			}
+			found = true
+
+			team := chain[depth]
+			if _, ok := seenTeams[team.TeamName]; ok {
+				continue
+			}
+			seenTeams[team.TeamName] = struct{}{}
+			names = append(names, team.TeamName)
+
+			for _, member := range team.Members {
+				if _, ok := seenMembers[member.UserId]; ok {
+					continue
+				}
+				seenMembers[member.UserId] = struct{}{}
+				members = append(members, member)
+			}
+		}
+
+		if !found {
+			return levels
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		levels = append(levels, domain.Team{
+			TeamName: strings.Join(names, ", "),
+			Members:  members,
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeTeamLevels(t *testing.T) {
+	t.Run("single chain is returned as is", func(t *testing.T) {
+		chain := []domain.Team{
+			{TeamName: "Payments", Members: []domain.TeamMember{{UserId: testUser1}}},
+			{TeamName: "Backend", Members: []domain.TeamMember{{UserId: testUser2}}},
+		}
+
+		levels := MergeTeamLevels([][]domain.Team{chain})
+
+		require.Len(t, levels, 2)
+		assert.Equal(t, "Payments", levels[0].TeamName)
+		assert.Equal(t, "Backend", levels[1].TeamName)
+	})
+
+	t.Run("teams of the same level are merged", func(t *testing.T) {
+		chains := [][]domain.Team{
+			{
+				{TeamName: "Backend", Members: []domain.TeamMember{{UserId: testUser1}, {UserId: testUser2}}},
+				{TeamName: "Engineering", Members: []domain.TeamMember{{UserId: testUser5}}},
+			},
+			{
+				{TeamName: "Mobile", Members: []domain.TeamMember{{UserId: testUser1}, {UserId: testUser3}}},
+			},
+		}
+
+		levels := MergeTeamLevels(chains)
+
+		require.Len(t, levels, 2)
+		assert.Equal(t, "Backend, Mobile", levels[0].TeamName)
+		assert.Equal(t, []domain.TeamMember{{UserId: testUser1}, {UserId: testUser2}, {UserId: testUser3}}, levels[0].Members)
+		assert.Equal(t, "Engineering", levels[1].TeamName)
+	})
+
+	t.Run("shared ancestor is counted once on the lowest level", func(t *testing.T) {
+		chains := [][]domain.Team{
+			{
+				{TeamName: "Payments", Members: []domain.TeamMember{{UserId: testUser1}}},
+				{TeamName: "Backend", Members: []domain.TeamMember{{UserId: testUser2}}},
+			},
+			{
+				{TeamName: "Backend", Members: []domain.TeamMember{{UserId: testUser2}}},
+			},
+		}
+
+		levels := MergeTeamLevels(chains)
+
+		require.Len(t, levels, 1)
+		assert.Equal(t, "Payments, Backend", levels[0].TeamName)
+	})
+
+	t.Run("no chains", func(t *testing.T) {
+		assert.Empty(t, MergeTeamLevels(nil))
+	})
+}
@@ -0,0 +1,11 @@
+package utils
+
+import "time"
+
+// ParseTimeParam разбирает время из query-параметра: RFC3339 или YYYY-MM-DD (полночь UTC)
+func ParseTimeParam(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, raw)
+}
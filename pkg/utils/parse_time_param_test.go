@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeParam(t *testing.T) {
+	t.Run("RFC3339", func(t *testing.T) {
+		parsed, err := ParseTimeParam("2025-11-24T10:30:00+03:00")
+		require.NoError(t, err)
+		assert.True(t, parsed.Equal(time.Date(2025, 11, 24, 7, 30, 0, 0, time.UTC)))
+	})
+
+	t.Run("date only", func(t *testing.T) {
+		parsed, err := ParseTimeParam("2025-11-24")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC), parsed)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseTimeParam("24.11.2025")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+)
+
+// PollBatches вызывает processBatch раз в interval до отмены ctx. processBatch обрабатывает одну пачку
+// и возвращает её размер: пока пачки полные (batchSize), следующая забирается без паузы.
+// name - имя воркера в логах ("chat notifier" и т.п.)
+func PollBatches(
+	ctx context.Context,
+	name string,
+	interval time.Duration,
+	batchSize int,
+	processBatch func(ctx context.Context) (int, error),
+) {
+	logger.Logger.Infow(name+" started", "poll_interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			claimed, err := processBatch(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Logger.Errorw(name+" batch failed", "error", err)
+				}
+				break
+			}
+			if claimed < batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Logger.Info(name + " stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/pkg/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestPollBatches(t *testing.T) {
+	logger.Logger = zap.NewNop().Sugar()
+
+	t.Run("full batches are taken without pause until a short one", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sizes := []int{2, 2, 1}
+		calls := 0
+		PollBatches(ctx, "test worker", time.Hour, 2, func(context.Context) (int, error) {
+			calls++
+			if calls == len(sizes) {
+				cancel()
+			}
+			return sizes[calls-1], nil
+		})
+
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("error ends the round until next tick", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		calls := 0
+		PollBatches(ctx, "test worker", time.Millisecond, 2, func(context.Context) (int, error) {
+			calls++
+			if calls == 2 {
+				cancel()
+				return 0, nil
+			}
+			return 2, errors.New("db error")
+		})
+
+		assert.Equal(t, 2, calls)
+	})
+}
@@ -7,7 +7,24 @@ import (
 	"github.com/nedokyrill/avito-pr-api/internal/domain"
 )
 
+// NewSeed возвращает seed для случайного выбора ревьюеров. Seed сохраняется в метаданных назначения,
+// чтобы выбор можно было воспроизвести
+func NewSeed() int64 {
+	return time.Now().UnixNano()
+}
+
 func RandSelectReviewers(members []domain.TeamMember, authorID string, maxCount int) []string {
+	return RandSelectReviewersWithSeed(members, authorID, maxCount, NewSeed())
+}
+
+// RandSelectReviewersWithSeed - RandSelectReviewers с заданным seed: при одинаковых участниках и seed
+// результат одинаковый
+func RandSelectReviewersWithSeed(members []domain.TeamMember, authorID string, maxCount int, seed int64) []string {
+	//nolint:gosec // G404: math/rand достаточно для случайного выбора ревьюеров
+	return randSelectReviewers(members, authorID, maxCount, rand.New(rand.NewSource(seed)))
+}
+
+func randSelectReviewers(members []domain.TeamMember, authorID string, maxCount int, rng *rand.Rand) []string {
 	if maxCount <= 0 {
 		return []string{}
 	}
@@ -23,11 +40,46 @@ func RandSelectReviewers(members []domain.TeamMember, authorID string, maxCount
 		return candidates
 	}
 
-	//nolint:gosec // G404: math/rand достаточно для случайного выбора ревьюеров
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 	rng.Shuffle(len(candidates), func(i, j int) {
 		candidates[i], candidates[j] = candidates[j], candidates[i]
 	})
 
 	return candidates[:maxCount]
 }
+
+// RandSelectReviewersWithFallback выбирает ревьюеров из первой команды списка и поднимается
+// к следующим (родительским) командам только если на текущем уровне не удалось заполнить все слоты.
+// Пользователи из exclude и уже выбранные на предыдущих уровнях повторно не выбираются
+func RandSelectReviewersWithFallback(teams []domain.Team, authorID string, exclude []string, maxCount int) []string {
+	return RandSelectReviewersWithFallbackSeed(teams, authorID, exclude, maxCount, NewSeed())
+}
+
+// RandSelectReviewersWithFallbackSeed - RandSelectReviewersWithFallback с заданным seed
+func RandSelectReviewersWithFallbackSeed(
+	teams []domain.Team,
+	authorID string,
+	exclude []string,
+	maxCount int,
+	seed int64,
+) []string {
+	//nolint:gosec // G404: math/rand достаточно для случайного выбора ревьюеров
+	rng := rand.New(rand.NewSource(seed))
+	selected := make([]string, 0)
+
+	for _, team := range teams {
+		if len(selected) >= maxCount {
+			break
+		}
+
+		members := make([]domain.TeamMember, 0, len(team.Members))
+		for _, member := range team.Members {
+			if !Contains(exclude, member.UserId) && !Contains(selected, member.UserId) {
+				members = append(members, member)
+			}
+		}
+
+		selected = append(selected, randSelectReviewers(members, authorID, maxCount-len(selected), rng)...)
+	}
+
+	return selected
+}
@@ -263,3 +263,148 @@ func TestRandSelectReviewers(t *testing.T) {
 		assert.GreaterOrEqual(t, len(results), 2, "Multiple calls should produce varied results due to randomness")
 	})
 }
+
+func TestRandSelectReviewersWithFallback(t *testing.T) {
+	t.Run("own team fills all slots - parent not used", func(t *testing.T) {
+		teams := []domain.Team{
+			{TeamName: "squad", Members: []domain.TeamMember{
+				{UserId: testUser1, IsActive: true},
+				{UserId: testUser2, IsActive: true},
+				{UserId: testUser3, IsActive: true},
+			}},
+			{TeamName: "guild", Members: []domain.TeamMember{
+				{UserId: testUser4, IsActive: true},
+				{UserId: testUser5, IsActive: true},
+			}},
+		}
+
+		result := RandSelectReviewersWithFallback(teams, testUser1, nil, 2)
+
+		require.Len(t, result, 2)
+		for _, reviewer := range result {
+			assert.Contains(t, []string{testUser2, testUser3}, reviewer)
+		}
+	})
+
+	t.Run("escalates to parent when squad cannot fill slots", func(t *testing.T) {
+		teams := []domain.Team{
+			{TeamName: "squad", Members: []domain.TeamMember{
+				{UserId: testUser1, IsActive: true},
+				{UserId: testUser2, IsActive: true},
+				{UserId: testUser3, IsActive: false},
+			}},
+			{TeamName: "guild", Members: []domain.TeamMember{
+				{UserId: testUser4, IsActive: true},
+			}},
+		}
+
+		result := RandSelectReviewersWithFallback(teams, testUser1, nil, 2)
+
+		require.Len(t, result, 2)
+		assert.Equal(t, testUser2, result[0], "Squad member should be selected first")
+		assert.Equal(t, testUser4, result[1])
+	})
+
+	t.Run("escalates through several levels", func(t *testing.T) {
+		teams := []domain.Team{
+			{TeamName: "squad", Members: []domain.TeamMember{
+				{UserId: testUser1, IsActive: true},
+			}},
+			{TeamName: "guild", Members: []domain.TeamMember{
+				{UserId: testUser2, IsActive: false},
+			}},
+			{TeamName: "department", Members: []domain.TeamMember{
+				{UserId: testUser3, IsActive: true},
+				{UserId: testUser4, IsActive: true},
+			}},
+		}
+
+		result := RandSelectReviewersWithFallback(teams, testUser1, nil, 2)
+
+		require.Len(t, result, 2)
+		assert.ElementsMatch(t, []string{testUser3, testUser4}, result)
+	})
+
+	t.Run("respects exclude list and does not duplicate members", func(t *testing.T) {
+		teams := []domain.Team{
+			{TeamName: "squad", Members: []domain.TeamMember{
+				{UserId: testUser1, IsActive: true},
+				{UserId: testUser2, IsActive: true},
+			}},
+			{TeamName: "guild", Members: []domain.TeamMember{
+				{UserId: testUser2, IsActive: true},
+				{UserId: testUser3, IsActive: true},
+			}},
+		}
+
+		result := RandSelectReviewersWithFallback(teams, testUser5, []string{testUser1}, 2)
+
+		require.Len(t, result, 2)
+		assert.Equal(t, []string{testUser2, testUser3}, result)
+	})
+
+	t.Run("not enough candidates in whole hierarchy", func(t *testing.T) {
+		teams := []domain.Team{
+			{TeamName: "squad", Members: []domain.TeamMember{
+				{UserId: testUser1, IsActive: true},
+			}},
+			{TeamName: "guild", Members: []domain.TeamMember{
+				{UserId: testUser2, IsActive: true},
+			}},
+		}
+
+		result := RandSelectReviewersWithFallback(teams, testUser1, nil, 2)
+
+		assert.Equal(t, []string{testUser2}, result)
+	})
+
+	t.Run("empty teams list", func(t *testing.T) {
+		result := RandSelectReviewersWithFallback(nil, testUser1, nil, 2)
+
+		assert.Empty(t, result)
+	})
+
+	t.Run("maxCount is zero", func(t *testing.T) {
+		teams := []domain.Team{
+			{TeamName: "squad", Members: []domain.TeamMember{
+				{UserId: testUser2, IsActive: true},
+			}},
+		}
+
+		result := RandSelectReviewersWithFallback(teams, testUser1, nil, 0)
+
+		assert.Empty(t, result)
+	})
+}
+
+func TestRandSelectReviewersWithSeed(t *testing.T) {
+	members := []domain.TeamMember{
+		{UserId: testUser1, IsActive: true},
+		{UserId: testUser2, IsActive: true},
+		{UserId: testUser3, IsActive: true},
+		{UserId: testUser4, IsActive: true},
+		{UserId: testUser5, IsActive: true},
+	}
+
+	t.Run("same seed gives same selection", func(t *testing.T) {
+		first := RandSelectReviewersWithSeed(members, testUser1, 2, 42)
+		second := RandSelectReviewersWithSeed(members, testUser1, 2, 42)
+
+		require.Len(t, first, 2)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("fallback selection is reproducible", func(t *testing.T) {
+		teams := []domain.Team{
+			{TeamName: "squad", Members: members[:2]},
+			{TeamName: "guild", Members: members},
+		}
+
+		first := RandSelectReviewersWithFallbackSeed(teams, testUser1, nil, 2, 7)
+		second := RandSelectReviewersWithFallbackSeed(teams, testUser1, nil, 2, 7)
+
+		require.Len(t, first, 2)
+		assert.Equal(t, testUser2, first[0])
+		assert.Equal(t, first, second)
+	})
+}
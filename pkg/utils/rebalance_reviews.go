@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"sort"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+)
+
+// RebalanceReviews переносит открытые ревью с самых загруженных доноров на получателей, пока разница
+// в нагрузке между ними больше одного ревью. Получателю не переносится PR, автором или ревьювером
+// которого он уже является. Нагрузка считается по reviews, остальные назначения не учитываются
+func RebalanceReviews(reviews []domain.OpenReview, donors, receivers []string) []domain.ReviewerReassignment {
+	reassignments := make([]domain.ReviewerReassignment, 0)
+	if len(donors) == 0 || len(receivers) == 0 {
+		return reassignments
+	}
+
+	load := make(map[string]int, len(donors)+len(receivers))
+	for _, userID := range donors {
+		load[userID] = 0
+	}
+	for _, userID := range receivers {
+		load[userID] = 0
+	}
+
+	byReviewer := make(map[string][]domain.OpenReview)
+	prReviewers := make(map[string]map[string]struct{})
+	for _, review := range reviews {
+		if prReviewers[review.PrID] == nil {
+			prReviewers[review.PrID] = make(map[string]struct{})
+		}
+		prReviewers[review.PrID][review.ReviewerID] = struct{}{}
+
+		if _, ok := load[review.ReviewerID]; ok {
+			load[review.ReviewerID]++
+			byReviewer[review.ReviewerID] = append(byReviewer[review.ReviewerID], review)
+		}
+	}
+
+	donors = append([]string{}, donors...)
+	receivers = append([]string{}, receivers...)
+
+	// Каждый перенос уменьшает разброс нагрузки, поэтому цикл конечен
+	for {
+		sort.Slice(donors, func(i, j int) bool {
+			if load[donors[i]] != load[donors[j]] {
+				return load[donors[i]] > load[donors[j]]
+			}
+			return donors[i] < donors[j]
+		})
+		sort.Slice(receivers, func(i, j int) bool {
+			if load[receivers[i]] != load[receivers[j]] {
+				return load[receivers[i]] < load[receivers[j]]
+			}
+			return receivers[i] < receivers[j]
+		})
+
+		moved := false
+	search:
+		for _, receiver := range receivers {
+			for _, donor := range donors {
+				if load[donor]-load[receiver] <= 1 {
+					break
+				}
+
+				for i, review := range byReviewer[donor] {
+					if review.AuthorID == receiver {
+						continue
+					}
+					if _, ok := prReviewers[review.PrID][receiver]; ok {
+						continue
+					}
+
+					byReviewer[donor] = append(byReviewer[donor][:i:i], byReviewer[donor][i+1:]...)
+					review.ReviewerID = receiver
+					byReviewer[receiver] = append(byReviewer[receiver], review)
+					delete(prReviewers[review.PrID], donor)
+					prReviewers[review.PrID][receiver] = struct{}{}
+					load[donor]--
+					load[receiver]++
+
+					reassignments = append(reassignments, domain.ReviewerReassignment{
+						PrID:          review.PrID,
+						OldReviewerID: donor,
+						NewReviewerID: receiver,
+					})
+					moved = true
+					break search
+				}
+			}
+		}
+
+		if !moved {
+			return reassignments
+		}
+	}
+}
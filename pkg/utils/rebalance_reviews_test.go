@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebalanceReviews(t *testing.T) {
+	t.Run("moves reviews from most loaded donor", func(t *testing.T) {
+		reviews := []domain.OpenReview{
+			{PrID: "pr-1", AuthorID: "author", ReviewerID: "busy"},
+			{PrID: "pr-2", AuthorID: "author", ReviewerID: "busy"},
+			{PrID: "pr-3", AuthorID: "author", ReviewerID: "busy"},
+			{PrID: "pr-4", AuthorID: "author", ReviewerID: "busy"},
+			{PrID: "pr-5", AuthorID: "author", ReviewerID: "calm"},
+		}
+
+		result := RebalanceReviews(reviews, []string{"busy", "calm"}, []string{"returning"})
+
+		require.Len(t, result, 2)
+		for _, r := range result {
+			assert.Equal(t, "busy", r.OldReviewerID)
+			assert.Equal(t, "returning", r.NewReviewerID)
+		}
+	})
+
+	t.Run("does not move own PR or PR already reviewed", func(t *testing.T) {
+		reviews := []domain.OpenReview{
+			{PrID: "pr-1", AuthorID: "returning", ReviewerID: "busy"},
+			{PrID: "pr-2", AuthorID: "author", ReviewerID: "busy"},
+			{PrID: "pr-2", AuthorID: "author", ReviewerID: "returning"},
+			{PrID: "pr-3", AuthorID: "author", ReviewerID: "busy"},
+		}
+
+		result := RebalanceReviews(reviews, []string{"busy"}, []string{"returning"})
+
+		require.Len(t, result, 1)
+		assert.Equal(t, "pr-3", result[0].PrID)
+	})
+
+	t.Run("spreads between several receivers", func(t *testing.T) {
+		var reviews []domain.OpenReview
+		for _, prID := range []string{"pr-1", "pr-2", "pr-3", "pr-4", "pr-5", "pr-6"} {
+			reviews = append(reviews, domain.OpenReview{PrID: prID, AuthorID: "author", ReviewerID: "busy"})
+		}
+
+		result := RebalanceReviews(reviews, []string{"busy"}, []string{"r1", "r2"})
+
+		require.Len(t, result, 4)
+		perReceiver := map[string]int{}
+		for _, r := range result {
+			perReceiver[r.NewReviewerID]++
+		}
+		assert.Equal(t, map[string]int{"r1": 2, "r2": 2}, perReceiver)
+	})
+
+	t.Run("balanced load is left as is", func(t *testing.T) {
+		reviews := []domain.OpenReview{{PrID: "pr-1", AuthorID: "author", ReviewerID: "busy"}}
+
+		result := RebalanceReviews(reviews, []string{"busy"}, []string{"returning"})
+
+		assert.Empty(t, result)
+	})
+
+	t.Run("no donors", func(t *testing.T) {
+		result := RebalanceReviews(nil, nil, []string{"returning"})
+
+		assert.Empty(t, result)
+	})
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+)
+
+const digestTimeLayout = "2006-01-02 15:04"
+
+// RenderTeamDigestMarkdown рендерит дайджест в Markdown. Возраст открытых PR считается на момент digest.To,
+// поэтому результат не зависит от времени рендера
+func RenderTeamDigestMarkdown(digest *domain.TeamDigest) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Review digest: %s\n\n", escapeMarkdownCell(digest.TeamName))
+	fmt.Fprintf(&b, "Period: %s — %s (UTC)\n\n",
+		digest.From.UTC().Format(digestTimeLayout), digest.To.UTC().Format(digestTimeLayout))
+
+	b.WriteString("## Summary\n\n")
+	fmt.Fprintf(&b, "- Opened: %d\n", digest.Opened)
+	fmt.Fprintf(&b, "- Merged: %d\n", digest.Merged)
+	if digest.AvgHoursToMerge != nil {
+		fmt.Fprintf(&b, "- Average time to merge: %.1f h\n", *digest.AvgHoursToMerge)
+	} else {
+		b.WriteString("- Average time to merge: —\n")
+	}
+	fmt.Fprintf(&b, "- Closed without merge: %d\n", digest.Closed)
+
+	b.WriteString("\n## Top reviewers\n\n")
+	if len(digest.TopReviewers) == 0 {
+		b.WriteString("No PRs were merged in this period.\n")
+	} else {
+		b.WriteString("| Reviewer | Merged PRs reviewed |\n|---|---|\n")
+		for _, reviewer := range digest.TopReviewers {
+			fmt.Fprintf(&b, "| %s (%s) | %d |\n",
+				escapeMarkdownCell(reviewer.Username), escapeMarkdownCell(reviewer.UserID), reviewer.Reviews)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n## Waiting for review (%d)\n\n", len(digest.Waiting))
+	if len(digest.Waiting) == 0 {
+		b.WriteString("No open PRs.\n")
+	} else {
+		b.WriteString("| PR | Author | Open for | Reviewers |\n|---|---|---|---|\n")
+		for _, pr := range digest.Waiting {
+			reviewers := "—"
+			if len(pr.ReviewerIDs) > 0 {
+				reviewers = escapeMarkdownCell(strings.Join(pr.ReviewerIDs, ", "))
+			}
+			if pr.NeedMoreReviewers {
+				reviewers += " (needs more reviewers)"
+			}
+			fmt.Fprintf(&b, "| %s: %s | %s | %s | %s |\n",
+				escapeMarkdownCell(pr.PullRequestID), escapeMarkdownCell(pr.PullRequestName),
+				escapeMarkdownCell(pr.AuthorID), formatDigestAge(digest.To.Sub(pr.CreatedAt)), reviewers)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n## Reassignments (%d)\n\n", len(digest.Reassignments))
+	if len(digest.Reassignments) == 0 {
+		b.WriteString("No reassignments in this period.\n")
+	} else {
+		b.WriteString("| When | PR | Event | From | To | Strategy |\n|---|---|---|---|---|---|\n")
+		for _, event := range digest.Reassignments {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+				event.CreatedAt.UTC().Format(digestTimeLayout),
+				escapeMarkdownCell(event.PullRequestID),
+				event.Type,
+				digestCellOrDash(event.OldReviewerID),
+				digestCellOrDash(event.NewReviewerID),
+				digestCellOrDash(strategyPtr(event.Strategy)),
+			)
+		}
+	}
+
+	return b.String()
+}
+
+func formatDigestAge(age time.Duration) string {
+	if age < 0 {
+		age = 0
+	}
+	days := int(age / (24 * time.Hour))
+	hours := int(age%(24*time.Hour)) / int(time.Hour)
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+	return fmt.Sprintf("%dh", hours)
+}
+
+func digestCellOrDash(value *string) string {
+	if value == nil || *value == "" {
+		return "—"
+	}
+	return escapeMarkdownCell(*value)
+}
+
+func strategyPtr(strategy domain.AssignmentStrategy) *string {
+	value := string(strategy)
+	return &value
+}
+
+func escapeMarkdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nedokyrill/avito-pr-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTeamDigestMarkdown(t *testing.T) {
+	to := time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC)
+	from := to.Add(-domain.DefaultDigestPeriod)
+
+	t.Run("full digest", func(t *testing.T) {
+		avg := 12.25
+		oldReviewer := "user-bob"
+		newReviewer := "user-dave"
+
+		digest := &domain.TeamDigest{
+			TeamName:        "Backend",
+			From:            from,
+			To:              to,
+			Opened:          3,
+			Merged:          2,
+			Closed:          1,
+			AvgHoursToMerge: &avg,
+			TopReviewers: []domain.DigestReviewer{
+				{UserID: "user-alice", Username: "Alice", Reviews: 2},
+			},
+			Waiting: []domain.DigestWaitingPR{
+				{
+					PullRequestID:     "pr-1",
+					PullRequestName:   "Fix a | b",
+					AuthorID:          "user-carol",
+					CreatedAt:         to.Add(-50 * time.Hour),
+					ReviewerIDs:       []string{"user-alice"},
+					NeedMoreReviewers: true,
+				},
+			},
+			Reassignments: []domain.DigestReassignment{
+				{
+					PullRequestID: "pr-1",
+					Type:          domain.AssignmentEventReassigned,
+					Strategy:      domain.AssignmentStrategyReassign,
+					OldReviewerID: &oldReviewer,
+					NewReviewerID: &newReviewer,
+					CreatedAt:     to.Add(-time.Hour),
+				},
+			},
+		}
+
+		markdown := RenderTeamDigestMarkdown(digest)
+
+		assert.Contains(t, markdown, "# Review digest: Backend")
+		assert.Contains(t, markdown, "Period: 2025-11-17 00:00 — 2025-11-24 00:00 (UTC)")
+		assert.Contains(t, markdown, "- Average time to merge: 12.2 h")
+		assert.Contains(t, markdown, "- Closed without merge: 1")
+		assert.Contains(t, markdown, "| Alice (user-alice) | 2 |")
+		assert.Contains(t, markdown, "| pr-1: Fix a \\| b | user-carol | 2d 2h | user-alice (needs more reviewers) |")
+		assert.Contains(t, markdown, "| 2025-11-23 23:00 | pr-1 | REASSIGNED | user-bob | user-dave | REASSIGN |")
+	})
+
+	t.Run("empty digest", func(t *testing.T) {
+		markdown := RenderTeamDigestMarkdown(&domain.TeamDigest{TeamName: "Mobile", From: from, To: to})
+
+		assert.Contains(t, markdown, "- Average time to merge: —")
+		assert.Contains(t, markdown, "No PRs were merged in this period.")
+		assert.Contains(t, markdown, "## Waiting for review (0)")
+		assert.Contains(t, markdown, "No reassignments in this period.")
+		assert.False(t, strings.Contains(markdown, "|---|"))
+	})
+}